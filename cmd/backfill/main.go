@@ -0,0 +1,90 @@
+// Command backfill fills in reject_category for rejected reviews that were moderated before
+// categories existed. It reuses the category from the review's last AI rejection in the audit log
+// when there is one, and otherwise re-runs moderation at a bounded rate. Review status and reason
+// are never changed.
+//
+// Updated reviews are queued in the ES outbox, so the running service syncs them to Elasticsearch.
+// Secrets such as ${GEMINI_API_KEY} are read from the process environment; the .env file is not loaded.
+//
+//	go run ./cmd/backfill -conf ./configs -dry-run
+//	go run ./cmd/backfill -conf ./configs -qps 2 -limit 1000
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"review/internal/conf"
+	"review/internal/data"
+
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/config/env"
+	"github.com/go-kratos/kratos/v2/config/file"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+var (
+	flagconf  string
+	batchSize int
+	qps       float64
+	limit     int
+	dryRun    bool
+)
+
+func init() {
+	flag.StringVar(&flagconf, "conf", "../../configs", "config path, eg: -conf config.yaml")
+	flag.IntVar(&batchSize, "batch", 100, "reviews read per batch")
+	flag.Float64Var(&qps, "qps", 1, "max moderation calls per second")
+	flag.IntVar(&limit, "limit", 0, "max reviews to process, 0 for no limit")
+	flag.BoolVar(&dryRun, "dry-run", false, "log the categories that would be written without changing anything")
+}
+
+func main() {
+	flag.Parse()
+	logger := log.With(log.NewStdLogger(os.Stdout), "ts", log.DefaultTimestamp)
+	helper := log.NewHelper(logger)
+
+	c := config.New(
+		config.WithSource(
+			env.NewSource(),
+			file.NewSource(flagconf),
+		),
+	)
+	defer c.Close()
+
+	if err := c.Load(); err != nil {
+		helper.Fatal(err)
+	}
+	var bc conf.Bootstrap
+	if err := c.Scan(&bc); err != nil {
+		helper.Fatal(err)
+	}
+
+	db, err := data.NewDB(bc.Data)
+	if err != nil {
+		helper.Fatal(err)
+	}
+	aiClient, err := data.NewAIClient(bc.Ai)
+	if err != nil {
+		helper.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	backfill := data.NewCategoryBackfill(db, aiClient, bc.Elasticsearch, bc.Review, logger)
+	stats, err := backfill.Run(ctx, data.BackfillOptions{
+		BatchSize: batchSize,
+		QPS:       qps,
+		Limit:     limit,
+		DryRun:    dryRun,
+	})
+	helper.Infof("backfill done: checked %d, filled %d (%d from audit logs, %d moderated), skipped %d, failed %d, dry-run %v",
+		stats.Checked, stats.Filled, stats.FromLog, stats.Moderated, stats.Skipped, stats.Failed, dryRun)
+	if err != nil {
+		helper.Fatal(err)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/joho/godotenv"
+)
+
+const (
+	// envFileVar overrides the path of the .env file.
+	envFileVar = "REVIEW_ENV_FILE"
+	// defaultEnvFile is relative to cmd/review, where the service is usually started during development.
+	defaultEnvFile = "../../.env"
+)
+
+// loadEnvFile loads environment variables from the .env file, if there is one.
+// In containers and CI the variables usually come from the orchestrator, so a missing file is only a warning;
+// variables that are already set are not overridden.
+func loadEnvFile() error {
+	path := os.Getenv(envFileVar)
+	if path == "" {
+		path = defaultEnvFile
+	}
+	err := godotenv.Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Warnf("env file %s not found, using the process environment only", path)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load env file %s: %w", path, err)
+	}
+	return nil
+}
+
+// placeholder matches ${NAME} and ${NAME:default} in config values.
+var placeholder = regexp.MustCompile(`\$\{(.*?)\}`)
+
+// envResolver expands ${NAME} and ${NAME:default} placeholders in config values, so secrets such as
+// ${GEMINI_API_KEY} come from the environment (including the .env file) instead of being committed.
+// NAME is looked up in the environment first, then as a dotted config key, e.g. ${auth.issuer};
+// a placeholder that resolves to nothing and has no default becomes an empty string.
+func envResolver(input map[string]any) error {
+	lookup := func(name string) string {
+		key, def, hasDefault := strings.Cut(strings.TrimSpace(name), ":")
+		if v, ok := os.LookupEnv(key); ok {
+			return v
+		}
+		if v, ok := configValue(input, key); ok {
+			return v
+		}
+		if hasDefault {
+			return def
+		}
+		return ""
+	}
+	var resolve func(v any) any
+	resolve = func(v any) any {
+		switch vt := v.(type) {
+		case string:
+			return placeholder.ReplaceAllStringFunc(vt, func(m string) string {
+				return lookup(placeholder.FindStringSubmatch(m)[1])
+			})
+		case map[string]any:
+			for k, sub := range vt {
+				vt[k] = resolve(sub)
+			}
+		case []any:
+			for i, sub := range vt {
+				vt[i] = resolve(sub)
+			}
+		}
+		return v
+	}
+	resolve(input)
+	return nil
+}
+
+// configValue reads a scalar config value by dotted key.
+func configValue(input map[string]any, key string) (string, bool) {
+	var cur any = input
+	for _, part := range strings.Split(key, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if cur, ok = m[part]; !ok {
+			return "", false
+		}
+	}
+	switch cur.(type) {
+	case map[string]any, []any, nil:
+		return "", false
+	}
+	return fmt.Sprint(cur), true
+}
+
+// checkRequired fails startup when a required secret is unset after the .env file, the environment
+// and the config file have all been considered. An unexpanded ${VAR} placeholder counts as unset.
+func checkRequired(bc *conf.Bootstrap) error {
+	var missing []string
+	if unset(bc.Ai.GetApiKey()) && !slices.ContainsFunc(bc.Ai.GetApiKeys(), func(k string) bool { return !unset(k) }) {
+		missing = append(missing, "ai.api_key or ai.api_keys (GEMINI_API_KEY)")
+	}
+	if unset(bc.Auth.GetJwtSecret()) {
+		missing = append(missing, "auth.jwt_secret (JWT_SECRET)")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("required settings are not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func unset(v string) bool {
+	v = strings.TrimSpace(v)
+	return v == "" || (strings.HasPrefix(v, "${") && strings.HasSuffix(v, "}"))
+}
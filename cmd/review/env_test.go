@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"review/internal/conf"
+)
+
+func TestLoadEnvFileMissing(t *testing.T) {
+	t.Setenv(envFileVar, filepath.Join(t.TempDir(), "missing.env"))
+	if err := loadEnvFile(); err != nil {
+		t.Fatalf("loadEnvFile() with a missing file = %v, want nil", err)
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("REVIEW_TEST_FROM_FILE=file\nREVIEW_TEST_PRESET=file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envFileVar, path)
+	t.Setenv("REVIEW_TEST_PRESET", "process")
+	// Setenv registers cleanup, so the variable loaded from the file is removed after the test.
+	t.Setenv("REVIEW_TEST_FROM_FILE", "")
+	os.Unsetenv("REVIEW_TEST_FROM_FILE")
+
+	if err := loadEnvFile(); err != nil {
+		t.Fatalf("loadEnvFile() = %v", err)
+	}
+	if got := os.Getenv("REVIEW_TEST_FROM_FILE"); got != "file" {
+		t.Errorf("REVIEW_TEST_FROM_FILE = %q, want it loaded from the file", got)
+	}
+	if got := os.Getenv("REVIEW_TEST_PRESET"); got != "process" {
+		t.Errorf("REVIEW_TEST_PRESET = %q, want the process environment to win", got)
+	}
+}
+
+func TestEnvResolver(t *testing.T) {
+	t.Setenv("REVIEW_TEST_SECRET", "from-env")
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "environment variable", value: "${REVIEW_TEST_SECRET}", want: "from-env"},
+		{name: "environment wins over default", value: "${REVIEW_TEST_SECRET:fallback}", want: "from-env"},
+		{name: "default when unset", value: "${REVIEW_TEST_UNSET:fallback}", want: "fallback"},
+		{name: "dotted config key", value: "${auth.issuer}", want: "review.service"},
+		{name: "unresolved without default", value: "${REVIEW_TEST_UNSET}", want: ""},
+		{name: "embedded placeholder", value: "Bearer ${REVIEW_TEST_SECRET}", want: "Bearer from-env"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := map[string]any{
+				"auth":  map[string]any{"issuer": "review.service"},
+				"value": tt.value,
+				"list":  []any{tt.value},
+			}
+			if err := envResolver(input); err != nil {
+				t.Fatalf("envResolver() = %v", err)
+			}
+			if got := input["value"]; got != tt.want {
+				t.Errorf("value = %q, want %q", got, tt.want)
+			}
+			if got := input["list"].([]any)[0]; got != tt.want {
+				t.Errorf("list[0] = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRequired(t *testing.T) {
+	tests := []struct {
+		name        string
+		bc          *conf.Bootstrap
+		wantMissing []string
+	}{
+		{
+			name: "all set",
+			bc:   &conf.Bootstrap{Ai: &conf.AI{ApiKey: "key"}, Auth: &conf.Auth{JwtSecret: "secret"}},
+		},
+		{
+			name: "api keys list is enough",
+			bc:   &conf.Bootstrap{Ai: &conf.AI{ApiKeys: []string{"${GEMINI_API_KEY}", "key"}}, Auth: &conf.Auth{JwtSecret: "secret"}},
+		},
+		{
+			name:        "unexpanded jwt secret",
+			bc:          &conf.Bootstrap{Ai: &conf.AI{ApiKey: "key"}, Auth: &conf.Auth{JwtSecret: "${JWT_SECRET}"}},
+			wantMissing: []string{"JWT_SECRET"},
+		},
+		{
+			name:        "nothing configured",
+			bc:          &conf.Bootstrap{},
+			wantMissing: []string{"GEMINI_API_KEY", "JWT_SECRET"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRequired(tt.bc)
+			if len(tt.wantMissing) == 0 {
+				if err != nil {
+					t.Fatalf("checkRequired() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("checkRequired() = nil, want missing %v", tt.wantMissing)
+			}
+			for _, name := range tt.wantMissing {
+				if !strings.Contains(err.Error(), name) {
+					t.Errorf("checkRequired() = %v, want it to mention %s", err, name)
+				}
+			}
+		})
+	}
+}
@@ -5,11 +5,12 @@ import (
 	"os"
 
 	"review/internal/conf"
+	"review/internal/data"
+	"review/internal/server"
 	"review/internal/service"
+	"review/pkg/redact"
 	"review/pkg/snowflake"
 
-	"github.com/joho/godotenv"
-
 	"github.com/go-kratos/kratos/v2"
 	"github.com/go-kratos/kratos/v2/config"
 	"github.com/go-kratos/kratos/v2/config/file"
@@ -22,12 +23,16 @@ import (
 	_ "go.uber.org/automaxprocs"
 )
 
-// go build -ldflags "-X main.Version=x.y.z"
+// go build -ldflags "-X main.Version=x.y.z -X main.GitCommit=abc123 -X main.BuildTime=2025-01-01T00:00:00Z"
 var (
 	// Name is the name of the compiled software.
 	Name string = "review.service"
 	// Version is the version of the compiled software.
 	Version string = "0.0.1"
+	// GitCommit is the git commit the software was built from.
+	GitCommit string = "unknown"
+	// BuildTime is the time the software was built.
+	BuildTime string = "unknown"
 	// flagconf is the config flag.
 	flagconf string
 
@@ -39,7 +44,14 @@ func init() {
 }
 
 func newApp(logger log.Logger, gs *grpc.Server, hs *http.Server, r registry.Registrar,
-	review *service.ReviewService, user *service.UserService, agent *service.AgentService) *kratos.App {
+	review *service.ReviewService, user *service.UserService, agent *service.AgentService, reconciler *data.Reconciler,
+	outboxRelay *data.OutboxRelay, startup *data.Startup) *kratos.App {
+	hs.HandleFunc("/version", server.VersionHandler(server.BuildInfo{
+		Name:      Name,
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+	}))
 	return kratos.New(
 		kratos.ID(id),
 		kratos.Name(Name),
@@ -49,18 +61,22 @@ func newApp(logger log.Logger, gs *grpc.Server, hs *http.Server, r registry.Regi
 		kratos.Server(
 			gs,
 			hs,
+			reconciler,
+			outboxRelay,
 		),
 		kratos.Registrar(r),
+		// Check MySQL, Redis and Elasticsearch before the servers start listening, see data.startup
+		kratos.BeforeStart(startup.Wait),
 	)
 }
 
 func main() {
 	flag.Parse()
 
-	// 从.env文件加载环境变量
+	// 从.env文件加载环境变量, 文件不存在时只使用进程环境变量
 	// 这应该在加载任何依赖于环境变量的配置之前完成
-	if err := godotenv.Load("../../.env"); err != nil {
-		log.Fatalf("Error loading .env file: %v", err)
+	if err := loadEnvFile(); err != nil {
+		log.Fatal(err)
 	}
 
 	logger := log.With(log.NewStdLogger(os.Stdout),
@@ -77,6 +93,7 @@ func main() {
 		config.WithSource(
 			file.NewSource(flagconf),
 		),
+		config.WithResolver(envResolver),
 	)
 	defer c.Close()
 
@@ -89,21 +106,18 @@ func main() {
 		panic(err)
 	}
 
-	// Manually set the API key from the environment variable.
-	// This bypasses any issues with config placeholder expansion.
-	if apiKey := os.Getenv("GEMINI_API_KEY"); apiKey != "" {
-		if bc.Ai == nil {
-			bc.Ai = &conf.AI{}
-		}
-		bc.Ai.ApiKey = apiKey
+	if err := checkRequired(&bc); err != nil {
+		log.Fatal(err)
 	}
 
+	redact.Init(bc.Log.GetRedact(), int(bc.Log.GetMaxContentLength()), bc.Log.GetFullContent())
+
 	var rc conf.Registry
 	if err := c.Scan(&rc); err != nil {
 		panic(err)
 	}
 
-	app, cleanup, err := wireApp(bc.Server, bc.Data, logger, &rc, bc.Elasticsearch, bc.Ai)
+	app, cleanup, err := wireApp(bc.Server, bc.Data, logger, &rc, bc.Elasticsearch, bc.Ai, bc.Auth, bc.Review)
 	if err != nil {
 		panic(err)
 	}
@@ -23,12 +23,12 @@ import (
 // Injectors from wire.go:
 
 // wireApp init kratos application.
-func wireApp(confServer *conf.Server, confData *conf.Data, logger log.Logger, registry *conf.Registry, elasticsearch *conf.Elasticsearch, ai *conf.AI) (*kratos.App, func(), error) {
+func wireApp(confServer *conf.Server, confData *conf.Data, logger log.Logger, registry *conf.Registry, elasticsearch *conf.Elasticsearch, ai *conf.AI, auth *conf.Auth, review *conf.Review) (*kratos.App, func(), error) {
 	db, err := data.NewDB(confData)
 	if err != nil {
 		return nil, nil, err
 	}
-	typedClient, err := data.NewESClient(elasticsearch)
+	typedClient, err := data.NewESClient(elasticsearch, logger)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -37,22 +37,47 @@ func wireApp(confServer *conf.Server, confData *conf.Data, logger log.Logger, re
 	if err != nil {
 		return nil, nil, err
 	}
-	dataData, cleanup, err := data.NewData(db, typedClient, client, logger, aiClient)
+	dataData, cleanup, err := data.NewData(confData, db, typedClient, client, logger, aiClient)
 	if err != nil {
 		return nil, nil, err
 	}
-	reviewRepo := data.NewReviewRepo(dataData, logger, aiClient)
-	reviewUsecase := biz.NewReviewUsecase(reviewRepo, logger)
+	reviewRepo := data.NewReviewRepo(dataData, logger, aiClient, elasticsearch, review)
+	reviewUsecase := biz.NewReviewUsecase(reviewRepo, logger, review)
 	reviewService := service.NewReviewService(reviewUsecase)
-	agentUsecase := biz.NewAgentUsecase(logger, aiClient, reviewUsecase)
+	agentUsecase, err := biz.NewAgentUsecase(logger, aiClient, reviewUsecase, ai)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
 	agentService := service.NewAgentService(agentUsecase)
-	userRepo := data.NewUserRepo(dataData, logger)
-	userUsecase := biz.NewUserUsecase(userRepo, logger)
+	tokenConfig, err := biz.NewTokenConfig(auth)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	userRepo := data.NewUserRepo(dataData, logger, tokenConfig)
+	tokenDenylist := data.NewTokenDenylist(dataData, logger, tokenConfig)
+	userUsecase, err := biz.NewUserUsecase(userRepo, tokenDenylist, logger, auth)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
 	userService := service.NewUserService(userUsecase)
 	grpcServer := server.NewGRPCServer(confServer, reviewService, agentService, userService, logger)
-	httpServer := server.NewHTTPServer(confServer, reviewService, agentService, userService, logger)
+	startup, err := data.NewStartup(confData, db, client, typedClient, elasticsearch, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	httpServer, err := server.NewHTTPServer(confServer, tokenConfig, tokenDenylist, aiClient, startup, reviewService, agentService, userService, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
 	registrar := server.NewRegistrar(registry)
-	app := newApp(logger, grpcServer, httpServer, registrar, reviewService, userService, agentService)
+	reconciler := data.NewReconciler(dataData, logger, elasticsearch)
+	outboxRelay := data.NewOutboxRelay(dataData, logger, elasticsearch)
+	app := newApp(logger, grpcServer, httpServer, registrar, reviewService, userService, agentService, reconciler, outboxRelay, startup)
 	return app, func() {
 		cleanup()
 	}, nil
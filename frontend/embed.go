@@ -0,0 +1,10 @@
+// Package frontend embeds the static frontend pages so the binary can serve
+// them regardless of the working directory it is started from.
+package frontend
+
+import "embed"
+
+// FS holds the frontend pages, rooted at this directory.
+//
+//go:embed index.html user agent dashboard ai-agent
+var FS embed.FS
@@ -1,19 +1,26 @@
 package biz
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"regexp"
 	"review/internal/client/ai"
+	"review/internal/conf"
+	"review/pkg/redact"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	pb "review/api/ai/v1"
 
 	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/log"
-	"github.com/tmc/langchaingo/llms"
+	"gorm.io/gorm"
 )
 
 // AgentUsecase is the usecase for AI agent.
@@ -21,156 +28,241 @@ type AgentUsecase struct {
 	log      *log.Helper
 	aiClient *ai.AIClient
 	reviewUC *ReviewUsecase // Dependency on ReviewUsecase
-	// simple in-memory memory store: sessionID -> messages
-	memMu  sync.RWMutex
-	memory map[string][]message
+	prompts  *promptTemplates
+	tools    *toolRegistry
+	limits   agentLimits
+	// timeouts bounds each CallTool, including the summarization of its result
+	timeouts toolTimeouts
+	// simple in-memory memory store: sessionKey(userID, sessionID) -> session,
+	// bounded to maxSessions with least-recently-used eviction (front of lru is the most recent)
+	memMu       sync.Mutex
+	memory      map[string]*agentSession
+	lru         *list.List
+	maxSessions int
 }
 
 // NewAgentUsecase creates a new agent usecase.
-func NewAgentUsecase(logger log.Logger, aiClient *ai.AIClient, reviewUC *ReviewUsecase) *AgentUsecase {
-	return &AgentUsecase{
+func NewAgentUsecase(logger log.Logger, aiClient *ai.AIClient, reviewUC *ReviewUsecase, c *conf.AI) (*AgentUsecase, error) {
+	prompts, err := loadPromptTemplates(c)
+	if err != nil {
+		return nil, err
+	}
+	uc := &AgentUsecase{
 		log:      log.NewHelper(logger),
 		aiClient: aiClient,
 		reviewUC: reviewUC,
-		memory:   make(map[string][]message),
+		prompts:  prompts,
+		limits:   newAgentLimits(c),
+		memory:   make(map[string]*agentSession),
+		lru:      list.New(),
+	}
+	if uc.maxSessions = int(c.GetMaxSessions()); uc.maxSessions <= 0 {
+		uc.maxSessions = defaultMaxSessions
+	}
+	if uc.tools, err = newToolRegistry(uc.toolHandlers(), configuredRoleTools(c)); err != nil {
+		return nil, err
 	}
+	if uc.timeouts, err = newToolTimeouts(c); err != nil {
+		return nil, err
+	}
+	return uc, nil
+}
+
+// sessionIDPattern limits client-supplied session IDs to a bounded, key-safe charset.
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ErrInvalidSessionID is returned when the session ID is too long or contains disallowed characters.
+var ErrInvalidSessionID = errors.BadRequest("INVALID_SESSION_ID", "session ID must be 1-64 letters, digits, '-' or '_'")
+
+// agentSession is a conversation of one user, stored under sessionKey.
+type agentSession struct {
+	messages []message
+	// lastAccess is when the session was last read or written; elem is its entry in the LRU list.
+	lastAccess time.Time
+	elem       *list.Element
+}
+
+// defaultSessionID derives the session used when the client doesn't provide one.
+func defaultSessionID(userID int64) string {
+	return "user-" + strconv.FormatInt(userID, 10)
+}
+
+// sessionKey namespaces a session ID by its user, so users choosing the same session ID
+// (including another user's default one) get separate conversations.
+func sessionKey(userID int64, sessionID string) string {
+	return strconv.FormatInt(userID, 10) + ":" + sessionID
 }
 
 type message struct {
-	Role string `json:"role"` // user | assistant
+	Role string `json:"role"` // user | assistant | context (client-supplied, never stored)
 	Text string `json:"text"`
 }
 
 // Process handles the core logic of the agent by calling an LLM with conversation memory.
-func (uc *AgentUsecase) Process(ctx context.Context, sessionID, query string) (*pb.ProcessResponse, error) {
-	uc.log.WithContext(ctx).Infof("Processing query with LLM: %s", query)
+// clientContext holds prior messages supplied by the caller, for stateless clients or unauthenticated
+// callers without server-side memory; it is placed before the session history.
+// lang is the response language resolved by ResponseLanguage.
+func (uc *AgentUsecase) Process(ctx context.Context, sessionID, query string, clientContext []string, lang string) (*pb.ProcessResponse, error) {
+	uc.log.WithContext(ctx).Infof("Processing query with LLM: %s", redact.Text(query))
+	if sessionID != "" && !sessionIDPattern.MatchString(sessionID) {
+		return nil, ErrInvalidSessionID
+	}
+	if err := uc.limits.validateQuery(query); err != nil {
+		return nil, err
+	}
+	if err := uc.limits.validateContext(clientContext); err != nil {
+		return nil, err
+	}
 
 	// Get user from context to personalize tools
 	user, err := userFromContext(ctx)
 	var tools string
+	var userID int64
 	if err == nil { // If user is logged in
-		tools = getToolsForRole(user.Role)
-	} else { // Fallback for unauthenticated users or errors
+		tools = uc.getToolsForRole(user.Role)
+		userID = user.UserID
+		if sessionID == "" {
+			sessionID = defaultSessionID(userID)
+		}
+	} else { // Fallback for unauthenticated users or errors; no memory without a user to bind it to
 		uc.log.WithContext(ctx).Warnf("Could not get user from context, falling back to public. Error: %v", err)
-		tools = getToolsForRole("public")
+		tools = uc.getToolsForRole("public")
+		sessionID = ""
 	}
 
-	history := uc.getHistory(sessionID)
-	prompt := buildSystemPromptWithMemory(tools, history, query)
+	history := uc.getHistory(sessionID, userID)
+	history = mergeClientContext(clientContext, history)
+	prompt, err := buildSystemPromptWithMemory(uc.prompts, tools, history, query, lang, uc.limits.maxPrompt)
+	if err != nil {
+		return nil, err
+	}
 
-	llmResponse, err := llms.GenerateFromSinglePrompt(
-		ctx,
-		uc.aiClient.GetLLM(),
-		prompt,
-	)
+	llmResponse, err := uc.aiClient.Generate(ctx, ai.PurposeAgent, prompt)
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("LLM generation failed: %v", err)
+		if stderrors.Is(err, ai.ErrOverloaded) {
+			return nil, errors.ServiceUnavailable("AI_OVERLOADED", "AI服务繁忙，请稍后再试")
+		}
 		return nil, fmt.Errorf("LLM generation failed: %w", err)
 	}
-	uc.log.WithContext(ctx).Infof("LLM raw response: %s", llmResponse)
+	uc.log.WithContext(ctx).Infof("LLM raw response: %s", redact.Text(llmResponse))
 
 	// parse model output
 	resp, err := parseLLMResponse(llmResponse)
 	if err == nil {
 		// persist memory
-		uc.appendHistory(sessionID, message{Role: "user", Text: query})
+		uc.appendHistory(sessionID, userID, message{Role: "user", Text: query})
 		if resp.FinalAnswer != "" {
-			uc.appendHistory(sessionID, message{Role: "assistant", Text: resp.FinalAnswer})
+			uc.appendHistory(sessionID, userID, message{Role: "assistant", Text: resp.FinalAnswer})
 		}
 	}
 	return resp, err
 }
 
-// CallTool executes the tool with RBAC checks.
-func (uc *AgentUsecase) CallTool(ctx context.Context, toolName, arguments, originalQuery string) (string, error) {
-	uc.log.WithContext(ctx).Infof("Calling tool: %s with args: %s for query: %s", toolName, arguments, originalQuery)
+// CallTool executes the tool with RBAC checks. The result is summarized in lang, see ResponseLanguage.
+func (uc *AgentUsecase) CallTool(ctx context.Context, toolName, arguments, originalQuery, lang string) (string, error) {
+	// The arguments carry user-supplied review text and ids, so only their size is logged at info level.
+	uc.log.WithContext(ctx).Infof("Calling tool: %s with %d bytes of args for query: %s", toolName, len(arguments), redact.Text(originalQuery))
+	uc.log.WithContext(ctx).Debugf("Tool %s args: %s", toolName, redact.Text(arguments))
 
 	user, err := userFromContext(ctx)
-	if user.Role != "customer" && user.Role != "merchant" && user.Role != "reviewer" {
-		return "", errors.Forbidden("FORBIDDEN", "invalid role")
+	if err != nil {
+		return "", err
 	}
-
-	var rawResult any
-
-	switch toolName {
-	case "GetReview":
-		// Allowed for all logged-in users
-		var args struct {
-			ReviewID string `json:"reviewID"`
-		}
-		if err = json.Unmarshal([]byte(arguments), &args); err != nil {
-			return "", errors.BadRequest("INVALID_ARGUMENTS", "无法解析GetReview的参数")
+	// The original query is optional here; it only feeds the summary prompt.
+	if originalQuery != "" {
+		if err := uc.limits.validateQuery(originalQuery); err != nil {
+			return "", err
 		}
-		reviewID, err := strconv.ParseInt(args.ReviewID, 10, 64)
-		if err != nil {
-			return "", errors.BadRequest("INVALID_ARGUMENTS", "reviewID必须是有效的数字")
-		}
-		rawResult, err = uc.reviewUC.GetReview(ctx, reviewID)
+	}
 
-	case "ListReviewByStoreID":
-		var args struct {
-			StoreID string `json:"storeID"`
-		}
-		if err = json.Unmarshal([]byte(arguments), &args); err != nil {
-			return "", errors.BadRequest("INVALID_ARGUMENTS", "无法解析ListReviewByStoreID的参数")
-		}
-		storeID, err := strconv.ParseInt(args.StoreID, 10, 64)
-		if err != nil {
-			return "", errors.BadRequest("INVALID_ARGUMENTS", "storeID必须是有效的数字")
-		}
+	tool, ok := uc.tools.tools[toolName]
+	if !ok {
+		return "", errors.NotFound("TOOL_NOT_FOUND", fmt.Sprintf("未找到名为 '%s' 的工具", toolName))
+	}
+	// RBAC: only tools offered to the caller's role may be executed.
+	if !uc.tools.offered(user.Role, toolName) {
+		return "", errors.Forbidden("FORBIDDEN", fmt.Sprintf("当前角色无权使用工具 '%s'", toolName))
+	}
+	args, err := parseToolArgs(tool, arguments)
+	if err != nil {
+		return "", err
+	}
+
+	// The tool and the summarization of its result share one budget, so a slow tool can't hold the agent.
+	budget := uc.timeouts.forTool(toolName)
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
 
-		// Enhanced RBAC check: Merchants can only list reviews for their own store.
-		if user.Role == "merchant" && user.StoreID != storeID {
-			return "", errors.Forbidden("FORBIDDEN", "商家只能查询自己店铺的评论")
+	var summary string
+	rawResult, err := uc.tools.handlers[toolName](ctx, user, args)
+	if err != nil {
+		// Keep the real error in logs; the user gets a conversational explanation instead of a raw error.
+		uc.log.WithContext(ctx).Errorf("Tool %s failed: %v", toolName, err)
+		if ctx.Err() == nil {
+			summary, err = uc.summarizeResult(ctx, originalQuery, lang, toolError{Error: toolErrorMessage(err)})
 		}
+	} else {
+		summary, err = uc.summarizeResult(ctx, originalQuery, lang, rawResult)
+	}
+	if stderrors.Is(ctx.Err(), context.DeadlineExceeded) {
+		uc.log.WithContext(ctx).Warnf("Tool %s exceeded its %v budget", toolName, budget)
+		return toolTimeoutMessage, nil
+	}
+	return summary, err
+}
 
-		rawResult, err = uc.reviewUC.ListReviewByStoreID(ctx, storeID, 1, 10)
+// toolTimeoutMessage is returned instead of a result when a tool call exceeds its budget.
+const toolTimeoutMessage = "查询耗时过长，请缩小查询范围或稍后再试"
 
-	case "ListMyReviews":
-		// RBAC: This tool is implicitly for the logged-in user, role check in getToolsForRole
-		if user.Role != "customer" {
-			return "", errors.Forbidden("FORBIDDEN", "只有顾客才能查询自己的评论")
-		}
-		rawResult, err = uc.reviewUC.ListReviewByUserID(ctx, user.UserID, 1, 10)
+// toolError is the result passed to the summarizer when a tool fails.
+type toolError struct {
+	Error string `json:"error"`
+}
 
-	default:
-		return "", errors.NotFound("TOOL_NOT_FOUND", fmt.Sprintf("未找到名为 '%s' 的工具", toolName))
+// toolErrorMessage maps a tool error to a short, user-safe description for the LLM.
+// Internal details (SQL, ES, stack traces) never leave the server.
+func toolErrorMessage(err error) string {
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return "没有找到相关数据"
 	}
-
-	if err != nil {
-		return "", err
+	se := errors.FromError(err)
+	switch se.Code {
+	case 400:
+		return se.Message
+	case 401, 403:
+		return "没有权限查看这些数据"
+	case 404:
+		return "没有找到相关数据"
+	default:
+		return "服务暂时不可用，请稍后再试"
 	}
-
-	return uc.summarizeResult(ctx, originalQuery, rawResult)
 }
 
 // summarizeResult sends the tool's output and original query to the LLM for a context-aware summary.
-func (uc *AgentUsecase) summarizeResult(ctx context.Context, originalQuery string, result any) (string, error) {
+func (uc *AgentUsecase) summarizeResult(ctx context.Context, originalQuery, lang string, result any) (string, error) {
 	resultBytes, err := json.Marshal(result)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal tool result: %w", err)
 	}
 
-	summaryPrompt := fmt.Sprintf(`
-你是一个乐于助人的AI助手Cortex。一个工具已经运行完毕，并返回了以下的JSON数据。
-你的任务是根据用户的“原始问题”，从这些JSON数据中提取用户最关心的信息，并组织成一段清晰、友好、易于理解的自然语言回复。
-不要杜撰JSON中不存在的信息。直接呈现核心信息即可,优先使用分点作答的格式。
-
-用户的原始问题: "%s"
-
-工具返回的JSON数据:
-%s
-
-请根据用户的原始问题，生成你的自然语言回复。
-`, originalQuery, string(resultBytes))
+	summaryPrompt, err := renderPrompt(uc.prompts.summary, summaryPromptData{Query: originalQuery, Result: string(resultBytes), Language: languageInstruction(lang)})
+	if err != nil {
+		return "", err
+	}
+	// Results too large to summarize are returned as-is rather than sent to the LLM.
+	if utf8.RuneCountInString(summaryPrompt) > uc.limits.maxPrompt {
+		uc.log.WithContext(ctx).Warnf("Summary prompt exceeds %d characters, returning raw tool result", uc.limits.maxPrompt)
+		return string(resultBytes), nil
+	}
 
-	summary, err := llms.GenerateFromSinglePrompt(ctx, uc.aiClient.GetLLM(), summaryPrompt)
+	summary, err := uc.aiClient.Generate(ctx, ai.PurposeSummary, summaryPrompt)
 	if err != nil {
 		uc.log.WithContext(ctx).Errorf("LLM summarization failed: %v", err)
 		return string(resultBytes), nil
 	}
 
-	uc.log.WithContext(ctx).Infof("LLM summary: %s", summary)
+	uc.log.WithContext(ctx).Infof("LLM summary: %s", redact.Text(summary))
 	return summary, nil
 }
 
@@ -205,17 +297,36 @@ func (uc *AgentUsecase) summarizeResult(ctx context.Context, originalQuery strin
 // }
 
 // buildSystemPromptWithMemory builds a prompt that includes short conversation history.
-func buildSystemPromptWithMemory(tools string, history []message, query string) string {
-	var historyLines []string
+// If the prompt exceeds maxLen characters, the oldest messages are dropped until it fits.
+func buildSystemPromptWithMemory(p *promptTemplates, tools string, history []message, query, lang string, maxLen int) (string, error) {
 	// keep last up to 6 turns (12 messages)
-	start := 0
 	if len(history) > 12 {
-		start = len(history) - 12
+		history = history[len(history)-12:]
+	}
+	for {
+		prompt, err := renderSystemPrompt(p, tools, history, query, lang)
+		if err != nil {
+			return "", err
+		}
+		if utf8.RuneCountInString(prompt) <= maxLen {
+			return prompt, nil
+		}
+		if len(history) == 0 {
+			return "", ErrPromptTooLong
+		}
+		history = history[1:]
 	}
-	for _, m := range history[start:] {
+}
+
+func renderSystemPrompt(p *promptTemplates, tools string, history []message, query, lang string) (string, error) {
+	var historyLines []string
+	for _, m := range history {
 		prefix := "[用户]"
-		if m.Role == "assistant" {
+		switch m.Role {
+		case "assistant":
 			prefix = "[Cortex]"
+		case "context":
+			prefix = "[上文]"
 		}
 		historyLines = append(historyLines, fmt.Sprintf("%s %s", prefix, m.Text))
 	}
@@ -223,98 +334,195 @@ func buildSystemPromptWithMemory(tools string, history []message, query string)
 	if joinedHistory == "" {
 		joinedHistory = "(无历史对话)"
 	}
-	return fmt.Sprintf(`
-你是一个强大的人工智能助手，你的名字叫 Cortex。你的任务是帮助用户与评论系统进行交互。
-你必须遵循以下规则：
-1. 结合对话上下文回答问题；若需要数据请调用工具。
-2. 如果你需要使用工具，你必须在思考(thought)后，从下面提供的可用工具列表中选择一个，并生成一个符合该工具参数格式的JSON对象。
-3. 你的输出必须是一个单一的、可被解析的JSON对象，不得包含任何JSON以外的额外文本、解释或注释。
-4. 如果用户的意图不明确或缺少必要信息，你应该直接回答，向用户提问以获取更多信息。
-5. 如果用户的查询与评论系统无关，你应该直接回答。
+	return renderPrompt(p.system, systemPromptData{History: joinedHistory, Tools: tools, Query: query, Language: languageInstruction(lang)})
+}
 
-对话历史：
-%s
+// Defaults for agent input limits, see conf.AI.max_query_length and max_prompt_length.
+const (
+	defaultMaxQueryLength     = 1000
+	defaultMaxPromptLength    = 16000
+	defaultMaxContextMessages = 20
+	defaultMaxSessions        = 10000
+	// maxSessionMessages is how many messages a session keeps; older ones are dropped.
+	maxSessionMessages = 100
+	// defaultHistoryLimit is how many messages GetSessionHistory returns when the caller doesn't say.
+	defaultHistoryLimit = 50
+)
+
+var (
+	// ErrEmptyQuery is returned when the query is empty or only whitespace.
+	ErrEmptyQuery = errors.BadRequest("QUERY_EMPTY", "query must not be empty")
+	// ErrPromptTooLong is returned when the prompt doesn't fit the limit even without history.
+	ErrPromptTooLong = errors.BadRequest("PROMPT_TOO_LONG", "request is too large to process")
+)
 
-可用工具列表:
-%s
+// agentLimits bounds the input sent to the LLM, in characters.
+type agentLimits struct {
+	maxQuery   int
+	maxPrompt  int
+	maxContext int
+}
 
-用户的查询: "%s"
+func newAgentLimits(c *conf.AI) agentLimits {
+	l := agentLimits{
+		maxQuery:   int(c.GetMaxQueryLength()),
+		maxPrompt:  int(c.GetMaxPromptLength()),
+		maxContext: int(c.GetMaxContextMessages()),
+	}
+	if l.maxQuery <= 0 {
+		l.maxQuery = defaultMaxQueryLength
+	}
+	if l.maxPrompt <= 0 {
+		l.maxPrompt = defaultMaxPromptLength
+	}
+	if l.maxContext <= 0 {
+		l.maxContext = defaultMaxContextMessages
+	}
+	return l
+}
 
-请严格按照以下格式输出JSON：
-{
-  "thought": "这里是你的思考过程...",
-  "tool_call": { "tool_name": "...", "arguments": "{...}" }
+// validateContext rejects client-supplied context with too many or over-long messages.
+func (l agentLimits) validateContext(msgs []string) error {
+	if len(msgs) > l.maxContext {
+		return errors.BadRequest("CONTEXT_TOO_LONG", fmt.Sprintf("context must have at most %d messages, got %d", l.maxContext, len(msgs)))
+	}
+	for i, m := range msgs {
+		if n := utf8.RuneCountInString(m); n > l.maxQuery {
+			return errors.BadRequest("CONTEXT_TOO_LONG", fmt.Sprintf("context message %d must be at most %d characters, got %d", i, l.maxQuery, n))
+		}
+	}
+	return nil
 }
-或者
-{
-  "thought": "这里是你的思考过程...",
-  "final_answer": "你的直接回答内容。"
+
+// mergeClientContext places non-empty client-supplied messages before the server history.
+// The prompt builder keeps only the most recent messages of the merged history.
+func mergeClientContext(msgs []string, history []message) []message {
+	if len(msgs) == 0 {
+		return history
+	}
+	merged := make([]message, 0, len(msgs)+len(history))
+	for _, text := range msgs {
+		if strings.TrimSpace(text) != "" {
+			merged = append(merged, message{Role: "context", Text: text})
+		}
+	}
+	return append(merged, history...)
 }
 
-现在，请处理用户的查询。
-`, joinedHistory, tools, query)
+// validateQuery rejects empty or over-long queries.
+func (l agentLimits) validateQuery(query string) error {
+	if strings.TrimSpace(query) == "" {
+		return ErrEmptyQuery
+	}
+	if n := utf8.RuneCountInString(query); n > l.maxQuery {
+		return errors.BadRequest("QUERY_TOO_LONG", fmt.Sprintf("query must be at most %d characters, got %d", l.maxQuery, n))
+	}
+	return nil
 }
 
-func (uc *AgentUsecase) getHistory(sessionID string) []message {
+// getHistory returns the messages of userID's session.
+func (uc *AgentUsecase) getHistory(sessionID string, userID int64) []message {
 	if sessionID == "" {
 		return nil
 	}
-	uc.memMu.RLock()
-	defer uc.memMu.RUnlock()
-	return append([]message(nil), uc.memory[sessionID]...)
+	uc.memMu.Lock()
+	defer uc.memMu.Unlock()
+	sess, ok := uc.memory[sessionKey(userID, sessionID)]
+	if !ok {
+		return nil
+	}
+	uc.touchSession(sess)
+	return append([]message(nil), sess.messages...)
 }
 
-func (uc *AgentUsecase) appendHistory(sessionID string, msg message) {
+// appendHistory appends to userID's session, creating it on first use.
+func (uc *AgentUsecase) appendHistory(sessionID string, userID int64, msg message) {
 	if sessionID == "" {
 		return
 	}
 	uc.memMu.Lock()
 	defer uc.memMu.Unlock()
-	uc.memory[sessionID] = append(uc.memory[sessionID], msg)
-	// cap at 100 messages to prevent unbounded growth
-	if len(uc.memory[sessionID]) > 100 {
-		uc.memory[sessionID] = uc.memory[sessionID][len(uc.memory[sessionID])-100:]
+	key := sessionKey(userID, sessionID)
+	sess, ok := uc.memory[key]
+	if !ok {
+		sess = &agentSession{elem: uc.lru.PushFront(key)}
+		uc.memory[key] = sess
+		uc.evictSessions()
+	}
+	uc.touchSession(sess)
+	sess.messages = append(sess.messages, msg)
+	// cap the messages per session to prevent unbounded growth
+	if len(sess.messages) > maxSessionMessages {
+		sess.messages = sess.messages[len(sess.messages)-maxSessionMessages:]
 	}
 }
 
-func getToolsForRole(role string) string {
-	// Base tools available to any logged-in user with specific roles
-	baseTools := []string{
-		`{
-			"name": "GetReview",
-			"description": "根据评论ID获取单条评论的详细信息。",
-			"parameters": { "type": "object", "properties": { "reviewID": { "type": "string", "description": "评论的唯一ID" } }, "required": ["reviewID"] }
-		}`,
-		`{
-			"name": "ListReviewByStoreID",
-			"description": "根据店铺ID查询该店铺的评论列表。商家只能查询自己店铺的评论。",
-			"parameters": { "type": "object", "properties": { "storeID": { "type": "string", "description": "店铺的唯一ID" } }, "required": ["storeID"] }
-		}`,
-	}
-
-	// Customer-specific tools
-	customerTools := []string{
-		`{
-			"name": "ListMyReviews",
-			"description": "查询我（当前登录用户）自己发布过的所有评论列表，不需要提供任何参数。",
-			"parameters": { "type": "object", "properties": {} }
-		}`,
-	}
-
-	log.Infof("Getting tools for role: '%s'", role)
-
-	var tools []string
-	switch role {
-	case "customer":
-		tools = append(baseTools, customerTools...)
-	case "merchant", "reviewer":
-		tools = baseTools
-	default:
-		// For "public" or any other role, no tools are available.
-		return "[]"
+// touchSession marks the session as the most recently used. Callers must hold memMu.
+func (uc *AgentUsecase) touchSession(sess *agentSession) {
+	sess.lastAccess = time.Now()
+	uc.lru.MoveToFront(sess.elem)
+}
+
+// evictSessions drops least-recently-used sessions until at most maxSessions remain. Callers must hold memMu.
+func (uc *AgentUsecase) evictSessions() {
+	for len(uc.memory) > uc.maxSessions {
+		oldest := uc.lru.Back()
+		key := uc.lru.Remove(oldest).(string)
+		uc.log.Debugf("evicting agent session %s, last accessed at %v", key, uc.memory[key].lastAccess)
+		delete(uc.memory, key)
+	}
+}
+
+// getToolsForRole returns the tools available to role as JSON for the system prompt.
+func (uc *AgentUsecase) getToolsForRole(role string) string {
+	uc.log.Infof("Getting tools for role: '%s'", role)
+	return uc.tools.prompt(role)
+}
+
+// SessionMessage is a stored conversation message returned to its owner.
+type SessionMessage struct {
+	Role string // user | assistant
+	Text string
+}
+
+// GetSessionHistory returns the caller's most recent messages in the session, oldest first, so a client
+// can restore a conversation. An empty sessionID means the caller's default session; limit is clamped to
+// 1..maxSessionMessages (0 means defaultHistoryLimit). Sessions are per user, so unknown sessions and
+// session IDs used only by other users return no messages.
+func (uc *AgentUsecase) GetSessionHistory(ctx context.Context, sessionID string, limit int) ([]SessionMessage, error) {
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
 	}
+	if sessionID == "" {
+		sessionID = defaultSessionID(user.UserID)
+	} else if !sessionIDPattern.MatchString(sessionID) {
+		return nil, ErrInvalidSessionID
+	}
+	switch {
+	case limit <= 0:
+		limit = defaultHistoryLimit
+	case limit > maxSessionMessages:
+		limit = maxSessionMessages
+	}
+	history := uc.getHistory(sessionID, user.UserID)
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	msgs := make([]SessionMessage, 0, len(history))
+	for _, m := range history {
+		msgs = append(msgs, SessionMessage{Role: m.Role, Text: m.Text})
+	}
+	return msgs, nil
+}
 
-	return fmt.Sprintf("[%s]", strings.Join(tools, ","))
+// ListTools returns the tools available to the caller's role; unauthenticated callers get the public set.
+func (uc *AgentUsecase) ListTools(ctx context.Context) []AgentTool {
+	role := "public"
+	if user, err := userFromContext(ctx); err == nil {
+		role = user.Role
+	}
+	return uc.tools.forRole(role)
 }
 
 type llmOutput struct {
@@ -0,0 +1,305 @@
+package biz
+
+import (
+	"container/list"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+func newTestAgentUsecase(maxSessions int) *AgentUsecase {
+	return &AgentUsecase{
+		log:         log.NewHelper(log.DefaultLogger),
+		memory:      make(map[string]*agentSession),
+		lru:         list.New(),
+		maxSessions: maxSessions,
+	}
+}
+
+func TestSessionsAreNamespacedByUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		sessionID string
+	}{
+		{name: "another user's default session", sessionID: defaultSessionID(42)},
+		{name: "same client-chosen session", sessionID: "chat-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestAgentUsecase(10)
+			// user 7 claiming the session ID first must not affect user 42
+			uc.appendHistory(tt.sessionID, 7, message{Role: "user", Text: "from 7"})
+			uc.appendHistory(tt.sessionID, 42, message{Role: "user", Text: "from 42"})
+
+			got := uc.getHistory(tt.sessionID, 42)
+			if len(got) != 1 || got[0].Text != "from 42" {
+				t.Errorf("user 42 history = %v, want only its own message", got)
+			}
+			got = uc.getHistory(tt.sessionID, 7)
+			if len(got) != 1 || got[0].Text != "from 7" {
+				t.Errorf("user 7 history = %v, want only its own message", got)
+			}
+		})
+	}
+}
+
+func TestSessionEviction(t *testing.T) {
+	uc := newTestAgentUsecase(2)
+	uc.appendHistory("a", 1, message{Role: "user", Text: "a"})
+	uc.appendHistory("b", 1, message{Role: "user", Text: "b"})
+	// reading a makes it the most recent, so writing c evicts b
+	uc.getHistory("a", 1)
+	uc.appendHistory("c", 1, message{Role: "user", Text: "c"})
+
+	tests := []struct {
+		sessionID string
+		wantKept  bool
+	}{
+		{"a", true},
+		{"b", false},
+		{"c", true},
+	}
+	for _, tt := range tests {
+		if got := len(uc.getHistory(tt.sessionID, 1)) > 0; got != tt.wantKept {
+			t.Errorf("session %s kept = %v, want %v", tt.sessionID, got, tt.wantKept)
+		}
+	}
+}
+
+func TestSessionIDPattern(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"chat-1", true},
+		{"A_b-9", true},
+		{strings.Repeat("a", 64), true},
+		{"", false},
+		{strings.Repeat("a", 65), false},
+		{"chat:1", false},
+		{"../etc", false},
+		{"会话", false},
+		{"chat 1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := sessionIDPattern.MatchString(tt.id); got != tt.want {
+				t.Errorf("sessionIDPattern.MatchString(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "record not found", err: fmt.Errorf("query: %w", gorm.ErrRecordNotFound), want: "没有找到相关数据"},
+		{name: "bad request keeps its message", err: errors.BadRequest("X", "评分应在1到5之间"), want: "评分应在1到5之间"},
+		{name: "forbidden", err: ErrPermissionDenied, want: "没有权限查看这些数据"},
+		{name: "not found", err: ErrReviewNotFound, want: "没有找到相关数据"},
+		{name: "internal details are hidden", err: stderrors.New("dial tcp 10.0.0.1:3306: connection refused"), want: "服务暂时不可用，请稍后再试"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toolErrorMessage(tt.err); got != tt.want {
+				t.Errorf("toolErrorMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListTools(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantTools int
+	}{
+		{name: "anonymous", ctx: context.Background(), wantTools: 0},
+		{name: "customer", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(1), "role": "customer"}), wantTools: len(roleTools["customer"])},
+		{name: "admin has no tools", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(1), "role": "admin"}), wantTools: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestAgentUsecase(10)
+			uc.tools = newTestToolRegistry(t, nil)
+			got := uc.ListTools(tt.ctx)
+			if got == nil || len(got) != tt.wantTools {
+				t.Errorf("ListTools() = %v, want %d tools", got, tt.wantTools)
+			}
+		})
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	l := newAgentLimits(&conf.AI{MaxQueryLength: 5})
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{name: "fits", query: "你好"},
+		{name: "limit in runes", query: "五个汉字啊"},
+		{name: "empty", query: "", wantErr: true},
+		{name: "whitespace", query: " \n\t", wantErr: true},
+		{name: "too long", query: "六个汉字啊啊", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := l.validateQuery(tt.query); (err != nil) != tt.wantErr {
+				t.Errorf("validateQuery(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildSystemPromptWithMemory(t *testing.T) {
+	p, err := loadPromptTemplates(&conf.AI{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := []message{
+		{Role: "user", Text: "oldest-" + strings.Repeat("x", 200)},
+		{Role: "assistant", Text: "newest"},
+	}
+	base, err := renderSystemPrompt(p, "[]", nil, "q", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, err := renderSystemPrompt(p, "[]", history, "q", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseLen, fullLen := utf8.RuneCountInString(base), utf8.RuneCountInString(full)
+
+	tests := []struct {
+		name       string
+		maxLen     int
+		wantErr    bool
+		wantOldest bool
+		wantNewest bool
+	}{
+		{name: "everything fits", maxLen: fullLen, wantOldest: true, wantNewest: true},
+		{name: "oldest message dropped", maxLen: fullLen - 1, wantNewest: true},
+		{name: "no room even without history", maxLen: baseLen - 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt, err := buildSystemPromptWithMemory(p, "[]", history, "q", "", tt.maxLen)
+			if tt.wantErr {
+				if !errors.Is(err, ErrPromptTooLong) {
+					t.Errorf("buildSystemPromptWithMemory() error = %v, want %v", err, ErrPromptTooLong)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildSystemPromptWithMemory() error = %v", err)
+			}
+			if got := strings.Contains(prompt, "oldest-"); got != tt.wantOldest {
+				t.Errorf("prompt contains the oldest message = %v, want %v", got, tt.wantOldest)
+			}
+			if got := strings.Contains(prompt, "newest"); got != tt.wantNewest {
+				t.Errorf("prompt contains the newest message = %v, want %v", got, tt.wantNewest)
+			}
+		})
+	}
+}
+
+func TestValidateContext(t *testing.T) {
+	l := newAgentLimits(&conf.AI{MaxQueryLength: 3, MaxContextMessages: 2})
+	tests := []struct {
+		name    string
+		msgs    []string
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "within limits", msgs: []string{"一二三", "四"}},
+		{name: "too many messages", msgs: []string{"a", "b", "c"}, wantErr: true},
+		{name: "message too long", msgs: []string{"一二三四"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := l.validateContext(tt.msgs); (err != nil) != tt.wantErr {
+				t.Errorf("validateContext(%q) error = %v, wantErr %v", tt.msgs, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeClientContext(t *testing.T) {
+	history := []message{{Role: "user", Text: "stored"}}
+	got := mergeClientContext([]string{"earlier", "  ", "later"}, history)
+	want := []message{{Role: "context", Text: "earlier"}, {Role: "context", Text: "later"}, {Role: "user", Text: "stored"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeClientContext() = %v, want %v", got, want)
+	}
+}
+
+func TestGetSessionHistory(t *testing.T) {
+	uc := newTestAgentUsecase(10)
+	for i := 0; i < maxSessionMessages; i++ {
+		uc.appendHistory(defaultSessionID(7), 7, message{Role: "user", Text: fmt.Sprint(i)})
+	}
+	uc.appendHistory("chat-1", 42, message{Role: "user", Text: "from 42"})
+	ctx := contextWithClaims(jwtv5.MapClaims{"user_id": float64(7), "role": "customer"})
+
+	tests := []struct {
+		name      string
+		sessionID string
+		limit     int
+		wantTexts []string
+		wantErr   error
+	}{
+		{name: "default session, last messages", limit: 2, wantTexts: []string{fmt.Sprint(maxSessionMessages - 2), fmt.Sprint(maxSessionMessages - 1)}},
+		{name: "default limit", wantTexts: lastTexts(maxSessionMessages, defaultHistoryLimit)},
+		{name: "limit clamped", limit: maxSessionMessages + 5, wantTexts: lastTexts(maxSessionMessages, maxSessionMessages)},
+		{name: "another user's session", sessionID: "chat-1", wantTexts: []string{}},
+		{name: "invalid session ID", sessionID: "../etc", wantErr: ErrInvalidSessionID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, err := uc.GetSessionHistory(ctx, tt.sessionID, tt.limit)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetSessionHistory() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSessionHistory() error = %v", err)
+			}
+			texts := make([]string, 0, len(msgs))
+			for _, m := range msgs {
+				texts = append(texts, m.Text)
+			}
+			if !reflect.DeepEqual(texts, tt.wantTexts) {
+				t.Errorf("history = %v, want %v", texts, tt.wantTexts)
+			}
+		})
+	}
+	if _, err := uc.GetSessionHistory(context.Background(), "", 0); err == nil {
+		t.Error("GetSessionHistory() without a user = nil error, want an error")
+	}
+}
+
+// lastTexts returns the texts of the last n of total messages numbered from 0.
+func lastTexts(total, n int) []string {
+	texts := make([]string, 0, n)
+	for i := total - n; i < total; i++ {
+		texts = append(texts, fmt.Sprint(i))
+	}
+	return texts
+}
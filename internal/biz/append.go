@@ -0,0 +1,52 @@
+package biz
+
+import (
+	"strings"
+
+	"review/internal/conf"
+	"review/internal/data/model"
+)
+
+// 追加评论拼接格式的默认值, 与追加评论单独保存之前写入评论内容的格式一致
+const (
+	defaultAppendSeparator  = "\n\n[追加评论 {time}]:\n"
+	defaultAppendTimeLayout = "2006-01-02 15:04:05"
+)
+
+// AppendView 对外返回的一条追加评论
+type AppendView struct {
+	Content  string `json:"content"`
+	CreateAt MyTime `json:"create_at"`
+}
+
+// NewAppendViews 按追加顺序转换追加评论
+func NewAppendViews(appends []*model.ReviewAppendInfo) []*AppendView {
+	views := make([]*AppendView, 0, len(appends))
+	for _, a := range appends {
+		views = append(views, &AppendView{Content: a.Content, CreateAt: MyTime(a.CreateAt)})
+	}
+	return views
+}
+
+// RenderContent 按 conf.Review.append_format 将评论原文和追加评论拼接成完整内容, 供审核使用
+// appends 需按追加顺序排列
+func RenderContent(c *conf.Review_AppendFormat, content string, appends []*model.ReviewAppendInfo) string {
+	if len(appends) == 0 {
+		return content
+	}
+	separator := c.GetSeparator()
+	if separator == "" {
+		separator = defaultAppendSeparator
+	}
+	layout := c.GetTimeLayout()
+	if layout == "" {
+		layout = defaultAppendTimeLayout
+	}
+	var b strings.Builder
+	b.WriteString(content)
+	for _, a := range appends {
+		b.WriteString(strings.ReplaceAll(separator, "{time}", a.CreateAt.Format(layout)))
+		b.WriteString(a.Content)
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package biz
+
+import (
+	"testing"
+	"time"
+
+	"review/internal/conf"
+	"review/internal/data/model"
+)
+
+func TestRenderContent(t *testing.T) {
+	first := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	appends := []*model.ReviewAppendInfo{
+		{Content: "用了一周还不错", CreateAt: first},
+		{Content: "Still good", CreateAt: first.Add(24 * time.Hour)},
+	}
+	tests := []struct {
+		name    string
+		c       *conf.Review_AppendFormat
+		appends []*model.ReviewAppendInfo
+		want    string
+	}{
+		{name: "no appends", c: &conf.Review_AppendFormat{Separator: " | "}, want: "很好"},
+		{
+			name:    "default format",
+			appends: appends,
+			want:    "很好\n\n[追加评论 2024-05-01 08:30:00]:\n用了一周还不错\n\n[追加评论 2024-05-02 08:30:00]:\nStill good",
+		},
+		{
+			name:    "configured separator and layout",
+			c:       &conf.Review_AppendFormat{Separator: "\n[Update {time}] ", TimeLayout: "2006-01-02"},
+			appends: appends,
+			want:    "很好\n[Update 2024-05-01] 用了一周还不错\n[Update 2024-05-02] Still good",
+		},
+		{
+			name:    "separator without time",
+			c:       &conf.Review_AppendFormat{Separator: " / "},
+			appends: appends[:1],
+			want:    "很好 / 用了一周还不错",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderContent(tt.c, "很好", tt.appends); got != tt.want {
+				t.Errorf("RenderContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAppendViews(t *testing.T) {
+	at := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	views := NewAppendViews([]*model.ReviewAppendInfo{{Content: "第一次", CreateAt: at}, {Content: "第二次", CreateAt: at.Add(time.Hour)}})
+	if len(views) != 2 || views[0].Content != "第一次" || views[1].Content != "第二次" {
+		t.Fatalf("NewAppendViews() = %+v, want both appends in order", views)
+	}
+	if !time.Time(views[1].CreateAt).Equal(at.Add(time.Hour)) {
+		t.Errorf("CreateAt = %v, want %v", time.Time(views[1].CreateAt), at.Add(time.Hour))
+	}
+	if views := NewAppendViews(nil); views == nil || len(views) != 0 {
+		t.Errorf("NewAppendViews(nil) = %v, want an empty list", views)
+	}
+}
@@ -2,6 +2,15 @@ package biz
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"review/internal/conf"
 
 	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/middleware/auth/jwt"
@@ -9,11 +18,131 @@ import (
 )
 
 var (
-	ErrMissingJwtToken = errors.Unauthorized("UNAUTHORIZED", "JWT token is missing")
-	ErrUserNotFound    = errors.Unauthorized("UNAUTHORIZED", "User not found in token")
-	ErrRoleInvalid     = errors.Unauthorized("UNAUTHORIZED", "Role is invalid")
+	ErrMissingJwtToken  = errors.Unauthorized("UNAUTHORIZED", "JWT token is missing")
+	ErrUserNotFound     = errors.Unauthorized("UNAUTHORIZED", "User not found in token")
+	ErrRoleInvalid      = errors.Unauthorized("UNAUTHORIZED", "Role is invalid")
+	ErrNoStore          = errors.Forbidden("NO_STORE", "No store is associated with this merchant")
+	ErrPermissionDenied = errors.Forbidden("FORBIDDEN", "Permission denied")
+)
+
+// defaultTokenTTL is how long an issued token stays valid when auth.token_ttl is unset.
+const defaultTokenTTL = 24 * time.Hour
+
+// defaultJWTIssuer is the service name, used for both iss and aud when unset.
+const defaultJWTIssuer = "review.service"
+
+var (
+	errTokenIssuer   = errors.Unauthorized("UNAUTHORIZED", "JWT token issuer is invalid")
+	errTokenAudience = errors.Unauthorized("UNAUTHORIZED", "JWT token audience is invalid")
 )
 
+// TokenConfig holds the parameters shared by token signing and verification.
+type TokenConfig struct {
+	Secret   []byte
+	Issuer   string
+	Audience string
+	// ttl is the default token lifetime; roleTTL overrides it per role.
+	ttl     time.Duration
+	roleTTL map[string]time.Duration
+}
+
+// NewTokenConfig builds the token config, falling back to defaults for unset fields.
+// It requires a signing secret and rejects per-role lifetimes for unknown roles and non-positive durations.
+func NewTokenConfig(c *conf.Auth) (*TokenConfig, error) {
+	if strings.TrimSpace(c.GetJwtSecret()) == "" {
+		return nil, fmt.Errorf("auth.jwt_secret is required")
+	}
+	tc := &TokenConfig{
+		Secret:   []byte(c.GetJwtSecret()),
+		Issuer:   c.GetIssuer(),
+		Audience: c.GetAudience(),
+		ttl:      defaultTokenTTL,
+		roleTTL:  make(map[string]time.Duration, len(c.GetRoleTokenTtl())),
+	}
+	if c.GetTokenTtl() != nil {
+		if tc.ttl = c.GetTokenTtl().AsDuration(); tc.ttl <= 0 {
+			return nil, fmt.Errorf("auth.token_ttl must be positive, got %v", tc.ttl)
+		}
+	}
+	for role, d := range c.GetRoleTokenTtl() {
+		if !slices.Contains(knownRoles, role) {
+			return nil, fmt.Errorf("auth.role_token_ttl: unknown role %q, must be one of %v", role, knownRoles)
+		}
+		if d.AsDuration() <= 0 {
+			return nil, fmt.Errorf("auth.role_token_ttl: lifetime for role %q must be positive, got %v", role, d.AsDuration())
+		}
+		tc.roleTTL[role] = d.AsDuration()
+	}
+	if tc.Issuer == "" {
+		tc.Issuer = defaultJWTIssuer
+	}
+	if tc.Audience == "" {
+		tc.Audience = defaultJWTIssuer
+	}
+	return tc, nil
+}
+
+// TTL returns the lifetime of a token issued to role.
+func (tc *TokenConfig) TTL(role string) time.Duration {
+	if d, ok := tc.roleTTL[role]; ok {
+		return d
+	}
+	return tc.ttl
+}
+
+// MaxTTL returns the longest lifetime of any issued token, which a revocation must outlive.
+func (tc *TokenConfig) MaxTTL() time.Duration {
+	longest := tc.ttl
+	for _, d := range tc.roleTTL {
+		longest = max(longest, d)
+	}
+	return longest
+}
+
+// Keyfunc verifies iss/aud before handing back the signing key,
+// so tokens minted by another service sharing the secret are rejected.
+func (tc *TokenConfig) Keyfunc(token *jwtv5.Token) (interface{}, error) {
+	if iss, err := token.Claims.GetIssuer(); err != nil || iss != tc.Issuer {
+		return nil, errTokenIssuer
+	}
+	if aud, err := token.Claims.GetAudience(); err != nil || !slices.Contains(aud, tc.Audience) {
+		return nil, errTokenAudience
+	}
+	return tc.Secret, nil
+}
+
+var errTokenRevoked = errors.Unauthorized("UNAUTHORIZED", "JWT token has been revoked, please log in again")
+
+// CheckTokenRevoked rejects a token issued before the user's tokens were revoked (e.g. after a role change).
+// Tokens without iat are treated as issued at the epoch.
+// A denylist lookup failure lets the request through, so a Redis outage does not lock every user out.
+func CheckTokenRevoked(ctx context.Context, denylist TokenDenylist) error {
+	claims, ok := jwt.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	mapClaims, ok := claims.(jwtv5.MapClaims)
+	if !ok {
+		return nil
+	}
+	userID, ok, err := intClaim(mapClaims, "user_id")
+	if err != nil || !ok {
+		return nil
+	}
+	revokedAt, err := denylist.TokensRevokedAt(ctx, userID)
+	if err != nil || revokedAt.IsZero() {
+		return nil
+	}
+	var issuedAt time.Time
+	if iat, err := mapClaims.GetIssuedAt(); err == nil && iat != nil {
+		issuedAt = iat.Time
+	}
+	if issuedAt.Before(revokedAt) {
+		return errTokenRevoked
+	}
+	return nil
+}
+
 type authedUser struct {
 	UserID  int64
 	Role    string
@@ -31,25 +160,98 @@ func userFromContext(ctx context.Context) (*authedUser, error) {
 		return nil, ErrUserNotFound
 	}
 
-	userIDFloat, ok := mapClaims["user_id"].(float64)
-	if !ok {
-		return nil, ErrUserNotFound
+	userID, ok, err := intClaim(mapClaims, "user_id")
+	if err != nil {
+		return nil, err
+	}
+	if !ok || userID <= 0 {
+		return nil, claimError(ErrUserNotFound, "user_id", "missing")
 	}
 
 	role, ok := mapClaims["role"].(string)
 	if !ok {
-		return nil, ErrRoleInvalid
+		return nil, claimError(ErrRoleInvalid, "role", "missing or not a string")
+	}
+	if !slices.Contains(knownRoles, role) {
+		return nil, claimError(ErrRoleInvalid, "role", fmt.Sprintf("%q, must be one of %v", role, knownRoles))
 	}
 
 	user := &authedUser{
-		UserID: int64(userIDFloat),
+		UserID: userID,
 		Role:   role,
 	}
 
-	// StoreID is optional, only for merchants
-	if storeIDFloat, ok := mapClaims["store_id"].(float64); ok {
-		user.StoreID = int64(storeIDFloat)
+	// StoreID is only present for merchants, and every merchant must have one.
+	// Fail here rather than letting store checks compare against a zero store ID.
+	storeID, _, err := intClaim(mapClaims, "store_id")
+	if err != nil {
+		return nil, err
+	}
+	user.StoreID = storeID
+	if role == "merchant" && user.StoreID == 0 {
+		return nil, ErrNoStore
+	}
+
+	return user, nil
+}
+
+// claimError names the offending claim in base's message, keeping base's reason so errors.Is still matches it.
+func claimError(base *errors.Error, claim, problem string) *errors.Error {
+	return errors.New(int(base.Code), base.Reason, fmt.Sprintf("JWT claim %s is %s", claim, problem)).
+		WithMetadata(map[string]string{"claim": claim})
+}
+
+// intClaim reads an integer claim, which may be a JSON number, a json.Number or a decimal string
+// depending on how the token was minted and parsed. ok is false when the claim is absent.
+func intClaim(claims jwtv5.MapClaims, name string) (v int64, ok bool, err error) {
+	raw, present := claims[name]
+	if !present || raw == nil {
+		return 0, false, nil
+	}
+	switch n := raw.(type) {
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+			return 0, true, claimError(ErrUserNotFound, name, fmt.Sprintf("%v, not an integer", n))
+		}
+		return int64(n), true, nil
+	case int64:
+		return n, true, nil
+	case int:
+		return int64(n), true, nil
+	case json.Number:
+		v, err = n.Int64()
+	case string:
+		v, err = strconv.ParseInt(n, 10, 64)
+	default:
+		return 0, true, claimError(ErrUserNotFound, name, fmt.Sprintf("of type %T, not a number", raw))
+	}
+	if err != nil {
+		return 0, true, claimError(ErrUserNotFound, name, fmt.Sprintf("%q, not an integer", fmt.Sprint(raw)))
 	}
+	return v, true, nil
+}
 
+// requireRole returns the caller if their role is one of roles, or ErrPermissionDenied otherwise.
+func requireRole(ctx context.Context, roles ...string) (*authedUser, error) {
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(roles, user.Role) {
+		return nil, ErrPermissionDenied
+	}
 	return user, nil
 }
+
+// RequireAdmin returns ErrPermissionDenied unless the caller is an admin,
+// for endpoints served outside the usecases such as the runtime metrics.
+func RequireAdmin(ctx context.Context) error {
+	_, err := requireRole(ctx, "admin")
+	return err
+}
+
+// IsPrivileged reports whether the caller is a reviewer or admin, who may see audit-only fields.
+func IsPrivileged(ctx context.Context) bool {
+	_, err := requireRole(ctx, "reviewer", "admin")
+	return err == nil
+}
@@ -0,0 +1,212 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware/auth/jwt"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// contextWithClaims returns a context carrying claims as the JWT middleware would.
+func contextWithClaims(claims jwtv5.MapClaims) context.Context {
+	return jwt.NewContext(context.Background(), claims)
+}
+
+func TestNewTokenConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       *conf.Auth
+		wantErr bool
+	}{
+		{name: "missing secret", c: &conf.Auth{}, wantErr: true},
+		{name: "blank secret", c: &conf.Auth{JwtSecret: "  "}, wantErr: true},
+		{name: "secret only", c: &conf.Auth{JwtSecret: "secret"}},
+		{name: "non-positive ttl", c: &conf.Auth{JwtSecret: "secret", TokenTtl: durationpb.New(0)}, wantErr: true},
+		{
+			name:    "unknown role ttl",
+			c:       &conf.Auth{JwtSecret: "secret", RoleTokenTtl: map[string]*durationpb.Duration{"guest": durationpb.New(time.Hour)}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, err := NewTokenConfig(tt.c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTokenConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if string(tc.Secret) != tt.c.GetJwtSecret() {
+				t.Errorf("Secret = %q, want %q", tc.Secret, tt.c.GetJwtSecret())
+			}
+			if tc.Issuer != defaultJWTIssuer || tc.Audience != defaultJWTIssuer {
+				t.Errorf("Issuer, Audience = %q, %q, want %q", tc.Issuer, tc.Audience, defaultJWTIssuer)
+			}
+			if got := tc.TTL("customer"); got != defaultTokenTTL {
+				t.Errorf("TTL(customer) = %v, want %v", got, defaultTokenTTL)
+			}
+		})
+	}
+}
+
+func TestTokenTTL(t *testing.T) {
+	tc, err := NewTokenConfig(&conf.Auth{
+		JwtSecret: "secret",
+		TokenTtl:  durationpb.New(72 * time.Hour),
+		RoleTokenTtl: map[string]*durationpb.Duration{
+			"reviewer": durationpb.New(8 * time.Hour),
+			"admin":    durationpb.New(time.Hour),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		role string
+		want time.Duration
+	}{
+		{"customer", 72 * time.Hour},
+		{"merchant", 72 * time.Hour},
+		{"reviewer", 8 * time.Hour},
+		{"admin", time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			if got := tc.TTL(tt.role); got != tt.want {
+				t.Errorf("TTL(%q) = %v, want %v", tt.role, got, tt.want)
+			}
+		})
+	}
+	if got := tc.MaxTTL(); got != 72*time.Hour {
+		t.Errorf("MaxTTL() = %v, want the default 72h", got)
+	}
+
+	// A role lifetime longer than the default must be outlived by revocations.
+	tc, err = NewTokenConfig(&conf.Auth{JwtSecret: "secret", RoleTokenTtl: map[string]*durationpb.Duration{"customer": durationpb.New(30 * 24 * time.Hour)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tc.MaxTTL(); got != 30*24*time.Hour {
+		t.Errorf("MaxTTL() = %v, want the customer lifetime", got)
+	}
+}
+
+func TestUserFromContextMerchantStore(t *testing.T) {
+	tests := []struct {
+		name        string
+		claims      jwtv5.MapClaims
+		wantErr     *errors.Error
+		wantStoreID int64
+	}{
+		{
+			name:        "merchant with store",
+			claims:      jwtv5.MapClaims{"user_id": float64(1), "role": "merchant", "store_id": float64(10)},
+			wantStoreID: 10,
+		},
+		{
+			name:    "merchant without store_id",
+			claims:  jwtv5.MapClaims{"user_id": float64(1), "role": "merchant"},
+			wantErr: ErrNoStore,
+		},
+		{
+			name:    "merchant with zero store_id",
+			claims:  jwtv5.MapClaims{"user_id": float64(1), "role": "merchant", "store_id": float64(0)},
+			wantErr: ErrNoStore,
+		},
+		{
+			name:   "customer without store_id",
+			claims: jwtv5.MapClaims{"user_id": float64(1), "role": "customer"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := userFromContext(contextWithClaims(tt.claims))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("userFromContext() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("userFromContext() error = %v", err)
+			}
+			if user.StoreID != tt.wantStoreID {
+				t.Errorf("StoreID = %d, want %d", user.StoreID, tt.wantStoreID)
+			}
+		})
+	}
+}
+
+func TestKeyfunc(t *testing.T) {
+	tc := &TokenConfig{Secret: []byte("secret"), Issuer: "review.service", Audience: "review.api"}
+	tests := []struct {
+		name    string
+		claims  jwtv5.MapClaims
+		wantErr error
+	}{
+		{name: "matching iss and aud", claims: jwtv5.MapClaims{"iss": "review.service", "aud": "review.api"}},
+		{name: "aud list containing ours", claims: jwtv5.MapClaims{"iss": "review.service", "aud": []interface{}{"other", "review.api"}}},
+		{name: "missing iss", claims: jwtv5.MapClaims{"aud": "review.api"}, wantErr: errTokenIssuer},
+		{name: "foreign iss", claims: jwtv5.MapClaims{"iss": "other.service", "aud": "review.api"}, wantErr: errTokenIssuer},
+		{name: "missing aud", claims: jwtv5.MapClaims{"iss": "review.service"}, wantErr: errTokenAudience},
+		{name: "foreign aud", claims: jwtv5.MapClaims{"iss": "review.service", "aud": "other.api"}, wantErr: errTokenAudience},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := tc.Keyfunc(jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, tt.claims))
+			if err != tt.wantErr {
+				t.Fatalf("Keyfunc() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && string(key.([]byte)) != "secret" {
+				t.Errorf("Keyfunc() key = %v, want the signing secret", key)
+			}
+		})
+	}
+}
+
+func TestUserFromContextClaimTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		claims    jwtv5.MapClaims
+		wantID    int64
+		wantClaim string
+	}{
+		{name: "float64", claims: jwtv5.MapClaims{"user_id": float64(7), "role": "customer"}, wantID: 7},
+		{name: "int64", claims: jwtv5.MapClaims{"user_id": int64(1<<53 + 1), "role": "customer"}, wantID: 1<<53 + 1},
+		{name: "int", claims: jwtv5.MapClaims{"user_id": 7, "role": "customer"}, wantID: 7},
+		{name: "json.Number", claims: jwtv5.MapClaims{"user_id": json.Number("9007199254740993"), "role": "customer"}, wantID: 9007199254740993},
+		{name: "decimal string", claims: jwtv5.MapClaims{"user_id": "7", "role": "customer"}, wantID: 7},
+		{name: "fractional", claims: jwtv5.MapClaims{"user_id": 7.5, "role": "customer"}, wantClaim: "user_id"},
+		{name: "not a number", claims: jwtv5.MapClaims{"user_id": "seven", "role": "customer"}, wantClaim: "user_id"},
+		{name: "wrong type", claims: jwtv5.MapClaims{"user_id": true, "role": "customer"}, wantClaim: "user_id"},
+		{name: "missing user", claims: jwtv5.MapClaims{"role": "customer"}, wantClaim: "user_id"},
+		{name: "role not a string", claims: jwtv5.MapClaims{"user_id": float64(7), "role": 1}, wantClaim: "role"},
+		{name: "unknown role", claims: jwtv5.MapClaims{"user_id": float64(7), "role": "root"}, wantClaim: "role"},
+		{name: "bad store", claims: jwtv5.MapClaims{"user_id": float64(7), "role": "merchant", "store_id": "x"}, wantClaim: "store_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := userFromContext(contextWithClaims(tt.claims))
+			if tt.wantClaim != "" {
+				if got := errors.FromError(err).Metadata["claim"]; got != tt.wantClaim {
+					t.Fatalf("userFromContext() error = %v, want it to name claim %s", err, tt.wantClaim)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("userFromContext() error = %v", err)
+			}
+			if user.UserID != tt.wantID {
+				t.Errorf("UserID = %d, want %d", user.UserID, tt.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"review/internal/data/model"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+const (
+	// maxAuditExportRange 审核日志导出一次最多覆盖的时间范围
+	maxAuditExportRange = 31 * 24 * time.Hour
+	// auditExportBatch 导出时每次从数据库读取的审核日志条数
+	auditExportBatch = 500
+)
+
+// errAuditExportRange 审核日志导出的时间范围无效
+var errAuditExportRange = errors.BadRequest("AUDIT_EXPORT_RANGE_INVALID",
+	fmt.Sprintf("导出审核日志需要指定开始和结束时间，开始时间早于结束时间，且范围不超过%d天", int(maxAuditExportRange/(24*time.Hour))))
+
+// ExportAuditLogs 按ID顺序导出创建时间在 [from, to) 内的审核日志, 仅管理员可用
+// 按ID分批读取, 每读到一批即逐条交给 emit 输出, 不在内存中缓存全部结果; emit 返回错误时停止导出
+func (uc *ReviewUsecase) ExportAuditLogs(ctx context.Context, from, to time.Time, emit func(*model.ReviewAuditLog) error) error {
+	uc.log.WithContext(ctx).Debugf("[biz] ExportAuditLogs, from: %s, to: %s", from, to)
+	if _, err := requireRole(ctx, "admin"); err != nil {
+		return err
+	}
+	if from.IsZero() || to.IsZero() || !from.Before(to) || to.Sub(from) > maxAuditExportRange {
+		return errAuditExportRange
+	}
+	var afterID int64
+	for {
+		logs, err := uc.repo.ScanAuditLogs(ctx, from, to, afterID, auditExportBatch)
+		if err != nil {
+			return err
+		}
+		for _, l := range logs {
+			if err := emit(l); err != nil {
+				return err
+			}
+		}
+		if len(logs) < auditExportBatch {
+			return nil
+		}
+		afterID = logs[len(logs)-1].ID
+	}
+}
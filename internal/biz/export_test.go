@@ -0,0 +1,80 @@
+package biz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"review/internal/data/model"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+func TestExportAuditLogs(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	var logs []*model.ReviewAuditLog
+	// Two and a half batches inside the range, plus one entry at its end.
+	for i := 1; i <= auditExportBatch*5/2; i++ {
+		logs = append(logs, &model.ReviewAuditLog{ID: int64(i), ReviewID: int64(i), CreateAt: from.Add(time.Duration(i) * time.Second)})
+	}
+	logs = append(logs, &model.ReviewAuditLog{ID: int64(len(logs) + 1), CreateAt: from.Add(24 * time.Hour)})
+	admin := contextWithClaims(jwtv5.MapClaims{"user_id": float64(1), "role": "admin"})
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		from, to   time.Time
+		wantReason string
+		wantCount  int
+		wantScans  int
+	}{
+		{name: "entries in range", ctx: admin, from: from, to: from.Add(24 * time.Hour), wantCount: auditExportBatch * 5 / 2, wantScans: 3},
+		{name: "reviewer", ctx: reviewerContext(), from: from, to: from.Add(time.Hour), wantReason: "FORBIDDEN"},
+		{name: "missing start", ctx: admin, to: from, wantReason: "AUDIT_EXPORT_RANGE_INVALID"},
+		{name: "reversed range", ctx: admin, from: from, to: from.Add(-time.Hour), wantReason: "AUDIT_EXPORT_RANGE_INVALID"},
+		{name: "range too long", ctx: admin, from: from, to: from.Add(maxAuditExportRange + time.Second), wantReason: "AUDIT_EXPORT_RANGE_INVALID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{auditLogs: logs}
+			var got []*model.ReviewAuditLog
+			err := newTestReviewUsecase(repo).ExportAuditLogs(tt.ctx, tt.from, tt.to, func(l *model.ReviewAuditLog) error {
+				got = append(got, l)
+				return nil
+			})
+			if tt.wantReason != "" {
+				if errors.Reason(err) != tt.wantReason {
+					t.Fatalf("ExportAuditLogs() error = %v, want reason %s", err, tt.wantReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExportAuditLogs() error = %v", err)
+			}
+			if len(got) != tt.wantCount || repo.scans != tt.wantScans {
+				t.Fatalf("exported %d entries in %d scans, want %d in %d", len(got), repo.scans, tt.wantCount, tt.wantScans)
+			}
+			for i, l := range got {
+				if l.ID != int64(i+1) {
+					t.Fatalf("entry %d has ID %d, want the entries in ID order", i, l.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestExportAuditLogsStopsOnEmitError(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeReviewRepo{auditLogs: []*model.ReviewAuditLog{{ID: 1, CreateAt: from}, {ID: 2, CreateAt: from}}}
+	admin := contextWithClaims(jwtv5.MapClaims{"user_id": float64(1), "role": "admin"})
+	stop := errors.New(499, "CLIENT_GONE", "client went away")
+	emitted := 0
+	err := newTestReviewUsecase(repo).ExportAuditLogs(admin, from, from.Add(time.Hour), func(*model.ReviewAuditLog) error {
+		emitted++
+		return stop
+	})
+	if !errors.Is(err, stop) || emitted != 1 {
+		t.Errorf("ExportAuditLogs() = %v after %d entries, want the emit error after the first", err, emitted)
+	}
+}
@@ -0,0 +1,123 @@
+package biz
+
+import "encoding/json"
+
+// appendCountKey review_info.ext_json 中记录追加评论次数的字段
+const appendCountKey = "append_count"
+
+// AppendCount 从评论的ext_json中读取已追加的次数, 解析失败按0处理
+func AppendCount(extJSON string) int {
+	if extJSON == "" {
+		return 0
+	}
+	var ext map[string]any
+	if err := json.Unmarshal([]byte(extJSON), &ext); err != nil {
+		return 0
+	}
+	n, _ := ext[appendCountKey].(float64)
+	return int(n)
+}
+
+// WithAppendCount 返回设置了追加次数的ext_json, 保留其它已有字段
+func WithAppendCount(extJSON string, n int) string {
+	ext := map[string]any{}
+	if extJSON != "" {
+		// 原内容不是合法JSON时直接覆盖
+		_ = json.Unmarshal([]byte(extJSON), &ext)
+	}
+	ext[appendCountKey] = n
+	b, _ := json.Marshal(ext)
+	return string(b)
+}
+
+// resubmitCountKey review_info.ext_json 中记录驳回后重新提交次数的字段
+const resubmitCountKey = "resubmit_count"
+
+// ResubmitCount 从评论的ext_json中读取已重新提交的次数, 解析失败按0处理
+func ResubmitCount(extJSON string) int {
+	if extJSON == "" {
+		return 0
+	}
+	var ext map[string]any
+	if err := json.Unmarshal([]byte(extJSON), &ext); err != nil {
+		return 0
+	}
+	n, _ := ext[resubmitCountKey].(float64)
+	return int(n)
+}
+
+// WithResubmitCount 返回设置了重新提交次数的ext_json, 保留其它已有字段
+func WithResubmitCount(extJSON string, n int) string {
+	ext := map[string]any{}
+	if extJSON != "" {
+		// 原内容不是合法JSON时直接覆盖
+		_ = json.Unmarshal([]byte(extJSON), &ext)
+	}
+	ext[resubmitCountKey] = n
+	b, _ := json.Marshal(ext)
+	return string(b)
+}
+
+// appealRecommendationKey review_appeal_info.ext_json 中记录AI申诉建议的字段
+const appealRecommendationKey = "ai_recommendation"
+
+// AppealRecommendation AI给出的申诉处理建议, 仅供审核员参考
+type AppealRecommendation struct {
+	// Decision uphold 驳回申诉、保留评论; overturn 支持申诉
+	Decision  string `json:"decision"`
+	Rationale string `json:"rationale"`
+}
+
+// AppealRecommendationFromExt 从申诉的ext_json中读取AI建议, 没有或解析失败时返回nil
+func AppealRecommendationFromExt(extJSON string) *AppealRecommendation {
+	if extJSON == "" {
+		return nil
+	}
+	var ext struct {
+		Recommendation *AppealRecommendation `json:"ai_recommendation"`
+	}
+	if err := json.Unmarshal([]byte(extJSON), &ext); err != nil {
+		return nil
+	}
+	return ext.Recommendation
+}
+
+// WithAppealRecommendation 返回设置了AI建议的ext_json, 保留其它已有字段
+func WithAppealRecommendation(extJSON string, rec *AppealRecommendation) string {
+	ext := map[string]any{}
+	if extJSON != "" {
+		// 原内容不是合法JSON时直接覆盖
+		_ = json.Unmarshal([]byte(extJSON), &ext)
+	}
+	ext[appealRecommendationKey] = rec
+	b, _ := json.Marshal(ext)
+	return string(b)
+}
+
+// appealStatusKey review_info.ext_json 中记录评论最近一次申诉状态的字段
+const appealStatusKey = "appeal_status"
+
+// AppealStatus 从评论的ext_json中读取最近一次申诉的状态(10待审核/20通过/30驳回), 没有申诉或解析失败时为0
+func AppealStatus(extJSON string) int32 {
+	if extJSON == "" {
+		return 0
+	}
+	var ext map[string]any
+	if err := json.Unmarshal([]byte(extJSON), &ext); err != nil {
+		return 0
+	}
+	n, _ := ext[appealStatusKey].(float64)
+	return int32(n)
+}
+
+// WithAppealStatus 返回设置了申诉状态的ext_json, 保留其它已有字段
+func WithAppealStatus(extJSON string, status int32) string {
+	ext := map[string]any{}
+	if extJSON != "" {
+		// 原内容不是合法JSON时直接覆盖
+		_ = json.Unmarshal([]byte(extJSON), &ext)
+	}
+	ext[appealStatusKey] = status
+	b, _ := json.Marshal(ext)
+	return string(b)
+}
@@ -0,0 +1,79 @@
+package biz
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWithAppendCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		extJSON string
+		n       int
+		wantExt map[string]any
+	}{
+		{name: "empty ext", n: 1, wantExt: map[string]any{"append_count": float64(1)}},
+		{name: "keeps other fields", extJSON: `{"source":"app","append_count":1}`, n: 2, wantExt: map[string]any{"source": "app", "append_count": float64(2)}},
+		{name: "invalid ext is replaced", extJSON: `not json`, n: 1, wantExt: map[string]any{"append_count": float64(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WithAppendCount(tt.extJSON, tt.n)
+			if n := AppendCount(got); n != tt.n {
+				t.Errorf("AppendCount(%s) = %d, want %d", got, n, tt.n)
+			}
+			var ext map[string]any
+			if err := json.Unmarshal([]byte(got), &ext); err != nil {
+				t.Fatalf("WithAppendCount() = %q, not JSON: %v", got, err)
+			}
+			for k, v := range tt.wantExt {
+				if ext[k] != v {
+					t.Errorf("ext[%q] = %v, want %v", k, ext[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestAppendCountInvalid(t *testing.T) {
+	for _, extJSON := range []string{"", "not json", `{"append_count":"2"}`, `{}`} {
+		if n := AppendCount(extJSON); n != 0 {
+			t.Errorf("AppendCount(%q) = %d, want 0", extJSON, n)
+		}
+	}
+}
+
+func TestWithAppealStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		extJSON string
+		status  int32
+		wantExt map[string]any
+	}{
+		{name: "empty ext", status: 10, wantExt: map[string]any{"appeal_status": float64(10)}},
+		{name: "keeps other fields", extJSON: `{"append_count":2,"appeal_status":10}`, status: 20, wantExt: map[string]any{"append_count": float64(2), "appeal_status": float64(20)}},
+		{name: "invalid ext is replaced", extJSON: `not json`, status: 30, wantExt: map[string]any{"appeal_status": float64(30)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WithAppealStatus(tt.extJSON, tt.status)
+			if s := AppealStatus(got); s != tt.status {
+				t.Errorf("AppealStatus(%s) = %d, want %d", got, s, tt.status)
+			}
+			var ext map[string]any
+			if err := json.Unmarshal([]byte(got), &ext); err != nil {
+				t.Fatalf("WithAppealStatus() = %q, not JSON: %v", got, err)
+			}
+			for k, v := range tt.wantExt {
+				if ext[k] != v {
+					t.Errorf("ext[%q] = %v, want %v", k, ext[k], v)
+				}
+			}
+		})
+	}
+	for _, extJSON := range []string{"", "not json", `{"appeal_status":"10"}`} {
+		if s := AppealStatus(extJSON); s != 0 {
+			t.Errorf("AppealStatus(%q) = %d, want 0", extJSON, s)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package biz
+
+import (
+	"fmt"
+	"slices"
+
+	"review/internal/client/ai"
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// 语言不在允许范围内时的处理方式, 见 conf.Review.LanguagePolicy.action
+const (
+	LanguageActionReject      = "reject"
+	LanguageActionHumanReview = "human_review"
+)
+
+// CheckLanguage 按语言策略检查评论内容, 返回检测到的语言及是否允许
+// 未开启、未配置允许的语言或无法判断语言时视为允许
+func CheckLanguage(c *conf.Review_LanguagePolicy, text string) (string, bool) {
+	if !c.GetEnabled() || len(c.GetAllowed()) == 0 {
+		return "", true
+	}
+	lang := ai.DetectLanguage(text)
+	return lang, lang == "" || slices.Contains(c.GetAllowed(), lang)
+}
+
+// LanguageAction 语言不在允许范围内时的处理方式, 未配置时为 reject
+func LanguageAction(c *conf.Review_LanguagePolicy) string {
+	if c.GetAction() == LanguageActionHumanReview {
+		return LanguageActionHumanReview
+	}
+	return LanguageActionReject
+}
+
+// validateLanguage 语言策略为 reject 时拒绝语言不在允许范围内的评论; human_review 时由审核流程转人工
+func validateLanguage(c *conf.Review_LanguagePolicy, text string) error {
+	lang, ok := CheckLanguage(c, text)
+	if ok || LanguageAction(c) != LanguageActionReject {
+		return nil
+	}
+	return errors.BadRequest("REVIEW_LANGUAGE_NOT_ALLOWED",
+		fmt.Sprintf("评论语言(%s)不在允许范围内，允许的语言: %v", lang, c.GetAllowed()))
+}
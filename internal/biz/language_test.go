@@ -0,0 +1,62 @@
+package biz
+
+import (
+	"testing"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+func TestValidateLanguage(t *testing.T) {
+	zhOnly := &conf.Review_LanguagePolicy{Enabled: true, Allowed: []string{"zh"}}
+	tests := []struct {
+		name       string
+		c          *conf.Review_LanguagePolicy
+		text       string
+		wantReason string
+	}{
+		{name: "policy off", c: &conf.Review_LanguagePolicy{Allowed: []string{"zh"}}, text: "Great product"},
+		{name: "no allowed languages", c: &conf.Review_LanguagePolicy{Enabled: true}, text: "Great product"},
+		{name: "allowed language", c: zhOnly, text: "质量很好"},
+		{name: "disallowed language", c: zhOnly, text: "Great product", wantReason: "REVIEW_LANGUAGE_NOT_ALLOWED"},
+		{name: "undetectable language", c: zhOnly, text: "👍👍 100"},
+		{
+			name: "human review is left to the audit",
+			c:    &conf.Review_LanguagePolicy{Enabled: true, Allowed: []string{"zh"}, Action: LanguageActionHumanReview},
+			text: "Great product",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLanguage(tt.c, tt.text)
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("validateLanguage(%q) error = %v, want accepted", tt.text, err)
+				}
+				return
+			}
+			if errors.Reason(err) != tt.wantReason {
+				t.Errorf("validateLanguage(%q) error = %v, want reason %s", tt.text, err, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestLanguageAction(t *testing.T) {
+	tests := []struct {
+		action string
+		want   string
+	}{
+		{action: "", want: LanguageActionReject},
+		{action: LanguageActionHumanReview, want: LanguageActionHumanReview},
+		{action: "bogus", want: LanguageActionReject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			if got := LanguageAction(&conf.Review_LanguagePolicy{Action: tt.action}); got != tt.want {
+				t.Errorf("LanguageAction(%q) = %q, want %q", tt.action, got, tt.want)
+			}
+		})
+	}
+}
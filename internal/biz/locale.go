@@ -0,0 +1,70 @@
+package biz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"golang.org/x/text/language"
+)
+
+// defaultResponseLanguage is used when the caller states no supported preference.
+const defaultResponseLanguage = "zh"
+
+// responseLanguages maps each supported agent response language to the instruction added to the prompts.
+var responseLanguages = map[string]string{
+	"zh": "请使用中文回答。",
+	"en": "Please answer in English.",
+	"ja": "日本語で回答してください。",
+}
+
+// ResponseLanguage picks the agent response language: an explicit request field wins and must be
+// supported; otherwise the first supported language in the Accept-Language header, by quality;
+// otherwise Chinese. Unsupported or malformed headers fall back silently, since browsers send them unasked.
+func ResponseLanguage(requested, acceptLanguage string) (string, error) {
+	if requested = strings.TrimSpace(requested); requested != "" {
+		if lang, ok := baseLanguage(requested); ok {
+			return lang, nil
+		}
+		supported := make([]string, 0, len(responseLanguages))
+		for lang := range responseLanguages {
+			supported = append(supported, lang)
+		}
+		sort.Strings(supported)
+		return "", errors.BadRequest("LANGUAGE_UNSUPPORTED",
+			fmt.Sprintf("不支持的回答语言 %q，可选: %s", requested, strings.Join(supported, ", ")))
+	}
+	if acceptLanguage != "" {
+		tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+		if err == nil {
+			for _, tag := range tags {
+				if lang, ok := baseLanguage(tag.String()); ok {
+					return lang, nil
+				}
+			}
+		}
+	}
+	return defaultResponseLanguage, nil
+}
+
+// baseLanguage reduces a BCP 47 tag such as zh-CN or en_US to its supported base language.
+func baseLanguage(tag string) (string, bool) {
+	t, err := language.Parse(tag)
+	if err != nil {
+		return "", false
+	}
+	base, _ := t.Base()
+	if _, ok := responseLanguages[base.String()]; !ok {
+		return "", false
+	}
+	return base.String(), true
+}
+
+// languageInstruction is the prompt instruction for lang, defaulting to Chinese for unknown values.
+func languageInstruction(lang string) string {
+	if s, ok := responseLanguages[lang]; ok {
+		return s
+	}
+	return responseLanguages[defaultResponseLanguage]
+}
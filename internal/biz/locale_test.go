@@ -0,0 +1,62 @@
+package biz
+
+import (
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+func TestResponseLanguage(t *testing.T) {
+	tests := []struct {
+		name           string
+		requested      string
+		acceptLanguage string
+		want           string
+		wantErr        bool
+	}{
+		{name: "nothing stated", want: "zh"},
+		{name: "requested", requested: "en", want: "en"},
+		{name: "requested with region", requested: " ja-JP ", want: "ja"},
+		{name: "requested wins over the header", requested: "zh", acceptLanguage: "en-US", want: "zh"},
+		{name: "unsupported request", requested: "fr", wantErr: true},
+		{name: "malformed request", requested: "not a tag!", wantErr: true},
+		{name: "header", acceptLanguage: "en-US,en;q=0.9", want: "en"},
+		{name: "header by quality", acceptLanguage: "zh;q=0.5,ja;q=0.8", want: "ja"},
+		{name: "header skips unsupported", acceptLanguage: "fr-FR,de;q=0.9,en;q=0.1", want: "en"},
+		{name: "unsupported header falls back", acceptLanguage: "fr-FR", want: "zh"},
+		{name: "malformed header falls back", acceptLanguage: ";;;q=x", want: "zh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResponseLanguage(tt.requested, tt.acceptLanguage)
+			if tt.wantErr {
+				if errors.Reason(err) != "LANGUAGE_UNSUPPORTED" {
+					t.Fatalf("ResponseLanguage() error = %v, want LANGUAGE_UNSUPPORTED", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ResponseLanguage(%q, %q) = %q, %v, want %q", tt.requested, tt.acceptLanguage, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguageInstruction(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{lang: "en", want: "Please answer in English."},
+		{lang: "zh", want: "请使用中文回答。"},
+		{lang: "", want: "请使用中文回答。"},
+		{lang: "fr", want: "请使用中文回答。"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			if got := languageInstruction(tt.lang); got != tt.want {
+				t.Errorf("languageInstruction(%q) = %q, want %q", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package biz
+
+import "github.com/go-kratos/kratos/v2/errors"
+
+// 查询的记录不存在, 与数据库错误区分, 客户端收到404
+var (
+	ErrReviewNotFound = errors.NotFound("REVIEW_NOT_FOUND", "评论不存在")
+	ErrAppealNotFound = errors.NotFound("APPEAL_NOT_FOUND", "申诉记录不存在")
+)
@@ -0,0 +1,98 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// 媒体大小检查的默认值
+const (
+	defaultMediaMaxBytes    = 20 << 20
+	defaultMediaTimeout     = 3 * time.Second
+	defaultMediaConcurrency = 4
+)
+
+// 无法确认媒体大小时的处理方式, 见 conf.Review.MediaSizeCheck.on_unknown
+const (
+	mediaUnknownSkip   = "skip"
+	mediaUnknownReject = "reject"
+)
+
+// mediaClient 检查媒体大小使用的HTTP客户端, 超时由每个请求的上下文控制
+var mediaClient = &http.Client{}
+
+// checkMediaSize 开启检查时, 对评论中的每个图片/视频URL发起HEAD请求, 按 Content-Length 拒绝超过上限的媒体
+// 请求失败、不支持HEAD或没有 Content-Length 时按 on_unknown 跳过(默认)或拒绝
+func checkMediaSize(ctx context.Context, c *conf.Review_MediaSizeCheck, media ...string) error {
+	if !c.GetEnabled() {
+		return nil
+	}
+	var urls []string
+	for _, m := range media {
+		urls = append(urls, splitMedia(m)...)
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+	maxBytes := c.GetMaxBytes()
+	if maxBytes <= 0 {
+		maxBytes = defaultMediaMaxBytes
+	}
+	timeout := c.GetTimeout().AsDuration()
+	if timeout <= 0 {
+		timeout = defaultMediaTimeout
+	}
+	concurrency := int(c.GetConcurrency())
+	if concurrency <= 0 {
+		concurrency = defaultMediaConcurrency
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for _, u := range urls {
+		g.Go(func() error {
+			size, err := headContentLength(ctx, u, timeout)
+			if err != nil {
+				if c.GetOnUnknown() == mediaUnknownReject {
+					return errors.BadRequest("MEDIA_SIZE_UNKNOWN", fmt.Sprintf("无法确认媒体大小: %s", u)).WithCause(err)
+				}
+				return nil
+			}
+			if size > maxBytes {
+				return errors.BadRequest("MEDIA_TOO_LARGE",
+					fmt.Sprintf("媒体文件不能超过%.1fMB，当前为%.1fMB: %s", float64(maxBytes)/(1<<20), float64(size)/(1<<20), u))
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// headContentLength 发起HEAD请求并返回 Content-Length
+func headContentLength(ctx context.Context, url string, timeout time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := mediaClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("HEAD %s: %s", url, resp.Status)
+	}
+	if resp.ContentLength < 0 {
+		return 0, fmt.Errorf("HEAD %s: no Content-Length", url)
+	}
+	return resp.ContentLength, nil
+}
@@ -0,0 +1,68 @@
+package biz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+func TestCheckMediaSize(t *testing.T) {
+	var heads atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+		heads.Add(1)
+		switch r.URL.Path {
+		case "/small.jpg":
+			w.Header().Set("Content-Length", "100")
+		case "/big.mp4":
+			w.Header().Set("Content-Length", "2000")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	small, big, missing := srv.URL+"/small.jpg", srv.URL+"/big.mp4", srv.URL+"/missing.jpg"
+
+	tests := []struct {
+		name       string
+		c          *conf.Review_MediaSizeCheck
+		pics       string
+		videos     string
+		wantReason string
+		wantHeads  int32
+	}{
+		{name: "disabled", c: &conf.Review_MediaSizeCheck{MaxBytes: 1000}, pics: big, wantHeads: 0},
+		{name: "within the limit", c: &conf.Review_MediaSizeCheck{Enabled: true, MaxBytes: 1000}, pics: small + "," + small, wantHeads: 2},
+		{name: "too large", c: &conf.Review_MediaSizeCheck{Enabled: true, MaxBytes: 1000}, pics: small, videos: big, wantReason: "MEDIA_TOO_LARGE"},
+		{name: "default limit", c: &conf.Review_MediaSizeCheck{Enabled: true}, videos: big, wantHeads: 1},
+		{name: "unknown size skipped", c: &conf.Review_MediaSizeCheck{Enabled: true, MaxBytes: 1000}, pics: missing, wantHeads: 1},
+		{name: "unknown size rejected", c: &conf.Review_MediaSizeCheck{Enabled: true, MaxBytes: 1000, OnUnknown: mediaUnknownReject}, pics: missing, wantReason: "MEDIA_SIZE_UNKNOWN"},
+		{name: "no media", c: &conf.Review_MediaSizeCheck{Enabled: true}, wantHeads: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			heads.Store(0)
+			err := checkMediaSize(context.Background(), tt.c, tt.pics, tt.videos)
+			if tt.wantReason != "" {
+				if errors.Reason(err) != tt.wantReason {
+					t.Errorf("checkMediaSize() error = %v, want reason %s", err, tt.wantReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkMediaSize() error = %v", err)
+			}
+			if heads.Load() != tt.wantHeads {
+				t.Errorf("HEAD requests = %d, want %d", heads.Load(), tt.wantHeads)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package biz
+
+import (
+	"fmt"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+const (
+	defaultPageSize int32 = 10
+	maxPageSize     int32 = 50
+)
+
+// ErrResultWindow 分页位置超出ES允许的最大结果窗口(offset+size)
+func ErrResultWindow(window int) error {
+	return errors.BadRequest("RESULT_WINDOW_EXCEEDED",
+		fmt.Sprintf("分页查询最多只能查看前%d条结果，请缩小查询范围（如按状态、标签过滤）后再翻页", window))
+}
+
+// Pagination 列表查询的分页参数
+type Pagination struct {
+	Offset int32
+	Limit  int32
+}
+
+// NewPagination 由页码(从1开始)和每页条数构造分页参数, 并做归一化
+func NewPagination(page, size int32) Pagination {
+	if page <= 0 {
+		page = 1
+	}
+	size = normalizeLimit(size)
+	return Pagination{Offset: (page - 1) * size, Limit: size}
+}
+
+// pagination 按配置的默认每页条数构造分页参数, 请求中指定的条数优先
+func (uc *ReviewUsecase) pagination(page, size int32) Pagination {
+	if size <= 0 {
+		size = uc.conf.GetDefaultPageSize()
+	}
+	return NewPagination(page, size)
+}
+
+// Normalize 应用默认值和上限: limit 未设置时取默认值, 超过上限时取上限; offset 不小于0
+func (p Pagination) Normalize() Pagination {
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+	p.Limit = normalizeLimit(p.Limit)
+	return p
+}
+
+func normalizeLimit(limit int32) int32 {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
+
+// PageMeta 列表返回的分页元数据
+type PageMeta struct {
+	Page    int32 `json:"page"`
+	Size    int32 `json:"size"`
+	HasMore bool  `json:"has_more"`
+}
+
+// Meta 根据命中总数构造分页元数据
+func (p Pagination) Meta(total int64) PageMeta {
+	return PageMeta{
+		Page:    p.Offset/p.Limit + 1,
+		Size:    p.Limit,
+		HasMore: int64(p.Offset)+int64(p.Limit) < total,
+	}
+}
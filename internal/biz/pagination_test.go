@@ -0,0 +1,70 @@
+package biz
+
+import (
+	"testing"
+
+	"review/internal/conf"
+)
+
+func TestNewPagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		page, size int32
+		want       Pagination
+	}{
+		{name: "first page", page: 1, size: 20, want: Pagination{Offset: 0, Limit: 20}},
+		{name: "third page", page: 3, size: 20, want: Pagination{Offset: 40, Limit: 20}},
+		{name: "zero page is the first", page: 0, size: 20, want: Pagination{Offset: 0, Limit: 20}},
+		{name: "default size", page: 2, size: 0, want: Pagination{Offset: defaultPageSize, Limit: defaultPageSize}},
+		{name: "size is capped", page: 2, size: 1000, want: Pagination{Offset: maxPageSize, Limit: maxPageSize}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewPagination(tt.page, tt.size); got != tt.want {
+				t.Errorf("NewPagination(%d, %d) = %+v, want %+v", tt.page, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginationNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Pagination
+		want Pagination
+	}{
+		{name: "valid", p: Pagination{Offset: 5, Limit: 20}, want: Pagination{Offset: 5, Limit: 20}},
+		{name: "negative offset", p: Pagination{Offset: -1, Limit: 20}, want: Pagination{Offset: 0, Limit: 20}},
+		{name: "unset limit", p: Pagination{}, want: Pagination{Limit: defaultPageSize}},
+		{name: "limit over the cap", p: Pagination{Limit: maxPageSize + 1}, want: Pagination{Limit: maxPageSize}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Normalize(); got != tt.want {
+				t.Errorf("Normalize(%+v) = %+v, want %+v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsecasePagination(t *testing.T) {
+	tests := []struct {
+		name            string
+		defaultPageSize int32
+		page, size      int32
+		want            Pagination
+	}{
+		{name: "unconfigured default", page: 1, want: Pagination{Limit: defaultPageSize}},
+		{name: "configured default", defaultPageSize: 20, page: 2, want: Pagination{Offset: 20, Limit: 20}},
+		{name: "request overrides the default", defaultPageSize: 20, page: 2, size: 5, want: Pagination{Offset: 5, Limit: 5}},
+		{name: "configured default is capped", defaultPageSize: 1000, page: 1, want: Pagination{Limit: maxPageSize}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &ReviewUsecase{conf: &conf.Review{DefaultPageSize: tt.defaultPageSize}}
+			if got := uc.pagination(tt.page, tt.size); got != tt.want {
+				t.Errorf("pagination(%d, %d) = %+v, want %+v", tt.page, tt.size, got, tt.want)
+			}
+		})
+	}
+}
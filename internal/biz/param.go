@@ -1,5 +1,7 @@
 package biz
 
+import "review/internal/data/model"
+
 type AuditReviewParam struct {
 	ReviewID int64
 	Status int32
@@ -8,6 +10,22 @@ type AuditReviewParam struct {
 	OpRemarks string
 }
 
+// BatchAuditResult 批量审核中单条评论的处理结果
+type BatchAuditResult struct {
+	ReviewID int64
+	Status   int32
+	Err      error
+}
+
+// ResubmitReviewParam 重新提交被驳回的评论
+type ResubmitReviewParam struct {
+	// Review 重新提交前的评论
+	Review  *model.ReviewInfo
+	Content string
+	Tags    string
+	OpUser  string
+}
+
 type AppealReviewParam struct {	
 	ReviewID int64
 	StoreID int64
@@ -15,6 +33,8 @@ type AppealReviewParam struct {
 	Content string
 	PicInfo string
 	VideoInfo string
+	// Type 申诉类型 AppealTypeHide/AppealTypeReinstate, 为空时由biz层按评论状态确定
+	Type string
 }
 
 type AuditAppealParam struct {
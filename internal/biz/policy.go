@@ -0,0 +1,68 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"review/internal/client/ai"
+)
+
+// ModerationPolicy 服务当前生效的审核配置, 未配置的项已按默认值填充, 不包含API Key等密钥
+type ModerationPolicy struct {
+	// AI 审核模型、违规类别及按语言的路由
+	AI *ai.ModerationPolicy `json:"ai"`
+	// OnAIError AI审核无法完成时的处理方式 hold/approve/reject/human_review
+	OnAIError string `json:"on_ai_error"`
+	// TrustedFastPath 受信用户快速通道, 未开启时为nil
+	TrustedFastPath *FastPathPolicy `json:"trusted_fast_path,omitempty"`
+	// ModerationCache 审核结论缓存, 未开启时为nil
+	ModerationCache *ModerationCachePolicy `json:"moderation_cache,omitempty"`
+	// LanguagePolicy 评论语言策略, 未开启时为nil
+	LanguagePolicy *LanguagePolicy `json:"language_policy,omitempty"`
+	// MediaSizeCheck 是否检查图片/视频大小
+	MediaSizeCheck bool `json:"media_size_check"`
+	// AllowRejectedAppeal 是否允许对已驳回的评论提起 reinstate 申诉
+	AllowRejectedAppeal bool `json:"allow_rejected_appeal"`
+	// AppealAIAssist 是否请求AI给出申诉处理建议
+	AppealAIAssist bool `json:"appeal_ai_assist"`
+	// StorePriorities 按店铺覆盖的审核任务优先级, 目前是唯一的店铺级配置
+	StorePriorities map[int64]int32 `json:"store_priorities,omitempty"`
+}
+
+// FastPathPolicy 受信用户快速通道的生效配置
+type FastPathPolicy struct {
+	MinApproved  int64    `json:"min_approved"`
+	BlockedWords []string `json:"blocked_words"`
+}
+
+// ModerationCachePolicy 审核结论缓存的生效配置
+type ModerationCachePolicy struct {
+	Mode          string        `json:"mode"`
+	TTL           time.Duration `json:"ttl"`
+	FuzzyDistance int           `json:"fuzzy_distance"`
+	// MaxEntries 缓存结论数的近似上限, 0 表示只按 TTL 过期
+	MaxEntries int64 `json:"max_entries"`
+}
+
+// LanguagePolicy 评论语言策略的生效配置
+type LanguagePolicy struct {
+	Allowed []string `json:"allowed"`
+	Action  string   `json:"action"`
+}
+
+// GetModerationPolicy 返回服务当前生效的审核配置, 便于排查评论被驳回的原因, 仅审核员/管理员可用
+// 每个副本返回自己加载的配置
+func (uc *ReviewUsecase) GetModerationPolicy(ctx context.Context) (*ModerationPolicy, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] GetModerationPolicy")
+	if _, err := requireRole(ctx, "reviewer", "admin"); err != nil {
+		return nil, err
+	}
+	p := uc.repo.ModerationPolicy(ctx)
+	if lp := uc.conf.GetLanguagePolicy(); lp.GetEnabled() && len(lp.GetAllowed()) > 0 {
+		p.LanguagePolicy = &LanguagePolicy{Allowed: lp.GetAllowed(), Action: LanguageAction(lp)}
+	}
+	p.MediaSizeCheck = uc.conf.GetMediaSizeCheck().GetEnabled()
+	p.AllowRejectedAppeal = uc.conf.GetAllowRejectedAppeal()
+	p.AppealAIAssist = uc.conf.GetAppealAiAssist()
+	return p, nil
+}
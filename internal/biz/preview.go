@@ -0,0 +1,60 @@
+package biz
+
+import (
+	"context"
+
+	"review/pkg/redact"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// defaultPreviewRatePerMinute 每个用户每分钟默认可以预审的次数
+const defaultPreviewRatePerMinute = 10
+
+var (
+	// ErrPreviewRateLimited 预审过于频繁
+	ErrPreviewRateLimited = errors.New(429, "PREVIEW_RATE_LIMITED", "预审过于频繁，请稍后再试")
+	// ErrModerationUnavailable AI审核暂时不可用
+	ErrModerationUnavailable = errors.ServiceUnavailable("AI_UNAVAILABLE", "AI审核服务暂时不可用，请稍后再试")
+)
+
+// ModerationVerdict 预审结论, 即内容提交后AI审核可能给出的结果
+type ModerationVerdict struct {
+	Approved   bool    `json:"approved"`
+	Category   string  `json:"category"`
+	Reason     string  `json:"reason"`
+	Confidence float64 `json:"confidence"`
+}
+
+// PreviewModeration 在提交评论前预审内容, 返回AI审核可能给出的结论
+// 只调用审核模型, 不创建评论、不写数据库和ES; 目前的审核流程只审核文字, pics 暂不参与预审
+func (uc *ReviewUsecase) PreviewModeration(ctx context.Context, content, pics string) (*ModerationVerdict, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] PreviewModeration, content: %s", redact.Text(content))
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 1. 数据校验
+	if err := validateContent(uc.conf, content); err != nil {
+		return nil, err
+	}
+	// 2. 按用户限流, 每次预审都会调用LLM
+	limit := int(uc.conf.GetPreviewRatePerMinute())
+	if limit <= 0 {
+		limit = defaultPreviewRatePerMinute
+	}
+	ok, err := uc.repo.AllowPreview(ctx, user.UserID, limit)
+	if err != nil {
+		// 限流计数不可用时放行, 仍受AI全局限流约束
+		uc.log.WithContext(ctx).Warnf("PreviewModeration rate limit check failed, userID: %d, err: %v", user.UserID, err)
+	} else if !ok {
+		return nil, ErrPreviewRateLimited
+	}
+	// 3. 预审
+	verdict, err := uc.repo.PreviewModeration(ctx, content)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("PreviewModeration failed: %v", err)
+		return nil, ErrModerationUnavailable
+	}
+	return verdict, nil
+}
@@ -0,0 +1,72 @@
+package biz
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// fakePreviewRepo only implements the preview calls; any persistence call panics.
+type fakePreviewRepo struct {
+	ReviewRepo
+	allow    bool
+	allowErr error
+	verdict  *ModerationVerdict
+	aiErr    error
+	limits   []int
+	previews []string
+}
+
+func (r *fakePreviewRepo) AllowPreview(_ context.Context, _ int64, limit int) (bool, error) {
+	r.limits = append(r.limits, limit)
+	return r.allow, r.allowErr
+}
+
+func (r *fakePreviewRepo) PreviewModeration(_ context.Context, content string) (*ModerationVerdict, error) {
+	r.previews = append(r.previews, content)
+	return r.verdict, r.aiErr
+}
+
+func TestPreviewModeration(t *testing.T) {
+	flagged := &ModerationVerdict{Approved: false, Category: "advertising", Reason: "含联系方式", Confidence: 0.9}
+	tests := []struct {
+		name        string
+		repo        *fakePreviewRepo
+		content     string
+		want        *ModerationVerdict
+		wantReason  string
+		wantPreview bool
+	}{
+		{name: "verdict", repo: &fakePreviewRepo{allow: true, verdict: flagged}, content: "加微信领优惠", want: flagged, wantPreview: true},
+		{name: "rate limited", repo: &fakePreviewRepo{allow: false}, content: "加微信领优惠", wantReason: "PREVIEW_RATE_LIMITED"},
+		{name: "limiter down fails open", repo: &fakePreviewRepo{allowErr: stderrors.New("redis down"), verdict: flagged}, content: "加微信领优惠", want: flagged, wantPreview: true},
+		{name: "ai unavailable", repo: &fakePreviewRepo{allow: true, aiErr: stderrors.New("quota")}, content: "加微信领优惠", wantReason: "AI_UNAVAILABLE", wantPreview: true},
+		{name: "invalid content", repo: &fakePreviewRepo{allow: true}, content: "", wantReason: "CONTENT_LENGTH_INVALID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestReviewUsecase(tt.repo)
+			got, err := uc.PreviewModeration(reviewerContext(), tt.content, "")
+			if tt.wantReason != "" {
+				if errors.Reason(err) != tt.wantReason {
+					t.Fatalf("error = %v, want reason %s", err, tt.wantReason)
+				}
+			} else if err != nil {
+				t.Fatalf("PreviewModeration() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("verdict = %+v, want %+v", got, tt.want)
+			}
+			if gotPreview := len(tt.repo.previews) == 1; gotPreview != tt.wantPreview {
+				t.Errorf("moderation called = %v, want %v", gotPreview, tt.wantPreview)
+			}
+			for _, limit := range tt.repo.limits {
+				if limit != defaultPreviewRatePerMinute {
+					t.Errorf("limit = %d, want default %d", limit, defaultPreviewRatePerMinute)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package biz
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"review/internal/conf"
+)
+
+// defaultSystemPrompt is the built-in agent system prompt, used when ai.system_prompt_file is not set.
+const defaultSystemPrompt = `
+你是一个强大的人工智能助手，你的名字叫 Cortex。你的任务是帮助用户与评论系统进行交互。
+你必须遵循以下规则：
+1. 结合对话上下文回答问题；若需要数据请调用工具。
+2. 如果你需要使用工具，你必须在思考(thought)后，从下面提供的可用工具列表中选择一个，并生成一个符合该工具参数格式的JSON对象。
+3. 你的输出必须是一个单一的、可被解析的JSON对象，不得包含任何JSON以外的额外文本、解释或注释。
+4. 如果用户的意图不明确或缺少必要信息，你应该直接回答，向用户提问以获取更多信息。
+5. 如果用户的查询与评论系统无关，你应该直接回答。
+6. {{.Language}}
+
+对话历史：
+{{.History}}
+
+可用工具列表:
+{{.Tools}}
+
+用户的查询: "{{.Query}}"
+
+请严格按照以下格式输出JSON：
+{
+  "thought": "这里是你的思考过程...",
+  "tool_call": { "tool_name": "...", "arguments": "{...}" }
+}
+或者
+{
+  "thought": "这里是你的思考过程...",
+  "final_answer": "你的直接回答内容。"
+}
+
+现在，请处理用户的查询。
+`
+
+// defaultSummaryPrompt is the built-in tool result summarizer prompt, used when ai.summary_prompt_file is not set.
+const defaultSummaryPrompt = `
+你是一个乐于助人的AI助手Cortex。一个工具已经运行完毕，并返回了以下的JSON数据。
+你的任务是根据用户的“原始问题”，从这些JSON数据中提取用户最关心的信息，并组织成一段清晰、友好、易于理解的自然语言回复。
+不要杜撰JSON中不存在的信息。直接呈现核心信息即可,优先使用分点作答的格式。
+{{.Language}}
+
+用户的原始问题: "{{.Query}}"
+
+工具返回的JSON数据:
+{{.Result}}
+
+请根据用户的原始问题，生成你的自然语言回复。
+`
+
+// systemPromptData holds the variables available to the system prompt template.
+type systemPromptData struct {
+	History string
+	Tools   string
+	Query   string
+	// Language is the instruction for the response language, see responseLanguages.
+	Language string
+}
+
+// summaryPromptData holds the variables available to the summary prompt template.
+type summaryPromptData struct {
+	Query  string
+	Result string
+	// Language is the instruction for the response language, see responseLanguages.
+	Language string
+}
+
+// languagePlaceholder is appended to prompt files that don't place the response language instruction themselves.
+const languagePlaceholder = "{{.Language}}"
+
+// promptTemplates are the agent prompt templates, parsed once at startup.
+type promptTemplates struct {
+	system  *template.Template
+	summary *template.Template
+}
+
+// loadPromptTemplates loads the prompt templates configured in c, falling back to the built-in defaults.
+func loadPromptTemplates(c *conf.AI) (*promptTemplates, error) {
+	system, err := loadPrompt("system", c.GetSystemPromptFile(), defaultSystemPrompt, systemPromptData{}, "{{.History}}", "{{.Tools}}", "{{.Query}}")
+	if err != nil {
+		return nil, err
+	}
+	summary, err := loadPrompt("summary", c.GetSummaryPromptFile(), defaultSummaryPrompt, summaryPromptData{}, "{{.Query}}", "{{.Result}}")
+	if err != nil {
+		return nil, err
+	}
+	return &promptTemplates{system: system, summary: summary}, nil
+}
+
+// loadPrompt reads and parses a template from path (or def when path is empty), checking it
+// contains every required placeholder and renders against the zero value of data.
+func loadPrompt(name, path, def string, data any, required ...string) (*template.Template, error) {
+	text := def
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s prompt: %w", name, err)
+		}
+		text = string(b)
+	}
+	for _, p := range required {
+		if !strings.Contains(text, p) {
+			return nil, fmt.Errorf("%s prompt %q is missing placeholder %s", name, path, p)
+		}
+	}
+	if !strings.Contains(text, languagePlaceholder) {
+		text += "\n" + languagePlaceholder + "\n"
+	}
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s prompt: %w", name, err)
+	}
+	if err := tmpl.Execute(&bytes.Buffer{}, data); err != nil {
+		return nil, fmt.Errorf("render %s prompt: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func renderPrompt(tmpl *template.Template, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render %s prompt: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,67 @@
+package biz
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"review/internal/conf"
+)
+
+func TestLoadPromptTemplates(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, text string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tests := []struct {
+		name    string
+		c       *conf.AI
+		wantErr bool
+		want    string
+	}{
+		{name: "built-in defaults", c: &conf.AI{}, want: "Cortex"},
+		{
+			name: "custom persona",
+			c:    &conf.AI{SystemPromptFile: write("persona.txt", "I am Ada. {{.History}} {{.Tools}} {{.Query}}")},
+			want: "I am Ada.",
+		},
+		{name: "missing file", c: &conf.AI{SystemPromptFile: filepath.Join(dir, "absent.txt")}, wantErr: true},
+		{
+			name:    "missing placeholder",
+			c:       &conf.AI{SystemPromptFile: write("no-query.txt", "{{.History}} {{.Tools}}")},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			c:       &conf.AI{SystemPromptFile: write("unknown.txt", "{{.History}} {{.Tools}} {{.Query}} {{.Secret}}")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := loadPromptTemplates(tt.c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadPromptTemplates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := renderPrompt(p.system, systemPromptData{Query: "hello", Language: "Answer in English."})
+			if err != nil {
+				t.Fatalf("renderPrompt() error = %v", err)
+			}
+			if !strings.Contains(got, tt.want) || !strings.Contains(got, "hello") {
+				t.Errorf("rendered prompt = %q, want it to contain %q and the query", got, tt.want)
+			}
+			// the language instruction is appended when the file doesn't place it
+			if !strings.Contains(got, "Answer in English.") {
+				t.Errorf("rendered prompt = %q, want the language instruction", got)
+			}
+		})
+	}
+}
@@ -3,64 +3,321 @@ package biz
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	v1 "review/api/review/v1"
+	"review/internal/conf"
 	"review/internal/data/model"
+	"review/pkg/redact"
 	"review/pkg/snowflake"
 
 	"github.com/go-kratos/kratos/v2/log"
+	"golang.org/x/sync/errgroup"
 )
 
+// 审核来源, 写入审核日志
+const (
+	AuditSourceAI    = "ai"
+	AuditSourceHuman = "human"
+	// AuditSourceAuthor 作者修改被驳回的评论后重新提交
+	AuditSourceAuthor = "author"
+	// AuditSourceTrusted 受信用户的评论经本地检查后直接通过, 见 conf.Review.trusted_fast_path
+	AuditSourceTrusted = "trusted-fast-path"
+	// AuditSourceAppeal 审核员处理商家申诉后评论状态的变更
+	AuditSourceAppeal = "appeal"
+	// AuditSourceLanguage 评论语言不在允许范围内, 转人工审核, 见 conf.Review.language_policy
+	AuditSourceLanguage = "language-policy"
+)
+
+// 申诉类型, 必须与申诉时评论的状态一致, 未指定时按评论状态确定
+//
+// 申诉审核的状态流转:
+//
+//	申诉类型   评论状态(申诉时)  申诉通过       申诉驳回
+//	hide       20 已通过         评论 40 隐藏   评论 30 驳回
+//	reinstate  30 已驳回         评论 20 已通过  评论保持 30 驳回
+//
+// 审核申诉时评论状态已不再是申诉时的状态(如已被审核员另行处理)的, 申诉不能再审核
+const (
+	// AppealTypeHide 商家申诉隐藏不公正的已通过评论
+	AppealTypeHide = "hide"
+	// AppealTypeReinstate 商家认为评论被误判, 申诉恢复已驳回的评论; 需开启 conf.Review.allow_rejected_appeal
+	AppealTypeReinstate = "reinstate"
+)
+
+// AppealReviewStatus 申诉类型要求的评论状态, 未知类型返回0
+func AppealReviewStatus(appealType string) int32 {
+	switch appealType {
+	case AppealTypeHide:
+		return 20
+	case AppealTypeReinstate:
+		return 30
+	default:
+		return 0
+	}
+}
+
+// 人工审核结果
+const (
+	AuditDecisionApprove = "approve"
+	AuditDecisionReject  = "reject"
+)
+
+// maxBatchAuditSize 单次批量审核的最大评论数
+const maxBatchAuditSize = 100
+
 type ReviewRepo interface {
 	SaveReview(context.Context, *model.ReviewInfo) (*model.ReviewInfo, error)
 	SaveReply(context.Context, *model.ReviewReplyInfo) (*model.ReviewReplyInfo, error)
 	GetReviewByOrderID(context.Context, int64) ([]*model.ReviewInfo, error)
 	GetReviewByReviewID(context.Context, int64) (*model.ReviewInfo, error)
+	GetReviewsByReviewIDs(context.Context, []int64) ([]*model.ReviewInfo, error)
 	AuditReview(context.Context, *AuditReviewParam) (*model.ReviewInfo, error)
+	ManualAuditReview(context.Context, *AuditReviewParam) (*model.ReviewInfo, error)
+	DeleteReview(context.Context, int64, []int32) error
+	ListAuditLogs(context.Context, int64) ([]*model.ReviewAuditLog, error)
+	// ModerationPolicy 返回数据层生效的审核配置: AI审核、AI失败策略、快速通道、结论缓存及店铺优先级
+	ModerationPolicy(context.Context) *ModerationPolicy
+	// ListAppends 按追加顺序返回评论的追加评论
+	ListAppends(context.Context, int64) ([]*model.ReviewAppendInfo, error)
+	// ScanAuditLogs 按ID顺序返回创建时间在 [from, to) 内、ID大于 afterID 的至多 limit 条审核日志
+	ScanAuditLogs(ctx context.Context, from, to time.Time, afterID int64, limit int) ([]*model.ReviewAuditLog, error)
+	// UpdateReviewScore 只更新评分, 同步ES并清理店铺的列表缓存; 评论在读取后被修改时返回 ErrReviewConflict
+	UpdateReviewScore(context.Context, *model.ReviewInfo, int32, int32, int32) (*model.ReviewInfo, error)
+	// ResubmitReview 将被驳回的评论更新为新内容并重置为待审核, 重新提交异步审核
+	ResubmitReview(context.Context, *ResubmitReviewParam) (*model.ReviewInfo, error)
+	// ReindexReviews 在后台将创建时间在 [from, to) 内的评论重新写入ES, 任务队列已满时返回 ErrReindexBusy
+	ReindexReviews(context.Context, time.Time, time.Time) error
+	GetModerationStats(context.Context, int64, time.Time, time.Time) (*ModerationStats, error)
+	GetReviewerStats(context.Context, time.Time, time.Time) ([]*ReviewerStats, error)
 	AppealReview(context.Context, *AppealReviewParam) (*model.ReviewAppealInfo, error)
+	GetAppealByReviewID(context.Context, int64) (*model.ReviewAppealInfo, error)
+	ListAppealsByReviewID(context.Context, int64) ([]*model.ReviewAppealInfo, error)
+	GetReplyByReviewID(context.Context, int64) (*model.ReviewReplyInfo, error)
+	// RecommendAppeal 异步请求AI对申诉给出建议并保存到申诉记录, 不改变申诉状态
+	RecommendAppeal(context.Context, *model.ReviewAppealInfo, *model.ReviewInfo)
 	AuditAppeal(context.Context, *AuditAppealParam) (*model.ReviewAppealInfo, error)
 	ReplyReview(context.Context, *ReplyReviewParam) (*model.ReviewInfo, error)
-	ListReviewByStoreID(context.Context, int64, int32, int32) ([]*MyReviewInfo, error)
-	ListReviewByUserID(context.Context, int64, int32, int32) ([]*MyReviewInfo, error)
-	ListReviewsByStatus(context.Context, int32, int32, int32) ([]*MyReviewInfo, error)
-	ListAppealsByStatus(context.Context, int32, int32, int32) ([]*model.ReviewAppealInfo, error)
+	ListReviewByStoreID(context.Context, int64, int32, int32, bool, string, ReviewVisibility) (*ReviewList, error)
+	// ListReviewsByStoreIDs 查询多个店铺的评论列表, storeIDs 需已排序去重
+	ListReviewsByStoreIDs(context.Context, []int64, int32, int32, ReviewVisibility) (*ReviewList, error)
+	// ListStoreIDsByUserID 返回商家用户名下的全部店铺ID
+	ListStoreIDsByUserID(context.Context, int64) ([]int64, error)
+	// ListRecentReviews 查询全平台已通过的评论, 按创建时间倒序
+	ListRecentReviews(context.Context, int32, int32) (*ReviewList, error)
+	// GetStoreNames 查询店铺名称, 不存在的店铺不在结果中
+	GetStoreNames(context.Context, []int64) (map[int64]string, error)
+	CountUnrepliedByStoreID(context.Context, int64) (int64, error)
+	ListReviewByUserID(context.Context, int64, int32, int32, ReviewVisibility) (*ReviewList, error)
+	ListReviewsByStatus(context.Context, int32, int32, int32, int32) (*ReviewList, error)
+	ListAppealsByStatus(context.Context, int32, int32, int32) ([]*model.ReviewAppealInfo, int64, error)
+	GetIndexStats(context.Context) (*IndexStats, error)
+	GetTagStats(context.Context, int64) (*TagStats, error)
+	// SuggestReviewTerms 返回店铺已通过评论中以 prefix 开头的常见词
+	SuggestReviewTerms(context.Context, int64, string) ([]*TermSuggestion, error)
+	GetStoreRating(context.Context, int64) (*StoreRating, error)
+	// PreviewModeration 只调用AI审核, 不读写数据库和ES
+	PreviewModeration(context.Context, string) (*ModerationVerdict, error)
+	// AllowPreview 按用户每分钟限流, 返回本次预审是否允许
+	AllowPreview(context.Context, int64, int) (bool, error)
 }
 
 type ReviewUsecase struct {
 	repo ReviewRepo
 	log  *log.Helper
+	conf *conf.Review
 }
 
-func NewReviewUsecase(repo ReviewRepo, logger log.Logger) *ReviewUsecase {
+func NewReviewUsecase(repo ReviewRepo, logger log.Logger, c *conf.Review) *ReviewUsecase {
 	return &ReviewUsecase{
 		repo: repo,
 		log:  log.NewHelper(logger),
+		conf: c,
 	}
 }
 
 // 自定义评论信息, 用于解决 unmarshal error: parsing time "2025-07-03 22:58:19" as "2006-01-02T15:04:05Z07:00"
 type MyReviewInfo struct {
 	*model.ReviewInfo
-	CreateAt     MyTime `json:"create_at"`
-	UpdateAt     MyTime `json:"update_at"`
-	ID           int64  `json:"id"`
-	Version      int32  `json:"version"`
-	ReviewID     int64  `json:"review_id"`
-	Score        int32  `json:"score"`
-	ServiceScore int32  `json:"service_score"`
-	ExpressScore int32  `json:"express_score"`
-	HasMedia     int32  `json:"has_media"`
-	OrderID      int64  `json:"order_id"`
-	SkuID        int64  `json:"sku_id"`
-	SpuID        int64  `json:"spu_id"`
-	StoreID      int64  `json:"store_id"`
-	UserID       int64  `json:"user_id"`
-	Anonymous    int32  `json:"anonymous"`
-	Status       int32  `json:"status"`
-	IsDefault    int32  `json:"is_default"`
-	HasReply     int32  `json:"has_reply"`
+	CreateAt     MyTime     `json:"create_at"`
+	UpdateAt     MyTime     `json:"update_at"`
+	Tags         ReviewTags `json:"tags"`
+	ID           int64      `json:"id"`
+	Version      int32      `json:"version"`
+	ReviewID     int64      `json:"review_id"`
+	Score        int32      `json:"score"`
+	ServiceScore int32      `json:"service_score"`
+	ExpressScore int32      `json:"express_score"`
+	HasMedia     int32      `json:"has_media"`
+	OrderID      int64      `json:"order_id"`
+	SkuID        int64      `json:"sku_id"`
+	SpuID        int64      `json:"spu_id"`
+	StoreID      int64      `json:"store_id"`
+	UserID       int64      `json:"user_id"`
+	Anonymous    int32      `json:"anonymous"`
+	Status       int32      `json:"status"`
+	IsDefault    int32      `json:"is_default"`
+	HasReply     int32      `json:"has_reply"`
+	// Appends 追加评论, 按追加顺序排列
+	Appends []*AppendView `json:"appends"`
+	// StoreName 店铺名称, 不存储在ES中, 仅全平台最新评论列表由biz层填充
+	StoreName string `json:"store_name,omitempty"`
+}
+
+// ReviewList ES评论列表查询结果
+type ReviewList struct {
+	List []*MyReviewInfo `json:"list"`
+	// Total 命中总数, TotalRelation 为 "eq" 时是精确值, 为 "gte" 时只是下限
+	Total         int64  `json:"total"`
+	TotalRelation string `json:"total_relation"`
+	// Partial 为 true 时ES有分片超时或失败, 列表可能不完整
+	Partial bool `json:"partial"`
+	// UnrepliedCount 商家未回复的评论数, 仅商家评论列表返回
+	UnrepliedCount int64 `json:"unreplied_count"`
+	// Page 分页信息, 由biz层根据请求的分页参数填充
+	Page PageMeta `json:"page"`
+	// Applied 实际生效的查询条件, 由biz层填充
+	Applied AppliedQuery `json:"applied"`
+}
+
+// AppliedQuery 列表查询实际生效的条件, 即归一化后的请求参数（如 size 超过上限时为上限）
+// 便于前端同步状态、排查问题时复现查询; 列表不指定排序, 沿用ES默认顺序
+type AppliedQuery struct {
+	StoreID       int64  `json:"store_id,omitempty"`
+	UserID        int64  `json:"user_id,omitempty"`
+	Status        int32  `json:"status,omitempty"`
+	Tag           string `json:"tag,omitempty"`
+	OnlyUnreplied bool   `json:"only_unreplied,omitempty"`
+	AppealStatus  int32  `json:"appeal_status,omitempty"`
+	Page          int32  `json:"page"`
+	Size          int32  `json:"size"`
+	Offset        int32  `json:"offset"`
+}
+
+// appliedPage 按归一化后的分页参数构造生效条件
+func appliedPage(p Pagination) AppliedQuery {
+	return AppliedQuery{Page: p.Offset/p.Limit + 1, Size: p.Limit, Offset: p.Offset}
+}
+
+// TotalIsLowerBound 命中总数是否只是下限（超出了ES的统计上限）
+func (l *ReviewList) TotalIsLowerBound() bool {
+	return l.TotalRelation == "gte"
+}
+
+// ReviewVisibility 列表查询中评论的可见性规则
+// 已通过(20)的评论总是可见; 其余字段放宽可见范围
+type ReviewVisibility struct {
+	// All 不按状态过滤, 审核员/管理员使用
+	All bool
+	// PendingPublic 待审核(10)的评论对所有人可见
+	PendingPublic bool
+	// PendingAuthorID 该用户自己的待审核评论可见, 0表示不放开
+	PendingAuthorID int64
+}
+
+// Allows 单条评论是否可见, 规则与列表查询的ES过滤条件一致
+func (v ReviewVisibility) Allows(review *model.ReviewInfo) bool {
+	switch {
+	case v.All || review.Status == 20:
+		return true
+	case review.Status != 10:
+		return false
+	default:
+		return v.PendingPublic || (v.PendingAuthorID > 0 && review.UserID == v.PendingAuthorID)
+	}
+}
+
+// ModerationStats 驳回评论按类别的统计结果
+type ModerationStats struct {
+	Total      int64            `json:"total"`
+	Categories []*CategoryCount `json:"categories"`
+}
+
+// CategoryCount 单个驳回类别的评论数
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+// ReviewerStats 一个操作人在时间范围内的人工审核工作量, 来自审核日志
+// 审核日志中的操作人与审核员的用户ID一致时归到该审核员, 填充 UserID 和 Username; 其余操作人只有 OpUser;
+// 没有审核记录的审核员各项计数为0
+type ReviewerStats struct {
+	OpUser   string `json:"op_user"`
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	// Approved, Rejected 人工审核通过(20)、驳回(30)的评论数
+	Approved int64 `json:"approved"`
+	Rejected int64 `json:"rejected"`
+	// AppealsHandled 处理的申诉数, 不区分支持或驳回申诉
+	AppealsHandled int64 `json:"appeals_handled"`
+}
+
+// StoreRating 店铺的平均评分
+// 只统计已通过(20)的评论: 待审核(10)的评论尚未确定能否发布, 即使 pending_visibility 为 public 也不计入;
+// 驳回(30)、隐藏(40)和已删除的评论不计入
+// 平均分是原始评分的算术平均值, 与评分处于同一范围 [ScaleMin, ScaleMax], 评分范围为 0~1 时即好评率
+type StoreRating struct {
+	StoreID      int64   `json:"store_id"`
+	Count        int64   `json:"count"`
+	Score        float64 `json:"score"`
+	ServiceScore float64 `json:"service_score"`
+	ExpressScore float64 `json:"express_score"`
+	// ScaleMin, ScaleMax 当前配置的评分范围, 由biz层填充, 不写入缓存
+	ScaleMin int32 `json:"-"`
+	ScaleMax int32 `json:"-"`
+}
+
+// TagStats 已发布评论按话题标签的分布
+type TagStats struct {
+	Total int64       `json:"total"`
+	Tags  []*TagCount `json:"tags"`
+}
+
+// TagCount 单个标签的评论数
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// TermSuggestion 搜索框输入提示的一个词, Count 为包含该词的评论数
+type TermSuggestion struct {
+	Term  string `json:"term"`
+	Count int64  `json:"count"`
+}
+
+// IndexStats ES评论索引的状态, 及与MySQL中评论数的对比
+type IndexStats struct {
+	Index     string `json:"index"`
+	DocCount  int64  `json:"doc_count"`
+	SizeBytes int64  `json:"size_bytes"`
+	// Health 索引健康状态 green/yellow/red
+	Health string `json:"health"`
+	// DBCount MySQL中未删除的评论数
+	DBCount int64 `json:"db_count"`
+	// Drift DBCount 与 DocCount 之差, 持续为正说明有评论未能同步到ES
+	Drift int64 `json:"drift"`
+}
+
+// defaultStatsRange 未指定开始时间时默认统计最近7天
+const defaultStatsRange = 7 * 24 * time.Hour
+
+// ReviewWithAppends 评论及其追加评论, 追加评论按追加顺序排列
+type ReviewWithAppends struct {
+	*model.ReviewInfo
+	Appends []*AppendView
+}
+
+// AppealWithReview 申诉记录及其关联的评论, 便于审核员一次拿到完整上下文
+type AppealWithReview struct {
+	*model.ReviewAppealInfo
+	Review *model.ReviewInfo // 关联评论已不存在时为nil
+	// Recommendation AI申诉建议, 未开启 appeal_ai_assist 或尚未生成时为nil
+	Recommendation *AppealRecommendation
 }
 
 // 自定义时间类型，便于实现UnmarshalJSON方法
@@ -78,35 +335,357 @@ func (t *MyTime) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON 按RFC3339格式序列化时间，与UnmarshalJSON对应
+func (t MyTime) MarshalJSON() ([]byte, error) {
+	return time.Time(t).MarshalJSON()
+}
+
 // 创建评论, service层调用
 func (uc *ReviewUsecase) CreateReview(ctx context.Context, review *model.ReviewInfo) (*model.ReviewInfo, error) {
-	uc.log.WithContext(ctx).Debugf("[biz] CreateReview, review: %v", review)
+	uc.log.WithContext(ctx).Debugf("[biz] CreateReview, userID: %s, orderID: %d, content: %s", redact.ID(review.UserID), review.OrderID, redact.Text(review.Content))
 	// 1. 数据校验
-	_, err := uc.repo.GetReviewByOrderID(ctx, review.OrderID)
+	if err := validateContent(uc.conf, review.Content); err != nil {
+		return nil, err
+	}
+	if err := validateScores(uc.conf, review.Score, review.ServiceScore, review.ExpressScore); err != nil {
+		return nil, err
+	}
+	if err := validateLanguage(uc.conf.GetLanguagePolicy(), review.Content); err != nil {
+		return nil, err
+	}
+	if err := checkMediaSize(ctx, uc.conf.GetMediaSizeCheck(), review.PicInfo, review.VideoInfo); err != nil {
+		return nil, err
+	}
+	reviews, err := uc.repo.GetReviewByOrderID(ctx, review.OrderID)
 	if err != nil {
 		return nil, v1.ErrorDbFailed("数据库查询评论失败, orderID: %d", review.OrderID)
 	}
+	// 已有评论时本次为追加, 限制追加次数, 防止内容无限增长
+	if len(reviews) > 0 {
+		if n, limit := AppendCount(reviews[0].ExtJSON), maxAppends(uc.conf); n >= limit {
+			return nil, v1.ErrorOrderReviewed("该订单最多只能追加%d次评论, orderID: %d", limit, review.OrderID)
+		}
+	}
 	// if len(reviews) > 0 {
 	// 	return nil, v1.ErrorOrderReviewed("已评价的订单不能重复评价, orderID: %d", review.OrderID)
 	// }
 
-	// 2. 拼装数据入库
+	// 2. 按话题词表打标签, 随评论一起入库并同步到ES
+	review.Tags = EncodeTags(matchTags(uc.conf.GetTags(), review.Content))
+
+	// 3. 拼装数据入库
 	return uc.repo.SaveReview(ctx, review)
 }
 
-// GetReview 获取评论
-func (uc *ReviewUsecase) GetReview(ctx context.Context, reviewID int64) (*model.ReviewInfo, error) {
+// GetReview 获取评论及其追加评论, 只有追加过的评论才查询追加评论
+func (uc *ReviewUsecase) GetReview(ctx context.Context, reviewID int64) (*ReviewWithAppends, error) {
 	uc.log.WithContext(ctx).Debugf("[biz] GetReview, reviewID: %d", reviewID)
-	return uc.repo.GetReviewByReviewID(ctx, reviewID)
+	review, err := uc.repo.GetReviewByReviewID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	res := &ReviewWithAppends{ReviewInfo: review, Appends: []*AppendView{}}
+	if AppendCount(review.ExtJSON) > 0 {
+		appends, err := uc.repo.ListAppends(ctx, reviewID)
+		if err != nil {
+			return nil, v1.ErrorDbFailed("数据库查询追加评论失败, reviewID: %d", reviewID)
+		}
+		res.Appends = NewAppendViews(appends)
+	}
+	return res, nil
+}
+
+// GetReviewDetail 一次返回评论及其商家回复、申诉记录和审核记录, 各部分并发查询
+// 评论和回复对所有可见该评论的人展示; 申诉记录只对该店铺的商家和审核员/管理员展示; 审核记录只对审核员/管理员展示
+// 未审核通过的评论只有作者、该店铺的商家和审核员/管理员可见, 或按 pending_visibility 对其他人可见
+func (uc *ReviewUsecase) GetReviewDetail(ctx context.Context, reviewID int64) (*ReviewDetail, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] GetReviewDetail, reviewID: %d", reviewID)
+	review, err := uc.repo.GetReviewByReviewID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	audience := AudienceFromContext(ctx)
+	user, _ := userFromContext(ctx)
+	owner := user != nil && (review.UserID == user.UserID || (audience == AudienceMerchant && review.StoreID == user.StoreID))
+	if !owner && !uc.visibility(ctx).Allows(review) {
+		return nil, ErrReviewNotFound
+	}
+	withAppeals := audience == AudienceReviewer || (audience == AudienceMerchant && review.StoreID == user.StoreID)
+
+	detail := &ReviewDetail{Review: NewReviewView(review, audience)}
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		reply, err := uc.repo.GetReplyByReviewID(gctx, reviewID)
+		detail.Reply = NewReplyView(reply)
+		return err
+	})
+	g.Go(func() error {
+		appends, err := uc.repo.ListAppends(gctx, reviewID)
+		if err != nil {
+			return v1.ErrorDbFailed("数据库查询追加评论失败, reviewID: %d", reviewID)
+		}
+		detail.Appends = NewAppendViews(appends)
+		return nil
+	})
+	if withAppeals {
+		g.Go(func() error {
+			appeals, err := uc.repo.ListAppealsByReviewID(gctx, reviewID)
+			detail.Appeals = NewAppealViews(appeals, audience)
+			return err
+		})
+	}
+	if audience == AudienceReviewer {
+		g.Go(func() error {
+			logs, err := uc.repo.ListAuditLogs(gctx, reviewID)
+			if err != nil {
+				return v1.ErrorDbFailed("数据库查询审核记录失败, reviewID: %d", reviewID)
+			}
+			detail.AuditTrail = NewAuditTrailView(logs, audience)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// GetTagStats 统计店铺已发布评论的话题标签分布
+// 商家只能统计自己的店铺; storeID为0时统计全部店铺, 仅审核员/管理员可用
+func (uc *ReviewUsecase) GetTagStats(ctx context.Context, storeID int64) (*TagStats, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] GetTagStats, storeID: %d", storeID)
+	user, err := requireRole(ctx, "merchant", "reviewer", "admin")
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == "merchant" && user.StoreID != storeID {
+		return nil, ErrPermissionDenied
+	}
+	return uc.repo.GetTagStats(ctx, storeID)
+}
+
+// SuggestReviewTerms 商家在评论搜索框输入时, 返回店铺已通过评论中以 prefix 开头的常见词
+// 商家只能查询自己的店铺, 审核员/管理员可以查询任意店铺
+func (uc *ReviewUsecase) SuggestReviewTerms(ctx context.Context, storeID int64, prefix string) ([]*TermSuggestion, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] SuggestReviewTerms, storeID: %d, prefix: %s", storeID, redact.Text(prefix))
+	user, err := requireRole(ctx, "merchant", "reviewer", "admin")
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == "merchant" && user.StoreID != storeID {
+		return nil, ErrPermissionDenied
+	}
+	if storeID <= 0 {
+		return nil, errors.New("店铺ID无效")
+	}
+	prefix, err = normalizeSuggestPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.SuggestReviewTerms(ctx, storeID, prefix)
+}
+
+// GetStoreRating 查询店铺已通过评论的平均评分, 没有评论时各项平均分为0
+func (uc *ReviewUsecase) GetStoreRating(ctx context.Context, storeID int64) (*StoreRating, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] GetStoreRating, storeID: %d", storeID)
+	if storeID <= 0 {
+		return nil, errors.New("店铺ID无效")
+	}
+	rating, err := uc.repo.GetStoreRating(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	rating.ScaleMin, rating.ScaleMax = scoreScale(uc.conf)
+	return rating, nil
+}
+
+// GetIndexStats 查询ES评论索引的文档数、大小、健康状态及与MySQL的差异, 仅管理员可用
+func (uc *ReviewUsecase) GetIndexStats(ctx context.Context) (*IndexStats, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] GetIndexStats")
+	if _, err := requireRole(ctx, "admin"); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetIndexStats(ctx)
+}
+
+// GetModerationStats 统计时间范围内被驳回评论的类别分布, 仅审核员/管理员可用
+// end为零值时取当前时间, start为零值时取end前7天; storeID为0时统计全部店铺
+func (uc *ReviewUsecase) GetModerationStats(ctx context.Context, storeID int64, start, end time.Time) (*ModerationStats, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] GetModerationStats, storeID: %d, start: %v, end: %v", storeID, start, end)
+	if _, err := requireRole(ctx, "reviewer", "admin"); err != nil {
+		return nil, err
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if start.IsZero() {
+		start = end.Add(-defaultStatsRange)
+	}
+	// 空区间直接返回空结果
+	if !start.Before(end) {
+		return &ModerationStats{Categories: []*CategoryCount{}}, nil
+	}
+	return uc.repo.GetModerationStats(ctx, storeID, start, end)
+}
+
+// GetReviewerStats 按操作人统计时间范围内的人工审核和申诉处理数, 仅管理员可用
+// end为零值时取当前时间, start为零值时取end前7天
+func (uc *ReviewUsecase) GetReviewerStats(ctx context.Context, start, end time.Time) ([]*ReviewerStats, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] GetReviewerStats, start: %v, end: %v", start, end)
+	if _, err := requireRole(ctx, "admin"); err != nil {
+		return nil, err
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if start.IsZero() {
+		start = end.Add(-defaultStatsRange)
+	}
+	if !start.Before(end) {
+		return nil, errors.New("统计开始时间必须早于结束时间")
+	}
+	return uc.repo.GetReviewerStats(ctx, start, end)
+}
+
+// ReindexReviews 按创建时间范围 [from, to) 重建ES中的评论文档, 用于修改索引映射后增量重建
+// 只允许管理员操作; 任务在后台执行, 结果见日志
+func (uc *ReviewUsecase) ReindexReviews(ctx context.Context, from, to time.Time) error {
+	uc.log.WithContext(ctx).Debugf("[biz] ReindexReviews, from: %v, to: %v", from, to)
+	if _, err := requireRole(ctx, "admin"); err != nil {
+		return err
+	}
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return ErrReindexRange
+	}
+	return uc.repo.ReindexReviews(ctx, from, to)
+}
+
+// UpdateReviewScore 作者在发布后的时间窗口内单独修改评分
+// 评论内容不变, 不需要重新审核, 状态保持不变
+func (uc *ReviewUsecase) UpdateReviewScore(ctx context.Context, reviewID int64, score, serviceScore, expressScore int32) (*model.ReviewInfo, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] UpdateReviewScore, reviewID: %d, score: %d, serviceScore: %d, expressScore: %d", reviewID, score, serviceScore, expressScore)
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 1. 数据校验
+	if err := validateScores(uc.conf, score, serviceScore, expressScore); err != nil {
+		return nil, err
+	}
+	review, err := uc.repo.GetReviewByReviewID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != user.UserID {
+		return nil, ErrPermissionDenied
+	}
+	if time.Since(review.CreateAt) > scoreEditWindow(uc.conf) {
+		return nil, errScoreEditExpired
+	}
+	// 2. 更新评分
+	return uc.repo.UpdateReviewScore(ctx, review, score, serviceScore, expressScore)
+}
+
+// GetReviewAuditTrail 按时间顺序返回评论的审核记录
+// 作者只能查看自己的评论, 商家只能查看自己店铺的评论, 审核员/管理员可以查看全部并看到内部备注
+func (uc *ReviewUsecase) GetReviewAuditTrail(ctx context.Context, reviewID int64) ([]*AuditTrailEntry, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] GetReviewAuditTrail, reviewID: %d", reviewID)
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	review, err := uc.repo.GetReviewByReviewID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	audience := AudienceFromContext(ctx)
+	switch audience {
+	case AudienceMerchant:
+		if review.StoreID != user.StoreID {
+			return nil, ErrPermissionDenied
+		}
+	case AudienceCustomer:
+		if review.UserID != user.UserID {
+			return nil, ErrPermissionDenied
+		}
+	}
+	logs, err := uc.repo.ListAuditLogs(ctx, reviewID)
+	if err != nil {
+		return nil, v1.ErrorDbFailed("数据库查询审核记录失败, reviewID: %d", reviewID)
+	}
+	return NewAuditTrailView(logs, audience), nil
+}
+
+// DeleteMyReview 作者删除自己的评论（软删除）
+// 只允许删除配置中允许的状态（默认待审核和审核驳回）, 已发布的评论不能删除
+func (uc *ReviewUsecase) DeleteMyReview(ctx context.Context, reviewID int64) error {
+	uc.log.WithContext(ctx).Debugf("[biz] DeleteMyReview, reviewID: %d", reviewID)
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	// 1. 数据校验
+	review, err := uc.repo.GetReviewByReviewID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.UserID != user.UserID {
+		return ErrPermissionDenied
+	}
+	statuses := deletableStatuses(uc.conf)
+	if !slices.Contains(statuses, review.Status) {
+		return errors.New("当前状态的评论不允许删除")
+	}
+	// 2. 删除评论, 带上状态条件防止校验后被异步审核改为已发布
+	return uc.repo.DeleteReview(ctx, reviewID, statuses)
+}
+
+// ResubmitReview 作者修改被驳回(30)的评论后重新提交审核
+// 评论ID不变, 已有的申诉和回复仍关联在这条评论上; 修改前的内容记录在审核日志中
+func (uc *ReviewUsecase) ResubmitReview(ctx context.Context, reviewID int64, newContent string) (*model.ReviewInfo, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] ResubmitReview, reviewID: %d", reviewID)
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 1. 数据校验
+	if err := validateContent(uc.conf, newContent); err != nil {
+		return nil, err
+	}
+	if err := validateLanguage(uc.conf.GetLanguagePolicy(), newContent); err != nil {
+		return nil, err
+	}
+	review, err := uc.repo.GetReviewByReviewID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != user.UserID {
+		return nil, ErrPermissionDenied
+	}
+	if review.Status != 30 {
+		return nil, errors.New("只有审核驳回的评论才能重新提交")
+	}
+	n, limit := ResubmitCount(review.ExtJSON), maxResubmits(uc.conf)
+	if n >= limit {
+		return nil, errResubmitLimit(limit)
+	}
+
+	// 2. 更新内容并重新审核
+	return uc.repo.ResubmitReview(ctx, &ResubmitReviewParam{
+		Review:  review,
+		Content: newContent,
+		Tags:    EncodeTags(matchTags(uc.conf.GetTags(), newContent)),
+		OpUser:  strconv.FormatInt(user.UserID, 10),
+	})
 }
 
 // AuditReview 审核评论
 func (uc *ReviewUsecase) AuditReview(ctx context.Context, param *AuditReviewParam) (*model.ReviewInfo, error) {
-	uc.log.WithContext(ctx).Debugf("[biz] AuditReview, param: %v", param)
+	uc.log.WithContext(ctx).Debugf("[biz] AuditReview, reviewID: %d", param.ReviewID)
 	// 1. 数据校验
 	review, err := uc.repo.GetReviewByReviewID(ctx, param.ReviewID)
 	if err != nil {
-		return nil, errors.New("无法获取评论信息")
+		return nil, err
 	}
 	if review.Status != 10 {
 		return nil, errors.New("评论状态无法审核")
@@ -115,30 +694,121 @@ func (uc *ReviewUsecase) AuditReview(ctx context.Context, param *AuditReviewPara
 	return uc.repo.AuditReview(ctx, param)
 }
 
+// BatchAuditReview 批量人工审核评论, 仅审核员/管理员可用, 操作人为当前登录用户
+// 每条评论独立走事务, 单条失败不影响其余评论, 逐条返回处理结果
+func (uc *ReviewUsecase) BatchAuditReview(ctx context.Context, reviewIDs []int64, decision, reason string) ([]*BatchAuditResult, error) {
+	uc.log.WithContext(ctx).Debugf("[biz] BatchAuditReview, reviewIDs: %v, decision: %s", reviewIDs, decision)
+	user, err := requireRole(ctx, "reviewer", "admin")
+	if err != nil {
+		return nil, err
+	}
+	opUser := strconv.FormatInt(user.UserID, 10)
+
+	// 1. 参数校验
+	if len(reviewIDs) == 0 {
+		return nil, errors.New("评论ID列表不能为空")
+	}
+	if len(reviewIDs) > maxBatchAuditSize {
+		return nil, fmt.Errorf("单次最多审核%d条评论", maxBatchAuditSize)
+	}
+	var status int32
+	switch decision {
+	case AuditDecisionApprove:
+		status = 20
+	case AuditDecisionReject:
+		status = 30
+	default:
+		return nil, errors.New("审核结果只能是approve或reject")
+	}
+
+	// 2. 逐条审核, 重复的ID只处理一次
+	results := make([]*BatchAuditResult, 0, len(reviewIDs))
+	seen := make(map[int64]bool, len(reviewIDs))
+	for _, id := range reviewIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		res := &BatchAuditResult{ReviewID: id}
+		review, err := uc.repo.ManualAuditReview(ctx, &AuditReviewParam{
+			ReviewID: id,
+			Status:   status,
+			OpUser:   opUser,
+			OpReason: reason,
+		})
+		if err != nil {
+			uc.log.WithContext(ctx).Warnf("[biz] BatchAuditReview, reviewID: %d failed: %v", id, err)
+			res.Err = err
+		} else {
+			res.Status = review.Status
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
 // AppealReview 申诉评论
 func (uc *ReviewUsecase) AppealReview(ctx context.Context, param *AppealReviewParam) (*model.ReviewAppealInfo, error) {
-	uc.log.WithContext(ctx).Debugf("[biz] AppealReview, param: %v", param)
+	uc.log.WithContext(ctx).Debugf("[biz] AppealReview, reviewID: %d, storeID: %d, reason: %s, content: %s", param.ReviewID, param.StoreID, redact.Text(param.Reason), redact.Text(param.Content))
 
 	// 1. 业务参数校验
+	if err := validateAppeal(uc.conf, param); err != nil {
+		return nil, err
+	}
 
 	// 2. 检查评论是否存在且状态可申诉
 	review, err := uc.repo.GetReviewByReviewID(ctx, param.ReviewID)
 	if err != nil {
-		return nil, errors.New("无法获取评论信息")
+		return nil, err
 	}
 
-	// 评论必须是已发布状态才能申诉（假设20为已发布状态）
-	if review.Status != 20 {
-		return nil, errors.New("只有已发布的评论才能申诉")
+	// 已发布的评论申诉隐藏; 开启 allow_rejected_appeal 时已驳回的评论可以申诉恢复
+	appealType, err := validateAppealType(uc.conf, param.Type, review.Status)
+	if err != nil {
+		return nil, err
+	}
+	param.Type = appealType
+
+	// 与上一次申诉完全相同的内容视为重复申诉
+	prev, err := uc.repo.GetAppealByReviewID(ctx, param.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	if prev != nil && sameAppeal(prev, param) {
+		return nil, errAppealDuplicate
 	}
 
 	// 3. 调用 data 层进行申诉
-	return uc.repo.AppealReview(ctx, param)
+	appeal, err := uc.repo.AppealReview(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 可选: AI给出申诉处理建议, 仅供审核员参考, 最终结论仍由审核员决定
+	if uc.conf.GetAppealAiAssist() {
+		uc.repo.RecommendAppeal(ctx, appeal, review)
+	}
+	return appeal, nil
+}
+
+// sameAppeal 申诉类型、理由、内容及图片视频是否与已有申诉完全一致
+func sameAppeal(prev *model.ReviewAppealInfo, param *AppealReviewParam) bool {
+	return prev.AppealType == param.Type &&
+		strings.TrimSpace(prev.Reason) == strings.TrimSpace(param.Reason) &&
+		strings.TrimSpace(prev.Content) == strings.TrimSpace(param.Content) &&
+		prev.PicInfo == param.PicInfo &&
+		prev.VideoInfo == param.VideoInfo
 }
 
 // AuditAppeal 审核申诉
 func (uc *ReviewUsecase) AuditAppeal(ctx context.Context, param *AuditAppealParam) (*model.ReviewAppealInfo, error) {
-	uc.log.WithContext(ctx).Debugf("[biz] AuditAppeal, param: %v", param)
+	uc.log.WithContext(ctx).Debugf("[biz] AuditAppeal, appealID: %d, status: %d", param.AppealID, param.Status)
+	// 操作人为当前登录的审核员/管理员, 不使用客户端传入的值
+	user, err := requireRole(ctx, "reviewer", "admin")
+	if err != nil {
+		return nil, err
+	}
+	param.OpUser = strconv.FormatInt(user.UserID, 10)
 
 	// 1. 业务参数校验
 	if param.Status != 20 && param.Status != 30 {
@@ -151,7 +821,7 @@ func (uc *ReviewUsecase) AuditAppeal(ctx context.Context, param *AuditAppealPara
 
 // ReplyReview 回复评论
 func (uc *ReviewUsecase) ReplyReview(ctx context.Context, param *ReplyReviewParam) (*model.ReviewReplyInfo, error) {
-	uc.log.WithContext(ctx).Debugf("[biz] ReplyReview, param: %v", param)
+	uc.log.WithContext(ctx).Debugf("[biz] ReplyReview, reviewID: %d, storeID: %d, content: %s", param.ReviewID, param.StoreID, redact.Text(param.Content))
 	reply := &model.ReviewReplyInfo{
 		ReplyID:   snowflake.GenID(),
 		ReviewID:  param.ReviewID,
@@ -163,61 +833,195 @@ func (uc *ReviewUsecase) ReplyReview(ctx context.Context, param *ReplyReviewPara
 	return uc.repo.SaveReply(ctx, reply)
 }
 
+// visibility 根据配置和当前用户确定列表中评论的可见性
+func (uc *ReviewUsecase) visibility(ctx context.Context) ReviewVisibility {
+	user, err := userFromContext(ctx)
+	if err == nil && (user.Role == "reviewer" || user.Role == "admin") {
+		return ReviewVisibility{All: true}
+	}
+	switch pendingVisibility(uc.conf) {
+	case pendingVisibilityPublic:
+		return ReviewVisibility{PendingPublic: true}
+	case pendingVisibilityAuthor:
+		if err == nil {
+			return ReviewVisibility{PendingAuthorID: user.UserID}
+		}
+	}
+	return ReviewVisibility{}
+}
+
 // ListReviewByStoreID 根据商家ID获取评论列表（分页）
-func (uc *ReviewUsecase) ListReviewByStoreID(ctx context.Context, storeID int64, page int32, size int32) ([]*MyReviewInfo, error) {
-	if page <= 0 {
-		page = 1
+// onlyUnreplied 为 true 时只返回商家尚未回复的评论, 便于商家优先处理
+// tag 非空时只返回带该话题标签的评论
+func (uc *ReviewUsecase) ListReviewByStoreID(ctx context.Context, storeID int64, page int32, size int32, onlyUnreplied bool, tag string) (*ReviewList, error) {
+	p := uc.pagination(page, size)
+	offset, limit := p.Offset, p.Limit
+
+	uc.log.WithContext(ctx).Debugf("[biz] ListReviewByStoreID, storeID: %d, offset: %d, limit: %d, onlyUnreplied: %v, tag: %s", storeID, offset, limit, onlyUnreplied, tag)
+	if err := validateTag(uc.conf.GetTags(), tag); err != nil {
+		return nil, err
 	}
-	if size <= 0 || size > 50 {
-		size = 10
+	reviews, err := uc.repo.ListReviewByStoreID(ctx, storeID, offset, limit, onlyUnreplied, tag, uc.visibility(ctx))
+	if err != nil {
+		return nil, err
+	}
+	// 未回复数只是辅助信息, 统计失败不影响列表返回
+	count, err := uc.repo.CountUnrepliedByStoreID(ctx, storeID)
+	if err != nil {
+		uc.log.WithContext(ctx).Warnf("[biz] CountUnrepliedByStoreID failed, storeID: %d, err: %v", storeID, err)
 	}
-	offset := (page - 1) * size
-	limit := size
+	reviews.UnrepliedCount = count
+	reviews.Page = p.Meta(reviews.Total)
+	reviews.Applied = appliedPage(p)
+	reviews.Applied.StoreID = storeID
+	reviews.Applied.Tag = tag
+	reviews.Applied.OnlyUnreplied = onlyUnreplied
+	return reviews, nil
+}
 
-	uc.log.WithContext(ctx).Debugf("[biz] ListReviewByStoreID, storeID: %d, offset: %d, limit: %d", storeID, offset, limit)
-	return uc.repo.ListReviewByStoreID(ctx, storeID, offset, limit)
+// ListRecentReviews 全平台最新的已通过评论（分页）, 按创建时间倒序, 用于首页展示
+// 匿名评论不返回作者(审核员除外); 每条评论带有店铺名称, 查询店铺名称失败时不影响列表返回
+func (uc *ReviewUsecase) ListRecentReviews(ctx context.Context, page int32, size int32) (*ReviewList, error) {
+	p := uc.pagination(page, size)
+	uc.log.WithContext(ctx).Debugf("[biz] ListRecentReviews, offset: %d, limit: %d", p.Offset, p.Limit)
+	reviews, err := uc.repo.ListRecentReviews(ctx, p.Offset, p.Limit)
+	if err != nil {
+		return nil, err
+	}
+	storeIDs := make([]int64, 0, len(reviews.List))
+	for _, r := range reviews.List {
+		if !slices.Contains(storeIDs, r.StoreID) {
+			storeIDs = append(storeIDs, r.StoreID)
+		}
+	}
+	names, err := uc.repo.GetStoreNames(ctx, storeIDs)
+	if err != nil {
+		uc.log.WithContext(ctx).Warnf("[biz] GetStoreNames failed, storeIDs: %v, err: %v", storeIDs, err)
+	}
+	audience := AudienceFromContext(ctx)
+	for _, r := range reviews.List {
+		r.StoreName = names[r.StoreID]
+		if r.Anonymous == 1 && audience != AudienceReviewer {
+			r.UserID = 0
+			if r.ReviewInfo != nil {
+				r.ReviewInfo.UserID = 0
+			}
+		}
+	}
+	reviews.Page = p.Meta(reviews.Total)
+	reviews.Applied = appliedPage(p)
+	reviews.Applied.Status = 20
+	return reviews, nil
 }
 
-// ListReviewByUserID 根据用户ID获取评论列表（分页）
-func (uc *ReviewUsecase) ListReviewByUserID(ctx context.Context, userID int64, page int32, size int32) ([]*MyReviewInfo, error) {
-	if page <= 0 {
-		page = 1
+// ListReviewsByStoreIDs 查询多个店铺的评论列表（分页）, 用于拥有多家店铺的商家查看汇总列表
+// 商家只能查询自己名下的店铺, 审核员/管理员可以查询任意店铺; 每条评论带有所属的店铺ID
+func (uc *ReviewUsecase) ListReviewsByStoreIDs(ctx context.Context, storeIDs []int64, page int32, size int32) (*ReviewList, error) {
+	p := uc.pagination(page, size)
+	offset, limit := p.Offset, p.Limit
+
+	uc.log.WithContext(ctx).Debugf("[biz] ListReviewsByStoreIDs, storeIDs: %v, offset: %d, limit: %d", storeIDs, offset, limit)
+	user, err := requireRole(ctx, "merchant", "reviewer", "admin")
+	if err != nil {
+		return nil, err
 	}
-	if size <= 0 || size > 50 {
-		size = 10
+	storeIDs, err = normalizeStoreIDs(storeIDs)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == "merchant" {
+		owned, err := uc.repo.ListStoreIDsByUserID(ctx, user.UserID)
+		if err != nil {
+			return nil, errors.New("无法获取商家的店铺信息")
+		}
+		for _, id := range storeIDs {
+			if !slices.Contains(owned, id) {
+				return nil, ErrPermissionDenied
+			}
+		}
 	}
-	offset := (page - 1) * size
-	limit := size
+	reviews, err := uc.repo.ListReviewsByStoreIDs(ctx, storeIDs, offset, limit, uc.visibility(ctx))
+	if err != nil {
+		return nil, err
+	}
+	reviews.Page = p.Meta(reviews.Total)
+	reviews.Applied = appliedPage(p)
+	return reviews, nil
+}
+
+// ListReviewByUserID 根据用户ID获取评论列表（分页）
+func (uc *ReviewUsecase) ListReviewByUserID(ctx context.Context, userID int64, page int32, size int32) (*ReviewList, error) {
+	p := uc.pagination(page, size)
+	offset, limit := p.Offset, p.Limit
 	uc.log.WithContext(ctx).Debugf("[biz] ListReviewByUserID, userID: %d, offset: %d, limit: %d", userID, offset, limit)
-	return uc.repo.ListReviewByUserID(ctx, userID, offset, limit)
+	reviews, err := uc.repo.ListReviewByUserID(ctx, userID, offset, limit, uc.visibility(ctx))
+	if err != nil {
+		return nil, err
+	}
+	reviews.Page = p.Meta(reviews.Total)
+	reviews.Applied = appliedPage(p)
+	reviews.Applied.UserID = userID
+	return reviews, nil
 }
 
 // ListReviewsByStatus lists reviews by their status with pagination.
-func (uc *ReviewUsecase) ListReviewsByStatus(ctx context.Context, status int32, page int32, size int32) ([]*MyReviewInfo, error) {
-	if page <= 0 {
-		page = 1
+// A non-zero appealStatus keeps only reviews whose latest appeal has that status, e.g. 10 for pending appeals.
+func (uc *ReviewUsecase) ListReviewsByStatus(ctx context.Context, status int32, appealStatus int32, page int32, size int32) (*ReviewList, error) {
+	if err := validateAppealStatusFilter(appealStatus); err != nil {
+		return nil, err
 	}
-	if size <= 0 || size > 50 {
-		size = 10
-	}
-	offset := (page - 1) * size
-	limit := size
+	p := uc.pagination(page, size)
+	offset, limit := p.Offset, p.Limit
 
-	uc.log.WithContext(ctx).Debugf("[biz] ListReviewsByStatus, status: %d, offset: %d, limit: %d", status, offset, limit)
-	return uc.repo.ListReviewsByStatus(ctx, status, offset, limit)
+	uc.log.WithContext(ctx).Debugf("[biz] ListReviewsByStatus, status: %d, appealStatus: %d, offset: %d, limit: %d", status, appealStatus, offset, limit)
+	reviews, err := uc.repo.ListReviewsByStatus(ctx, status, appealStatus, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	reviews.Page = p.Meta(reviews.Total)
+	reviews.Applied = appliedPage(p)
+	reviews.Applied.Status = status
+	reviews.Applied.AppealStatus = appealStatus
+	return reviews, nil
 }
 
-// ListAppealsByStatus lists appeals by their status with pagination.
-func (uc *ReviewUsecase) ListAppealsByStatus(ctx context.Context, status int32, page int32, size int32) ([]*model.ReviewAppealInfo, error) {
-	if page <= 0 {
-		page = 1
+// ListAppealsByStatus lists appeals by their status with pagination,
+// each enriched with the related review via a single batched lookup.
+// It also returns the total number of appeals with that status, for paging.
+func (uc *ReviewUsecase) ListAppealsByStatus(ctx context.Context, status int32, page int32, size int32) ([]*AppealWithReview, int64, error) {
+	p := uc.pagination(page, size)
+	offset, limit := p.Offset, p.Limit
+
+	uc.log.WithContext(ctx).Debugf("[biz] ListAppealsByStatus, status: %d, offset: %d, limit: %d", status, offset, limit)
+	appeals, total, err := uc.repo.ListAppealsByStatus(ctx, status, offset, limit)
+	if err != nil {
+		return nil, 0, err
 	}
-	if size <= 0 || size > 50 {
-		size = 10
+	if len(appeals) == 0 {
+		return []*AppealWithReview{}, total, nil
 	}
-	offset := (page - 1) * size
-	limit := size
 
-	uc.log.WithContext(ctx).Debugf("[biz] ListAppealsByStatus, status: %d, offset: %d, limit: %d", status, offset, limit)
-	return uc.repo.ListAppealsByStatus(ctx, status, offset, limit)
+	// 批量查询关联评论, 避免逐条查询带来的N+1问题
+	reviewIDs := make([]int64, 0, len(appeals))
+	for _, a := range appeals {
+		reviewIDs = append(reviewIDs, a.ReviewID)
+	}
+	reviews, err := uc.repo.GetReviewsByReviewIDs(ctx, reviewIDs)
+	if err != nil {
+		return nil, 0, v1.ErrorDbFailed("数据库查询申诉关联评论失败")
+	}
+	reviewMap := make(map[int64]*model.ReviewInfo, len(reviews))
+	for _, r := range reviews {
+		reviewMap[r.ReviewID] = r
+	}
+
+	list := make([]*AppealWithReview, 0, len(appeals))
+	for _, a := range appeals {
+		list = append(list, &AppealWithReview{
+			ReviewAppealInfo: a,
+			Review:           reviewMap[a.ReviewID],
+			Recommendation:   AppealRecommendationFromExt(a.ExtJSON),
+		})
+	}
+	return list, total, nil
 }
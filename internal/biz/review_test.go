@@ -0,0 +1,905 @@
+package biz
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// fakeReviewRepo records the calls a test cares about; any other ReviewRepo method panics.
+type fakeReviewRepo struct {
+	ReviewRepo
+	reviews      map[int64]*model.ReviewInfo
+	deleted      []int64
+	statsRange   [2]time.Time
+	appeals      []*model.ReviewAppealInfo
+	appealsPage  Pagination
+	listPage     Pagination
+	resubmits    []*ResubmitReviewParam
+	auditLogs    []*model.ReviewAuditLog
+	audits       []*AuditReviewParam
+	appealAudits []*AuditAppealParam
+	scoreEdits   [][3]int32
+	reindexed    [][2]time.Time
+	ownedStores  []int64
+	storeIDs     []int64
+	reply        *model.ReviewReplyInfo
+	filed        []*AppealReviewParam
+	recent       []MyReviewInfo
+	storeNames   map[int64]string
+	suggested    []string
+	scans        int
+	appends      []*model.ReviewAppendInfo
+	appendReads  int       // ListAppends calls
+	lookups      int       // GetReviewByReviewID calls
+	batches      [][]int64 // GetReviewsByReviewIDs calls
+}
+
+func (r *fakeReviewRepo) GetReviewByReviewID(_ context.Context, reviewID int64) (*model.ReviewInfo, error) {
+	r.lookups++
+	review, ok := r.reviews[reviewID]
+	if !ok {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
+
+func (r *fakeReviewRepo) DeleteReview(_ context.Context, reviewID int64, _ []int32) error {
+	r.deleted = append(r.deleted, reviewID)
+	return nil
+}
+
+func (r *fakeReviewRepo) GetModerationStats(_ context.Context, _ int64, start, end time.Time) (*ModerationStats, error) {
+	r.statsRange = [2]time.Time{start, end}
+	return &ModerationStats{Total: 1}, nil
+}
+
+func (r *fakeReviewRepo) GetReviewsByReviewIDs(_ context.Context, reviewIDs []int64) ([]*model.ReviewInfo, error) {
+	r.batches = append(r.batches, reviewIDs)
+	var reviews []*model.ReviewInfo
+	for _, id := range reviewIDs {
+		if review, ok := r.reviews[id]; ok {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+func (r *fakeReviewRepo) ListAppealsByStatus(_ context.Context, _ int32, offset, limit int32) ([]*model.ReviewAppealInfo, int64, error) {
+	r.appealsPage = Pagination{Offset: offset, Limit: limit}
+	return r.appeals, int64(len(r.appeals)) + 100, nil
+}
+
+func (r *fakeReviewRepo) GetIndexStats(context.Context) (*IndexStats, error) {
+	return &IndexStats{Index: "review"}, nil
+}
+
+func (r *fakeReviewRepo) ResubmitReview(_ context.Context, param *ResubmitReviewParam) (*model.ReviewInfo, error) {
+	r.resubmits = append(r.resubmits, param)
+	return param.Review, nil
+}
+
+func (r *fakeReviewRepo) ListAuditLogs(context.Context, int64) ([]*model.ReviewAuditLog, error) {
+	return r.auditLogs, nil
+}
+
+func (r *fakeReviewRepo) ManualAuditReview(_ context.Context, param *AuditReviewParam) (*model.ReviewInfo, error) {
+	r.audits = append(r.audits, param)
+	return &model.ReviewInfo{ReviewID: param.ReviewID, Status: param.Status}, nil
+}
+
+func (r *fakeReviewRepo) AuditAppeal(_ context.Context, param *AuditAppealParam) (*model.ReviewAppealInfo, error) {
+	r.appealAudits = append(r.appealAudits, param)
+	return &model.ReviewAppealInfo{AppealID: param.AppealID, Status: param.Status}, nil
+}
+
+func (r *fakeReviewRepo) UpdateReviewScore(_ context.Context, review *model.ReviewInfo, score, serviceScore, expressScore int32) (*model.ReviewInfo, error) {
+	r.scoreEdits = append(r.scoreEdits, [3]int32{score, serviceScore, expressScore})
+	return review, nil
+}
+
+func (r *fakeReviewRepo) ListReviewByUserID(_ context.Context, _ int64, offset, limit int32, _ ReviewVisibility) (*ReviewList, error) {
+	r.listPage = Pagination{Offset: offset, Limit: limit}
+	return &ReviewList{Total: 200}, nil
+}
+
+func (r *fakeReviewRepo) ReindexReviews(_ context.Context, from, to time.Time) error {
+	r.reindexed = append(r.reindexed, [2]time.Time{from, to})
+	return nil
+}
+
+func (r *fakeReviewRepo) ListStoreIDsByUserID(context.Context, int64) ([]int64, error) {
+	return r.ownedStores, nil
+}
+
+func (r *fakeReviewRepo) ListReviewsByStoreIDs(_ context.Context, storeIDs []int64, _, _ int32, _ ReviewVisibility) (*ReviewList, error) {
+	r.storeIDs = storeIDs
+	return &ReviewList{}, nil
+}
+
+func (r *fakeReviewRepo) GetReplyByReviewID(context.Context, int64) (*model.ReviewReplyInfo, error) {
+	return r.reply, nil
+}
+
+func (r *fakeReviewRepo) ListAppends(context.Context, int64) ([]*model.ReviewAppendInfo, error) {
+	r.appendReads++
+	return r.appends, nil
+}
+
+func (r *fakeReviewRepo) ListAppealsByReviewID(context.Context, int64) ([]*model.ReviewAppealInfo, error) {
+	return r.appeals, nil
+}
+
+func (r *fakeReviewRepo) GetAppealByReviewID(context.Context, int64) (*model.ReviewAppealInfo, error) {
+	return nil, nil
+}
+
+func (r *fakeReviewRepo) AppealReview(_ context.Context, param *AppealReviewParam) (*model.ReviewAppealInfo, error) {
+	r.filed = append(r.filed, param)
+	return &model.ReviewAppealInfo{ReviewID: param.ReviewID, AppealType: param.Type}, nil
+}
+
+// ListRecentReviews returns fresh copies of r.recent, since the usecase masks them in place.
+func (r *fakeReviewRepo) ListRecentReviews(context.Context, int32, int32) (*ReviewList, error) {
+	list := &ReviewList{Total: int64(len(r.recent))}
+	for _, review := range r.recent {
+		review := review
+		list.List = append(list.List, &review)
+	}
+	return list, nil
+}
+
+func (r *fakeReviewRepo) GetStoreNames(context.Context, []int64) (map[int64]string, error) {
+	return r.storeNames, nil
+}
+
+func (r *fakeReviewRepo) SuggestReviewTerms(_ context.Context, _ int64, prefix string) ([]*TermSuggestion, error) {
+	r.suggested = append(r.suggested, prefix)
+	return []*TermSuggestion{{Term: prefix + "快", Count: 1}}, nil
+}
+
+// ScanAuditLogs pages through r.auditLogs, which tests keep in ID order.
+func (r *fakeReviewRepo) ScanAuditLogs(_ context.Context, from, to time.Time, afterID int64, limit int) ([]*model.ReviewAuditLog, error) {
+	r.scans++
+	var logs []*model.ReviewAuditLog
+	for _, l := range r.auditLogs {
+		if l.ID > afterID && !l.CreateAt.Before(from) && l.CreateAt.Before(to) && len(logs) < limit {
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
+
+func (r *fakeReviewRepo) ModerationPolicy(context.Context) *ModerationPolicy {
+	return &ModerationPolicy{OnAIError: "hold"}
+}
+
+func newTestReviewUsecase(repo ReviewRepo) *ReviewUsecase {
+	return NewReviewUsecase(repo, log.DefaultLogger, &conf.Review{})
+}
+
+func reviewerContext() context.Context {
+	return contextWithClaims(jwtv5.MapClaims{"user_id": float64(7), "role": "reviewer"})
+}
+
+func TestBatchAuditReview(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		reviewIDs  []int64
+		decision   string
+		wantErr    bool
+		wantAudits int
+	}{
+		{name: "customer is denied", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(7), "role": "customer"}), reviewIDs: []int64{1}, decision: AuditDecisionApprove, wantErr: true},
+		{name: "empty ids", ctx: reviewerContext(), decision: AuditDecisionApprove, wantErr: true},
+		{name: "too many ids", ctx: reviewerContext(), reviewIDs: make([]int64, maxBatchAuditSize+1), decision: AuditDecisionApprove, wantErr: true},
+		{name: "unknown decision", ctx: reviewerContext(), reviewIDs: []int64{1}, decision: "maybe", wantErr: true},
+		{name: "duplicates audited once", ctx: reviewerContext(), reviewIDs: []int64{1, 2, 1}, decision: AuditDecisionReject, wantAudits: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{}
+			results, err := newTestReviewUsecase(repo).BatchAuditReview(tt.ctx, tt.reviewIDs, tt.decision, "reason")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BatchAuditReview() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(repo.audits) != tt.wantAudits || len(results) != tt.wantAudits {
+				t.Fatalf("audited %d reviews with %d results, want %d", len(repo.audits), len(results), tt.wantAudits)
+			}
+			for _, a := range repo.audits {
+				if a.OpUser != "7" {
+					t.Errorf("OpUser = %q, want the caller's user ID", a.OpUser)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditAppealOperator(t *testing.T) {
+	repo := &fakeReviewRepo{}
+	uc := newTestReviewUsecase(repo)
+
+	// The operator always comes from the token, never from the request.
+	if _, err := uc.AuditAppeal(reviewerContext(), &AuditAppealParam{AppealID: 1, Status: 20, OpUser: "forged"}); err != nil {
+		t.Fatalf("AuditAppeal() error = %v", err)
+	}
+	if got := repo.appealAudits[0].OpUser; got != "7" {
+		t.Errorf("OpUser = %q, want the caller's user ID", got)
+	}
+
+	merchant := contextWithClaims(jwtv5.MapClaims{"user_id": float64(8), "role": "merchant", "store_id": float64(1)})
+	if _, err := uc.AuditAppeal(merchant, &AuditAppealParam{AppealID: 1, Status: 20}); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("AuditAppeal() as merchant error = %v, want %v", err, ErrPermissionDenied)
+	}
+}
+
+func TestDeleteMyReview(t *testing.T) {
+	author := contextWithClaims(jwtv5.MapClaims{"user_id": float64(5), "role": "customer"})
+	tests := []struct {
+		name    string
+		c       *conf.Review
+		ctx     context.Context
+		review  *model.ReviewInfo
+		wantErr bool
+	}{
+		{name: "pending", c: &conf.Review{}, ctx: author, review: &model.ReviewInfo{UserID: 5, Status: 10}},
+		{name: "rejected", c: &conf.Review{}, ctx: author, review: &model.ReviewInfo{UserID: 5, Status: 30}},
+		{name: "published", c: &conf.Review{}, ctx: author, review: &model.ReviewInfo{UserID: 5, Status: 20}, wantErr: true},
+		{
+			name:    "another user's review",
+			c:       &conf.Review{},
+			ctx:     contextWithClaims(jwtv5.MapClaims{"user_id": float64(6), "role": "customer"}),
+			review:  &model.ReviewInfo{UserID: 5, Status: 10},
+			wantErr: true,
+		},
+		{
+			name:   "configured statuses",
+			c:      &conf.Review{DeletableStatuses: []int32{20}},
+			ctx:    author,
+			review: &model.ReviewInfo{UserID: 5, Status: 20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{reviews: map[int64]*model.ReviewInfo{1: tt.review}}
+			err := NewReviewUsecase(repo, log.DefaultLogger, tt.c).DeleteMyReview(tt.ctx, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteMyReview() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if deleted := len(repo.deleted) == 1; deleted == tt.wantErr {
+				t.Errorf("deleted = %v, want %v", deleted, !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetModerationStatsRange(t *testing.T) {
+	end := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		start, end time.Time
+		wantStart  time.Time
+		wantQuery  bool
+	}{
+		{name: "explicit range", start: end.Add(-time.Hour), end: end, wantStart: end.Add(-time.Hour), wantQuery: true},
+		{name: "default start", end: end, wantStart: end.Add(-defaultStatsRange), wantQuery: true},
+		{name: "empty range", start: end, end: end},
+		{name: "reversed range", start: end.Add(time.Hour), end: end},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{}
+			stats, err := newTestReviewUsecase(repo).GetModerationStats(reviewerContext(), 0, tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("GetModerationStats() error = %v", err)
+			}
+			if queried := !repo.statsRange[1].IsZero(); queried != tt.wantQuery {
+				t.Fatalf("queried = %v, want %v", queried, tt.wantQuery)
+			}
+			if !tt.wantQuery {
+				if stats.Total != 0 || stats.Categories == nil {
+					t.Errorf("empty range stats = %+v, want zero total and empty categories", stats)
+				}
+				return
+			}
+			if !repo.statsRange[0].Equal(tt.wantStart) || !repo.statsRange[1].Equal(tt.end) {
+				t.Errorf("queried range = %v, want [%v, %v]", repo.statsRange, tt.wantStart, tt.end)
+			}
+		})
+	}
+}
+
+func TestReviewVisibilityAllows(t *testing.T) {
+	pending := &model.ReviewInfo{UserID: 5, Status: 10}
+	approved := &model.ReviewInfo{UserID: 5, Status: 20}
+	rejected := &model.ReviewInfo{UserID: 5, Status: 30}
+	tests := []struct {
+		name   string
+		v      ReviewVisibility
+		review *model.ReviewInfo
+		want   bool
+	}{
+		{name: "approved is always visible", v: ReviewVisibility{}, review: approved, want: true},
+		{name: "pending is hidden by default", v: ReviewVisibility{}, review: pending},
+		{name: "pending public", v: ReviewVisibility{PendingPublic: true}, review: pending, want: true},
+		{name: "pending to its author", v: ReviewVisibility{PendingAuthorID: 5}, review: pending, want: true},
+		{name: "pending to another user", v: ReviewVisibility{PendingAuthorID: 6}, review: pending},
+		{name: "rejected stays hidden from its author", v: ReviewVisibility{PendingAuthorID: 5, PendingPublic: true}, review: rejected},
+		{name: "all", v: ReviewVisibility{All: true}, review: rejected, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.Allows(tt.review); got != tt.want {
+				t.Errorf("Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibility(t *testing.T) {
+	customer := contextWithClaims(jwtv5.MapClaims{"user_id": float64(5), "role": "customer"})
+	tests := []struct {
+		name    string
+		pending string
+		ctx     context.Context
+		want    ReviewVisibility
+	}{
+		{name: "reviewer sees all", pending: pendingVisibilityHidden, ctx: reviewerContext(), want: ReviewVisibility{All: true}},
+		{name: "hidden", pending: pendingVisibilityHidden, ctx: customer},
+		{name: "invalid falls back to hidden", pending: "everyone", ctx: customer},
+		{name: "public", pending: pendingVisibilityPublic, ctx: context.Background(), want: ReviewVisibility{PendingPublic: true}},
+		{name: "author", pending: pendingVisibilityAuthor, ctx: customer, want: ReviewVisibility{PendingAuthorID: 5}},
+		{name: "author when anonymous", pending: pendingVisibilityAuthor, ctx: context.Background()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewReviewUsecase(&fakeReviewRepo{}, log.DefaultLogger, &conf.Review{PendingVisibility: tt.pending})
+			if got := uc.visibility(tt.ctx); got != tt.want {
+				t.Errorf("visibility() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListAppealsByStatus(t *testing.T) {
+	published := &model.ReviewInfo{ReviewID: 1, Content: "好评", Score: 5, Status: 20}
+	hidden := &model.ReviewInfo{ReviewID: 3, Content: "差评", Score: 1, Status: 40}
+	tests := []struct {
+		name        string
+		appeals     []*model.ReviewAppealInfo
+		wantReviews []*model.ReviewInfo
+		wantBatches [][]int64
+	}{
+		{name: "no appeals", appeals: nil, wantBatches: nil},
+		{
+			name: "appeals carry their reviews",
+			appeals: []*model.ReviewAppealInfo{
+				{AppealID: 10, ReviewID: 1, Reason: "恶意评价", Status: 10},
+				{AppealID: 12, ReviewID: 3, Reason: "与事实不符", Status: 10},
+			},
+			wantReviews: []*model.ReviewInfo{published, hidden},
+			wantBatches: [][]int64{{1, 3}},
+		},
+		{
+			name: "missing review",
+			appeals: []*model.ReviewAppealInfo{
+				{AppealID: 10, ReviewID: 1, Reason: "恶意评价", Status: 10},
+				{AppealID: 11, ReviewID: 2, Reason: "评论已删除", Status: 10},
+			},
+			wantReviews: []*model.ReviewInfo{published, nil},
+			wantBatches: [][]int64{{1, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{
+				reviews: map[int64]*model.ReviewInfo{1: published, 3: hidden},
+				appeals: tt.appeals,
+			}
+			list, total, err := newTestReviewUsecase(repo).ListAppealsByStatus(reviewerContext(), 10, 2, 5)
+			if err != nil {
+				t.Fatalf("ListAppealsByStatus() error = %v", err)
+			}
+			if want := int64(len(tt.appeals)) + 100; total != want {
+				t.Errorf("total = %d, want the repo's total %d", total, want)
+			}
+			if want := (Pagination{Offset: 5, Limit: 5}); repo.appealsPage != want {
+				t.Errorf("queried page = %+v, want %+v", repo.appealsPage, want)
+			}
+			if len(list) != len(tt.appeals) {
+				t.Fatalf("%d items, want %d", len(list), len(tt.appeals))
+			}
+			for i, item := range list {
+				if item.ReviewAppealInfo != tt.appeals[i] {
+					t.Errorf("item %d appeal = %+v, want %+v", i, item.ReviewAppealInfo, tt.appeals[i])
+				}
+				if item.Review != tt.wantReviews[i] {
+					t.Errorf("item %d review = %+v, want %+v", i, item.Review, tt.wantReviews[i])
+				}
+			}
+			// The reviews are fetched in one batch, never row by row.
+			if !reflect.DeepEqual(repo.batches, tt.wantBatches) || repo.lookups != 0 {
+				t.Errorf("review lookups = %v batched, %d single, want %v batched, none single", repo.batches, repo.lookups, tt.wantBatches)
+			}
+		})
+	}
+}
+
+func TestGetIndexStatsAdminOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr bool
+	}{
+		{name: "admin", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(1), "role": "admin"})},
+		{name: "reviewer", ctx: reviewerContext(), wantErr: true},
+		{name: "anonymous", ctx: context.Background(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newTestReviewUsecase(&fakeReviewRepo{}).GetIndexStats(tt.ctx); (err != nil) != tt.wantErr {
+				t.Errorf("GetIndexStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResubmitReview(t *testing.T) {
+	author := contextWithClaims(jwtv5.MapClaims{"user_id": float64(5), "role": "customer"})
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		review  *model.ReviewInfo
+		wantErr bool
+	}{
+		{name: "rejected review", ctx: author, review: &model.ReviewInfo{UserID: 5, Status: 30}},
+		{name: "under the limit", ctx: author, review: &model.ReviewInfo{UserID: 5, Status: 30, ExtJSON: WithResubmitCount("", defaultMaxResubmits-1)}},
+		{name: "limit reached", ctx: author, review: &model.ReviewInfo{UserID: 5, Status: 30, ExtJSON: WithResubmitCount("", defaultMaxResubmits)}, wantErr: true},
+		{name: "not rejected", ctx: author, review: &model.ReviewInfo{UserID: 5, Status: 20}, wantErr: true},
+		{name: "another user's review", ctx: reviewerContext(), review: &model.ReviewInfo{UserID: 5, Status: 30}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{reviews: map[int64]*model.ReviewInfo{1: tt.review}}
+			_, err := newTestReviewUsecase(repo).ResubmitReview(tt.ctx, 1, "修改后的内容")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResubmitReview() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if resubmitted := len(repo.resubmits) == 1; resubmitted == tt.wantErr {
+				t.Errorf("resubmitted = %v, want %v", resubmitted, !tt.wantErr)
+			}
+			if !tt.wantErr && repo.resubmits[0].OpUser != "5" {
+				t.Errorf("OpUser = %q, want the author", repo.resubmits[0].OpUser)
+			}
+		})
+	}
+}
+
+func TestGetReviewAuditTrail(t *testing.T) {
+	review := &model.ReviewInfo{ReviewID: 1, UserID: 5, StoreID: 3}
+	logs := []*model.ReviewAuditLog{{FromStatus: 10, ToStatus: 30, Source: "ai", Reason: "广告", Remarks: "internal", Confidence: 0.9}}
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantErr     bool
+		wantRemarks bool
+	}{
+		{name: "author", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(5), "role": "customer"})},
+		{name: "another customer", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(6), "role": "customer"}), wantErr: true},
+		{name: "store's merchant", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(8), "role": "merchant", "store_id": float64(3)})},
+		{name: "other merchant", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(8), "role": "merchant", "store_id": float64(4)}), wantErr: true},
+		{name: "reviewer", ctx: reviewerContext(), wantRemarks: true},
+		{name: "anonymous", ctx: context.Background(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{reviews: map[int64]*model.ReviewInfo{1: review}, auditLogs: logs}
+			trail, err := newTestReviewUsecase(repo).GetReviewAuditTrail(tt.ctx, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetReviewAuditTrail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(trail) != 1 || trail[0].Reason != "广告" {
+				t.Fatalf("trail = %+v, want the one entry", trail)
+			}
+			if hasRemarks := trail[0].Remarks != "" || trail[0].Confidence != 0; hasRemarks != tt.wantRemarks {
+				t.Errorf("entry = %+v, want remarks and confidence %v", trail[0], tt.wantRemarks)
+			}
+		})
+	}
+}
+
+func TestUpdateReviewScore(t *testing.T) {
+	now := time.Now()
+	repo := &fakeReviewRepo{reviews: map[int64]*model.ReviewInfo{
+		1: {ReviewID: 1, UserID: 7, CreateAt: now.Add(-time.Hour)},
+		2: {ReviewID: 2, UserID: 8, CreateAt: now.Add(-time.Hour)},
+		3: {ReviewID: 3, UserID: 7, CreateAt: now.Add(-defaultScoreEditWindow - time.Minute)},
+	}}
+	uc := newTestReviewUsecase(repo)
+	tests := []struct {
+		name       string
+		reviewID   int64
+		scores     [3]int32
+		wantReason string
+	}{
+		{name: "within the window", reviewID: 1, scores: [3]int32{5, 4, 3}},
+		{name: "not the author", reviewID: 2, scores: [3]int32{5, 5, 5}, wantReason: "FORBIDDEN"},
+		{name: "window expired", reviewID: 3, scores: [3]int32{5, 5, 5}, wantReason: "SCORE_EDIT_EXPIRED"},
+		{name: "score out of range", reviewID: 1, scores: [3]int32{6, 5, 5}, wantReason: "SCORE_INVALID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.scoreEdits = nil
+			_, err := uc.UpdateReviewScore(reviewerContext(), tt.reviewID, tt.scores[0], tt.scores[1], tt.scores[2])
+			if tt.wantReason != "" {
+				if errors.Reason(err) != tt.wantReason {
+					t.Fatalf("error = %v, want reason %s", err, tt.wantReason)
+				}
+				if len(repo.scoreEdits) != 0 {
+					t.Errorf("scores updated despite error: %v", repo.scoreEdits)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateReviewScore() error = %v", err)
+			}
+			if len(repo.scoreEdits) != 1 || repo.scoreEdits[0] != tt.scores {
+				t.Errorf("scores updated = %v, want [%v]", repo.scoreEdits, tt.scores)
+			}
+		})
+	}
+}
+
+func TestListReviewsApplied(t *testing.T) {
+	tests := []struct {
+		name       string
+		page, size int32
+		want       AppliedQuery
+	}{
+		{name: "as requested", page: 3, size: 20, want: AppliedQuery{UserID: 7, Page: 3, Size: 20, Offset: 40}},
+		{name: "defaults", want: AppliedQuery{UserID: 7, Page: 1, Size: defaultPageSize}},
+		{name: "size clamped", page: 2, size: 1000, want: AppliedQuery{UserID: 7, Page: 2, Size: maxPageSize, Offset: maxPageSize}},
+	}
+	repo := &fakeReviewRepo{}
+	uc := newTestReviewUsecase(repo)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := uc.ListReviewByUserID(context.Background(), 7, tt.page, tt.size)
+			if err != nil {
+				t.Fatalf("ListReviewByUserID() error = %v", err)
+			}
+			if list.Applied != tt.want {
+				t.Errorf("Applied = %+v, want %+v", list.Applied, tt.want)
+			}
+			// The echoed query is the one actually sent to the repo.
+			if got := (Pagination{Offset: list.Applied.Offset, Limit: list.Applied.Size}); got != repo.listPage {
+				t.Errorf("Applied pagination = %+v, repo queried %+v", got, repo.listPage)
+			}
+		})
+	}
+}
+
+func TestReindexReviews(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	admin := contextWithClaims(jwtv5.MapClaims{"user_id": float64(1), "role": "admin"})
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		from, to   time.Time
+		wantReason string
+	}{
+		{name: "admin", ctx: admin, from: from, to: to},
+		{name: "reviewer", ctx: reviewerContext(), from: from, to: to, wantReason: "FORBIDDEN"},
+		{name: "missing start", ctx: admin, to: to, wantReason: "REINDEX_RANGE_INVALID"},
+		{name: "missing end", ctx: admin, from: from, wantReason: "REINDEX_RANGE_INVALID"},
+		{name: "empty range", ctx: admin, from: to, to: to, wantReason: "REINDEX_RANGE_INVALID"},
+		{name: "reversed range", ctx: admin, from: to, to: from, wantReason: "REINDEX_RANGE_INVALID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{}
+			err := newTestReviewUsecase(repo).ReindexReviews(tt.ctx, tt.from, tt.to)
+			if tt.wantReason != "" {
+				if errors.Reason(err) != tt.wantReason {
+					t.Fatalf("error = %v, want reason %s", err, tt.wantReason)
+				}
+				if len(repo.reindexed) != 0 {
+					t.Errorf("reindex started despite error: %v", repo.reindexed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReindexReviews() error = %v", err)
+			}
+			if len(repo.reindexed) != 1 || repo.reindexed[0] != [2]time.Time{tt.from, tt.to} {
+				t.Errorf("reindexed = %v, want [%v %v]", repo.reindexed, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestListReviewsByStoreIDs(t *testing.T) {
+	merchant := contextWithClaims(jwtv5.MapClaims{"user_id": float64(3), "role": "merchant", "store_id": float64(11)})
+	customer := contextWithClaims(jwtv5.MapClaims{"user_id": float64(4), "role": "customer"})
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		storeIDs   []int64
+		want       []int64
+		wantReason string
+	}{
+		{name: "merchant's own stores", ctx: merchant, storeIDs: []int64{12, 11, 12}, want: []int64{11, 12}},
+		{name: "merchant with another store", ctx: merchant, storeIDs: []int64{11, 99}, wantReason: "FORBIDDEN"},
+		{name: "reviewer any store", ctx: reviewerContext(), storeIDs: []int64{99}, want: []int64{99}},
+		{name: "customer", ctx: customer, storeIDs: []int64{11}, wantReason: "FORBIDDEN"},
+		{name: "no stores", ctx: merchant, wantReason: "STORE_IDS_INVALID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{ownedStores: []int64{11, 12}}
+			_, err := newTestReviewUsecase(repo).ListReviewsByStoreIDs(tt.ctx, tt.storeIDs, 1, 10)
+			if tt.wantReason != "" {
+				if errors.Reason(err) != tt.wantReason {
+					t.Fatalf("error = %v, want reason %s", err, tt.wantReason)
+				}
+				if repo.storeIDs != nil {
+					t.Errorf("stores queried despite error: %v", repo.storeIDs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListReviewsByStoreIDs() error = %v", err)
+			}
+			if !reflect.DeepEqual(repo.storeIDs, tt.want) {
+				t.Errorf("stores queried = %v, want %v", repo.storeIDs, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetReviewDetail(t *testing.T) {
+	repo := &fakeReviewRepo{
+		reviews: map[int64]*model.ReviewInfo{
+			1: {ReviewID: 1, UserID: 4, StoreID: 11, Status: 20},
+			2: {ReviewID: 2, UserID: 4, StoreID: 11, Status: 30},
+		},
+		reply:     &model.ReviewReplyInfo{ReplyID: 5, ReviewID: 1, Content: "感谢支持"},
+		appeals:   []*model.ReviewAppealInfo{{AppealID: 6, ReviewID: 1, Status: 10}},
+		auditLogs: []*model.ReviewAuditLog{{ReviewID: 1, FromStatus: 10, ToStatus: 20}},
+	}
+	uc := newTestReviewUsecase(repo)
+	author := contextWithClaims(jwtv5.MapClaims{"user_id": float64(4), "role": "customer"})
+	stranger := contextWithClaims(jwtv5.MapClaims{"user_id": float64(8), "role": "customer"})
+	merchant := contextWithClaims(jwtv5.MapClaims{"user_id": float64(3), "role": "merchant", "store_id": float64(11)})
+	otherMerchant := contextWithClaims(jwtv5.MapClaims{"user_id": float64(9), "role": "merchant", "store_id": float64(12)})
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		reviewID     int64
+		wantNotFound bool
+		wantAppeals  bool
+		wantTrail    bool
+	}{
+		{name: "customer", ctx: stranger, reviewID: 1},
+		{name: "store's merchant", ctx: merchant, reviewID: 1, wantAppeals: true},
+		{name: "another merchant", ctx: otherMerchant, reviewID: 1},
+		{name: "reviewer", ctx: reviewerContext(), reviewID: 1, wantAppeals: true, wantTrail: true},
+		{name: "rejected review hidden from others", ctx: stranger, reviewID: 2, wantNotFound: true},
+		{name: "rejected review shown to its author", ctx: author, reviewID: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, err := uc.GetReviewDetail(tt.ctx, tt.reviewID)
+			if tt.wantNotFound {
+				if !errors.Is(err, ErrReviewNotFound) {
+					t.Fatalf("error = %v, want ErrReviewNotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetReviewDetail() error = %v", err)
+			}
+			if detail.Review == nil || detail.Review.ReviewID != tt.reviewID || detail.Reply == nil || detail.Reply.ReplyID != 5 {
+				t.Errorf("detail = %+v, want the review with its reply", detail)
+			}
+			if got := len(detail.Appeals) > 0; got != tt.wantAppeals {
+				t.Errorf("appeals included = %v, want %v", got, tt.wantAppeals)
+			}
+			if got := len(detail.AuditTrail) > 0; got != tt.wantTrail {
+				t.Errorf("audit trail included = %v, want %v", got, tt.wantTrail)
+			}
+		})
+	}
+}
+
+func TestGetReviewAppends(t *testing.T) {
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		extJSON   string
+		wantReads int
+		want      []string
+	}{
+		{name: "not appended", want: []string{}},
+		{name: "appended", extJSON: WithAppendCount("", 2), wantReads: 1, want: []string{"用了一周", "客服很及时"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{
+				reviews: map[int64]*model.ReviewInfo{1: {ReviewID: 1, Content: "好评", ExtJSON: tt.extJSON}},
+				appends: []*model.ReviewAppendInfo{{ReviewID: 1, Content: "用了一周", CreateAt: at}, {ReviewID: 1, Content: "客服很及时", CreateAt: at.Add(time.Hour)}},
+			}
+			review, err := newTestReviewUsecase(repo).GetReview(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("GetReview() error = %v", err)
+			}
+			got := []string{}
+			for _, a := range review.Appends {
+				got = append(got, a.Content)
+			}
+			if review.Content != "好评" || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetReview() content, appends = %q, %q, want %q, %q", review.Content, got, "好评", tt.want)
+			}
+			if repo.appendReads != tt.wantReads {
+				t.Errorf("ListAppends called %d times, want %d", repo.appendReads, tt.wantReads)
+			}
+		})
+	}
+}
+
+func TestAppealRejectedReview(t *testing.T) {
+	tests := []struct {
+		name       string
+		allow      bool
+		wantReason string
+	}{
+		{name: "disabled by default", wantReason: "APPEAL_TYPE_DISABLED"},
+		{name: "allowed", allow: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{reviews: map[int64]*model.ReviewInfo{1: {ReviewID: 1, StoreID: 11, Status: 30}}}
+			uc := NewReviewUsecase(repo, log.DefaultLogger, &conf.Review{AllowRejectedAppeal: tt.allow})
+			appeal, err := uc.AppealReview(context.Background(), &AppealReviewParam{ReviewID: 1, StoreID: 11, Reason: "AI误判"})
+			if tt.wantReason != "" {
+				if errors.Reason(err) != tt.wantReason {
+					t.Fatalf("error = %v, want reason %s", err, tt.wantReason)
+				}
+				if len(repo.filed) != 0 {
+					t.Errorf("appeal filed despite error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AppealReview() error = %v", err)
+			}
+			// Without an explicit type, an appeal on a rejected review asks to reinstate it.
+			if appeal.AppealType != AppealTypeReinstate {
+				t.Errorf("appeal type = %q, want %q", appeal.AppealType, AppealTypeReinstate)
+			}
+		})
+	}
+}
+
+func TestListRecentReviews(t *testing.T) {
+	repo := &fakeReviewRepo{
+		recent: []MyReviewInfo{
+			{ReviewID: 2, UserID: 4, StoreID: 11, Anonymous: 1},
+			{ReviewID: 1, UserID: 5, StoreID: 12},
+		},
+		storeNames: map[int64]string{11: "一号店"},
+	}
+	uc := newTestReviewUsecase(repo)
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantUserIDs []int64
+	}{
+		{name: "anonymous caller", ctx: context.Background(), wantUserIDs: []int64{0, 5}},
+		{name: "customer", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(8), "role": "customer"}), wantUserIDs: []int64{0, 5}},
+		{name: "reviewer", ctx: reviewerContext(), wantUserIDs: []int64{4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := uc.ListRecentReviews(tt.ctx, 1, 10)
+			if err != nil {
+				t.Fatalf("ListRecentReviews() error = %v", err)
+			}
+			var userIDs []int64
+			for _, r := range list.List {
+				userIDs = append(userIDs, r.UserID)
+			}
+			if !reflect.DeepEqual(userIDs, tt.wantUserIDs) {
+				t.Errorf("user IDs = %v, want %v", userIDs, tt.wantUserIDs)
+			}
+			// A store missing from the lookup leaves its name empty.
+			if list.List[0].StoreName != "一号店" || list.List[1].StoreName != "" {
+				t.Errorf("store names = %q, %q, want %q, empty", list.List[0].StoreName, list.List[1].StoreName, "一号店")
+			}
+			if list.Applied.Status != 20 {
+				t.Errorf("applied status = %d, want 20", list.Applied.Status)
+			}
+		})
+	}
+}
+
+func TestSuggestReviewTerms(t *testing.T) {
+	merchant := contextWithClaims(jwtv5.MapClaims{"user_id": float64(3), "role": "merchant", "store_id": float64(11)})
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		storeID       int64
+		wantErr       error
+		wantSuggested []string
+	}{
+		{name: "own store", ctx: merchant, storeID: 11, wantSuggested: []string{"物流"}},
+		{name: "another store", ctx: merchant, storeID: 12, wantErr: ErrPermissionDenied},
+		{name: "reviewer", ctx: reviewerContext(), storeID: 12, wantSuggested: []string{"物流"}},
+		{name: "customer", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(8), "role": "customer"}), storeID: 11, wantErr: ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepo{}
+			_, err := newTestReviewUsecase(repo).SuggestReviewTerms(tt.ctx, tt.storeID, " 物流 ")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("SuggestReviewTerms() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("SuggestReviewTerms() error = %v", err)
+			}
+			if !reflect.DeepEqual(repo.suggested, tt.wantSuggested) {
+				t.Errorf("repo queried prefixes %q, want %q", repo.suggested, tt.wantSuggested)
+			}
+		})
+	}
+}
+
+func TestGetModerationPolicy(t *testing.T) {
+	c := &conf.Review{
+		LanguagePolicy:      &conf.Review_LanguagePolicy{Enabled: true, Allowed: []string{"zh"}},
+		MediaSizeCheck:      &conf.Review_MediaSizeCheck{Enabled: true},
+		AllowRejectedAppeal: true,
+	}
+	uc := NewReviewUsecase(&fakeReviewRepo{}, log.DefaultLogger, c)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr bool
+	}{
+		{name: "reviewer", ctx: reviewerContext()},
+		{name: "admin", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(1), "role": "admin"})},
+		{name: "merchant", ctx: contextWithClaims(jwtv5.MapClaims{"user_id": float64(3), "role": "merchant", "store_id": float64(11)}), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := uc.GetModerationPolicy(tt.ctx)
+			if tt.wantErr {
+				if !errors.Is(err, ErrPermissionDenied) {
+					t.Fatalf("GetModerationPolicy() error = %v, want ErrPermissionDenied", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetModerationPolicy() error = %v", err)
+			}
+			want := &LanguagePolicy{Allowed: []string{"zh"}, Action: LanguageActionReject}
+			if p.OnAIError != "hold" || !reflect.DeepEqual(p.LanguagePolicy, want) || !p.MediaSizeCheck || !p.AllowRejectedAppeal || p.AppealAIAssist {
+				t.Errorf("GetModerationPolicy() = %+v, want the data policy with the review config applied", p)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package biz
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// ErrUnknownTag 过滤条件中的标签不在配置的词表中
+var ErrUnknownTag = errors.BadRequest("TAG_INVALID", "标签不在话题标签词表中")
+
+// ReviewTags 评论的话题标签
+// 数据库中以JSON数组字符串保存, ES中以数组索引; 兼容旧ES文档中的字符串格式
+type ReviewTags []string
+
+// UnmarshalJSON 同时接受数组和JSON数组字符串（含空字符串）
+func (t *ReviewTags) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*t = list
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*t = DecodeTags(s)
+	return nil
+}
+
+// DecodeTags 解析数据库中的标签JSON, 为空或解析失败时返回nil
+func DecodeTags(tagsJSON string) []string {
+	if tagsJSON == "" {
+		return nil
+	}
+	var tags []string
+	if err := json.Unmarshal([]byte(tagsJSON), &tags); err != nil {
+		return nil
+	}
+	return tags
+}
+
+// EncodeTags 将标签编码为数据库中保存的JSON
+func EncodeTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(tags)
+	return string(b)
+}
+
+// MergeTags 合并两个标签JSON并去重, 用于追加评论
+func MergeTags(a, b string) string {
+	tags := DecodeTags(a)
+	for _, t := range DecodeTags(b) {
+		if !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	return EncodeTags(tags)
+}
+
+// matchTags 按词表为评论内容打标签: 内容包含某个标签的任一关键词即打上该标签, 按词表顺序返回
+// 词表为空时不打标签
+func matchTags(vocab []*conf.Review_Tag, content string) []string {
+	var tags []string
+	content = strings.ToLower(content)
+	for _, t := range vocab {
+		for _, kw := range t.GetKeywords() {
+			if kw != "" && strings.Contains(content, strings.ToLower(kw)) {
+				tags = append(tags, t.GetName())
+				break
+			}
+		}
+	}
+	return tags
+}
+
+// validateTag 校验标签过滤条件, 空字符串表示不过滤
+func validateTag(vocab []*conf.Review_Tag, tag string) error {
+	if tag == "" {
+		return nil
+	}
+	for _, t := range vocab {
+		if t.GetName() == tag {
+			return nil
+		}
+	}
+	return ErrUnknownTag
+}
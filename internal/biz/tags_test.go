@@ -0,0 +1,75 @@
+package biz
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"review/internal/conf"
+)
+
+func TestMatchTags(t *testing.T) {
+	vocab := []*conf.Review_Tag{
+		{Name: "物流", Keywords: []string{"快递", "物流", "Delivery"}},
+		{Name: "口味", Keywords: []string{"好吃", "味道"}},
+		{Name: "空", Keywords: []string{""}},
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "no match", content: "一般", want: nil},
+		{name: "one keyword per tag", content: "快递很快，物流也好", want: []string{"物流"}},
+		{name: "vocabulary order", content: "味道不错，快递慢", want: []string{"物流", "口味"}},
+		{name: "case insensitive", content: "fast DELIVERY", want: []string{"物流"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchTags(vocab, tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matchTags(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewTagsUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want ReviewTags
+	}{
+		{name: "array", data: `["物流","口味"]`, want: ReviewTags{"物流", "口味"}},
+		{name: "legacy JSON string", data: `"[\"物流\"]"`, want: ReviewTags{"物流"}},
+		{name: "legacy empty string", data: `""`, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ReviewTags
+			if err := json.Unmarshal([]byte(tt.data), &got); err != nil {
+				t.Fatalf("Unmarshal(%s) error = %v", tt.data, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	if got, want := MergeTags(`["物流"]`, `["口味","物流"]`), `["物流","口味"]`; got != want {
+		t.Errorf("MergeTags() = %s, want %s", got, want)
+	}
+	if got := MergeTags("", ""); got != "" {
+		t.Errorf(`MergeTags("", "") = %q, want ""`, got)
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	vocab := []*conf.Review_Tag{{Name: "物流"}}
+	for tag, wantErr := range map[string]bool{"": false, "物流": false, "口味": true} {
+		if err := validateTag(vocab, tag); (err != nil) != wantErr {
+			t.Errorf("validateTag(%q) error = %v, wantErr %v", tag, err, wantErr)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// AgentTool describes a tool the agent can offer to the LLM.
+type AgentTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  ToolParameters `json:"parameters"`
+}
+
+// ToolParameters is the JSON schema of a tool's arguments.
+type ToolParameters struct {
+	Type       string                  `json:"type"`
+	Properties map[string]ToolProperty `json:"properties"`
+	Required   []string                `json:"required,omitempty"`
+}
+
+// ToolProperty is a single tool argument. Only string arguments are supported.
+type ToolProperty struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// toolHandler executes a tool for user with arguments already validated against its schema.
+type toolHandler func(ctx context.Context, user *authedUser, args map[string]string) (any, error)
+
+// agentTools is the registry of every tool definition, in prompt order.
+var agentTools = []AgentTool{
+	{
+		Name:        "GetReview",
+		Description: "根据评论ID获取单条评论的详细信息。",
+		Parameters: ToolParameters{
+			Type:       "object",
+			Properties: map[string]ToolProperty{"reviewID": {Type: "string", Description: "评论的唯一ID"}},
+			Required:   []string{"reviewID"},
+		},
+	},
+	{
+		Name:        "ListReviewByStoreID",
+		Description: "根据店铺ID查询该店铺的评论列表。商家只能查询自己店铺的评论。",
+		Parameters: ToolParameters{
+			Type:       "object",
+			Properties: map[string]ToolProperty{"storeID": {Type: "string", Description: "店铺的唯一ID"}},
+			Required:   []string{"storeID"},
+		},
+	},
+	{
+		Name:        "ListMyReviews",
+		Description: "查询我（当前登录用户）自己发布过的所有评论列表，不需要提供任何参数。",
+		Parameters:  ToolParameters{Type: "object", Properties: map[string]ToolProperty{}},
+	},
+}
+
+// roleTools lists the tools offered to each role by default, overridable with ai.role_tools.
+// Roles not listed (e.g. "public") get no tools.
+var roleTools = map[string][]string{
+	"customer": {"GetReview", "ListReviewByStoreID", "ListMyReviews"},
+	"merchant": {"GetReview", "ListReviewByStoreID"},
+	"reviewer": {"GetReview", "ListReviewByStoreID"},
+}
+
+// toolRegistry resolves role tool lists to definitions and handlers, built once at startup.
+type toolRegistry struct {
+	tools    map[string]AgentTool
+	handlers map[string]toolHandler
+	byRole   map[string][]AgentTool
+	// prompts caches the JSON tool list injected into the system prompt for each role.
+	prompts map[string]string
+}
+
+// newToolRegistry builds the registry, failing if the definitions, role lists and handlers disagree.
+// roles overrides the default roleTools mapping when not empty.
+func newToolRegistry(handlers map[string]toolHandler, roles map[string][]string) (*toolRegistry, error) {
+	// The built-in mapping is always checked, so the code stays consistent whatever is configured.
+	if err := validateToolRegistry(agentTools, roleTools, handlers); err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		roles = roleTools
+	} else if err := validateRoleTools(agentTools, roles); err != nil {
+		return nil, err
+	}
+	reg := &toolRegistry{
+		tools:    make(map[string]AgentTool, len(agentTools)),
+		handlers: handlers,
+		byRole:   make(map[string][]AgentTool, len(roles)),
+		prompts:  make(map[string]string, len(roles)),
+	}
+	for _, t := range agentTools {
+		reg.tools[t.Name] = t
+	}
+	for role, names := range roles {
+		tools := make([]AgentTool, 0, len(names))
+		for _, name := range names {
+			tools = append(tools, reg.tools[name])
+		}
+		b, err := json.Marshal(tools)
+		if err != nil {
+			return nil, fmt.Errorf("marshal tools for role %q: %w", role, err)
+		}
+		reg.byRole[role] = tools
+		reg.prompts[role] = string(b)
+	}
+	return reg, nil
+}
+
+// validateToolRegistry checks that every tool offered to a role is defined and has a handler,
+// and conversely that every definition and handler is offered to at least one role, so a
+// renamed or forgotten tool fails at startup rather than as "tool not found" at runtime.
+func validateToolRegistry(tools []AgentTool, roles map[string][]string, handlers map[string]toolHandler) error {
+	defined := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		if defined[t.Name] {
+			return fmt.Errorf("tool %q is defined more than once", t.Name)
+		}
+		defined[t.Name] = true
+		if _, ok := handlers[t.Name]; !ok {
+			return fmt.Errorf("tool %q has no handler", t.Name)
+		}
+	}
+	offered := make(map[string]bool, len(tools))
+	for role, names := range roles {
+		for _, name := range names {
+			if !defined[name] {
+				return fmt.Errorf("tool %q offered to role %q has no definition", name, role)
+			}
+			offered[name] = true
+		}
+	}
+	for name := range handlers {
+		if !defined[name] {
+			return fmt.Errorf("handler %q has no tool definition", name)
+		}
+		if !offered[name] {
+			return fmt.Errorf("tool %q is not offered to any role", name)
+		}
+	}
+	return nil
+}
+
+// validateRoleTools checks a configured role mapping: roles must exist and tools must be defined.
+// Unlike the built-in mapping, a configured one may leave tools unused.
+func validateRoleTools(tools []AgentTool, roles map[string][]string) error {
+	defined := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		defined[t.Name] = true
+	}
+	for role, names := range roles {
+		if role != "public" && !slices.Contains(knownRoles, role) {
+			return fmt.Errorf("ai.role_tools: unknown role %q", role)
+		}
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			if !defined[name] {
+				return fmt.Errorf("ai.role_tools: tool %q offered to role %q is not implemented", name, role)
+			}
+			if seen[name] {
+				return fmt.Errorf("ai.role_tools: tool %q is listed twice for role %q", name, role)
+			}
+			seen[name] = true
+		}
+	}
+	return nil
+}
+
+// configuredRoleTools flattens ai.role_tools, returning nil when it is not configured.
+func configuredRoleTools(c *conf.AI) map[string][]string {
+	if len(c.GetRoleTools()) == 0 {
+		return nil
+	}
+	roles := make(map[string][]string, len(c.GetRoleTools()))
+	for role, list := range c.GetRoleTools() {
+		roles[role] = list.GetTools()
+	}
+	return roles
+}
+
+// defaultToolTimeout bounds a tool call and its summarization when ai.tool_timeout is not set.
+const defaultToolTimeout = 20 * time.Second
+
+// toolTimeouts holds the per-tool time budgets.
+type toolTimeouts struct {
+	fallback time.Duration
+	byTool   map[string]time.Duration
+}
+
+// newToolTimeouts reads ai.tool_timeout and ai.tool_timeouts, rejecting unknown tools and non-positive budgets.
+func newToolTimeouts(c *conf.AI) (toolTimeouts, error) {
+	t := toolTimeouts{
+		fallback: c.GetToolTimeout().AsDuration(),
+		byTool:   make(map[string]time.Duration, len(c.GetToolTimeouts())),
+	}
+	if t.fallback <= 0 {
+		t.fallback = defaultToolTimeout
+	}
+	for name, d := range c.GetToolTimeouts() {
+		if !slices.ContainsFunc(agentTools, func(tool AgentTool) bool { return tool.Name == name }) {
+			return toolTimeouts{}, fmt.Errorf("ai.tool_timeouts: tool %q is not implemented", name)
+		}
+		if d.AsDuration() <= 0 {
+			return toolTimeouts{}, fmt.Errorf("ai.tool_timeouts: timeout for tool %q must be positive", name)
+		}
+		t.byTool[name] = d.AsDuration()
+	}
+	return t, nil
+}
+
+// forTool returns the budget for the named tool.
+func (t toolTimeouts) forTool(name string) time.Duration {
+	if d, ok := t.byTool[name]; ok {
+		return d
+	}
+	return t.fallback
+}
+
+// forRole returns the tools offered to role.
+func (reg *toolRegistry) forRole(role string) []AgentTool {
+	if tools, ok := reg.byRole[role]; ok {
+		return tools
+	}
+	return []AgentTool{}
+}
+
+// prompt returns the JSON tool list for role's system prompt.
+func (reg *toolRegistry) prompt(role string) string {
+	if p, ok := reg.prompts[role]; ok {
+		return p
+	}
+	return "[]"
+}
+
+// offered reports whether role may call the tool.
+func (reg *toolRegistry) offered(role, name string) bool {
+	for _, t := range reg.byRole[role] {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseToolArgs decodes the LLM-produced arguments and validates them against the tool schema.
+func parseToolArgs(tool AgentTool, arguments string) (map[string]string, error) {
+	raw := map[string]any{}
+	if arguments != "" {
+		if err := json.Unmarshal([]byte(arguments), &raw); err != nil {
+			return nil, errors.BadRequest("INVALID_ARGUMENTS", fmt.Sprintf("无法解析%s的参数", tool.Name))
+		}
+	}
+	args := make(map[string]string, len(tool.Parameters.Properties))
+	for name := range tool.Parameters.Properties {
+		v, ok := raw[name]
+		if !ok {
+			continue
+		}
+		str, ok := v.(string)
+		if !ok {
+			return nil, errors.BadRequest("INVALID_ARGUMENTS", fmt.Sprintf("参数%s必须是字符串", name))
+		}
+		args[name] = str
+	}
+	for _, name := range tool.Parameters.Required {
+		if args[name] == "" {
+			return nil, errors.BadRequest("INVALID_ARGUMENTS", fmt.Sprintf("缺少参数%s", name))
+		}
+	}
+	return args, nil
+}
+
+// toolHandlers returns the handler for each tool.
+func (uc *AgentUsecase) toolHandlers() map[string]toolHandler {
+	return map[string]toolHandler{
+		"GetReview":           uc.toolGetReview,
+		"ListReviewByStoreID": uc.toolListReviewByStoreID,
+		"ListMyReviews":       uc.toolListMyReviews,
+	}
+}
+
+func (uc *AgentUsecase) toolGetReview(ctx context.Context, _ *authedUser, args map[string]string) (any, error) {
+	reviewID, err := strconv.ParseInt(args["reviewID"], 10, 64)
+	if err != nil {
+		return nil, errors.BadRequest("INVALID_ARGUMENTS", "reviewID必须是有效的数字")
+	}
+	review, err := uc.reviewUC.GetReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	// 工具结果会交给LLM并转述给用户, 只返回当前读者可见的字段
+	v := NewReviewView(review.ReviewInfo, AudienceFromContext(ctx))
+	v.Appends = review.Appends
+	return v, nil
+}
+
+func (uc *AgentUsecase) toolListReviewByStoreID(ctx context.Context, user *authedUser, args map[string]string) (any, error) {
+	storeID, err := strconv.ParseInt(args["storeID"], 10, 64)
+	if err != nil {
+		return nil, errors.BadRequest("INVALID_ARGUMENTS", "storeID必须是有效的数字")
+	}
+	// Enhanced RBAC check: Merchants can only list reviews for their own store.
+	if user.Role == "merchant" && user.StoreID != storeID {
+		return nil, errors.Forbidden("FORBIDDEN", "商家只能查询自己店铺的评论")
+	}
+	// 每页条数为0时使用配置的默认每页条数
+	list, err := uc.reviewUC.ListReviewByStoreID(ctx, storeID, 1, 0, false, "")
+	if err != nil {
+		return nil, err
+	}
+	return NewReviewListView(list, AudienceFromContext(ctx)), nil
+}
+
+func (uc *AgentUsecase) toolListMyReviews(ctx context.Context, user *authedUser, _ map[string]string) (any, error) {
+	// 每页条数为0时使用配置的默认每页条数
+	list, err := uc.reviewUC.ListReviewByUserID(ctx, user.UserID, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	return NewReviewListView(list, AudienceFromContext(ctx)), nil
+}
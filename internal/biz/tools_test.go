@@ -0,0 +1,240 @@
+package biz
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"review/internal/conf"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// stubHandlers returns a handler for every defined tool that returns the tool's name.
+func stubHandlers() map[string]toolHandler {
+	handlers := make(map[string]toolHandler, len(agentTools))
+	for _, t := range agentTools {
+		name := t.Name
+		handlers[name] = func(context.Context, *authedUser, map[string]string) (any, error) { return name, nil }
+	}
+	return handlers
+}
+
+func newTestToolRegistry(t *testing.T, roles map[string][]string) *toolRegistry {
+	t.Helper()
+	reg, err := newToolRegistry(stubHandlers(), roles)
+	if err != nil {
+		t.Fatalf("newToolRegistry() error = %v", err)
+	}
+	return reg
+}
+
+func TestParseToolArgs(t *testing.T) {
+	tool := agentTools[0] // GetReview, requires reviewID
+	tests := []struct {
+		name      string
+		arguments string
+		want      string
+		wantErr   bool
+	}{
+		{name: "valid", arguments: `{"reviewID":"42"}`, want: "42"},
+		{name: "unknown arguments are dropped", arguments: `{"reviewID":"42","drop":"table"}`, want: "42"},
+		{name: "missing required", arguments: `{}`, wantErr: true},
+		{name: "empty arguments", arguments: "", wantErr: true},
+		{name: "not a string", arguments: `{"reviewID":42}`, wantErr: true},
+		{name: "invalid JSON", arguments: `{reviewID:42`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := parseToolArgs(tool, tt.arguments)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseToolArgs(%q) error = %v, wantErr %v", tt.arguments, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(args) != 1 || args["reviewID"] != tt.want {
+				t.Errorf("parseToolArgs(%q) = %v, want only reviewID=%s", tt.arguments, args, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolRegistryPrompt(t *testing.T) {
+	reg := newTestToolRegistry(t, nil)
+	tests := []struct {
+		role, tool string
+		want       bool
+	}{
+		{"customer", "ListMyReviews", true},
+		{"merchant", "ListMyReviews", false},
+		{"merchant", "ListReviewByStoreID", true},
+		{"public", "GetReview", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role+"/"+tt.tool, func(t *testing.T) {
+			if got := reg.offered(tt.role, tt.tool); got != tt.want {
+				t.Errorf("offered(%q, %q) = %v, want %v", tt.role, tt.tool, got, tt.want)
+			}
+			if got := strings.Contains(reg.prompt(tt.role), `"name":"`+tt.tool+`"`); got != tt.want {
+				t.Errorf("prompt(%q) contains %q = %v, want %v", tt.role, tt.tool, got, tt.want)
+			}
+		})
+	}
+	if got := reg.prompt("public"); got != "[]" {
+		t.Errorf(`prompt("public") = %q, want "[]"`, got)
+	}
+}
+
+func TestValidateToolRegistry(t *testing.T) {
+	tools := []AgentTool{{Name: "A"}, {Name: "B"}}
+	handler := func(context.Context, *authedUser, map[string]string) (any, error) { return nil, nil }
+	both := map[string]toolHandler{"A": handler, "B": handler}
+	tests := []struct {
+		name     string
+		tools    []AgentTool
+		roles    map[string][]string
+		handlers map[string]toolHandler
+		wantErr  bool
+	}{
+		{name: "consistent", tools: tools, roles: map[string][]string{"customer": {"A", "B"}}, handlers: both},
+		{name: "duplicate definition", tools: append(tools, AgentTool{Name: "A"}), roles: map[string][]string{"customer": {"A", "B"}}, handlers: both, wantErr: true},
+		{name: "definition without handler", tools: tools, roles: map[string][]string{"customer": {"A"}}, handlers: map[string]toolHandler{"A": handler}, wantErr: true},
+		{name: "offered without definition", tools: tools, roles: map[string][]string{"customer": {"A", "B", "C"}}, handlers: both, wantErr: true},
+		{name: "handler without definition", tools: tools, roles: map[string][]string{"customer": {"A", "B"}}, handlers: map[string]toolHandler{"A": handler, "B": handler, "C": handler}, wantErr: true},
+		{name: "not offered to any role", tools: tools, roles: map[string][]string{"customer": {"A"}}, handlers: both, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateToolRegistry(tt.tools, tt.roles, tt.handlers); (err != nil) != tt.wantErr {
+				t.Errorf("validateToolRegistry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuiltInToolRegistry(t *testing.T) {
+	if err := validateToolRegistry(agentTools, roleTools, newTestAgentUsecase(10).toolHandlers()); err != nil {
+		t.Errorf("built-in tool registry: %v", err)
+	}
+}
+
+func TestValidateRoleTools(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   map[string][]string
+		wantErr bool
+	}{
+		{name: "subset of tools", roles: map[string][]string{"reviewer": {"ListMyReviews"}, "public": {}}},
+		{name: "unknown role", roles: map[string][]string{"auditor": {"GetReview"}}, wantErr: true},
+		{name: "unimplemented tool", roles: map[string][]string{"customer": {"DropTable"}}, wantErr: true},
+		{name: "duplicate tool", roles: map[string][]string{"customer": {"GetReview", "GetReview"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRoleTools(agentTools, tt.roles); (err != nil) != tt.wantErr {
+				t.Errorf("validateRoleTools() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfiguredRoleTools(t *testing.T) {
+	c := &conf.AI{RoleTools: map[string]*conf.AI_ToolList{
+		"reviewer": {Tools: []string{"GetReview", "ListMyReviews"}},
+		"customer": {Tools: []string{"GetReview"}},
+	}}
+	reg := newTestToolRegistry(t, configuredRoleTools(c))
+	tests := []struct {
+		role, tool string
+		want       bool
+	}{
+		{"reviewer", "ListMyReviews", true},
+		{"reviewer", "GetReview", true},
+		{"customer", "GetReview", true},
+		{"customer", "ListMyReviews", false},
+		// Roles missing from the configured mapping get no tools.
+		{"merchant", "ListReviewByStoreID", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role+"/"+tt.tool, func(t *testing.T) {
+			if got := reg.offered(tt.role, tt.tool); got != tt.want {
+				t.Errorf("offered(%q, %q) = %v, want %v", tt.role, tt.tool, got, tt.want)
+			}
+		})
+	}
+	if got := configuredRoleTools(&conf.AI{}); got != nil {
+		t.Errorf("configuredRoleTools(unset) = %v, want nil for the default mapping", got)
+	}
+	if _, err := newToolRegistry(stubHandlers(), map[string][]string{"customer": {"DropTable"}}); err == nil {
+		t.Error("newToolRegistry() accepted a mapping with an unimplemented tool")
+	}
+}
+
+func TestNewToolTimeouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       *conf.AI
+		want    map[string]time.Duration
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			c:    &conf.AI{},
+			want: map[string]time.Duration{"GetReview": defaultToolTimeout, "ListMyReviews": defaultToolTimeout},
+		},
+		{
+			name: "per-tool override",
+			c: &conf.AI{
+				ToolTimeout:  durationpb.New(5 * time.Second),
+				ToolTimeouts: map[string]*durationpb.Duration{"ListReviewByStoreID": durationpb.New(time.Minute)},
+			},
+			want: map[string]time.Duration{"GetReview": 5 * time.Second, "ListReviewByStoreID": time.Minute},
+		},
+		{name: "unknown tool", c: &conf.AI{ToolTimeouts: map[string]*durationpb.Duration{"DropTable": durationpb.New(time.Second)}}, wantErr: true},
+		{name: "non-positive", c: &conf.AI{ToolTimeouts: map[string]*durationpb.Duration{"GetReview": durationpb.New(0)}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeouts, err := newToolTimeouts(tt.c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newToolTimeouts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for tool, want := range tt.want {
+				if got := timeouts.forTool(tool); got != want {
+					t.Errorf("forTool(%q) = %v, want %v", tool, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCallToolTimeout(t *testing.T) {
+	uc := newTestAgentUsecase(10)
+	handlers := stubHandlers()
+	handlers["ListMyReviews"] = func(ctx context.Context, _ *authedUser, _ map[string]string) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	reg, err := newToolRegistry(handlers, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc.tools = reg
+	uc.timeouts = toolTimeouts{fallback: time.Hour, byTool: map[string]time.Duration{"ListMyReviews": 10 * time.Millisecond}}
+
+	ctx := contextWithClaims(jwtv5.MapClaims{"user_id": float64(7), "role": "customer"})
+	start := time.Now()
+	got, err := uc.CallTool(ctx, "ListMyReviews", "{}", "", "")
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if got != toolTimeoutMessage {
+		t.Errorf("CallTool() = %q, want the timeout message", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("CallTool() took %v, want it bounded by the 10ms tool budget", elapsed)
+	}
+}
@@ -2,11 +2,38 @@ package biz
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/log"
 )
 
+// ErrMerchantStoreMissing is returned when a merchant account has no store record.
+var ErrMerchantStoreMissing = errors.InternalServer("STORE_MISSING", "No store is associated with this merchant account")
+
+var (
+	// ErrRoleNotSelfAssignable is returned when a public registration asks for a privileged role.
+	ErrRoleNotSelfAssignable = errors.Forbidden("ROLE_NOT_ALLOWED", "This role cannot be self-assigned at registration")
+	// ErrUnknownRole is returned for a role the service does not know.
+	ErrUnknownRole = errors.BadRequest("ROLE_INVALID", "Role is invalid")
+	// ErrNoUserFields is returned for an update that sets no fields.
+	ErrNoUserFields = errors.BadRequest("USER_UPDATE_EMPTY", "No fields to update")
+)
+
+const defaultRegisterRole = "customer"
+
+// registerRoles are the roles anyone may pick when registering.
+// Privileged roles (reviewer, admin) can only be granted by an admin.
+var registerRoles = []string{"customer", "merchant"}
+
+// knownRoles are all roles a user record may carry.
+var knownRoles = []string{"customer", "merchant", "reviewer", "admin"}
+
 // User is a User model.
 type User struct {
 	ID        int64
@@ -18,39 +45,106 @@ type User struct {
 	UpdatedAt time.Time
 }
 
+// UserUpdate is a partial update of a user's information.
+// Only non-nil fields are written, so a field can be set to any value, including its zero value,
+// without touching the others.
+type UserUpdate struct {
+	ID       int64
+	Username *string
+	Email    *string
+}
+
+// Fields returns the columns to update, keyed by column name.
+func (u *UserUpdate) Fields() map[string]interface{} {
+	fields := make(map[string]interface{}, 2)
+	if u.Username != nil {
+		fields["username"] = *u.Username
+	}
+	if u.Email != nil {
+		fields["email"] = *u.Email
+	}
+	return fields
+}
+
+// LoginResult is returned by a successful login.
+// The token stays authoritative for the backend; the other fields let the client
+// tailor its UI without decoding the token.
+type LoginResult struct {
+	Token   string
+	UserID  int64
+	Role    string
+	StoreID int64 // only set for merchants
+	// ExpiresAt is when the token expires; its lifetime depends on the role.
+	ExpiresAt time.Time
+}
+
 // UserRepo is a user repo.
 type UserRepo interface {
 	Register(ctx context.Context, u *User) error
-	Login(ctx context.Context, username, password string) (string, error)
+	Login(ctx context.Context, username, password string) (*LoginResult, error)
 	GetUserInfo(ctx context.Context, id int64) (*User, error)
-	UpdateUserInfo(ctx context.Context, u *User) error
+	// UpdateUserInfo writes only the fields set in u.
+	UpdateUserInfo(ctx context.Context, u *UserUpdate) error
 	DeleteUser(ctx context.Context, id int64) error
 	GetUserList(ctx context.Context, offset, limit int32) ([]*User, int64, error)
+	// SetUserRole changes a user's role, creating or removing the merchant store in the same transaction.
+	SetUserRole(ctx context.Context, id int64, role string) error
+}
+
+// TokenDenylist invalidates tokens already issued to a user.
+// Tokens issued before the user's revocation time are rejected by the auth middleware.
+type TokenDenylist interface {
+	RevokeUserTokens(ctx context.Context, userID int64) error
+	TokensRevokedAt(ctx context.Context, userID int64) (time.Time, error)
 }
 
 // UserUsecase is a User usecase.
 type UserUsecase struct {
-	repo UserRepo
-	log  *log.Helper
+	repo        UserRepo
+	denylist    TokenDenylist
+	log         *log.Helper
+	defaultRole string
 }
 
 // NewUserUsecase new a User usecase.
-func NewUserUsecase(repo UserRepo, logger log.Logger) *UserUsecase {
-	return &UserUsecase{
-		repo: repo,
-		log:  log.NewHelper(logger),
+// It fails if the configured default role is not one users may self-assign.
+func NewUserUsecase(repo UserRepo, denylist TokenDenylist, logger log.Logger, c *conf.Auth) (*UserUsecase, error) {
+	defaultRole := c.GetDefaultRole()
+	if defaultRole == "" {
+		defaultRole = defaultRegisterRole
+	}
+	if !slices.Contains(registerRoles, defaultRole) {
+		return nil, fmt.Errorf("auth.default_role %q must be one of %v", defaultRole, registerRoles)
 	}
+	return &UserUsecase{
+		repo:        repo,
+		denylist:    denylist,
+		log:         log.NewHelper(logger),
+		defaultRole: defaultRole,
+	}, nil
 }
 
 // Register creates a User, and returns the new User.
+// An empty role falls back to the configured default; privileged roles are rejected.
 func (uc *UserUsecase) Register(ctx context.Context, u *User) error {
 	uc.log.WithContext(ctx).Debugf("Register: username=%s, email=%s, role=%s", u.Username, u.Email, u.Role)
 
+	if u.Role == "" {
+		u.Role = uc.defaultRole
+	}
+	if !slices.Contains(registerRoles, u.Role) {
+		if slices.Contains(knownRoles, u.Role) {
+			uc.log.WithContext(ctx).Warnf("Register: rejected self-assigned role %s for username=%s", u.Role, u.Username)
+			return ErrRoleNotSelfAssignable
+		}
+		return ErrUnknownRole
+	}
+
 	return uc.repo.Register(ctx, u)
 }
 
 // Login verifies user credentials and returns a token.
-func (uc *UserUsecase) Login(ctx context.Context, username, password string) (string, error) {
+func (uc *UserUsecase) Login(ctx context.Context, username, password string) (*LoginResult, error) {
 	uc.log.WithContext(ctx).Debugf("Login: username=%s", username)
 
 	return uc.repo.Login(ctx, username, password)
@@ -63,23 +157,52 @@ func (uc *UserUsecase) GetUserInfo(ctx context.Context, id int64) (*User, error)
 	return uc.repo.GetUserInfo(ctx, id)
 }
 
-// UpdateUserInfo updates a user's information.
-func (uc *UserUsecase) UpdateUserInfo(ctx context.Context, u *User) error {
-	uc.log.WithContext(ctx).Debugf("UpdateUserInfo: id=%d", u.ID)
-	
+// UpdateUserInfo updates the fields of a user's information set in u, leaving the others unchanged.
+// Username and email are required columns, so setting either to an empty value is rejected.
+func (uc *UserUsecase) UpdateUserInfo(ctx context.Context, u *UserUpdate) error {
+	uc.log.WithContext(ctx).Debugf("UpdateUserInfo: id=%d, username set=%v, email set=%v", u.ID, u.Username != nil, u.Email != nil)
+
+	fields := u.Fields()
+	if len(fields) == 0 {
+		return ErrNoUserFields
+	}
+	for name, v := range fields {
+		if strings.TrimSpace(v.(string)) == "" {
+			return errors.BadRequest("USER_FIELD_REQUIRED", name+" cannot be empty")
+		}
+	}
 	return uc.repo.UpdateUserInfo(ctx, u)
 }
 
 // DeleteUser deletes a user.
 func (uc *UserUsecase) DeleteUser(ctx context.Context, id int64) error {
 	uc.log.WithContext(ctx).Debugf("DeleteUser: id=%d", id)
-	
+
 	return uc.repo.DeleteUser(ctx, id)
 }
 
 // GetUserList gets a list of users.
 func (uc *UserUsecase) GetUserList(ctx context.Context, offset, limit int32) ([]*User, int64, error) {
-	uc.log.WithContext(ctx).Debugf("GetUserList: offset=%d, limit=%d", offset, limit)
+	p := Pagination{Offset: offset, Limit: limit}.Normalize()
+	uc.log.WithContext(ctx).Debugf("GetUserList: offset=%d, limit=%d", p.Offset, p.Limit)
+
+	return uc.repo.GetUserList(ctx, p.Offset, p.Limit)
+}
 
-	return uc.repo.GetUserList(ctx, offset, limit)
+// SetUserRole changes a user's role. Only admins may call it.
+// The user's existing tokens are revoked so the new role takes effect on their next login.
+func (uc *UserUsecase) SetUserRole(ctx context.Context, id int64, role string) error {
+	admin, err := requireRole(ctx, "admin")
+	if err != nil {
+		return err
+	}
+	uc.log.WithContext(ctx).Infof("SetUserRole: id=%d, role=%s, by admin=%d", id, role, admin.UserID)
+
+	if !slices.Contains(knownRoles, role) {
+		return ErrUnknownRole
+	}
+	if err := uc.repo.SetUserRole(ctx, id, role); err != nil {
+		return err
+	}
+	return uc.denylist.RevokeUserTokens(ctx, id)
 }
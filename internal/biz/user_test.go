@@ -0,0 +1,213 @@
+package biz
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// fakeUserRepo records the calls a test cares about; any other UserRepo method panics.
+type fakeUserRepo struct {
+	UserRepo
+	registered []*User
+	roles      map[int64]string
+	updates    []*UserUpdate
+}
+
+func (r *fakeUserRepo) Register(_ context.Context, u *User) error {
+	r.registered = append(r.registered, u)
+	return nil
+}
+
+func (r *fakeUserRepo) SetUserRole(_ context.Context, id int64, role string) error {
+	if r.roles == nil {
+		r.roles = make(map[int64]string)
+	}
+	r.roles[id] = role
+	return nil
+}
+
+func (r *fakeUserRepo) UpdateUserInfo(_ context.Context, u *UserUpdate) error {
+	r.updates = append(r.updates, u)
+	return nil
+}
+
+// fakeDenylist records revocations in memory.
+type fakeDenylist struct {
+	revoked map[int64]time.Time
+}
+
+func (d *fakeDenylist) RevokeUserTokens(_ context.Context, userID int64) error {
+	if d.revoked == nil {
+		d.revoked = make(map[int64]time.Time)
+	}
+	d.revoked[userID] = time.Now()
+	return nil
+}
+
+func (d *fakeDenylist) TokensRevokedAt(_ context.Context, userID int64) (time.Time, error) {
+	return d.revoked[userID], nil
+}
+
+func newTestUserUsecase(t *testing.T, repo UserRepo, denylist TokenDenylist) *UserUsecase {
+	t.Helper()
+	uc, err := NewUserUsecase(repo, denylist, log.DefaultLogger, &conf.Auth{})
+	if err != nil {
+		t.Fatalf("NewUserUsecase() error = %v", err)
+	}
+	return uc
+}
+
+func TestNewUserUsecaseDefaultRole(t *testing.T) {
+	tests := []struct {
+		role    string
+		wantErr bool
+	}{
+		{role: ""},
+		{role: "customer"},
+		{role: "merchant"},
+		{role: "reviewer", wantErr: true},
+		{role: "guest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			_, err := NewUserUsecase(&fakeUserRepo{}, nil, log.DefaultLogger, &conf.Auth{DefaultRole: tt.role})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewUserUsecase(default_role=%q) error = %v, wantErr %v", tt.role, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegisterRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		wantRole string
+		wantErr  *errors.Error
+	}{
+		{name: "default", role: "", wantRole: defaultRegisterRole},
+		{name: "merchant", role: "merchant", wantRole: "merchant"},
+		{name: "privileged", role: "admin", wantErr: ErrRoleNotSelfAssignable},
+		{name: "unknown", role: "guest", wantErr: ErrUnknownRole},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepo{}
+			err := newTestUserUsecase(t, repo, nil).Register(context.Background(), &User{Username: "u", Role: tt.role})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || len(repo.registered) != 0 {
+					t.Errorf("Register() error = %v, registered %d, want %v and nothing registered", err, len(repo.registered), tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			if got := repo.registered[0].Role; got != tt.wantRole {
+				t.Errorf("registered role = %q, want %q", got, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestSetUserRole(t *testing.T) {
+	admin := contextWithClaims(jwtv5.MapClaims{"user_id": float64(1), "role": "admin"})
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		role    string
+		wantErr *errors.Error
+	}{
+		{name: "admin grants reviewer", ctx: admin, role: "reviewer"},
+		{name: "unknown role", ctx: admin, role: "owner", wantErr: ErrUnknownRole},
+		{name: "reviewer may not grant", ctx: reviewerContext(), role: "admin", wantErr: ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, denylist := &fakeUserRepo{}, &fakeDenylist{}
+			err := newTestUserUsecase(t, repo, denylist).SetUserRole(tt.ctx, 9, tt.role)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || len(repo.roles) != 0 || len(denylist.revoked) != 0 {
+					t.Errorf("SetUserRole() error = %v, want %v with no change and no revocation", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetUserRole() error = %v", err)
+			}
+			if repo.roles[9] != tt.role {
+				t.Errorf("role = %q, want %q", repo.roles[9], tt.role)
+			}
+			if denylist.revoked[9].IsZero() {
+				t.Error("existing tokens were not revoked")
+			}
+		})
+	}
+}
+
+func TestCheckTokenRevoked(t *testing.T) {
+	revokedAt := time.Now().Truncate(time.Second)
+	denylist := &fakeDenylist{revoked: map[int64]time.Time{9: revokedAt}}
+	tests := []struct {
+		name    string
+		claims  jwtv5.MapClaims
+		wantErr bool
+	}{
+		{name: "issued before revocation", claims: jwtv5.MapClaims{"user_id": float64(9), "iat": float64(revokedAt.Add(-time.Minute).Unix())}, wantErr: true},
+		{name: "issued without iat", claims: jwtv5.MapClaims{"user_id": float64(9)}, wantErr: true},
+		{name: "issued after revocation", claims: jwtv5.MapClaims{"user_id": float64(9), "iat": float64(revokedAt.Add(time.Minute).Unix())}},
+		{name: "user never revoked", claims: jwtv5.MapClaims{"user_id": float64(10), "iat": float64(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckTokenRevoked(contextWithClaims(tt.claims), denylist); (err != nil) != tt.wantErr {
+				t.Errorf("CheckTokenRevoked() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateUserInfo(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	tests := []struct {
+		name       string
+		update     *UserUpdate
+		wantFields map[string]interface{}
+		wantReason string
+	}{
+		{name: "email only", update: &UserUpdate{ID: 1, Email: ptr("a@example.com")}, wantFields: map[string]interface{}{"email": "a@example.com"}},
+		{name: "username only", update: &UserUpdate{ID: 1, Username: ptr("alice")}, wantFields: map[string]interface{}{"username": "alice"}},
+		{name: "both", update: &UserUpdate{ID: 1, Username: ptr("alice"), Email: ptr("a@example.com")}, wantFields: map[string]interface{}{"username": "alice", "email": "a@example.com"}},
+		{name: "no fields", update: &UserUpdate{ID: 1}, wantReason: "USER_UPDATE_EMPTY"},
+		{name: "clearing a required field", update: &UserUpdate{ID: 1, Email: ptr(" ")}, wantReason: "USER_FIELD_REQUIRED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepo{}
+			err := newTestUserUsecase(t, repo, &fakeDenylist{}).UpdateUserInfo(context.Background(), tt.update)
+			if tt.wantReason != "" {
+				if errors.Reason(err) != tt.wantReason {
+					t.Fatalf("UpdateUserInfo() error = %v, want reason %s", err, tt.wantReason)
+				}
+				if len(repo.updates) != 0 {
+					t.Errorf("repo updated despite error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateUserInfo() error = %v", err)
+			}
+			if len(repo.updates) != 1 || !reflect.DeepEqual(repo.updates[0].Fields(), tt.wantFields) {
+				t.Errorf("updated fields = %v, want %v", repo.updates, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package biz
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+const (
+	// 与 review_info.content varchar(512) 保持一致
+	defaultContentMinLength = 1
+	defaultContentMaxLength = 512
+)
+
+// 申诉校验的默认值
+const (
+	defaultAppealReasonMaxLength  = 512
+	defaultAppealContentMaxLength = 512
+	defaultAppealMaxPics          = 9
+	defaultAppealMaxVideos        = 3
+)
+
+// ErrAppealStatusChanged 评论状态已不是申诉时的状态, 申诉不能再审核
+var ErrAppealStatusChanged = errors.Conflict("APPEAL_REVIEW_STATUS_CHANGED", "评论状态已变更，该申诉不能再审核")
+
+// errAppealDuplicate 申诉与上一次申诉内容完全相同
+var errAppealDuplicate = errors.BadRequest("APPEAL_DUPLICATE", "申诉内容与上一次申诉完全相同，请补充新的理由或证据")
+
+// defaultMaxAppends 同一订单默认最多追加评论的次数
+const defaultMaxAppends = 3
+
+// defaultMaxResubmits 被驳回的评论默认最多重新提交的次数
+const defaultMaxResubmits = 2
+
+// defaultScoreEditWindow 评论发布后默认允许修改评分的时间
+const defaultScoreEditWindow = 24 * time.Hour
+
+// 默认评分范围
+const (
+	defaultMinScore = 1
+	defaultMaxScore = 5
+)
+
+// errScoreEditExpired 已超过修改评分的时间窗口
+var errScoreEditExpired = errors.Forbidden("SCORE_EDIT_EXPIRED", "已超过可修改评分的时间")
+
+// ErrReindexRange 重建索引的创建时间范围无效
+var ErrReindexRange = errors.BadRequest("REINDEX_RANGE_INVALID", "重建索引需要指定开始和结束时间，且开始时间早于结束时间")
+
+// ErrReindexBusy 后台任务队列已满, 重建索引任务未提交
+var ErrReindexBusy = errors.ServiceUnavailable("REINDEX_BUSY", "后台任务繁忙，请稍后重试")
+
+// ErrSearchIndexMissing ES中的评论索引不存在, 需要创建索引或重建索引后才能查询
+var ErrSearchIndexMissing = errors.ServiceUnavailable("SEARCH_INDEX_MISSING", "评论搜索索引不存在，请联系管理员创建索引")
+
+// maxStoreIDs 多店铺评论列表一次最多查询的店铺数
+const maxStoreIDs = 50
+
+// errStoreIDs 多店铺评论列表的店铺ID无效
+var errStoreIDs = errors.BadRequest("STORE_IDS_INVALID", fmt.Sprintf("需要指定 1 到 %d 个有效的店铺ID", maxStoreIDs))
+
+// normalizeStoreIDs 校验店铺ID并排序去重, 相同的店铺集合得到相同的结果, 便于缓存
+func normalizeStoreIDs(storeIDs []int64) ([]int64, error) {
+	ids := slices.Clone(storeIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if len(ids) == 0 || len(ids) > maxStoreIDs || ids[0] <= 0 {
+		return nil, errStoreIDs
+	}
+	return ids, nil
+}
+
+// defaultDeletableStatuses 作者可自行删除的评论状态: 待审核、审核驳回
+var defaultDeletableStatuses = []int32{10, 30}
+
+// contentLengthRange 返回评论内容允许的长度范围，未配置时使用默认值
+func contentLengthRange(c *conf.Review) (int, int) {
+	lo, hi := int(c.GetContentMinLength()), int(c.GetContentMaxLength())
+	if lo <= 0 {
+		lo = defaultContentMinLength
+	}
+	if hi <= 0 {
+		hi = defaultContentMaxLength
+	}
+	return lo, hi
+}
+
+// validateContent 校验评论内容长度
+// 按rune计数而不是字节数，否则一个汉字会被算作3个字符
+func validateContent(c *conf.Review, content string) error {
+	lo, hi := contentLengthRange(c)
+	n := utf8.RuneCountInString(content)
+	if n < lo || n > hi {
+		return errors.BadRequest("CONTENT_LENGTH_INVALID",
+			fmt.Sprintf("评论内容长度应在%d到%d个字之间，当前为%d个字", lo, hi, n))
+	}
+	return nil
+}
+
+// deletableStatuses 返回作者可自行删除的评论状态，未配置时使用默认值
+func deletableStatuses(c *conf.Review) []int32 {
+	if s := c.GetDeletableStatuses(); len(s) > 0 {
+		return s
+	}
+	return defaultDeletableStatuses
+}
+
+// maxAppends 返回同一订单允许追加评论的次数，未配置时使用默认值
+func maxAppends(c *conf.Review) int {
+	if n := int(c.GetMaxAppends()); n > 0 {
+		return n
+	}
+	return defaultMaxAppends
+}
+
+// maxResubmits 返回被驳回的评论允许重新提交的次数，未配置时使用默认值
+func maxResubmits(c *conf.Review) int {
+	if n := int(c.GetMaxResubmits()); n > 0 {
+		return n
+	}
+	return defaultMaxResubmits
+}
+
+// scoreEditWindow 返回允许修改评分的时间窗口，未配置时使用默认值
+func scoreEditWindow(c *conf.Review) time.Duration {
+	if d := c.GetScoreEditWindow().AsDuration(); d > 0 {
+		return d
+	}
+	return defaultScoreEditWindow
+}
+
+// scoreScale 返回评分的取值范围，未配置或配置无效(max不大于min)时使用默认值
+func scoreScale(c *conf.Review) (int32, int32) {
+	lo, hi := c.GetScoreScale().GetMin(), c.GetScoreScale().GetMax()
+	if hi <= lo {
+		return defaultMinScore, defaultMaxScore
+	}
+	return lo, hi
+}
+
+// validateScores 校验评分、服务评分和物流评分都在配置的评分范围内
+// 按固定顺序校验, 多项超出范围时错误信息总是指向第一项
+func validateScores(c *conf.Review, score, serviceScore, expressScore int32) error {
+	lo, hi := scoreScale(c)
+	for _, s := range []struct {
+		name  string
+		value int32
+	}{{"评分", score}, {"服务评分", serviceScore}, {"物流评分", expressScore}} {
+		if s.value < lo || s.value > hi {
+			return errors.BadRequest("SCORE_INVALID", fmt.Sprintf("%s应在%d到%d之间，当前为%d", s.name, lo, hi, s.value))
+		}
+	}
+	return nil
+}
+
+// errResubmitLimit 重新提交次数已达上限
+func errResubmitLimit(limit int) error {
+	return errors.Forbidden("RESUBMIT_LIMIT", fmt.Sprintf("每条评论最多只能重新提交%d次", limit))
+}
+
+// 待审核评论的可见性策略, 见 conf.Review.pending_visibility
+const (
+	pendingVisibilityHidden = "hidden"
+	pendingVisibilityAuthor = "author"
+	pendingVisibilityPublic = "public"
+)
+
+// pendingVisibility 返回配置的待审核评论可见性, 未配置或取值无效时为 hidden
+func pendingVisibility(c *conf.Review) string {
+	switch v := c.GetPendingVisibility(); v {
+	case pendingVisibilityAuthor, pendingVisibilityPublic:
+		return v
+	default:
+		return pendingVisibilityHidden
+	}
+}
+
+// validateAppeal 校验申诉理由、内容及附带的图片/视频
+func validateAppeal(c *conf.Review, param *AppealReviewParam) error {
+	reason := strings.TrimSpace(param.Reason)
+	if reason == "" {
+		return errors.BadRequest("APPEAL_REASON_REQUIRED", "申诉理由不能为空")
+	}
+	if n := utf8.RuneCountInString(reason); n > defaultAppealReasonMaxLength {
+		return errors.BadRequest("APPEAL_REASON_LENGTH_INVALID",
+			fmt.Sprintf("申诉理由最多%d个字，当前为%d个字", defaultAppealReasonMaxLength, n))
+	}
+	maxContent := int(c.GetAppealContentMaxLength())
+	if maxContent <= 0 {
+		maxContent = defaultAppealContentMaxLength
+	}
+	if n := utf8.RuneCountInString(param.Content); n > maxContent {
+		return errors.BadRequest("APPEAL_CONTENT_LENGTH_INVALID",
+			fmt.Sprintf("申诉内容最多%d个字，当前为%d个字", maxContent, n))
+	}
+	maxPics, maxVideos := int(c.GetAppealMaxPics()), int(c.GetAppealMaxVideos())
+	if maxPics <= 0 {
+		maxPics = defaultAppealMaxPics
+	}
+	if maxVideos <= 0 {
+		maxVideos = defaultAppealMaxVideos
+	}
+	if err := validateMedia(c, "pic_info", "图片", param.PicInfo, maxPics); err != nil {
+		return err
+	}
+	return validateMedia(c, "video_info", "视频", param.VideoInfo, maxVideos)
+}
+
+// validateMedia 校验逗号分隔的媒体URL: 数量不超过max, 且必须是允许域名下的 http(s) 地址
+func validateMedia(c *conf.Review, field, name, media string, max int) error {
+	urls := splitMedia(media)
+	if len(urls) > max {
+		return errors.BadRequest("APPEAL_MEDIA_TOO_MANY",
+			fmt.Sprintf("%s(%s)最多%d个，当前为%d个", name, field, max, len(urls)))
+	}
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			return errors.BadRequest("APPEAL_MEDIA_INVALID", fmt.Sprintf("%s(%s)地址无效: %s", name, field, raw))
+		}
+		if !mediaHostAllowed(c.GetMediaHosts(), u.Hostname()) {
+			return errors.BadRequest("APPEAL_MEDIA_HOST_NOT_ALLOWED", fmt.Sprintf("%s(%s)域名不在允许范围内: %s", name, field, u.Hostname()))
+		}
+	}
+	return nil
+}
+
+// splitMedia 拆分逗号分隔的媒体URL, 忽略空项
+func splitMedia(media string) []string {
+	var urls []string
+	for _, s := range strings.Split(media, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			urls = append(urls, s)
+		}
+	}
+	return urls
+}
+
+// mediaHostAllowed 域名与允许列表中的某项相同或是其子域名时允许; 列表为空时不限制
+func mediaHostAllowed(allowed []string, host string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, h := range allowed {
+		h = strings.ToLower(h)
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAppealType 校验申诉类型与评论当前状态是否匹配, appealType 为空时按评论状态确定
+func validateAppealType(c *conf.Review, appealType string, reviewStatus int32) (string, error) {
+	if appealType == "" {
+		appealType = AppealTypeHide
+		if reviewStatus == 30 {
+			appealType = AppealTypeReinstate
+		}
+	}
+	want := AppealReviewStatus(appealType)
+	switch {
+	case want == 0:
+		return "", errors.BadRequest("APPEAL_TYPE_INVALID", fmt.Sprintf("申诉类型只能是 %s 或 %s", AppealTypeHide, AppealTypeReinstate))
+	case appealType == AppealTypeReinstate && !c.GetAllowRejectedAppeal():
+		return "", errors.BadRequest("APPEAL_TYPE_DISABLED", "暂不支持对已驳回的评论申诉")
+	case reviewStatus != want:
+		return "", errors.BadRequest("APPEAL_TYPE_MISMATCH",
+			fmt.Sprintf("%s 申诉只适用于状态为%d的评论，当前评论状态为%d", appealType, want, reviewStatus))
+	}
+	return appealType, nil
+}
+
+// 搜索输入提示的前缀最多32个字
+const maxSuggestPrefixLength = 32
+
+// normalizeSuggestPrefix 去掉首尾空白并转为小写(与索引分词后的词一致), 前缀不能为空且最多32个字
+func normalizeSuggestPrefix(prefix string) (string, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return "", errors.BadRequest("SUGGEST_PREFIX_REQUIRED", "输入提示的前缀不能为空")
+	}
+	if n := utf8.RuneCountInString(prefix); n > maxSuggestPrefixLength {
+		return "", errors.BadRequest("SUGGEST_PREFIX_LENGTH_INVALID",
+			fmt.Sprintf("输入提示的前缀最多%d个字，当前为%d个字", maxSuggestPrefixLength, n))
+	}
+	return prefix, nil
+}
+
+// errAppealStatusFilter 按申诉状态筛选评论时的状态无效
+var errAppealStatusFilter = errors.BadRequest("APPEAL_STATUS_INVALID", "申诉状态只能为10(待审核)、20(通过)或30(驳回)")
+
+// validateAppealStatusFilter 申诉状态筛选条件为0时不筛选, 否则须为申诉的状态之一
+func validateAppealStatusFilter(status int32) error {
+	switch status {
+	case 0, 10, 20, 30:
+		return nil
+	}
+	return errAppealStatusFilter
+}
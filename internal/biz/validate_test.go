@@ -0,0 +1,236 @@
+package biz
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+func TestValidateContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       *conf.Review
+		content string
+		wantErr bool
+	}{
+		{name: "empty", c: &conf.Review{}, content: "", wantErr: true},
+		{name: "default range", c: &conf.Review{}, content: "好评"},
+		{name: "default max in runes", c: &conf.Review{}, content: strings.Repeat("好", defaultContentMaxLength)},
+		{name: "over default max", c: &conf.Review{}, content: strings.Repeat("好", defaultContentMaxLength+1), wantErr: true},
+		{name: "multibyte counts runes not bytes", c: &conf.Review{ContentMinLength: 3, ContentMaxLength: 3}, content: "很好吃"},
+		{name: "below configured min", c: &conf.Review{ContentMinLength: 5}, content: "很好吃", wantErr: true},
+		{name: "above configured max", c: &conf.Review{ContentMaxLength: 2}, content: "很好吃", wantErr: true},
+		{name: "emoji is one rune", c: &conf.Review{ContentMaxLength: 1}, content: "👍"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateContent(tt.c, tt.content); (err != nil) != tt.wantErr {
+				t.Errorf("validateContent(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaxAppends(t *testing.T) {
+	tests := []struct {
+		name string
+		c    *conf.Review
+		want int
+	}{
+		{name: "unset", c: &conf.Review{}, want: defaultMaxAppends},
+		{name: "negative", c: &conf.Review{MaxAppends: -1}, want: defaultMaxAppends},
+		{name: "configured", c: &conf.Review{MaxAppends: 5}, want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maxAppends(tt.c); got != tt.want {
+				t.Errorf("maxAppends() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAppeal(t *testing.T) {
+	hosts := &conf.Review{MediaHosts: []string{"cdn.example.com"}}
+	tests := []struct {
+		name    string
+		c       *conf.Review
+		param   AppealReviewParam
+		wantErr bool
+	}{
+		{name: "reason only", c: &conf.Review{}, param: AppealReviewParam{Reason: "恶意差评"}},
+		{name: "blank reason", c: &conf.Review{}, param: AppealReviewParam{Reason: "  "}, wantErr: true},
+		{name: "reason too long", c: &conf.Review{}, param: AppealReviewParam{Reason: strings.Repeat("长", defaultAppealReasonMaxLength+1)}, wantErr: true},
+		{name: "content over configured max", c: &conf.Review{AppealContentMaxLength: 2}, param: AppealReviewParam{Reason: "r", Content: "三个字"}, wantErr: true},
+		{name: "too many pics", c: &conf.Review{AppealMaxPics: 1}, param: AppealReviewParam{Reason: "r", PicInfo: "https://a.com/1.jpg,https://a.com/2.jpg"}, wantErr: true},
+		{name: "empty media items are ignored", c: &conf.Review{AppealMaxPics: 1}, param: AppealReviewParam{Reason: "r", PicInfo: "https://a.com/1.jpg, ,"}},
+		{name: "not http", c: &conf.Review{}, param: AppealReviewParam{Reason: "r", VideoInfo: "ftp://a.com/1.mp4"}, wantErr: true},
+		{name: "allowed subdomain", c: hosts, param: AppealReviewParam{Reason: "r", PicInfo: "https://img.CDN.example.com/1.jpg"}},
+		{name: "host not allowed", c: hosts, param: AppealReviewParam{Reason: "r", PicInfo: "https://evilcdn.example.com/1.jpg"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateAppeal(tt.c, &tt.param); (err != nil) != tt.wantErr {
+				t.Errorf("validateAppeal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeStoreIDs(t *testing.T) {
+	many := make([]int64, maxStoreIDs+1)
+	for i := range many {
+		many[i] = int64(i + 1)
+	}
+	tests := []struct {
+		name    string
+		in      []int64
+		want    []int64
+		wantErr bool
+	}{
+		{name: "sorted and deduplicated", in: []int64{3, 1, 3, 2}, want: []int64{1, 2, 3}},
+		{name: "duplicates count once", in: append(many[:maxStoreIDs:maxStoreIDs], 1), want: many[:maxStoreIDs]},
+		{name: "empty", in: nil, wantErr: true},
+		{name: "too many", in: many, wantErr: true},
+		{name: "invalid id", in: []int64{2, 0}, wantErr: true},
+		{name: "negative id", in: []int64{-1, 2}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := append([]int64(nil), tt.in...)
+			got, err := normalizeStoreIDs(in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeStoreIDs(%v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(in, tt.in) && tt.in != nil {
+				t.Errorf("normalizeStoreIDs modified its argument: %v", in)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeStoreIDs(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateScores(t *testing.T) {
+	tenPoint := &conf.Review{ScoreScale: &conf.Review_ScoreScale{Min: 1, Max: 10}}
+	thumbs := &conf.Review{ScoreScale: &conf.Review_ScoreScale{Min: 0, Max: 1}}
+	tests := []struct {
+		name        string
+		c           *conf.Review
+		scores      [3]int32
+		wantErr     bool
+		wantMessage string
+	}{
+		{name: "default scale", c: &conf.Review{}, scores: [3]int32{1, 3, 5}},
+		{name: "default scale too high", c: &conf.Review{}, scores: [3]int32{6, 3, 5}, wantErr: true},
+		{name: "default scale zero", c: &conf.Review{}, scores: [3]int32{0, 3, 5}, wantErr: true},
+		{name: "ten-point scale", c: tenPoint, scores: [3]int32{10, 1, 7}},
+		{name: "thumbs", c: thumbs, scores: [3]int32{0, 1, 1}},
+		{name: "invalid scale falls back to default", c: &conf.Review{ScoreScale: &conf.Review_ScoreScale{Min: 5, Max: 5}}, scores: [3]int32{5, 5, 6}, wantErr: true},
+		{name: "first bad score is reported", c: &conf.Review{}, scores: [3]int32{3, 9, 9}, wantErr: true, wantMessage: "服务评分应在1到5之间，当前为9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateScores(tt.c, tt.scores[0], tt.scores[1], tt.scores[2])
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateScores(%v) error = %v, wantErr %v", tt.scores, err, tt.wantErr)
+			}
+			if tt.wantMessage != "" && errors.FromError(err).Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", errors.FromError(err).Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestValidateAppealType(t *testing.T) {
+	allow := &conf.Review{AllowRejectedAppeal: true}
+	tests := []struct {
+		name       string
+		c          *conf.Review
+		appealType string
+		status     int32
+		want       string
+		wantReason string
+	}{
+		{name: "derived hide", c: allow, status: 20, want: AppealTypeHide},
+		{name: "derived reinstate", c: allow, status: 30, want: AppealTypeReinstate},
+		{name: "explicit hide", c: &conf.Review{}, appealType: AppealTypeHide, status: 20, want: AppealTypeHide},
+		{name: "explicit reinstate", c: allow, appealType: AppealTypeReinstate, status: 30, want: AppealTypeReinstate},
+		{name: "unknown type", c: allow, appealType: "delete", status: 20, wantReason: "APPEAL_TYPE_INVALID"},
+		{name: "reinstate disabled", c: &conf.Review{}, appealType: AppealTypeReinstate, status: 30, wantReason: "APPEAL_TYPE_DISABLED"},
+		{name: "hide on rejected review", c: allow, appealType: AppealTypeHide, status: 30, wantReason: "APPEAL_TYPE_MISMATCH"},
+		{name: "reinstate on approved review", c: allow, appealType: AppealTypeReinstate, status: 20, wantReason: "APPEAL_TYPE_MISMATCH"},
+		{name: "derived hide on pending review", c: allow, status: 10, wantReason: "APPEAL_TYPE_MISMATCH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateAppealType(tt.c, tt.appealType, tt.status)
+			if tt.wantReason != "" {
+				if errors.Reason(err) != tt.wantReason {
+					t.Fatalf("validateAppealType() error = %v, want reason %s", err, tt.wantReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateAppealType() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("validateAppealType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeSuggestPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		prefix     string
+		want       string
+		wantReason string
+	}{
+		{name: "trimmed and lowercased", prefix: "  Good ", want: "good"},
+		{name: "chinese", prefix: "物流", want: "物流"},
+		{name: "blank", prefix: "   ", wantReason: "SUGGEST_PREFIX_REQUIRED"},
+		{name: "longest allowed", prefix: strings.Repeat("好", maxSuggestPrefixLength), want: strings.Repeat("好", maxSuggestPrefixLength)},
+		{name: "too long", prefix: strings.Repeat("好", maxSuggestPrefixLength+1), wantReason: "SUGGEST_PREFIX_LENGTH_INVALID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeSuggestPrefix(tt.prefix)
+			if tt.wantReason != "" {
+				if errors.Reason(err) != tt.wantReason {
+					t.Fatalf("normalizeSuggestPrefix() error = %v, want reason %s", err, tt.wantReason)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("normalizeSuggestPrefix(%q) = %q, %v, want %q", tt.prefix, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAppealStatusFilter(t *testing.T) {
+	tests := []struct {
+		status  int32
+		wantErr bool
+	}{
+		{status: 0},
+		{status: 10},
+		{status: 20},
+		{status: 30},
+		{status: 40, wantErr: true},
+		{status: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := validateAppealStatusFilter(tt.status)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateAppealStatusFilter(%d) error = %v, wantErr %v", tt.status, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package biz
+
+import "github.com/go-kratos/kratos/v2/errors"
+
+// ErrReviewConflict 评论在读取后被其他操作(作者编辑、商家回复、异步审核等)修改, 本次更新未生效
+// review_info.version 在每次更新时递增, 更新时校验读取到的版本号; 调用方可重新读取评论后重试
+var ErrReviewConflict = errors.Conflict("REVIEW_CONFLICT", "评论已被修改，请刷新后重试")
@@ -0,0 +1,259 @@
+package biz
+
+import (
+	"context"
+
+	"review/internal/data/model"
+)
+
+// ReviewAudience 评论数据的读者, 决定响应中可以出现哪些字段
+type ReviewAudience string
+
+const (
+	// AudienceCustomer 顾客及未登录用户: 只能看到评论本身
+	AudienceCustomer ReviewAudience = "customer"
+	// AudienceMerchant 商家: 额外可见审核结论及原因, 便于申诉
+	AudienceMerchant ReviewAudience = "merchant"
+	// AudienceReviewer 审核员/管理员: 可见全部审核信息及客户端信息
+	AudienceReviewer ReviewAudience = "reviewer"
+)
+
+// AudienceFromContext 根据当前登录用户的角色确定读者, 未登录按顾客处理
+func AudienceFromContext(ctx context.Context) ReviewAudience {
+	user, err := userFromContext(ctx)
+	if err != nil {
+		return AudienceCustomer
+	}
+	switch user.Role {
+	case "reviewer", "admin":
+		return AudienceReviewer
+	case "merchant":
+		return AudienceMerchant
+	default:
+		return AudienceCustomer
+	}
+}
+
+// ReviewView 对外返回的评论, 字段按读者裁剪
+// 不直接返回 model.ReviewInfo / MyReviewInfo, 避免 op_user、op_remarks、client_ip 等内部字段泄露
+type ReviewView struct {
+	ReviewID     int64    `json:"review_id"`
+	OrderID      int64    `json:"order_id"`
+	StoreID      int64    `json:"store_id"`
+	UserID       int64    `json:"user_id,omitempty"`
+	Anonymous    bool     `json:"anonymous"`
+	Score        int32    `json:"score"`
+	ServiceScore int32    `json:"service_score"`
+	ExpressScore int32    `json:"express_score"`
+	Content      string   `json:"content"`
+	PicInfo      string   `json:"pic_info"`
+	VideoInfo    string   `json:"video_info"`
+	Tags         []string `json:"tags"`
+	Status       int32    `json:"status"`
+	HasReply     bool     `json:"has_reply"`
+	CreateAt     MyTime   `json:"create_at"`
+	// Appends 追加评论, 按追加顺序排列; 评论详情中另行返回, 此处为空
+	Appends []*AppendView `json:"appends,omitempty"`
+	// Moderation 审核信息, 仅商家和审核员可见
+	Moderation *ReviewModeration `json:"moderation,omitempty"`
+}
+
+// ReviewModeration 评论的审核信息
+type ReviewModeration struct {
+	OpReason       string `json:"op_reason"`
+	RejectCategory string `json:"reject_category"`
+	// 以下字段仅审核员可见
+	OpUser    string `json:"op_user,omitempty"`
+	OpRemarks string `json:"op_remarks,omitempty"`
+	ClientIP  string `json:"client_ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// NewReviewView 按读者将数据库中的评论转换为对外的评论
+func NewReviewView(review *model.ReviewInfo, audience ReviewAudience) *ReviewView {
+	if review == nil {
+		return nil
+	}
+	v := &ReviewView{
+		ReviewID:     review.ReviewID,
+		OrderID:      review.OrderID,
+		StoreID:      review.StoreID,
+		UserID:       review.UserID,
+		Anonymous:    review.Anonymous == 1,
+		Score:        review.Score,
+		ServiceScore: review.ServiceScore,
+		ExpressScore: review.ExpressScore,
+		Content:      review.Content,
+		PicInfo:      review.PicInfo,
+		VideoInfo:    review.VideoInfo,
+		Tags:         DecodeTags(review.Tags),
+		Status:       review.Status,
+		HasReply:     review.HasReply == 1,
+		CreateAt:     MyTime(review.CreateAt),
+	}
+	// 匿名评论只对审核员展示作者
+	if v.Anonymous && audience != AudienceReviewer {
+		v.UserID = 0
+	}
+	switch audience {
+	case AudienceMerchant:
+		v.Moderation = &ReviewModeration{OpReason: review.OpReason, RejectCategory: review.RejectCategory}
+	case AudienceReviewer:
+		v.Moderation = &ReviewModeration{
+			OpReason:       review.OpReason,
+			RejectCategory: review.RejectCategory,
+			OpUser:         review.OpUser,
+			OpRemarks:      review.OpRemarks,
+			ClientIP:       review.ClientIP,
+			UserAgent:      review.UserAgent,
+		}
+	}
+	return v
+}
+
+// NewReviewViewFromES 按读者将ES列表中的评论转换为对外的评论
+func NewReviewViewFromES(review *MyReviewInfo, audience ReviewAudience) *ReviewView {
+	if review == nil {
+		return nil
+	}
+	base := model.ReviewInfo{}
+	if review.ReviewInfo != nil {
+		base = *review.ReviewInfo
+	}
+	// MyReviewInfo 的数值和时间字段覆盖了内嵌结构体中的同名字段, 以外层为准
+	base.ReviewID = review.ReviewID
+	base.OrderID = review.OrderID
+	base.StoreID = review.StoreID
+	base.UserID = review.UserID
+	base.Anonymous = review.Anonymous
+	base.Score = review.Score
+	base.ServiceScore = review.ServiceScore
+	base.ExpressScore = review.ExpressScore
+	base.Status = review.Status
+	base.HasReply = review.HasReply
+	v := NewReviewView(&base, audience)
+	v.Tags = review.Tags
+	v.CreateAt = review.CreateAt
+	v.Appends = review.Appends
+	return v
+}
+
+// NewReviewListView 按读者转换ES评论列表
+func NewReviewListView(list *ReviewList, audience ReviewAudience) []*ReviewView {
+	views := make([]*ReviewView, 0, len(list.List))
+	for _, review := range list.List {
+		views = append(views, NewReviewViewFromES(review, audience))
+	}
+	return views
+}
+
+// ReviewDetail 评论详情, 各部分按读者裁剪, 不可见的部分为空
+type ReviewDetail struct {
+	Review *ReviewView `json:"review"`
+	// Reply 商家回复, 没有回复时为nil
+	Reply *ReplyView `json:"reply,omitempty"`
+	// Appends 追加评论, 按追加顺序排列
+	Appends []*AppendView `json:"appends"`
+	// Appeals 申诉记录, 仅该店铺的商家和审核员可见
+	Appeals []*AppealView `json:"appeals,omitempty"`
+	// AuditTrail 审核记录, 仅审核员可见
+	AuditTrail []*AuditTrailEntry `json:"audit_trail,omitempty"`
+}
+
+// ReplyView 对外返回的商家回复
+type ReplyView struct {
+	ReplyID   int64  `json:"reply_id"`
+	Content   string `json:"content"`
+	PicInfo   string `json:"pic_info"`
+	VideoInfo string `json:"video_info"`
+	CreateAt  MyTime `json:"create_at"`
+}
+
+// NewReplyView 转换商家回复, reply为nil时返回nil
+func NewReplyView(reply *model.ReviewReplyInfo) *ReplyView {
+	if reply == nil {
+		return nil
+	}
+	return &ReplyView{
+		ReplyID:   reply.ReplyID,
+		Content:   reply.Content,
+		PicInfo:   reply.PicInfo,
+		VideoInfo: reply.VideoInfo,
+		CreateAt:  MyTime(reply.CreateAt),
+	}
+}
+
+// AppealView 对外返回的申诉记录
+type AppealView struct {
+	AppealID int64 `json:"appeal_id"`
+	Status   int32 `json:"status"`
+	// Type 申诉类型 hide/reinstate
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Content   string `json:"content"`
+	PicInfo   string `json:"pic_info"`
+	VideoInfo string `json:"video_info"`
+	CreateAt  MyTime `json:"create_at"`
+	// 以下字段仅审核员可见
+	OpUser    string `json:"op_user,omitempty"`
+	OpRemarks string `json:"op_remarks,omitempty"`
+}
+
+// NewAppealViews 按读者转换申诉记录, 处理人和内部备注只对审核员展示
+func NewAppealViews(appeals []*model.ReviewAppealInfo, audience ReviewAudience) []*AppealView {
+	views := make([]*AppealView, 0, len(appeals))
+	for _, a := range appeals {
+		v := &AppealView{
+			AppealID:  a.AppealID,
+			Status:    a.Status,
+			Type:      a.AppealType,
+			Reason:    a.Reason,
+			Content:   a.Content,
+			PicInfo:   a.PicInfo,
+			VideoInfo: a.VideoInfo,
+			CreateAt:  MyTime(a.CreateAt),
+		}
+		if audience == AudienceReviewer {
+			v.OpUser = a.OpUser
+			v.OpRemarks = a.OpRemarks
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+// AuditTrailEntry 评论的一次状态变更
+type AuditTrailEntry struct {
+	FromStatus int32  `json:"from_status"`
+	ToStatus   int32  `json:"to_status"`
+	Source     string `json:"source"`
+	OpUser     string `json:"op_user"`
+	Reason     string `json:"reason"`
+	Category   string `json:"category"`
+	CreateAt   MyTime `json:"create_at"`
+	// 以下字段仅审核员可见
+	Remarks    string  `json:"remarks,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// NewAuditTrailView 按读者转换审核日志, 备注和AI置信度只对审核员展示
+func NewAuditTrailView(logs []*model.ReviewAuditLog, audience ReviewAudience) []*AuditTrailEntry {
+	trail := make([]*AuditTrailEntry, 0, len(logs))
+	for _, l := range logs {
+		e := &AuditTrailEntry{
+			FromStatus: l.FromStatus,
+			ToStatus:   l.ToStatus,
+			Source:     l.Source,
+			OpUser:     l.OpUser,
+			Reason:     l.Reason,
+			Category:   l.Category,
+			CreateAt:   MyTime(l.CreateAt),
+		}
+		if audience == AudienceReviewer {
+			e.Remarks = l.Remarks
+			e.Confidence = l.Confidence
+		}
+		trail = append(trail, e)
+	}
+	return trail
+}
@@ -0,0 +1,77 @@
+package biz
+
+import (
+	"testing"
+
+	"review/internal/data/model"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+func TestNewReviewView(t *testing.T) {
+	review := &model.ReviewInfo{
+		ReviewID:       1,
+		UserID:         5,
+		Anonymous:      1,
+		Content:        "好评",
+		Status:         30,
+		OpReason:       "含有广告",
+		RejectCategory: "广告",
+		OpUser:         "Gemini",
+		OpRemarks:      "internal",
+		ClientIP:       "203.0.113.7",
+		UserAgent:      "curl/8",
+	}
+	tests := []struct {
+		name          string
+		audience      ReviewAudience
+		wantUserID    int64
+		wantReason    bool
+		wantInternals bool
+	}{
+		{name: "customer", audience: AudienceCustomer},
+		{name: "merchant", audience: AudienceMerchant, wantReason: true},
+		{name: "reviewer", audience: AudienceReviewer, wantUserID: 5, wantReason: true, wantInternals: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewReviewView(review, tt.audience)
+			if v.UserID != tt.wantUserID {
+				t.Errorf("UserID = %d, want %d", v.UserID, tt.wantUserID)
+			}
+			m := v.Moderation
+			if (m != nil) != tt.wantReason {
+				t.Fatalf("Moderation = %+v, want present %v", m, tt.wantReason)
+			}
+			if m == nil {
+				return
+			}
+			if m.OpReason != review.OpReason || m.RejectCategory != review.RejectCategory {
+				t.Errorf("Moderation reason = %q %q, want %q %q", m.OpReason, m.RejectCategory, review.OpReason, review.RejectCategory)
+			}
+			if hasInternals := m.OpUser != "" || m.OpRemarks != "" || m.ClientIP != "" || m.UserAgent != ""; hasInternals != tt.wantInternals {
+				t.Errorf("Moderation = %+v, want internal fields %v", m, tt.wantInternals)
+			}
+		})
+	}
+}
+
+func TestAudienceFromContext(t *testing.T) {
+	tests := []struct {
+		role string
+		want ReviewAudience
+	}{
+		{"customer", AudienceCustomer},
+		{"merchant", AudienceMerchant},
+		{"reviewer", AudienceReviewer},
+		{"admin", AudienceReviewer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			claims := jwtv5.MapClaims{"user_id": float64(1), "role": tt.role, "store_id": float64(1)}
+			if got := AudienceFromContext(contextWithClaims(claims)); got != tt.want {
+				t.Errorf("AudienceFromContext() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
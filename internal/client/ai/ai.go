@@ -2,7 +2,10 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"review/internal/conf"
+	"slices"
 	"strings"
 
 	"github.com/tmc/langchaingo/llms"
@@ -10,69 +13,197 @@ import (
 )
 
 type AIClient struct {
-	llm *googleai.GoogleAI
+	// keys 按轮询使用的API Key, 每个Key一个LLM客户端
+	keys    *keyPool
+	limiter *limiter
+	models  map[Purpose]string
+	guide   *guideLoader
+	// routes 按语言选择的审核提示词和模型, 未配置的语言使用 guide
+	routes map[string]*moderationRoute
+	health *healthChecker
+}
+
+// Purpose 调用LLM的用途, 不同用途可以配置不同的模型
+type Purpose int
+
+const (
+	// PurposeModeration 评论审核及申诉建议
+	PurposeModeration Purpose = iota
+	// PurposeAgent 智能助手推理
+	PurposeAgent
+	// PurposeSummary 工具结果总结
+	PurposeSummary
+)
+
+// supportedModels 支持的Gemini模型
+var supportedModels = []string{
+	"gemini-1.5-flash",
+	"gemini-1.5-flash-8b",
+	"gemini-1.5-pro",
+	"gemini-2.0-flash",
+	"gemini-2.0-flash-lite",
+	"gemini-2.5-flash",
+	"gemini-2.5-flash-lite",
+	"gemini-2.5-pro",
 }
 
 func NewAIClient(c *conf.AI) (*AIClient, error) {
-	llm, err := googleai.New(
-		context.Background(),
-		googleai.WithAPIKey(c.ApiKey),
-		googleai.WithDefaultModel(c.Model),
-	)
+	models, err := resolveModels(c)
+	if err != nil {
+		return nil, err
+	}
+	guide, err := newGuideLoader(c.GetModerationGuideFile())
+	if err != nil {
+		return nil, err
+	}
+	routes, err := newModerationRoutes(c, models[PurposeModeration])
 	if err != nil {
 		return nil, err
 	}
-	return &AIClient{llm: llm}, nil
+	keys, err := newKeyPool(c)
+	if err != nil {
+		return nil, err
+	}
+	client := &AIClient{keys: keys, limiter: newLimiter(c), models: models, guide: guide, routes: routes}
+	client.health = &healthChecker{probe: client.ping}
+	return client, nil
 }
 
-// GetLLM 获取LLM实例
-func (c *AIClient) GetLLM() *googleai.GoogleAI {
-	return c.llm
+// resolveModels 确定每种用途使用的模型, 未单独配置时使用 c.Model, 并校验模型是否受支持
+func resolveModels(c *conf.AI) (map[Purpose]string, error) {
+	models := map[Purpose]string{
+		PurposeModeration: c.GetModerationModel(),
+		PurposeAgent:      c.GetAgentModel(),
+		PurposeSummary:    c.GetSummaryModel(),
+	}
+	if !slices.Contains(supportedModels, c.GetModel()) {
+		return nil, fmt.Errorf("ai.model %q is not supported, must be one of %v", c.GetModel(), supportedModels)
+	}
+	for p, m := range models {
+		if m == "" {
+			models[p] = c.GetModel()
+			continue
+		}
+		if !slices.Contains(supportedModels, m) {
+			return nil, fmt.Errorf("ai model %q is not supported, must be one of %v", m, supportedModels)
+		}
+	}
+	return models, nil
 }
 
-// ModerateText 使用LLM审核文本内容
-// 返回值: is_approved, reason
-func (c *AIClient) ModerateText(ctx context.Context, text string) (bool, string, error) {
-	prompt := `你是一个严格的内容审核员。你的任务是判断给定的评论是否包含不当内容。
+// Model 返回该用途使用的模型
+func (c *AIClient) Model(p Purpose) string {
+	return c.models[p]
+}
 
-不当内容主要分为以下几类：
-- 辱骂：包含人身攻击、侮辱性言论或粗俗语言。
-- 广告：推广产品、服务或网站，包含链接或联系方式。
-- 垃圾信息：无意义的字符、重复文本或与主题无关的内容。
-- 色情：涉及露骨的性描述或性暗示。
-- 暴力：宣扬、描述或鼓励暴力行为。
-- 其他：包含不当内容，如政治敏感话题、宗教敏感话题、种族歧视、性别歧视、地域歧视等。
+// GetLLM 获取第一个API Key的LLM实例
+// 直接使用LLM实例不受全局限流约束, 也不参与Key轮询, 业务调用应使用 Generate
+func (c *AIClient) GetLLM() *googleai.GoogleAI {
+	return c.keys.first().llm
+}
 
-你的输出必须严格遵循以下格式：
-- 如果评论内容得当，只回答“是”。
-- 如果评论内容不当，回答“否”，然后紧跟一个冒号“：”，并用一句话简要说明理由。
+// Generate 使用该用途配置的模型, 在全局并发/QPS限制内调用LLM生成回复, 超出限制且排队超时返回 ErrOverloaded
+func (c *AIClient) Generate(ctx context.Context, p Purpose, prompt string) (string, error) {
+	return c.generate(ctx, c.Model(p), prompt)
+}
 
-示例 1:
-[评论内容]: "这个产品真是太棒了，强烈推荐！"
-你的回答: 是
+// generate 在全局限流内使用指定模型调用LLM
+func (c *AIClient) generate(ctx context.Context, model, prompt string) (string, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	var completion string
+	err = c.keys.do(ctx, func(llm *googleai.GoogleAI) error {
+		var err error
+		completion, err = llms.GenerateFromSinglePrompt(ctx, llm, prompt, llms.WithModel(model))
+		return err
+	})
+	return completion, err
+}
 
-示例 2:
-[评论内容]: "想赚钱吗？快来加我VX: 123456"
-你的回答: 否：包含广告和联系方式。
+// ModerationResult AI审核结果
+type ModerationResult struct {
+	Approved bool
+	// Category 违规类别, 如 辱骂/广告/垃圾信息/色情/暴力/其他, 通过时为空
+	Category string
+	Reason   string
+	// Confidence 置信度 0~1, 模型未给出时为0
+	Confidence float64
+	// Language 评论语言, 如 zh/en, 模型未给出时为本地检测的语言
+	Language string
+	// Template 使用的审核提示词, 即 ai.moderation_routes 中的语言代码或 DefaultModerationTemplate
+	Template string
+}
 
-示例 3:
-[评论内容]: "方却无法前期亲子课女郎尾气污染"
-你的回答: 否：包含垃圾信息。
+// ModerationErrorReason 调用模型失败或无法解析模型输出时审核结果的原因, 此时的结论不可信
+const ModerationErrorReason = "AI content moderation service error"
 
-现在，请审核以下评论：
-[评论内容]: "` + text + `"`
+// moderationReply LLM按约定返回的JSON结构
+type moderationReply struct {
+	Approved   bool    `json:"approved"`
+	Category   string  `json:"category"`
+	Reason     string  `json:"reason"`
+	Confidence float64 `json:"confidence"`
+	Language   string  `json:"language"`
+}
 
-	completion, err := llms.GenerateFromSinglePrompt(ctx, c.llm, prompt)
+// Moderate 使用LLM审核文本内容, 返回结构化的审核结果
+// 先在本地检测文本语言, 按 ai.moderation_routes 选择该语言的审核提示词和模型
+func (c *AIClient) Moderate(ctx context.Context, text string) (*ModerationResult, error) {
+	lang := DetectLanguage(text)
+	route := c.moderationRoute(lang)
+	completion, err := c.generate(ctx, route.model, route.guide.Prompt()+text+`"`)
 	if err != nil {
-		return false, "AI content moderation service error", err
+		return &ModerationResult{Reason: ModerationErrorReason, Language: lang, Template: route.template}, err
+	}
+	res := parseModeration(completion)
+	if res.Language == "" {
+		res.Language = lang
+	}
+	res.Template = route.template
+	return res, nil
+}
+
+// parseModeration 解析LLM的审核输出
+// 优先按JSON解析; 模型未遵循JSON格式时兼容旧的"是/否：理由"格式; 都无法解析时按不通过处理
+func parseModeration(completion string) *ModerationResult {
+	completion = strings.TrimSpace(completion)
+	// 去掉模型可能附带的代码块标记
+	completion = strings.TrimPrefix(completion, "```json")
+	completion = strings.TrimPrefix(completion, "```")
+	completion = strings.TrimSuffix(completion, "```")
+	completion = strings.TrimSpace(completion)
+
+	var reply moderationReply
+	if err := json.Unmarshal([]byte(completion), &reply); err == nil {
+		res := &ModerationResult{
+			Approved:   reply.Approved,
+			Category:   reply.Category,
+			Reason:     reply.Reason,
+			Confidence: reply.Confidence,
+			Language:   reply.Language,
+		}
+		if res.Confidence < 0 || res.Confidence > 1 {
+			res.Confidence = 0
+		}
+		if res.Approved {
+			res.Category = ""
+			if res.Reason == "" {
+				res.Reason = "Content approved by AI."
+			}
+		} else if res.Reason == "" {
+			res.Reason = "内容不当，但未提供具体理由。"
+		}
+		return res
 	}
 
-	// 这里需要添加解析AI返回结果的逻辑
-	// 为简化起见，我们假设如果返回以"是"开头则为通过
+	// 兼容旧格式: 以"是"开头为通过
 	if strings.HasPrefix(completion, "是") {
-		return true, "Content approved by AI.", nil
+		return &ModerationResult{Approved: true, Reason: "Content approved by AI."}
 	}
-	// 如果以"否"开头，提取并返回后面的理由
+	// 如果以"否"开头，提取后面的理由
 	if strings.HasPrefix(completion, "否") {
 		reason := strings.TrimSpace(strings.TrimPrefix(completion, "否"))
 		// 移除可能的前缀，如冒号或逗号
@@ -84,7 +215,16 @@ func (c *AIClient) ModerateText(ctx context.Context, text string) (bool, string,
 		if reason == "" {
 			reason = "内容不当，但未提供具体理由。"
 		}
-		return false, reason, nil
+		return &ModerationResult{Reason: reason}
 	}
-	return false, "AI content moderation service error", nil
+	return &ModerationResult{Reason: ModerationErrorReason}
+}
+
+// ModerateText 使用LLM审核文本内容
+// 返回值: is_approved, reason
+//
+// Deprecated: 使用 Moderate 获取包含类别、置信度和语言的完整审核结果。
+func (c *AIClient) ModerateText(ctx context.Context, text string) (bool, string, error) {
+	res, err := c.Moderate(ctx, text)
+	return res.Approved, res.Reason, err
 }
@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"review/internal/conf"
+)
+
+func TestParseModeration(t *testing.T) {
+	tests := []struct {
+		name       string
+		completion string
+		want       *ModerationResult
+	}{
+		{
+			name:       "json approved",
+			completion: `{"approved":true,"category":"广告","reason":"","confidence":0.9,"language":"zh"}`,
+			want:       &ModerationResult{Approved: true, Reason: "Content approved by AI.", Confidence: 0.9, Language: "zh"},
+		},
+		{
+			name:       "json rejected in a code fence",
+			completion: "```json\n{\"approved\":false,\"category\":\"辱骂\",\"reason\":\"含有辱骂\",\"confidence\":0.8}\n```",
+			want:       &ModerationResult{Category: "辱骂", Reason: "含有辱骂", Confidence: 0.8},
+		},
+		{
+			name:       "json rejected without reason",
+			completion: `{"approved":false,"category":"其他"}`,
+			want:       &ModerationResult{Category: "其他", Reason: "内容不当，但未提供具体理由。"},
+		},
+		{
+			name:       "out of range confidence",
+			completion: `{"approved":true,"reason":"ok","confidence":7}`,
+			want:       &ModerationResult{Approved: true, Reason: "ok"},
+		},
+		{name: "legacy approved", completion: "是", want: &ModerationResult{Approved: true, Reason: "Content approved by AI."}},
+		{name: "legacy rejected", completion: "否：含有广告", want: &ModerationResult{Reason: "含有广告"}},
+		{name: "legacy rejected without reason", completion: "否", want: &ModerationResult{Reason: "内容不当，但未提供具体理由。"}},
+		{name: "unparsable", completion: "I cannot decide", want: &ModerationResult{Reason: ModerationErrorReason}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseModeration(tt.completion); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseModeration(%q) = %+v, want %+v", tt.completion, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveModels(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       *conf.AI
+		want    map[Purpose]string
+		wantErr bool
+	}{
+		{
+			name: "default model for every purpose",
+			c:    &conf.AI{Model: "gemini-1.5-flash"},
+			want: map[Purpose]string{PurposeModeration: "gemini-1.5-flash", PurposeAgent: "gemini-1.5-flash", PurposeSummary: "gemini-1.5-flash"},
+		},
+		{
+			name: "separate agent model",
+			c:    &conf.AI{Model: "gemini-1.5-flash", AgentModel: "gemini-1.5-pro"},
+			want: map[Purpose]string{PurposeModeration: "gemini-1.5-flash", PurposeAgent: "gemini-1.5-pro", PurposeSummary: "gemini-1.5-flash"},
+		},
+		{name: "unsupported default", c: &conf.AI{Model: "gpt-4"}, wantErr: true},
+		{name: "unsupported purpose model", c: &conf.AI{Model: "gemini-1.5-flash", SummaryModel: "gpt-4"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveModels(tt.c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveModels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveModels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package ai
+
+import "testing"
+
+func TestParseAppealRecommendation(t *testing.T) {
+	tests := []struct {
+		name       string
+		completion string
+		want       string
+		wantErr    bool
+	}{
+		{name: "uphold", completion: `{"decision":"uphold","rationale":"真实体验"}`, want: AppealUphold},
+		{name: "overturn in a code fence", completion: "```json\n{\"decision\":\"overturn\",\"rationale\":\"广告\"}\n```", want: AppealOverturn},
+		{name: "unknown decision", completion: `{"decision":"maybe"}`, wantErr: true},
+		{name: "not JSON", completion: "支持申诉", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := parseAppealRecommendation(tt.completion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAppealRecommendation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && rec.Decision != tt.want {
+				t.Errorf("Decision = %q, want %q", rec.Decision, tt.want)
+			}
+		})
+	}
+}
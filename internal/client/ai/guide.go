@@ -0,0 +1,234 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// ModerationGuide 审核提示词中的违规类别定义和示例
+// 可以通过 ai.moderation_guide_file 指定JSON文件替换内置的默认值, 文件修改后在下一次审核时自动重新加载
+type ModerationGuide struct {
+	Categories []ModerationCategory `json:"categories"`
+	Examples   []ModerationExample  `json:"examples"`
+}
+
+// ModerationCategory 违规类别
+type ModerationCategory struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ModerationExample 审核示例, 即一条评论及期望的审核结论
+type ModerationExample struct {
+	Content    string  `json:"content"`
+	Approved   bool    `json:"approved"`
+	Category   string  `json:"category"`
+	Reason     string  `json:"reason"`
+	Confidence float64 `json:"confidence"`
+	Language   string  `json:"language"`
+}
+
+// defaultModerationGuide 内置的违规类别和示例
+var defaultModerationGuide = &ModerationGuide{
+	Categories: []ModerationCategory{
+		{Name: "辱骂", Description: "包含人身攻击、侮辱性言论或粗俗语言。"},
+		{Name: "广告", Description: "推广产品、服务或网站，包含链接或联系方式。"},
+		{Name: "垃圾信息", Description: "无意义的字符、重复文本或与主题无关的内容。"},
+		{Name: "色情", Description: "涉及露骨的性描述或性暗示。"},
+		{Name: "暴力", Description: "宣扬、描述或鼓励暴力行为。"},
+		{Name: "其他", Description: "包含不当内容，如政治敏感话题、宗教敏感话题、种族歧视、性别歧视、地域歧视等。"},
+	},
+	Examples: []ModerationExample{
+		{Content: "这个产品真是太棒了，强烈推荐！", Approved: true, Reason: "内容正常。", Confidence: 0.98, Language: "zh"},
+		{Content: "想赚钱吗？快来加我VX: 123456", Category: "广告", Reason: "包含广告和联系方式。", Confidence: 0.95, Language: "zh"},
+		{Content: "方却无法前期亲子课女郎尾气污染", Category: "垃圾信息", Reason: "包含垃圾信息。", Confidence: 0.9, Language: "zh"},
+	},
+}
+
+// moderationTemplate 审核提示词模板, 待审核的评论内容拼接在末尾
+var moderationTemplate = template.Must(template.New("moderation").Parse(`你是一个严格的内容审核员。你的任务是判断给定的评论是否包含不当内容。
+
+不当内容主要分为以下几类：
+{{range .Categories}}- {{.Name}}：{{.Description}}
+{{end}}
+你的输出必须是一个JSON对象，不要包含任何其他文字或代码块标记，字段如下：
+- approved: 布尔值，评论内容得当为 true，否则为 false。
+- category: 违规类别，取值为上面的类别名称之一；内容得当时为空字符串。
+- reason: 用一句话简要说明理由。
+- confidence: 0 到 1 之间的小数，表示你对判断的把握程度。
+- language: 评论所用语言的 ISO 639-1 代码，如 zh、en。
+
+{{range .Examples}}示例 {{.N}}:
+[评论内容]: "{{.Content}}"
+你的回答: {{.Reply}}
+
+{{end}}现在，请审核以下评论：
+[评论内容]: "`))
+
+// renderedExample 模板中的一条示例
+type renderedExample struct {
+	N       int
+	Content string
+	Reply   string
+}
+
+// Validate 校验至少有一个类别和一个示例, 且示例的类别都已定义
+func (g *ModerationGuide) Validate() error {
+	if len(g.Categories) == 0 {
+		return fmt.Errorf("moderation guide must define at least one category")
+	}
+	if len(g.Examples) == 0 {
+		return fmt.Errorf("moderation guide must contain at least one example")
+	}
+	names := make([]string, 0, len(g.Categories))
+	for _, c := range g.Categories {
+		if c.Name == "" {
+			return fmt.Errorf("moderation guide category name must not be empty")
+		}
+		names = append(names, c.Name)
+	}
+	for i, e := range g.Examples {
+		if e.Content == "" {
+			return fmt.Errorf("moderation guide example %d has empty content", i+1)
+		}
+		if e.Approved && e.Category != "" {
+			return fmt.Errorf("moderation guide example %d is approved but has category %q", i+1, e.Category)
+		}
+		if !e.Approved && !slices.Contains(names, e.Category) {
+			return fmt.Errorf("moderation guide example %d has unknown category %q", i+1, e.Category)
+		}
+	}
+	return nil
+}
+
+func (g *ModerationGuide) categoryNames() []string {
+	names := make([]string, 0, len(g.Categories))
+	for _, c := range g.Categories {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// Render 将类别和示例渲染为审核提示词
+func (g *ModerationGuide) Render() (string, error) {
+	examples := make([]renderedExample, 0, len(g.Examples))
+	for i, e := range g.Examples {
+		reply, err := json.Marshal(moderationReply{
+			Approved:   e.Approved,
+			Category:   e.Category,
+			Reason:     e.Reason,
+			Confidence: e.Confidence,
+			Language:   e.Language,
+		})
+		if err != nil {
+			return "", err
+		}
+		examples = append(examples, renderedExample{N: i + 1, Content: e.Content, Reply: string(reply)})
+	}
+	var buf bytes.Buffer
+	err := moderationTemplate.Execute(&buf, struct {
+		Categories []ModerationCategory
+		Examples   []renderedExample
+	}{g.Categories, examples})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// guideLoader 缓存渲染好的审核提示词, 配置了文件时按修改时间重新加载
+type guideLoader struct {
+	path string
+
+	mu      sync.RWMutex
+	modTime time.Time
+	prompt  string
+	// categories 当前提示词中的违规类别名称
+	categories []string
+}
+
+// newGuideLoader 加载并校验审核提示词, path为空时使用内置的默认值
+func newGuideLoader(path string) (*guideLoader, error) {
+	l := &guideLoader{path: path}
+	if path == "" {
+		prompt, err := defaultModerationGuide.Render()
+		if err != nil {
+			return nil, err
+		}
+		l.prompt = prompt
+		l.categories = defaultModerationGuide.categoryNames()
+		return l, nil
+	}
+	if err := l.reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Prompt 返回当前的审核提示词
+// 文件被修改时重新加载; 新文件无效时记录日志并继续使用上一次加载成功的提示词
+func (l *guideLoader) Prompt() string {
+	if l.path != "" {
+		if fi, err := os.Stat(l.path); err == nil && l.changed(fi.ModTime()) {
+			if err := l.reload(); err != nil {
+				log.Warnf("reload moderation guide %s failed, keep the previous one: %v", l.path, err)
+			}
+		}
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.prompt
+}
+
+func (l *guideLoader) changed(modTime time.Time) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return !modTime.Equal(l.modTime)
+}
+
+// reload 重新加载文件; 无论成功与否都记录修改时间, 无效的文件在再次修改前不会重复加载
+func (l *guideLoader) reload() error {
+	fi, err := os.Stat(l.path)
+	if err != nil {
+		return fmt.Errorf("read moderation guide: %w", err)
+	}
+	l.mu.Lock()
+	l.modTime = fi.ModTime()
+	l.mu.Unlock()
+	b, err := os.ReadFile(l.path)
+	if err != nil {
+		return fmt.Errorf("read moderation guide: %w", err)
+	}
+	var g ModerationGuide
+	if err := json.Unmarshal(b, &g); err != nil {
+		return fmt.Errorf("parse moderation guide %s: %w", l.path, err)
+	}
+	if err := g.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", l.path, err)
+	}
+	prompt, err := g.Render()
+	if err != nil {
+		return fmt.Errorf("render moderation guide %s: %w", l.path, err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prompt = prompt
+	l.categories = g.categoryNames()
+	return nil
+}
+
+// Categories 返回当前提示词中的违规类别名称, 与 Prompt 一样在文件被修改时重新加载
+func (l *guideLoader) Categories() []string {
+	l.Prompt()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clone(l.categories)
+}
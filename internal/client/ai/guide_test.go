@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestModerationGuideValidate(t *testing.T) {
+	cats := []ModerationCategory{{Name: "广告"}}
+	tests := []struct {
+		name    string
+		g       ModerationGuide
+		wantErr bool
+	}{
+		{name: "default", g: *defaultModerationGuide},
+		{name: "no categories", g: ModerationGuide{Examples: []ModerationExample{{Content: "x", Approved: true}}}, wantErr: true},
+		{name: "no examples", g: ModerationGuide{Categories: cats}, wantErr: true},
+		{name: "empty category name", g: ModerationGuide{Categories: []ModerationCategory{{}}, Examples: []ModerationExample{{Content: "x", Approved: true}}}, wantErr: true},
+		{name: "empty example", g: ModerationGuide{Categories: cats, Examples: []ModerationExample{{Approved: true}}}, wantErr: true},
+		{name: "approved with category", g: ModerationGuide{Categories: cats, Examples: []ModerationExample{{Content: "x", Approved: true, Category: "广告"}}}, wantErr: true},
+		{name: "unknown category", g: ModerationGuide{Categories: cats, Examples: []ModerationExample{{Content: "x", Category: "色情"}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.g.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGuideLoaderReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guide.json")
+	write := func(text string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		// set the time explicitly so a rewrite within the filesystem's timestamp granularity is still seen
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	write(`{"categories":[{"name":"广告"}],"examples":[{"content":"加我VX","category":"广告"}]}`, now)
+
+	l, err := newGuideLoader(path)
+	if err != nil {
+		t.Fatalf("newGuideLoader() error = %v", err)
+	}
+	if !strings.Contains(l.Prompt(), "加我VX") {
+		t.Errorf("Prompt() does not contain the file's example")
+	}
+
+	write(`{"categories":[{"name":"辱骂"}],"examples":[{"content":"骂人","category":"辱骂"}]}`, now.Add(time.Second))
+	if got := l.Categories(); len(got) != 1 || got[0] != "辱骂" {
+		t.Errorf("Categories() after a change = %v, want [辱骂]", got)
+	}
+
+	// an invalid file keeps the previous prompt
+	write(`{"categories":[]}`, now.Add(2*time.Second))
+	if !strings.Contains(l.Prompt(), "骂人") {
+		t.Errorf("Prompt() after an invalid change does not keep the previous guide")
+	}
+}
+
+func TestNewGuideLoaderRejectsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guide.json")
+	if err := os.WriteFile(path, []byte(`not json`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newGuideLoader(path); err == nil {
+		t.Error("newGuideLoader() with an invalid file = nil error, want an error")
+	}
+}
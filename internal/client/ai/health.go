@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"context"
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/googleai"
+)
+
+const (
+	// healthTimeout 健康检查调用的超时时间
+	healthTimeout = 5 * time.Second
+	// healthTTL 健康检查结果的缓存时间, 避免探针频繁调用Gemini
+	healthTTL = 30 * time.Second
+)
+
+// aiHealthy AI服务最近一次健康检查的结果, 1 可用, 0 不可用, 通过 /debug/vars 暴露
+var aiHealthy = expvar.NewInt("ai_healthy")
+
+// healthChecker 缓存AI服务的健康检查结果
+type healthChecker struct {
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+	// probe 执行一次检查, 便于替换
+	probe func(ctx context.Context) error
+}
+
+// Health 检查AI服务是否可用, 如API Key错误或服务不可达时返回错误
+// 结果缓存 healthTTL; 检查调用不占用业务调用的限流配额
+func (c *AIClient) Health(ctx context.Context) error {
+	return c.health.check(ctx)
+}
+
+// ping 发起一次最小的生成请求
+func (c *AIClient) ping(ctx context.Context) error {
+	return c.keys.do(ctx, func(llm *googleai.GoogleAI) error {
+		_, err := llms.GenerateFromSinglePrompt(ctx, llm, "ping",
+			llms.WithModel(c.Model(PurposeModeration)),
+			llms.WithMaxTokens(1),
+		)
+		return err
+	})
+}
+
+func (h *healthChecker) check(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.checkedAt.IsZero() && time.Since(h.checkedAt) < healthTTL {
+		return h.err
+	}
+	// 结果会被缓存, 不跟随调用方取消, 避免探针断开时缓存一个错误结果
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), healthTimeout)
+	defer cancel()
+	h.err = h.probe(ctx)
+	h.checkedAt = time.Now()
+	if h.err != nil {
+		aiHealthy.Set(0)
+	} else {
+		aiHealthy.Set(1)
+	}
+	return h.err
+}
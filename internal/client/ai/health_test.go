@@ -0,0 +1,55 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthChecker(t *testing.T) {
+	tests := []struct {
+		name        string
+		probeErr    error
+		wantHealthy int64
+	}{
+		{name: "healthy", wantHealthy: 1},
+		{name: "bad api key", probeErr: errors.New("API key not valid"), wantHealthy: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			h := &healthChecker{probe: func(context.Context) error {
+				calls++
+				return tt.probeErr
+			}}
+			for i := 0; i < 3; i++ {
+				if err := h.check(context.Background()); !errors.Is(err, tt.probeErr) {
+					t.Fatalf("check() error = %v, want %v", err, tt.probeErr)
+				}
+			}
+			if calls != 1 {
+				t.Errorf("probe called %d times, want 1 within the cache TTL", calls)
+			}
+			if got := aiHealthy.Value(); got != tt.wantHealthy {
+				t.Errorf("ai_healthy = %d, want %d", got, tt.wantHealthy)
+			}
+
+			// Once the cached result expires the provider is probed again.
+			h.checkedAt = time.Now().Add(-healthTTL)
+			_ = h.check(context.Background())
+			if calls != 2 {
+				t.Errorf("probe called %d times after the TTL, want 2", calls)
+			}
+		})
+	}
+}
+
+func TestHealthCheckerIgnoresCallerCancel(t *testing.T) {
+	h := &healthChecker{probe: func(ctx context.Context) error { return ctx.Err() }}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := h.check(ctx); err != nil {
+		t.Errorf("check() with a canceled caller = %v, want the probe to run with its own deadline", err)
+	}
+}
@@ -0,0 +1,175 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"review/internal/conf"
+
+	"github.com/tmc/langchaingo/llms/googleai"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultKeyCooldown 未配置 ai.key_cooldown 时API Key被暂停使用的时间
+	defaultKeyCooldown = time.Minute
+	// keyFailureThreshold 连续失败达到该次数的Key也会被暂停使用
+	keyFailureThreshold = 5
+)
+
+// ErrNoAPIKey 配置中没有可用的API Key
+var ErrNoAPIKey = errors.New("ai: no api key configured")
+
+// 每个API Key的调用次数和失败次数, 按Key的序号(配置中的位置, 从0开始)统计, 通过 /debug/vars 暴露
+var (
+	aiKeyRequests = expvar.NewMap("ai_key_requests")
+	aiKeyFailures = expvar.NewMap("ai_key_failures")
+)
+
+// apiKey 一个API Key及其LLM客户端
+type apiKey struct {
+	name string // 序号, 用于日志和指标, 不输出Key本身
+	llm  *googleai.GoogleAI
+
+	mu sync.Mutex
+	// failures 连续失败次数, 成功一次后清零
+	failures int
+	// sidelinedUntil 在此之前不再选用该Key
+	sidelinedUntil time.Time
+}
+
+// keyPool 按轮询选用API Key; 配额错误或连续失败的Key暂停使用一段时间, 期间由其它Key承担调用
+type keyPool struct {
+	keys     []*apiKey
+	cooldown time.Duration
+
+	mu   sync.Mutex
+	next int
+}
+
+// configuredKeys 返回 ai.api_keys, 未配置时使用 ai.api_key, 忽略空值和重复的Key
+func configuredKeys(c *conf.AI) []string {
+	keys := c.GetApiKeys()
+	if len(keys) == 0 {
+		keys = []string{c.GetApiKey()}
+	}
+	seen := make(map[string]bool, len(keys))
+	res := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k = strings.TrimSpace(k); k != "" && !seen[k] {
+			seen[k] = true
+			res = append(res, k)
+		}
+	}
+	return res
+}
+
+// newKeyPool 为每个API Key创建一个LLM客户端
+func newKeyPool(c *conf.AI) (*keyPool, error) {
+	keys := configuredKeys(c)
+	if len(keys) == 0 {
+		return nil, ErrNoAPIKey
+	}
+	p := &keyPool{cooldown: c.GetKeyCooldown().AsDuration()}
+	if p.cooldown <= 0 {
+		p.cooldown = defaultKeyCooldown
+	}
+	for i, k := range keys {
+		llm, err := googleai.New(
+			context.Background(),
+			googleai.WithAPIKey(k),
+			googleai.WithDefaultModel(c.Model),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ai api key #%d: %w", i, err)
+		}
+		p.keys = append(p.keys, &apiKey{name: strconv.Itoa(i), llm: llm})
+	}
+	return p, nil
+}
+
+// first 返回第一个Key, 用于健康检查等不参与轮询的调用
+func (p *keyPool) first() *apiKey {
+	return p.keys[0]
+}
+
+// candidates 按轮询顺序返回本次调用依次尝试的Key, 暂停中的Key排在最后
+// 全部Key都在暂停时仍按顺序尝试, 不因为暂停而直接拒绝调用
+func (p *keyPool) candidates() []*apiKey {
+	p.mu.Lock()
+	start := p.next
+	p.next = (p.next + 1) % len(p.keys)
+	p.mu.Unlock()
+
+	now := time.Now()
+	active := make([]*apiKey, 0, len(p.keys))
+	var sidelined []*apiKey
+	for i := range p.keys {
+		k := p.keys[(start+i)%len(p.keys)]
+		if k.sidelined(now) {
+			sidelined = append(sidelined, k)
+		} else {
+			active = append(active, k)
+		}
+	}
+	return append(active, sidelined...)
+}
+
+// do 依次使用候选Key调用call, 遇到配额错误时换下一个Key重试, 其它错误直接返回
+func (p *keyPool) do(ctx context.Context, call func(llm *googleai.GoogleAI) error) error {
+	var err error
+	for _, k := range p.candidates() {
+		aiKeyRequests.Add(k.name, 1)
+		err = call(k.llm)
+		if err == nil {
+			k.succeeded()
+			return nil
+		}
+		aiKeyFailures.Add(k.name, 1)
+		quota := isQuotaError(err)
+		k.failed(quota, p.cooldown)
+		if !quota || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (k *apiKey) sidelined(now time.Time) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return now.Before(k.sidelinedUntil)
+}
+
+func (k *apiKey) succeeded() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.failures = 0
+	k.sidelinedUntil = time.Time{}
+}
+
+// failed 记录一次失败, 配额错误或连续失败达到阈值时暂停使用该Key
+func (k *apiKey) failed(quota bool, cooldown time.Duration) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.failures++
+	if quota || k.failures >= keyFailureThreshold {
+		k.sidelinedUntil = time.Now().Add(cooldown)
+	}
+}
+
+// isQuotaError 判断是否为配额/限流错误(HTTP 429, gRPC RESOURCE_EXHAUSTED)
+func isQuotaError(err error) bool {
+	if s, ok := status.FromError(err); ok && s.Code() == codes.ResourceExhausted {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "429") || strings.Contains(msg, "RESOURCE_EXHAUSTED") || strings.Contains(strings.ToLower(msg), "quota")
+}
@@ -0,0 +1,148 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"review/internal/conf"
+
+	"github.com/tmc/langchaingo/llms/googleai"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConfiguredKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		c    *conf.AI
+		want []string
+	}{
+		{name: "single key", c: &conf.AI{ApiKey: "a"}, want: []string{"a"}},
+		{name: "list wins", c: &conf.AI{ApiKey: "a", ApiKeys: []string{"b", "c"}}, want: []string{"b", "c"}},
+		{name: "blank and duplicate keys dropped", c: &conf.AI{ApiKeys: []string{" b ", "", "c", "b"}}, want: []string{"b", "c"}},
+		{name: "none", c: &conf.AI{}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := configuredKeys(tt.c); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("configuredKeys() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsQuotaError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "grpc resource exhausted", err: status.Error(codes.ResourceExhausted, "slow down"), want: true},
+		{name: "http 429", err: errors.New("googleapi: Error 429: Too Many Requests"), want: true},
+		{name: "quota message", err: errors.New("Quota exceeded for aiplatform"), want: true},
+		{name: "invalid key", err: status.Error(codes.PermissionDenied, "API key not valid"), want: false},
+		{name: "timeout", err: context.DeadlineExceeded, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isQuotaError(tt.err); got != tt.want {
+				t.Errorf("isQuotaError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestKeyPool returns a pool of n keys whose LLM clients are told apart by pointer.
+func newTestKeyPool(n int) *keyPool {
+	p := &keyPool{cooldown: time.Minute}
+	for i := 0; i < n; i++ {
+		p.keys = append(p.keys, &apiKey{name: strconv.Itoa(i), llm: &googleai.GoogleAI{}})
+	}
+	return p
+}
+
+// keyIndex returns which of p's keys llm belongs to.
+func keyIndex(p *keyPool, llm *googleai.GoogleAI) int {
+	for i, k := range p.keys {
+		if k.llm == llm {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestKeyPoolFailover(t *testing.T) {
+	quota := status.Error(codes.ResourceExhausted, "quota")
+	tests := []struct {
+		name string
+		// errs is the error returned by each key, nil for success
+		errs      []error
+		wantTried []int
+		wantErr   error
+	}{
+		{name: "first key works", errs: []error{nil, nil, nil}, wantTried: []int{0}},
+		{name: "quota fails over", errs: []error{quota, nil, nil}, wantTried: []int{0, 1}},
+		{name: "all keys exhausted", errs: []error{quota, quota, quota}, wantTried: []int{0, 1, 2}, wantErr: quota},
+		{name: "other errors are not retried", errs: []error{errors.New("bad request"), nil, nil}, wantTried: []int{0}, wantErr: errors.New("bad request")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestKeyPool(len(tt.errs))
+			var tried []int
+			err := p.do(context.Background(), func(llm *googleai.GoogleAI) error {
+				i := keyIndex(p, llm)
+				tried = append(tried, i)
+				return tt.errs[i]
+			})
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Fatalf("do() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tried, tt.wantTried) {
+				t.Errorf("keys tried = %v, want %v", tried, tt.wantTried)
+			}
+		})
+	}
+}
+
+func TestKeyPoolSidelinesExhaustedKey(t *testing.T) {
+	p := newTestKeyPool(2)
+	quota := status.Error(codes.ResourceExhausted, "quota")
+	call := func(tried *[]int) func(*googleai.GoogleAI) error {
+		return func(llm *googleai.GoogleAI) error {
+			i := keyIndex(p, llm)
+			*tried = append(*tried, i)
+			if i == 0 {
+				return quota
+			}
+			return nil
+		}
+	}
+	var first, second []int
+	if err := p.do(context.Background(), call(&first)); err != nil {
+		t.Fatal(err)
+	}
+	// The second call starts at key 1; the third would start at key 0, but a sidelined key goes last.
+	if err := p.do(context.Background(), call(&second)); err != nil {
+		t.Fatal(err)
+	}
+	var third []int
+	if err := p.do(context.Background(), call(&third)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, []int{0, 1}) || !reflect.DeepEqual(second, []int{1}) || !reflect.DeepEqual(third, []int{1}) {
+		t.Errorf("keys tried = %v, %v, %v, want [0 1], [1], [1] while key 0 cools down", first, second, third)
+	}
+
+	// After the cooldown the key is back in the rotation.
+	p.keys[0].sidelinedUntil = time.Now().Add(-time.Second)
+	p.next = 0
+	var fourth []int
+	_ = p.do(context.Background(), call(&fourth))
+	if len(fourth) == 0 || fourth[0] != 0 {
+		t.Errorf("keys tried after cooldown = %v, want key 0 first", fourth)
+	}
+}
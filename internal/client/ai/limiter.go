@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"time"
+
+	"review/internal/conf"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrOverloaded AI调用超出全局并发或QPS上限且在排队时间内未获得配额
+var ErrOverloaded = errors.New("ai: too many requests in flight, try again later")
+
+// AI调用限流指标, 通过 /debug/vars 暴露
+var (
+	aiInFlight = expvar.NewInt("ai_requests_in_flight")
+	aiRejected = expvar.NewInt("ai_requests_rejected")
+)
+
+// limiter 全局AI调用限流: 并发数上限 + QPS上限, 超出时排队等待, 超过queue_timeout则拒绝
+type limiter struct {
+	slots        chan struct{} // nil 表示不限制并发
+	rate         *rate.Limiter // nil 表示不限制QPS
+	queueTimeout time.Duration
+}
+
+func newLimiter(c *conf.AI) *limiter {
+	l := &limiter{}
+	if n := c.GetMaxInFlight(); n > 0 {
+		l.slots = make(chan struct{}, n)
+	}
+	if qps := c.GetMaxQps(); qps > 0 {
+		burst := int(qps)
+		if burst < 1 {
+			burst = 1
+		}
+		l.rate = rate.NewLimiter(rate.Limit(qps), burst)
+	}
+	if c.GetQueueTimeout() != nil {
+		l.queueTimeout = c.GetQueueTimeout().AsDuration()
+	}
+	return l
+}
+
+// acquire 等待获得调用配额, 成功后必须调用返回的release
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	waitCtx := ctx
+	if l.queueTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, l.queueTimeout)
+		defer cancel()
+	}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-waitCtx.Done():
+			return nil, l.reject(ctx)
+		}
+	}
+	release := func() {
+		aiInFlight.Add(-1)
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+	aiInFlight.Add(1)
+	if l.rate != nil {
+		if err := l.rate.Wait(waitCtx); err != nil {
+			release()
+			return nil, l.reject(ctx)
+		}
+	}
+	return release, nil
+}
+
+// reject 调用方上下文已结束时返回其错误, 否则视为排队超时
+func (l *limiter) reject(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	aiRejected.Add(1)
+	return ErrOverloaded
+}
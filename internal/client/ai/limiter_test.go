@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"review/internal/conf"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestLimiterInFlight(t *testing.T) {
+	l := newLimiter(&conf.AI{MaxInFlight: 1, QueueTimeout: durationpb.New(20 * time.Millisecond)})
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first acquire() error = %v", err)
+	}
+
+	if _, err := l.acquire(context.Background()); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("acquire() while full error = %v, want %v", err, ErrOverloaded)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("acquire() with a canceled caller error = %v, want %v", err, context.Canceled)
+	}
+
+	release()
+	release, err = l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire() after release error = %v", err)
+	}
+	release()
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	l := newLimiter(&conf.AI{})
+	for i := 0; i < 100; i++ {
+		if _, err := l.acquire(context.Background()); err != nil {
+			t.Fatalf("acquire() #%d error = %v", i, err)
+		}
+	}
+}
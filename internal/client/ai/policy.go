@@ -0,0 +1,45 @@
+package ai
+
+import "sort"
+
+// ModerationPolicy 当前生效的AI审核配置, 只包含API Key的数量, 不包含Key本身
+type ModerationPolicy struct {
+	// Model 未按语言路由时使用的审核模型
+	Model string `json:"model"`
+	// Categories 默认提示词中的违规类别
+	Categories []string `json:"categories"`
+	// Routes 按语言配置的审核提示词和模型, 按语言排序
+	Routes []*ModerationRoutePolicy `json:"routes"`
+	// APIKeys 轮询使用的API Key数量
+	APIKeys int `json:"api_keys"`
+}
+
+// ModerationRoutePolicy 一种语言使用的审核提示词和模型
+type ModerationRoutePolicy struct {
+	Language   string   `json:"language"`
+	Model      string   `json:"model"`
+	Categories []string `json:"categories"`
+}
+
+// ModerationPolicy 返回当前生效的AI审核配置, 类别取自当前加载的提示词文件
+func (c *AIClient) ModerationPolicy() *ModerationPolicy {
+	p := &ModerationPolicy{
+		Model:      c.Model(PurposeModeration),
+		Categories: c.guide.Categories(),
+		APIKeys:    len(c.keys.keys),
+	}
+	langs := make([]string, 0, len(c.routes))
+	for lang := range c.routes {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		r := c.routes[lang]
+		p.Routes = append(p.Routes, &ModerationRoutePolicy{
+			Language:   lang,
+			Model:      r.model,
+			Categories: r.guide.Categories(),
+		})
+	}
+	return p
+}
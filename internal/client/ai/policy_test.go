@@ -0,0 +1,43 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"review/internal/conf"
+)
+
+func TestModerationPolicy(t *testing.T) {
+	routes, err := newModerationRoutes(&conf.AI{ModerationRoutes: map[string]*conf.AI_ModerationRoute{
+		"ja": {Model: "gemini-1.5-pro"},
+		"en": {},
+	}}, "gemini-1.5-flash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	guide, err := newGuideLoader("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &AIClient{routes: routes, guide: guide, keys: newTestKeyPool(3), models: map[Purpose]string{PurposeModeration: "gemini-1.5-flash"}}
+
+	p := c.ModerationPolicy()
+	if p.Model != "gemini-1.5-flash" || p.APIKeys != 3 {
+		t.Errorf("model, api keys = %q, %d, want gemini-1.5-flash, 3", p.Model, p.APIKeys)
+	}
+	if want := defaultModerationGuide.categoryNames(); len(want) == 0 || !reflect.DeepEqual(p.Categories, want) {
+		t.Errorf("categories = %v, want the default guide's %v", p.Categories, want)
+	}
+	var langs, models []string
+	for _, r := range p.Routes {
+		langs, models = append(langs, r.Language), append(models, r.Model)
+	}
+	if !reflect.DeepEqual(langs, []string{"en", "ja"}) || !reflect.DeepEqual(models, []string{"gemini-1.5-flash", "gemini-1.5-pro"}) {
+		t.Errorf("routes = %v with models %v, want en, ja sorted by language", langs, models)
+	}
+	// The policy returns a copy, so callers cannot change the loaded categories.
+	p.Categories[0] = "changed"
+	if c.ModerationPolicy().Categories[0] == "changed" {
+		t.Error("ModerationPolicy() shares its categories with the guide")
+	}
+}
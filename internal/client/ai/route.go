@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"fmt"
+	"slices"
+	"unicode"
+
+	"review/internal/conf"
+)
+
+// DefaultModerationTemplate 未按语言路由时使用的审核提示词名称, 记录在审核日志中
+const DefaultModerationTemplate = "default"
+
+// moderationRoute 一种语言使用的审核提示词和模型
+type moderationRoute struct {
+	// template 提示词名称, 即配置中的语言代码或 DefaultModerationTemplate
+	template string
+	guide    *guideLoader
+	model    string
+}
+
+// newModerationRoutes 加载 ai.moderation_routes 中每种语言的审核提示词, 并校验模型是否受支持
+// 未单独配置提示词文件时使用内置的默认值, 未单独配置模型时使用 fallbackModel
+func newModerationRoutes(c *conf.AI, fallbackModel string) (map[string]*moderationRoute, error) {
+	routes := make(map[string]*moderationRoute, len(c.GetModerationRoutes()))
+	for lang, rc := range c.GetModerationRoutes() {
+		if lang == "" || lang == DefaultModerationTemplate {
+			return nil, fmt.Errorf("ai.moderation_routes: invalid language %q", lang)
+		}
+		guide, err := newGuideLoader(rc.GetGuideFile())
+		if err != nil {
+			return nil, fmt.Errorf("ai.moderation_routes[%s]: %w", lang, err)
+		}
+		model := rc.GetModel()
+		if model == "" {
+			model = fallbackModel
+		} else if !slices.Contains(supportedModels, model) {
+			return nil, fmt.Errorf("ai.moderation_routes[%s]: model %q is not supported, must be one of %v", lang, model, supportedModels)
+		}
+		routes[lang] = &moderationRoute{template: lang, guide: guide, model: model}
+	}
+	return routes, nil
+}
+
+// moderationRoute 按检测到的语言选择审核提示词, 语言未配置路由时使用默认提示词
+func (c *AIClient) moderationRoute(lang string) *moderationRoute {
+	if r, ok := c.routes[lang]; ok {
+		return r
+	}
+	return &moderationRoute{template: DefaultModerationTemplate, guide: c.guide, model: c.Model(PurposeModeration)}
+}
+
+// DetectLanguage 按文字所属的书写系统粗略判断文本语言, 返回 ISO 639-1 代码
+// 包含假名为 ja, 包含谚文为 ko, 包含汉字为 zh, 字母以拉丁字母为主时为 en, 无法判断时为空
+// 只用于在调用模型前选择审核提示词, 审核日志中的语言以模型给出的结果为准
+func DetectLanguage(text string) string {
+	var han, latin, letters int
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			return "ja"
+		case unicode.Is(unicode.Hangul, r):
+			return "ko"
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	switch {
+	case han > 0:
+		return "zh"
+	case letters > 0 && latin*2 > letters:
+		return "en"
+	default:
+		return ""
+	}
+}
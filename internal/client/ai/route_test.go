@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"testing"
+
+	"review/internal/conf"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"味道不错，下次还来", "zh"},
+		{"美味しいです", "ja"},
+		{"맛있어요", "ko"},
+		{"Great food, will come back", "en"},
+		{"Tasty 好吃", "zh"},
+		{"12345 !!!", ""},
+		{"Отличная еда", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := DetectLanguage(tt.text); got != tt.want {
+				t.Errorf("DetectLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewModerationRoutes(t *testing.T) {
+	tests := []struct {
+		name      string
+		routes    map[string]*conf.AI_ModerationRoute
+		wantModel map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "fallback and configured models",
+			routes:    map[string]*conf.AI_ModerationRoute{"en": {}, "ja": {Model: "gemini-1.5-pro"}},
+			wantModel: map[string]string{"en": "gemini-1.5-flash", "ja": "gemini-1.5-pro"},
+		},
+		{name: "unsupported model", routes: map[string]*conf.AI_ModerationRoute{"en": {Model: "gpt-4"}}, wantErr: true},
+		{name: "reserved language", routes: map[string]*conf.AI_ModerationRoute{DefaultModerationTemplate: {}}, wantErr: true},
+		{name: "missing guide file", routes: map[string]*conf.AI_ModerationRoute{"en": {GuideFile: "testdata/missing.yaml"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes, err := newModerationRoutes(&conf.AI{ModerationRoutes: tt.routes}, "gemini-1.5-flash")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newModerationRoutes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for lang, model := range tt.wantModel {
+				if r := routes[lang]; r == nil || r.model != model || r.template != lang {
+					t.Errorf("route %s = %+v, want template %s with model %s", lang, r, lang, model)
+				}
+			}
+		})
+	}
+}
+
+func TestModerationRouteFallback(t *testing.T) {
+	routes, err := newModerationRoutes(&conf.AI{ModerationRoutes: map[string]*conf.AI_ModerationRoute{"en": {}}}, "gemini-1.5-pro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	guide, err := newGuideLoader("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &AIClient{routes: routes, guide: guide, models: map[Purpose]string{PurposeModeration: "gemini-1.5-flash"}}
+	tests := []struct {
+		lang, wantTemplate, wantModel string
+	}{
+		{"en", "en", "gemini-1.5-pro"},
+		{"zh", DefaultModerationTemplate, "gemini-1.5-flash"},
+		{"", DefaultModerationTemplate, "gemini-1.5-flash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			r := c.moderationRoute(tt.lang)
+			if r.template != tt.wantTemplate || r.model != tt.wantModel || r.guide == nil {
+				t.Errorf("moderationRoute(%q) = %+v, want template %s with model %s", tt.lang, r, tt.wantTemplate, tt.wantModel)
+			}
+		})
+	}
+}
@@ -29,6 +29,9 @@ type Bootstrap struct {
 	Snowflake     *Snowflake             `protobuf:"bytes,3,opt,name=snowflake,proto3" json:"snowflake,omitempty"`
 	Elasticsearch *Elasticsearch         `protobuf:"bytes,4,opt,name=elasticsearch,proto3" json:"elasticsearch,omitempty"`
 	Ai            *AI                    `protobuf:"bytes,5,opt,name=ai,proto3" json:"ai,omitempty"`
+	Auth          *Auth                  `protobuf:"bytes,6,opt,name=auth,proto3" json:"auth,omitempty"`
+	Review        *Review                `protobuf:"bytes,7,opt,name=review,proto3" json:"review,omitempty"`
+	Log           *Log                   `protobuf:"bytes,8,opt,name=log,proto3" json:"log,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -98,17 +101,103 @@ func (x *Bootstrap) GetAi() *AI {
 	return nil
 }
 
+func (x *Bootstrap) GetAuth() *Auth {
+	if x != nil {
+		return x.Auth
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+func (x *Bootstrap) GetLog() *Log {
+	if x != nil {
+		return x.Log
+	}
+	return nil
+}
+
+// Log 日志中评论内容、用户标识及LLM输入输出的记录方式
+type Log struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// redact 脱敏模式：文本只记录长度，用户ID只保留后4位
+	Redact bool `protobuf:"varint,1,opt,name=redact,proto3" json:"redact,omitempty"`
+	// max_content_length 非脱敏模式下文本保留的最大字符数，超出部分截断，默认 64
+	MaxContentLength int32 `protobuf:"varint,2,opt,name=max_content_length,json=maxContentLength,proto3" json:"max_content_length,omitempty"`
+	// full_content 记录完整文本，仅用于调试；redact 开启时无效
+	FullContent   bool `protobuf:"varint,3,opt,name=full_content,json=fullContent,proto3" json:"full_content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Log) Reset() {
+	*x = Log{}
+	mi := &file_conf_conf_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Log) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Log) ProtoMessage() {}
+
+func (x *Log) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Log.ProtoReflect.Descriptor instead.
+func (*Log) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Log) GetRedact() bool {
+	if x != nil {
+		return x.Redact
+	}
+	return false
+}
+
+func (x *Log) GetMaxContentLength() int32 {
+	if x != nil {
+		return x.MaxContentLength
+	}
+	return 0
+}
+
+func (x *Log) GetFullContent() bool {
+	if x != nil {
+		return x.FullContent
+	}
+	return false
+}
+
 type Server struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Http          *Server_HTTP           `protobuf:"bytes,1,opt,name=http,proto3" json:"http,omitempty"`
 	Grpc          *Server_GRPC           `protobuf:"bytes,2,opt,name=grpc,proto3" json:"grpc,omitempty"`
+	Static        *Server_Static         `protobuf:"bytes,3,opt,name=static,proto3" json:"static,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Server) Reset() {
 	*x = Server{}
-	mi := &file_conf_conf_proto_msgTypes[1]
+	mi := &file_conf_conf_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -120,7 +209,7 @@ func (x *Server) String() string {
 func (*Server) ProtoMessage() {}
 
 func (x *Server) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[1]
+	mi := &file_conf_conf_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -133,7 +222,7 @@ func (x *Server) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Server.ProtoReflect.Descriptor instead.
 func (*Server) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{1}
+	return file_conf_conf_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Server) GetHttp() *Server_HTTP {
@@ -143,36 +232,1675 @@ func (x *Server) GetHttp() *Server_HTTP {
 	return nil
 }
 
-func (x *Server) GetGrpc() *Server_GRPC {
+func (x *Server) GetGrpc() *Server_GRPC {
+	if x != nil {
+		return x.Grpc
+	}
+	return nil
+}
+
+func (x *Server) GetStatic() *Server_Static {
+	if x != nil {
+		return x.Static
+	}
+	return nil
+}
+
+type Data struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Database      *Data_Database         `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Redis         *Data_Redis            `protobuf:"bytes,2,opt,name=redis,proto3" json:"redis,omitempty"`
+	Async         *Data_Async            `protobuf:"bytes,3,opt,name=async,proto3" json:"async,omitempty"`
+	Startup       *Data_Startup          `protobuf:"bytes,4,opt,name=startup,proto3" json:"startup,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Data) Reset() {
+	*x = Data{}
+	mi := &file_conf_conf_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Data) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Data) ProtoMessage() {}
+
+func (x *Data) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Data.ProtoReflect.Descriptor instead.
+func (*Data) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Data) GetDatabase() *Data_Database {
+	if x != nil {
+		return x.Database
+	}
+	return nil
+}
+
+func (x *Data) GetRedis() *Data_Redis {
+	if x != nil {
+		return x.Redis
+	}
+	return nil
+}
+
+func (x *Data) GetAsync() *Data_Async {
+	if x != nil {
+		return x.Async
+	}
+	return nil
+}
+
+func (x *Data) GetStartup() *Data_Startup {
+	if x != nil {
+		return x.Startup
+	}
+	return nil
+}
+
+type Snowflake struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     string                 `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	MachineId     int64                  `protobuf:"varint,2,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Snowflake) Reset() {
+	*x = Snowflake{}
+	mi := &file_conf_conf_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snowflake) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snowflake) ProtoMessage() {}
+
+func (x *Snowflake) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snowflake.ProtoReflect.Descriptor instead.
+func (*Snowflake) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Snowflake) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *Snowflake) GetMachineId() int64 {
+	if x != nil {
+		return x.MachineId
+	}
+	return 0
+}
+
+type Registry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Consul        *Registry_Consul       `protobuf:"bytes,1,opt,name=consul,proto3" json:"consul,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Registry) Reset() {
+	*x = Registry{}
+	mi := &file_conf_conf_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Registry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Registry) ProtoMessage() {}
+
+func (x *Registry) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Registry.ProtoReflect.Descriptor instead.
+func (*Registry) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Registry) GetConsul() *Registry_Consul {
+	if x != nil {
+		return x.Consul
+	}
+	return nil
+}
+
+type Elasticsearch struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Addresses []string               `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	// refresh 索引写入时的刷新策略: false | wait_for | true，默认 false。
+	// false: 不等待刷新，吞吐最高，但新文档要等到下一次刷新（默认1s）后才可被搜索到；
+	// wait_for: 请求阻塞直到下一次刷新完成，写后即可读，写延迟增加但不额外产生段文件，适合开发/测试环境；
+	// true: 立即强制刷新，写后即可读，但频繁产生小段文件，会显著拖慢集群，不建议在生产使用。
+	Refresh string `protobuf:"bytes,2,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	// track_total_hits 为 true 时列表查询统计精确的命中总数；
+	// 否则 ES 最多精确统计 10000 条，超出时 total.relation 为 gte，返回的总数只是下限。
+	TrackTotalHits bool `protobuf:"varint,3,opt,name=track_total_hits,json=trackTotalHits,proto3" json:"track_total_hits,omitempty"`
+	// timeout 列表查询超时时间，同时作为 ES 服务端的分片查询超时，超时的分片不再等待；
+	// 客户端在此基础上多等 1s 作为兜底，避免 ES 无响应时请求一直阻塞。为空则不限制。
+	Timeout *durationpb.Duration `protobuf:"bytes,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// allow_partial_search_results 为 true 时部分分片失败或超时仍返回已有结果，
+	// 结果会标记为 partial 且不写入缓存；为 false 时沿用集群默认配置。
+	AllowPartialSearchResults bool                     `protobuf:"varint,5,opt,name=allow_partial_search_results,json=allowPartialSearchResults,proto3" json:"allow_partial_search_results,omitempty"`
+	Reconcile                 *Elasticsearch_Reconcile `protobuf:"bytes,6,opt,name=reconcile,proto3" json:"reconcile,omitempty"`
+	// analyzer review 索引中 content、reply 字段的分词器，如 ik_max_word（IK插件）或 smartcn（analysis-smartcn插件），
+	// 需要先在ES集群的每个节点上安装对应插件。为空时使用ES默认的 standard 分词器，中文会被逐字切分。
+	// 只在启动时 review 索引不存在的情况下生效；已有索引不会被修改，更换分词器需要重建索引。
+	// 插件未安装时记录警告并回退为 standard 分词器创建索引。
+	Analyzer string `protobuf:"bytes,7,opt,name=analyzer,proto3" json:"analyzer,omitempty"`
+	// search_analyzer 查询时使用的分词器，如 ik_smart；为空时与 analyzer 相同
+	SearchAnalyzer string              `protobuf:"bytes,8,opt,name=search_analyzer,json=searchAnalyzer,proto3" json:"search_analyzer,omitempty"`
+	Bulk           *Elasticsearch_Bulk `protobuf:"bytes,9,opt,name=bulk,proto3" json:"bulk,omitempty"`
+	// max_result_window 分页查询允许的最大 offset+size，应与集群的 index.max_result_window 一致，默认 10000；
+	// 超出时直接返回 400，不再把请求发给ES
+	MaxResultWindow int32 `protobuf:"varint,10,opt,name=max_result_window,json=maxResultWindow,proto3" json:"max_result_window,omitempty"`
+	// max_document_bytes 写入ES的评论文档（JSON）大小上限，0 表示不限制；
+	// on_oversize 超过上限时的处理方式：truncate 截断评论内容直到文档不超过上限（默认），截断后仍超过上限时不写入；
+	// reject 不写入ES，评论在MySQL中不受影响，但无法被搜索到
+	MaxDocumentBytes int64                 `protobuf:"varint,11,opt,name=max_document_bytes,json=maxDocumentBytes,proto3" json:"max_document_bytes,omitempty"`
+	OnOversize       string                `protobuf:"bytes,12,opt,name=on_oversize,json=onOversize,proto3" json:"on_oversize,omitempty"`
+	Outbox           *Elasticsearch_Outbox `protobuf:"bytes,13,opt,name=outbox,proto3" json:"outbox,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Elasticsearch) Reset() {
+	*x = Elasticsearch{}
+	mi := &file_conf_conf_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Elasticsearch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Elasticsearch) ProtoMessage() {}
+
+func (x *Elasticsearch) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Elasticsearch.ProtoReflect.Descriptor instead.
+func (*Elasticsearch) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Elasticsearch) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *Elasticsearch) GetRefresh() string {
+	if x != nil {
+		return x.Refresh
+	}
+	return ""
+}
+
+func (x *Elasticsearch) GetTrackTotalHits() bool {
+	if x != nil {
+		return x.TrackTotalHits
+	}
+	return false
+}
+
+func (x *Elasticsearch) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Elasticsearch) GetAllowPartialSearchResults() bool {
+	if x != nil {
+		return x.AllowPartialSearchResults
+	}
+	return false
+}
+
+func (x *Elasticsearch) GetReconcile() *Elasticsearch_Reconcile {
+	if x != nil {
+		return x.Reconcile
+	}
+	return nil
+}
+
+func (x *Elasticsearch) GetAnalyzer() string {
+	if x != nil {
+		return x.Analyzer
+	}
+	return ""
+}
+
+func (x *Elasticsearch) GetSearchAnalyzer() string {
+	if x != nil {
+		return x.SearchAnalyzer
+	}
+	return ""
+}
+
+func (x *Elasticsearch) GetBulk() *Elasticsearch_Bulk {
+	if x != nil {
+		return x.Bulk
+	}
+	return nil
+}
+
+func (x *Elasticsearch) GetMaxResultWindow() int32 {
+	if x != nil {
+		return x.MaxResultWindow
+	}
+	return 0
+}
+
+func (x *Elasticsearch) GetMaxDocumentBytes() int64 {
+	if x != nil {
+		return x.MaxDocumentBytes
+	}
+	return 0
+}
+
+func (x *Elasticsearch) GetOnOversize() string {
+	if x != nil {
+		return x.OnOversize
+	}
+	return ""
+}
+
+func (x *Elasticsearch) GetOutbox() *Elasticsearch_Outbox {
+	if x != nil {
+		return x.Outbox
+	}
+	return nil
+}
+
+type AI struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Model  string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	// 智能助手提示词模板文件（Go text/template），为空时使用内置模板；启动时加载并校验占位符。
+	// system_prompt_file 必须包含 {{.History}} {{.Tools}} {{.Query}}；
+	// summary_prompt_file 必须包含 {{.Query}} {{.Result}}。
+	SystemPromptFile  string `protobuf:"bytes,3,opt,name=system_prompt_file,json=systemPromptFile,proto3" json:"system_prompt_file,omitempty"`
+	SummaryPromptFile string `protobuf:"bytes,4,opt,name=summary_prompt_file,json=summaryPromptFile,proto3" json:"summary_prompt_file,omitempty"`
+	// 全局AI调用限流，作用于审核、智能助手、总结等所有Gemini调用，避免整体超出账号配额。
+	// max_in_flight 同时进行的调用数上限，0 表示不限制
+	MaxInFlight int32 `protobuf:"varint,5,opt,name=max_in_flight,json=maxInFlight,proto3" json:"max_in_flight,omitempty"`
+	// max_qps 每秒发起的调用数上限，0 表示不限制
+	MaxQps float64 `protobuf:"fixed64,6,opt,name=max_qps,json=maxQps,proto3" json:"max_qps,omitempty"`
+	// queue_timeout 超出上限时排队等待的最长时间，超时返回繁忙错误；未配置时一直等待直到请求上下文结束
+	QueueTimeout *durationpb.Duration `protobuf:"bytes,7,opt,name=queue_timeout,json=queueTimeout,proto3" json:"queue_timeout,omitempty"`
+	// 按用途分别指定模型，未配置时使用 model；启动时校验模型名称是否受支持。
+	// moderation_model 评论审核及申诉建议；agent_model 智能助手推理；summary_model 工具结果总结
+	ModerationModel string `protobuf:"bytes,8,opt,name=moderation_model,json=moderationModel,proto3" json:"moderation_model,omitempty"`
+	AgentModel      string `protobuf:"bytes,9,opt,name=agent_model,json=agentModel,proto3" json:"agent_model,omitempty"`
+	SummaryModel    string `protobuf:"bytes,10,opt,name=summary_model,json=summaryModel,proto3" json:"summary_model,omitempty"`
+	// moderation_guide_file 审核提示词中的违规类别和示例（JSON，格式见 ai.ModerationGuide），为空时使用内置的默认值；
+	// 启动时加载并校验至少包含一个示例，文件修改后在下一次审核时自动重新加载，无效的修改会被忽略
+	ModerationGuideFile string `protobuf:"bytes,11,opt,name=moderation_guide_file,json=moderationGuideFile,proto3" json:"moderation_guide_file,omitempty"`
+	// 智能助手输入限制（按字符计数）：max_query_length 单次查询的最大长度，默认 1000；
+	// max_prompt_length 渲染后提示词的最大长度，超出时从最早的历史对话开始丢弃，仍超出则拒绝，默认 16000
+	MaxQueryLength  int32 `protobuf:"varint,12,opt,name=max_query_length,json=maxQueryLength,proto3" json:"max_query_length,omitempty"`
+	MaxPromptLength int32 `protobuf:"varint,13,opt,name=max_prompt_length,json=maxPromptLength,proto3" json:"max_prompt_length,omitempty"`
+	// max_context_messages 客户端随请求携带的历史消息条数上限，超出时拒绝，默认 20；每条长度受 max_query_length 限制
+	MaxContextMessages int32                   `protobuf:"varint,14,opt,name=max_context_messages,json=maxContextMessages,proto3" json:"max_context_messages,omitempty"`
+	RoleTools          map[string]*AI_ToolList `protobuf:"bytes,15,rep,name=role_tools,json=roleTools,proto3" json:"role_tools,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// max_sessions 进程内保存的智能助手会话数上限，超出时淘汰最久未访问的会话，默认 10000
+	MaxSessions int32 `protobuf:"varint,16,opt,name=max_sessions,json=maxSessions,proto3" json:"max_sessions,omitempty"`
+	// tool_timeout 智能助手单次工具调用（执行工具及总结结果）的时长上限，超时返回提示信息，默认 20s；
+	// tool_timeouts 按工具名单独指定时长上限，覆盖 tool_timeout，启动时校验工具名
+	ToolTimeout      *durationpb.Duration            `protobuf:"bytes,17,opt,name=tool_timeout,json=toolTimeout,proto3" json:"tool_timeout,omitempty"`
+	ToolTimeouts     map[string]*durationpb.Duration `protobuf:"bytes,18,rep,name=tool_timeouts,json=toolTimeouts,proto3" json:"tool_timeouts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ModerationRoutes map[string]*AI_ModerationRoute  `protobuf:"bytes,19,rep,name=moderation_routes,json=moderationRoutes,proto3" json:"moderation_routes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// api_keys 多个API Key按轮询使用，某个Key返回配额错误（429）时换下一个Key重试；
+	// 配额错误或连续失败 5 次的Key暂停使用 key_cooldown（默认 1m），期间由其它Key承担调用。
+	// 未配置时只使用 api_key
+	ApiKeys       []string             `protobuf:"bytes,20,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	KeyCooldown   *durationpb.Duration `protobuf:"bytes,21,opt,name=key_cooldown,json=keyCooldown,proto3" json:"key_cooldown,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AI) Reset() {
+	*x = AI{}
+	mi := &file_conf_conf_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AI) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AI) ProtoMessage() {}
+
+func (x *AI) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AI.ProtoReflect.Descriptor instead.
+func (*AI) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AI) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *AI) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *AI) GetSystemPromptFile() string {
+	if x != nil {
+		return x.SystemPromptFile
+	}
+	return ""
+}
+
+func (x *AI) GetSummaryPromptFile() string {
+	if x != nil {
+		return x.SummaryPromptFile
+	}
+	return ""
+}
+
+func (x *AI) GetMaxInFlight() int32 {
+	if x != nil {
+		return x.MaxInFlight
+	}
+	return 0
+}
+
+func (x *AI) GetMaxQps() float64 {
+	if x != nil {
+		return x.MaxQps
+	}
+	return 0
+}
+
+func (x *AI) GetQueueTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.QueueTimeout
+	}
+	return nil
+}
+
+func (x *AI) GetModerationModel() string {
+	if x != nil {
+		return x.ModerationModel
+	}
+	return ""
+}
+
+func (x *AI) GetAgentModel() string {
+	if x != nil {
+		return x.AgentModel
+	}
+	return ""
+}
+
+func (x *AI) GetSummaryModel() string {
+	if x != nil {
+		return x.SummaryModel
+	}
+	return ""
+}
+
+func (x *AI) GetModerationGuideFile() string {
+	if x != nil {
+		return x.ModerationGuideFile
+	}
+	return ""
+}
+
+func (x *AI) GetMaxQueryLength() int32 {
+	if x != nil {
+		return x.MaxQueryLength
+	}
+	return 0
+}
+
+func (x *AI) GetMaxPromptLength() int32 {
+	if x != nil {
+		return x.MaxPromptLength
+	}
+	return 0
+}
+
+func (x *AI) GetMaxContextMessages() int32 {
+	if x != nil {
+		return x.MaxContextMessages
+	}
+	return 0
+}
+
+func (x *AI) GetRoleTools() map[string]*AI_ToolList {
+	if x != nil {
+		return x.RoleTools
+	}
+	return nil
+}
+
+func (x *AI) GetMaxSessions() int32 {
+	if x != nil {
+		return x.MaxSessions
+	}
+	return 0
+}
+
+func (x *AI) GetToolTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.ToolTimeout
+	}
+	return nil
+}
+
+func (x *AI) GetToolTimeouts() map[string]*durationpb.Duration {
+	if x != nil {
+		return x.ToolTimeouts
+	}
+	return nil
+}
+
+func (x *AI) GetModerationRoutes() map[string]*AI_ModerationRoute {
+	if x != nil {
+		return x.ModerationRoutes
+	}
+	return nil
+}
+
+func (x *AI) GetApiKeys() []string {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+func (x *AI) GetKeyCooldown() *durationpb.Duration {
+	if x != nil {
+		return x.KeyCooldown
+	}
+	return nil
+}
+
+type Auth struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// jwt_secret HS256 签名密钥
+	JwtSecret string `protobuf:"bytes,1,opt,name=jwt_secret,json=jwtSecret,proto3" json:"jwt_secret,omitempty"`
+	// issuer/audience 签发时写入 iss/aud，校验时不匹配的令牌会被拒绝，默认均为服务名
+	Issuer   string `protobuf:"bytes,2,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Audience string `protobuf:"bytes,3,opt,name=audience,proto3" json:"audience,omitempty"`
+	// default_role 注册时未指定角色使用的默认角色，只能是 customer 或 merchant，默认 customer
+	DefaultRole string `protobuf:"bytes,4,opt,name=default_role,json=defaultRole,proto3" json:"default_role,omitempty"`
+	// token_ttl 登录签发的令牌有效期，默认 24h；role_token_ttl 按角色单独指定有效期，覆盖 token_ttl。
+	// 启动时校验角色名和时长（必须为正）
+	TokenTtl      *durationpb.Duration            `protobuf:"bytes,5,opt,name=token_ttl,json=tokenTtl,proto3" json:"token_ttl,omitempty"`
+	RoleTokenTtl  map[string]*durationpb.Duration `protobuf:"bytes,6,rep,name=role_token_ttl,json=roleTokenTtl,proto3" json:"role_token_ttl,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Auth) Reset() {
+	*x = Auth{}
+	mi := &file_conf_conf_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Auth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Auth) ProtoMessage() {}
+
+func (x *Auth) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Auth.ProtoReflect.Descriptor instead.
+func (*Auth) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Auth) GetJwtSecret() string {
+	if x != nil {
+		return x.JwtSecret
+	}
+	return ""
+}
+
+func (x *Auth) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *Auth) GetAudience() string {
+	if x != nil {
+		return x.Audience
+	}
+	return ""
+}
+
+func (x *Auth) GetDefaultRole() string {
+	if x != nil {
+		return x.DefaultRole
+	}
+	return ""
+}
+
+func (x *Auth) GetTokenTtl() *durationpb.Duration {
+	if x != nil {
+		return x.TokenTtl
+	}
+	return nil
+}
+
+func (x *Auth) GetRoleTokenTtl() map[string]*durationpb.Duration {
+	if x != nil {
+		return x.RoleTokenTtl
+	}
+	return nil
+}
+
+type Review struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 评论内容长度限制，按字符（rune）计数，一个汉字算一个字符；未配置时为 1~512
+	ContentMinLength int32 `protobuf:"varint,1,opt,name=content_min_length,json=contentMinLength,proto3" json:"content_min_length,omitempty"`
+	ContentMaxLength int32 `protobuf:"varint,2,opt,name=content_max_length,json=contentMaxLength,proto3" json:"content_max_length,omitempty"`
+	// 作者可自行删除评论的状态列表，未配置时为待审核(10)和审核驳回(30)；
+	// 已发布的评论默认不允许删除，防止刷评后删评操纵店铺评分
+	DeletableStatuses []int32 `protobuf:"varint,3,rep,packed,name=deletable_statuses,json=deletableStatuses,proto3" json:"deletable_statuses,omitempty"`
+	// 同一订单最多允许追加评论的次数，超过后拒绝继续追加，未配置时为 3
+	MaxAppends int32 `protobuf:"varint,4,opt,name=max_appends,json=maxAppends,proto3" json:"max_appends,omitempty"`
+	// pending_visibility 待审核评论在列表中的可见性: hidden | author | public，默认 hidden。
+	// hidden: 只展示已通过(20)的评论；author: 作者可以看到自己待审核的评论；
+	// public: 待审核评论对所有人可见，客户端根据 status=10 展示“审核中”标记。
+	// 审核员和管理员不受限制，可以看到所有状态的评论。
+	PendingVisibility string `protobuf:"bytes,5,opt,name=pending_visibility,json=pendingVisibility,proto3" json:"pending_visibility,omitempty"`
+	// 申诉校验：申诉理由必填，最多 512 个字；申诉内容按字符计数，未配置时最多 512 个字
+	AppealContentMaxLength int32 `protobuf:"varint,6,opt,name=appeal_content_max_length,json=appealContentMaxLength,proto3" json:"appeal_content_max_length,omitempty"`
+	// 申诉附带的图片/视频URL（逗号分隔）数量上限，未配置时图片 9 个、视频 3 个
+	AppealMaxPics   int32 `protobuf:"varint,7,opt,name=appeal_max_pics,json=appealMaxPics,proto3" json:"appeal_max_pics,omitempty"`
+	AppealMaxVideos int32 `protobuf:"varint,8,opt,name=appeal_max_videos,json=appealMaxVideos,proto3" json:"appeal_max_videos,omitempty"`
+	// media_hosts 允许的图片/视频域名（含子域名），为空时不限制域名，但URL必须是 http(s)
+	MediaHosts []string `protobuf:"bytes,9,rep,name=media_hosts,json=mediaHosts,proto3" json:"media_hosts,omitempty"`
+	// appeal_ai_assist 为 true 时，商家提交申诉后异步请求AI给出处理建议（维持/支持申诉及理由），
+	// 在申诉列表中展示给审核员；建议仅供参考，不会自动改变申诉状态。默认关闭
+	AppealAiAssist bool `protobuf:"varint,10,opt,name=appeal_ai_assist,json=appealAiAssist,proto3" json:"appeal_ai_assist,omitempty"`
+	// tags 话题标签词表，创建或追加评论时自动打标签，可用于列表过滤和标签分布统计；为空时不打标签
+	Tags []*Review_Tag `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	// 被驳回的评论修改后最多允许重新提交的次数，未配置时为 2
+	MaxResubmits int32 `protobuf:"varint,12,opt,name=max_resubmits,json=maxResubmits,proto3" json:"max_resubmits,omitempty"`
+	// on_ai_error AI审核无法完成时评论的处理方式，并以"AI unavailable"记录审核日志：
+	// hold 保持待审核，不做处理（默认）；approve 直接通过；reject 直接驳回；
+	// human_review 保持待审核并在审核备注中标记待人工审核
+	OnAiError string `protobuf:"bytes,13,opt,name=on_ai_error,json=onAiError,proto3" json:"on_ai_error,omitempty"`
+	// preview_rate_per_minute 每个用户每分钟可以预审评论内容的次数，未配置时为 10
+	PreviewRatePerMinute int32 `protobuf:"varint,14,opt,name=preview_rate_per_minute,json=previewRatePerMinute,proto3" json:"preview_rate_per_minute,omitempty"`
+	// score_edit_window 评论发布后作者可以单独修改评分的时间窗口，未配置时为 24 小时
+	ScoreEditWindow *durationpb.Duration    `protobuf:"bytes,15,opt,name=score_edit_window,json=scoreEditWindow,proto3" json:"score_edit_window,omitempty"`
+	ScoreScale      *Review_ScoreScale      `protobuf:"bytes,17,opt,name=score_scale,json=scoreScale,proto3" json:"score_scale,omitempty"`
+	MediaSizeCheck  *Review_MediaSizeCheck  `protobuf:"bytes,18,opt,name=media_size_check,json=mediaSizeCheck,proto3" json:"media_size_check,omitempty"`
+	TrustedFastPath *Review_TrustedFastPath `protobuf:"bytes,16,opt,name=trusted_fast_path,json=trustedFastPath,proto3" json:"trusted_fast_path,omitempty"`
+	// allow_rejected_appeal 为 true 时，商家可以对已驳回(30)的评论提起 reinstate 申诉（认为AI或审核员误判），
+	// 申诉通过后评论恢复为已通过(20)，驳回时评论保持驳回；已通过评论的申诉为 hide 申诉，通过后评论隐藏(40)。默认关闭
+	AllowRejectedAppeal bool                    `protobuf:"varint,19,opt,name=allow_rejected_appeal,json=allowRejectedAppeal,proto3" json:"allow_rejected_appeal,omitempty"`
+	ModerationCache     *Review_ModerationCache `protobuf:"bytes,20,opt,name=moderation_cache,json=moderationCache,proto3" json:"moderation_cache,omitempty"`
+	LanguagePolicy      *Review_LanguagePolicy  `protobuf:"bytes,21,opt,name=language_policy,json=languagePolicy,proto3" json:"language_policy,omitempty"`
+	AppendFormat        *Review_AppendFormat    `protobuf:"bytes,22,opt,name=append_format,json=appendFormat,proto3" json:"append_format,omitempty"`
+	// 评论列表（包括Agent工具查询的列表）未指定每页条数时的默认值，未配置时为 10；请求中指定的条数仍然生效，均不超过上限 50
+	DefaultPageSize int32 `protobuf:"varint,23,opt,name=default_page_size,json=defaultPageSize,proto3" json:"default_page_size,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Review) Reset() {
+	*x = Review{}
+	mi := &file_conf_conf_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Review) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Review) ProtoMessage() {}
+
+func (x *Review) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Review.ProtoReflect.Descriptor instead.
+func (*Review) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Review) GetContentMinLength() int32 {
+	if x != nil {
+		return x.ContentMinLength
+	}
+	return 0
+}
+
+func (x *Review) GetContentMaxLength() int32 {
+	if x != nil {
+		return x.ContentMaxLength
+	}
+	return 0
+}
+
+func (x *Review) GetDeletableStatuses() []int32 {
+	if x != nil {
+		return x.DeletableStatuses
+	}
+	return nil
+}
+
+func (x *Review) GetMaxAppends() int32 {
+	if x != nil {
+		return x.MaxAppends
+	}
+	return 0
+}
+
+func (x *Review) GetPendingVisibility() string {
+	if x != nil {
+		return x.PendingVisibility
+	}
+	return ""
+}
+
+func (x *Review) GetAppealContentMaxLength() int32 {
+	if x != nil {
+		return x.AppealContentMaxLength
+	}
+	return 0
+}
+
+func (x *Review) GetAppealMaxPics() int32 {
+	if x != nil {
+		return x.AppealMaxPics
+	}
+	return 0
+}
+
+func (x *Review) GetAppealMaxVideos() int32 {
+	if x != nil {
+		return x.AppealMaxVideos
+	}
+	return 0
+}
+
+func (x *Review) GetMediaHosts() []string {
+	if x != nil {
+		return x.MediaHosts
+	}
+	return nil
+}
+
+func (x *Review) GetAppealAiAssist() bool {
+	if x != nil {
+		return x.AppealAiAssist
+	}
+	return false
+}
+
+func (x *Review) GetTags() []*Review_Tag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Review) GetMaxResubmits() int32 {
+	if x != nil {
+		return x.MaxResubmits
+	}
+	return 0
+}
+
+func (x *Review) GetOnAiError() string {
+	if x != nil {
+		return x.OnAiError
+	}
+	return ""
+}
+
+func (x *Review) GetPreviewRatePerMinute() int32 {
+	if x != nil {
+		return x.PreviewRatePerMinute
+	}
+	return 0
+}
+
+func (x *Review) GetScoreEditWindow() *durationpb.Duration {
+	if x != nil {
+		return x.ScoreEditWindow
+	}
+	return nil
+}
+
+func (x *Review) GetScoreScale() *Review_ScoreScale {
+	if x != nil {
+		return x.ScoreScale
+	}
+	return nil
+}
+
+func (x *Review) GetMediaSizeCheck() *Review_MediaSizeCheck {
+	if x != nil {
+		return x.MediaSizeCheck
+	}
+	return nil
+}
+
+func (x *Review) GetTrustedFastPath() *Review_TrustedFastPath {
+	if x != nil {
+		return x.TrustedFastPath
+	}
+	return nil
+}
+
+func (x *Review) GetAllowRejectedAppeal() bool {
+	if x != nil {
+		return x.AllowRejectedAppeal
+	}
+	return false
+}
+
+func (x *Review) GetModerationCache() *Review_ModerationCache {
+	if x != nil {
+		return x.ModerationCache
+	}
+	return nil
+}
+
+func (x *Review) GetLanguagePolicy() *Review_LanguagePolicy {
+	if x != nil {
+		return x.LanguagePolicy
+	}
+	return nil
+}
+
+func (x *Review) GetAppendFormat() *Review_AppendFormat {
+	if x != nil {
+		return x.AppendFormat
+	}
+	return nil
+}
+
+func (x *Review) GetDefaultPageSize() int32 {
+	if x != nil {
+		return x.DefaultPageSize
+	}
+	return 0
+}
+
+type Server_HTTP struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Timeout       *durationpb.Duration   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server_HTTP) Reset() {
+	*x = Server_HTTP{}
+	mi := &file_conf_conf_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_HTTP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_HTTP) ProtoMessage() {}
+
+func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_HTTP.ProtoReflect.Descriptor instead.
+func (*Server_HTTP) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *Server_HTTP) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Server_HTTP) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *Server_HTTP) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type Server_GRPC struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Timeout       *durationpb.Duration   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server_GRPC) Reset() {
+	*x = Server_GRPC{}
+	mi := &file_conf_conf_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_GRPC) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_GRPC) ProtoMessage() {}
+
+func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_GRPC.ProtoReflect.Descriptor instead.
+func (*Server_GRPC) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{2, 1}
+}
+
+func (x *Server_GRPC) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Server_GRPC) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *Server_GRPC) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+// Static 前端静态页面挂载配置，不配置时沿用 ../../frontend 下的 user/agent/dashboard 三个目录
+type Server_Static struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// disabled 为 true 时不提供静态页面，适用于纯API部署
+	Disabled bool `protobuf:"varint,1,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	// embedded 为 true 时使用编译进二进制的前端资源，不依赖运行目录
+	Embedded      bool                   `protobuf:"varint,2,opt,name=embedded,proto3" json:"embedded,omitempty"`
+	Mounts        []*Server_Static_Mount `protobuf:"bytes,3,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server_Static) Reset() {
+	*x = Server_Static{}
+	mi := &file_conf_conf_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_Static) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_Static) ProtoMessage() {}
+
+func (x *Server_Static) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_Static.ProtoReflect.Descriptor instead.
+func (*Server_Static) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{2, 2}
+}
+
+func (x *Server_Static) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *Server_Static) GetEmbedded() bool {
+	if x != nil {
+		return x.Embedded
+	}
+	return false
+}
+
+func (x *Server_Static) GetMounts() []*Server_Static_Mount {
+	if x != nil {
+		return x.Mounts
+	}
+	return nil
+}
+
+type Server_Static_Mount struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// prefix 挂载的URL前缀，必须以 / 结尾，如 /user/
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// dir 文件系统目录；embedded 为 true 时为内嵌前端资源中的相对目录，如 user
+	Dir           string `protobuf:"bytes,2,opt,name=dir,proto3" json:"dir,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server_Static_Mount) Reset() {
+	*x = Server_Static_Mount{}
+	mi := &file_conf_conf_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_Static_Mount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_Static_Mount) ProtoMessage() {}
+
+func (x *Server_Static_Mount) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_Static_Mount.ProtoReflect.Descriptor instead.
+func (*Server_Static_Mount) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{2, 2, 0}
+}
+
+func (x *Server_Static_Mount) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *Server_Static_Mount) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+type Data_Database struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Driver        string                 `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Data_Database) Reset() {
+	*x = Data_Database{}
+	mi := &file_conf_conf_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Data_Database) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Data_Database) ProtoMessage() {}
+
+func (x *Data_Database) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Data_Database.ProtoReflect.Descriptor instead.
+func (*Data_Database) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3, 0}
+}
+
+func (x *Data_Database) GetDriver() string {
+	if x != nil {
+		return x.Driver
+	}
+	return ""
+}
+
+func (x *Data_Database) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type Data_Redis struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Network      string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Addr         string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	ReadTimeout  *durationpb.Duration   `protobuf:"bytes,3,opt,name=read_timeout,json=readTimeout,proto3" json:"read_timeout,omitempty"`
+	WriteTimeout *durationpb.Duration   `protobuf:"bytes,4,opt,name=write_timeout,json=writeTimeout,proto3" json:"write_timeout,omitempty"`
+	// max_value_bytes 列表等查询结果写入缓存的大小上限，超过时不写缓存，这些key每次都查询ES；0 表示不限制
+	MaxValueBytes int64 `protobuf:"varint,5,opt,name=max_value_bytes,json=maxValueBytes,proto3" json:"max_value_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Data_Redis) Reset() {
+	*x = Data_Redis{}
+	mi := &file_conf_conf_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Data_Redis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Data_Redis) ProtoMessage() {}
+
+func (x *Data_Redis) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Data_Redis.ProtoReflect.Descriptor instead.
+func (*Data_Redis) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3, 1}
+}
+
+func (x *Data_Redis) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Data_Redis) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+func (x *Data_Redis) GetReadTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.ReadTimeout
+	}
+	return nil
+}
+
+func (x *Data_Redis) GetWriteTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.WriteTimeout
+	}
+	return nil
+}
+
+func (x *Data_Redis) GetMaxValueBytes() int64 {
+	if x != nil {
+		return x.MaxValueBytes
+	}
+	return 0
+}
+
+// Async 评论保存后异步AI审核及ES同步的任务池
+type Data_Async struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// workers 并发执行的任务数上限，默认 8
+	Workers int32 `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`
+	// queue_size 等待执行的任务数上限，队列满时新任务被丢弃并记录错误，默认 1000
+	QueueSize int32 `protobuf:"varint,2,opt,name=queue_size,json=queueSize,proto3" json:"queue_size,omitempty"`
+	// order 审核与ES同步的先后顺序: audit_first | sync_first，默认 audit_first。
+	// audit_first: 审核完成后再写入ES，评论在AI审核返回前无法被搜索到；
+	// sync_first: 先将待审核状态的评论写入ES，审核完成后再更新状态，审核失败时保留首次写入的文档。
+	Order string `protobuf:"bytes,3,opt,name=order,proto3" json:"order,omitempty"`
+	// timeout 每条评论审核和同步ES的总时长上限，默认 60s。超时后放弃本次处理：
+	// AI审核按 review.on_ai_error 处理并记录审核日志，未完成的ES同步由定时对账补写
+	Timeout       *durationpb.Duration `protobuf:"bytes,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Priority      *Data_Async_Priority `protobuf:"bytes,5,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Data_Async) Reset() {
+	*x = Data_Async{}
+	mi := &file_conf_conf_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Data_Async) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Data_Async) ProtoMessage() {}
+
+func (x *Data_Async) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Data_Async.ProtoReflect.Descriptor instead.
+func (*Data_Async) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3, 2}
+}
+
+func (x *Data_Async) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *Data_Async) GetQueueSize() int32 {
+	if x != nil {
+		return x.QueueSize
+	}
+	return 0
+}
+
+func (x *Data_Async) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *Data_Async) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Data_Async) GetPriority() *Data_Async_Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return nil
+}
+
+// Startup 服务开始接收请求前检查 MySQL、Redis、ES 是否可用，并确保ES的 review 索引存在（不存在时创建），逐项记录结果；
+// 检查完成前 /readyz 返回 503
+type Data_Startup struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// mode 检查方式: off | fail_fast | wait，默认 off（不检查，与以前一致）。
+	// fail_fast: 检查一次，有依赖不可用时启动失败；
+	// wait: 每隔 interval 重试，timeout 内全部可用后启动，超时后启动失败
+	Mode string `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	// timeout wait 模式的最长等待时间，默认 60s
+	Timeout *durationpb.Duration `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// interval wait 模式的重试间隔，默认 2s
+	Interval      *durationpb.Duration `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Data_Startup) Reset() {
+	*x = Data_Startup{}
+	mi := &file_conf_conf_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Data_Startup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Data_Startup) ProtoMessage() {}
+
+func (x *Data_Startup) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Data_Startup.ProtoReflect.Descriptor instead.
+func (*Data_Startup) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3, 3}
+}
+
+func (x *Data_Startup) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *Data_Startup) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Data_Startup) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+// Priority 评论审核任务的优先级规则，积压时优先级高的评论先审核，同优先级按提交顺序；
+// 优先级为命中的各项规则之和，未配置时所有评论优先级为 0
+type Data_Async_Priority struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// trusted_user 作者为受信用户（已通过评论数达到 review.trusted_fast_path.min_approved 且从未被驳回或隐藏）时增加的优先级
+	TrustedUser int32 `protobuf:"varint,1,opt,name=trusted_user,json=trustedUser,proto3" json:"trusted_user,omitempty"`
+	// stores 店铺ID到优先级的映射，用于重点店铺
+	Stores map[int64]int32 `protobuf:"bytes,2,rep,name=stores,proto3" json:"stores,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// resubmission 被驳回后修改重新提交的评论增加的优先级
+	Resubmission  int32 `protobuf:"varint,3,opt,name=resubmission,proto3" json:"resubmission,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Data_Async_Priority) Reset() {
+	*x = Data_Async_Priority{}
+	mi := &file_conf_conf_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Data_Async_Priority) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Data_Async_Priority) ProtoMessage() {}
+
+func (x *Data_Async_Priority) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Data_Async_Priority.ProtoReflect.Descriptor instead.
+func (*Data_Async_Priority) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3, 2, 0}
+}
+
+func (x *Data_Async_Priority) GetTrustedUser() int32 {
+	if x != nil {
+		return x.TrustedUser
+	}
+	return 0
+}
+
+func (x *Data_Async_Priority) GetStores() map[int64]int32 {
+	if x != nil {
+		return x.Stores
+	}
+	return nil
+}
+
+func (x *Data_Async_Priority) GetResubmission() int32 {
+	if x != nil {
+		return x.Resubmission
+	}
+	return 0
+}
+
+type Registry_Consul struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Scheme        string                 `protobuf:"bytes,2,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Registry_Consul) Reset() {
+	*x = Registry_Consul{}
+	mi := &file_conf_conf_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Registry_Consul) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Registry_Consul) ProtoMessage() {}
+
+func (x *Registry_Consul) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Registry_Consul.ProtoReflect.Descriptor instead.
+func (*Registry_Consul) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *Registry_Consul) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Registry_Consul) GetScheme() string {
+	if x != nil {
+		return x.Scheme
+	}
+	return ""
+}
+
+// Reconcile 定时对账：找出MySQL中比ES文档更新或ES中缺失的评论并重新写入ES，
+// 多副本部署时通过Redis锁保证同一时间只有一个副本执行
+type Elasticsearch_Reconcile struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// interval 对账间隔，为空表示不开启
+	Interval *durationpb.Duration `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	// batch_size 每批从MySQL读取并比对的评论数，默认 200
+	BatchSize     int32 `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Elasticsearch_Reconcile) Reset() {
+	*x = Elasticsearch_Reconcile{}
+	mi := &file_conf_conf_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Elasticsearch_Reconcile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Elasticsearch_Reconcile) ProtoMessage() {}
+
+func (x *Elasticsearch_Reconcile) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Elasticsearch_Reconcile.ProtoReflect.Descriptor instead.
+func (*Elasticsearch_Reconcile) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{6, 0}
+}
+
+func (x *Elasticsearch_Reconcile) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *Elasticsearch_Reconcile) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+// Bulk 对账、按时间范围重建索引等批量任务通过 _bulk 接口批量写入ES；创建评论等单条写入不受影响
+type Elasticsearch_Bulk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// flush_size 缓冲的文档数达到该值时写入，默认 500
+	FlushSize int32 `protobuf:"varint,1,opt,name=flush_size,json=flushSize,proto3" json:"flush_size,omitempty"`
+	// flush_interval 距第一条缓冲的文档超过该时间时写入，默认 1s
+	FlushInterval *durationpb.Duration `protobuf:"bytes,2,opt,name=flush_interval,json=flushInterval,proto3" json:"flush_interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Elasticsearch_Bulk) Reset() {
+	*x = Elasticsearch_Bulk{}
+	mi := &file_conf_conf_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Elasticsearch_Bulk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Elasticsearch_Bulk) ProtoMessage() {}
+
+func (x *Elasticsearch_Bulk) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Elasticsearch_Bulk.ProtoReflect.Descriptor instead.
+func (*Elasticsearch_Bulk) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{6, 1}
+}
+
+func (x *Elasticsearch_Bulk) GetFlushSize() int32 {
+	if x != nil {
+		return x.FlushSize
+	}
+	return 0
+}
+
+func (x *Elasticsearch_Bulk) GetFlushInterval() *durationpb.Duration {
 	if x != nil {
-		return x.Grpc
+		return x.FlushInterval
 	}
 	return nil
 }
 
-type Data struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Database      *Data_Database         `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
-	Redis         *Data_Redis            `protobuf:"bytes,2,opt,name=redis,proto3" json:"redis,omitempty"`
+// Outbox ES同步发件箱：评论写入MySQL时在同一事务中记录一条待同步记录，后台按间隔读取并写入ES，成功后删除，
+// 失败时保留记录在下一轮重试，进程重启也不会丢失同步；写入后的即时同步成功时同样删除对应记录。
+// 多副本部署时通过Redis锁保证同一时间只有一个副本执行
+type Elasticsearch_Outbox struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// interval 处理间隔，为空表示不开启，写入评论时也不记录
+	Interval *durationpb.Duration `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	// batch_size 每批处理的记录数，默认 200
+	BatchSize int32 `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	// min_age 只处理写入超过该时间的记录，留给写入后的即时同步完成，默认 1m
+	MinAge        *durationpb.Duration `protobuf:"bytes,3,opt,name=min_age,json=minAge,proto3" json:"min_age,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Data) Reset() {
-	*x = Data{}
-	mi := &file_conf_conf_proto_msgTypes[2]
+func (x *Elasticsearch_Outbox) Reset() {
+	*x = Elasticsearch_Outbox{}
+	mi := &file_conf_conf_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Data) String() string {
+func (x *Elasticsearch_Outbox) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Data) ProtoMessage() {}
+func (*Elasticsearch_Outbox) ProtoMessage() {}
 
-func (x *Data) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[2]
+func (x *Elasticsearch_Outbox) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -183,48 +1911,56 @@ func (x *Data) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Data.ProtoReflect.Descriptor instead.
-func (*Data) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use Elasticsearch_Outbox.ProtoReflect.Descriptor instead.
+func (*Elasticsearch_Outbox) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{6, 2}
 }
 
-func (x *Data) GetDatabase() *Data_Database {
+func (x *Elasticsearch_Outbox) GetInterval() *durationpb.Duration {
 	if x != nil {
-		return x.Database
+		return x.Interval
 	}
 	return nil
 }
 
-func (x *Data) GetRedis() *Data_Redis {
+func (x *Elasticsearch_Outbox) GetBatchSize() int32 {
 	if x != nil {
-		return x.Redis
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *Elasticsearch_Outbox) GetMinAge() *durationpb.Duration {
+	if x != nil {
+		return x.MinAge
 	}
 	return nil
 }
 
-type Snowflake struct {
+// role_tools 各角色可用的智能助手工具（按工具名引用），未配置时使用内置的默认映射；
+// 启动时校验角色和工具名，未列出的角色没有工具，未登录用户对应角色 public
+type AI_ToolList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	StartTime     string                 `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
-	MachineId     int64                  `protobuf:"varint,2,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
+	Tools         []string               `protobuf:"bytes,1,rep,name=tools,proto3" json:"tools,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Snowflake) Reset() {
-	*x = Snowflake{}
-	mi := &file_conf_conf_proto_msgTypes[3]
+func (x *AI_ToolList) Reset() {
+	*x = AI_ToolList{}
+	mi := &file_conf_conf_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Snowflake) String() string {
+func (x *AI_ToolList) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Snowflake) ProtoMessage() {}
+func (*AI_ToolList) ProtoMessage() {}
 
-func (x *Snowflake) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[3]
+func (x *AI_ToolList) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -235,47 +1971,44 @@ func (x *Snowflake) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Snowflake.ProtoReflect.Descriptor instead.
-func (*Snowflake) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *Snowflake) GetStartTime() string {
-	if x != nil {
-		return x.StartTime
-	}
-	return ""
+// Deprecated: Use AI_ToolList.ProtoReflect.Descriptor instead.
+func (*AI_ToolList) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 0}
 }
 
-func (x *Snowflake) GetMachineId() int64 {
+func (x *AI_ToolList) GetTools() []string {
 	if x != nil {
-		return x.MachineId
+		return x.Tools
 	}
-	return 0
+	return nil
 }
 
-type Registry struct {
+// moderation_routes 按评论语言（ISO 639-1 代码，如 zh、en，审核前在本地按文字检测）选择审核提示词和模型，
+// 未配置的语言使用 moderation_guide_file 和 moderation_model；审核日志记录检测到的语言和使用的提示词。
+// guide_file 格式同 moderation_guide_file，为空时使用内置的默认值；model 为空时使用 moderation_model
+type AI_ModerationRoute struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Consul        *Registry_Consul       `protobuf:"bytes,1,opt,name=consul,proto3" json:"consul,omitempty"`
+	GuideFile     string                 `protobuf:"bytes,1,opt,name=guide_file,json=guideFile,proto3" json:"guide_file,omitempty"`
+	Model         string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Registry) Reset() {
-	*x = Registry{}
-	mi := &file_conf_conf_proto_msgTypes[4]
+func (x *AI_ModerationRoute) Reset() {
+	*x = AI_ModerationRoute{}
+	mi := &file_conf_conf_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Registry) String() string {
+func (x *AI_ModerationRoute) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Registry) ProtoMessage() {}
+func (*AI_ModerationRoute) ProtoMessage() {}
 
-func (x *Registry) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[4]
+func (x *AI_ModerationRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -286,40 +2019,49 @@ func (x *Registry) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Registry.ProtoReflect.Descriptor instead.
-func (*Registry) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use AI_ModerationRoute.ProtoReflect.Descriptor instead.
+func (*AI_ModerationRoute) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{7, 3}
 }
 
-func (x *Registry) GetConsul() *Registry_Consul {
+func (x *AI_ModerationRoute) GetGuideFile() string {
 	if x != nil {
-		return x.Consul
+		return x.GuideFile
 	}
-	return nil
+	return ""
 }
 
-type Elasticsearch struct {
+func (x *AI_ModerationRoute) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+// Tag 话题标签及其关键词，评论内容包含任一关键词（不区分大小写）即打上该标签
+type Review_Tag struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Addresses     []string               `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Keywords      []string               `protobuf:"bytes,2,rep,name=keywords,proto3" json:"keywords,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Elasticsearch) Reset() {
-	*x = Elasticsearch{}
-	mi := &file_conf_conf_proto_msgTypes[5]
+func (x *Review_Tag) Reset() {
+	*x = Review_Tag{}
+	mi := &file_conf_conf_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Elasticsearch) String() string {
+func (x *Review_Tag) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Elasticsearch) ProtoMessage() {}
+func (*Review_Tag) ProtoMessage() {}
 
-func (x *Elasticsearch) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[5]
+func (x *Review_Tag) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -330,41 +2072,52 @@ func (x *Elasticsearch) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Elasticsearch.ProtoReflect.Descriptor instead.
-func (*Elasticsearch) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use Review_Tag.ProtoReflect.Descriptor instead.
+func (*Review_Tag) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 0}
 }
 
-func (x *Elasticsearch) GetAddresses() []string {
+func (x *Review_Tag) GetName() string {
 	if x != nil {
-		return x.Addresses
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Review_Tag) GetKeywords() []string {
+	if x != nil {
+		return x.Keywords
 	}
 	return nil
 }
 
-type AI struct {
+// ScoreScale 评分（score、service_score、express_score）的取值范围，三项评分使用同一范围，
+// 如 1~5 五星、1~10 十分制、0~1 点赞/点踩；max 未配置或不大于 min 时为 1~5。
+// 店铺平均评分是已通过评论原始评分的算术平均值，与评分处于同一范围（0~1 时即好评率），
+// 修改范围不会换算已有评论的评分，已有评论仍按原值参与平均
+type Review_ScoreScale struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
-	Model         string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Min           int32                  `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           int32                  `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AI) Reset() {
-	*x = AI{}
-	mi := &file_conf_conf_proto_msgTypes[6]
+func (x *Review_ScoreScale) Reset() {
+	*x = Review_ScoreScale{}
+	mi := &file_conf_conf_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AI) String() string {
+func (x *Review_ScoreScale) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AI) ProtoMessage() {}
+func (*Review_ScoreScale) ProtoMessage() {}
 
-func (x *AI) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[6]
+func (x *Review_ScoreScale) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -375,49 +2128,56 @@ func (x *AI) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AI.ProtoReflect.Descriptor instead.
-func (*AI) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use Review_ScoreScale.ProtoReflect.Descriptor instead.
+func (*Review_ScoreScale) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 1}
 }
 
-func (x *AI) GetApiKey() string {
+func (x *Review_ScoreScale) GetMin() int32 {
 	if x != nil {
-		return x.ApiKey
+		return x.Min
 	}
-	return ""
+	return 0
 }
 
-func (x *AI) GetModel() string {
+func (x *Review_ScoreScale) GetMax() int32 {
 	if x != nil {
-		return x.Model
+		return x.Max
 	}
-	return ""
+	return 0
 }
 
-type Server_HTTP struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
-	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
-	Timeout       *durationpb.Duration   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+// MediaSizeCheck 创建评论时对每个图片/视频URL发起HEAD请求，按 Content-Length 拒绝过大的媒体；
+// 会增加创建评论的耗时，默认关闭
+type Review_MediaSizeCheck struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Enabled bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// max_bytes 单个媒体文件的大小上限，默认 20MB
+	MaxBytes int64 `protobuf:"varint,2,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	// timeout 单个HEAD请求的超时时间，默认 3s；concurrency 同时进行的HEAD请求数，默认 4
+	Timeout     *durationpb.Duration `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Concurrency int32                `protobuf:"varint,4,opt,name=concurrency,proto3" json:"concurrency,omitempty"`
+	// on_unknown 请求失败、返回非2xx（如不支持HEAD）或没有 Content-Length 时的处理方式：skip 跳过（默认），reject 拒绝
+	OnUnknown     string `protobuf:"bytes,5,opt,name=on_unknown,json=onUnknown,proto3" json:"on_unknown,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Server_HTTP) Reset() {
-	*x = Server_HTTP{}
-	mi := &file_conf_conf_proto_msgTypes[7]
+func (x *Review_MediaSizeCheck) Reset() {
+	*x = Review_MediaSizeCheck{}
+	mi := &file_conf_conf_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Server_HTTP) String() string {
+func (x *Review_MediaSizeCheck) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Server_HTTP) ProtoMessage() {}
+func (*Review_MediaSizeCheck) ProtoMessage() {}
 
-func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[7]
+func (x *Review_MediaSizeCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -428,56 +2188,75 @@ func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Server_HTTP.ProtoReflect.Descriptor instead.
-func (*Server_HTTP) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{1, 0}
+// Deprecated: Use Review_MediaSizeCheck.ProtoReflect.Descriptor instead.
+func (*Review_MediaSizeCheck) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 2}
 }
 
-func (x *Server_HTTP) GetNetwork() string {
+func (x *Review_MediaSizeCheck) GetEnabled() bool {
 	if x != nil {
-		return x.Network
+		return x.Enabled
 	}
-	return ""
+	return false
 }
 
-func (x *Server_HTTP) GetAddr() string {
+func (x *Review_MediaSizeCheck) GetMaxBytes() int64 {
 	if x != nil {
-		return x.Addr
+		return x.MaxBytes
 	}
-	return ""
+	return 0
 }
 
-func (x *Server_HTTP) GetTimeout() *durationpb.Duration {
+func (x *Review_MediaSizeCheck) GetTimeout() *durationpb.Duration {
 	if x != nil {
 		return x.Timeout
 	}
 	return nil
 }
 
-type Server_GRPC struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
-	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
-	Timeout       *durationpb.Duration   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+func (x *Review_MediaSizeCheck) GetConcurrency() int32 {
+	if x != nil {
+		return x.Concurrency
+	}
+	return 0
+}
+
+func (x *Review_MediaSizeCheck) GetOnUnknown() string {
+	if x != nil {
+		return x.OnUnknown
+	}
+	return ""
+}
+
+// TrustedFastPath 受信用户快速通道：作者已通过的评论数达到阈值且从未被驳回或隐藏时，
+// 新评论只做本地敏感词检查，不调用AI，检查通过即直接通过，并以 trusted-fast-path 记录审核日志；
+// 本地检查不通过时仍走AI审核
+type Review_TrustedFastPath struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Enabled bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// min_approved 作者已通过的评论数下限，未配置时为 5
+	MinApproved int32 `protobuf:"varint,2,opt,name=min_approved,json=minApproved,proto3" json:"min_approved,omitempty"`
+	// blocked_words 本地敏感词表（不区分大小写），内容包含任一词时走AI审核
+	BlockedWords  []string `protobuf:"bytes,3,rep,name=blocked_words,json=blockedWords,proto3" json:"blocked_words,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Server_GRPC) Reset() {
-	*x = Server_GRPC{}
-	mi := &file_conf_conf_proto_msgTypes[8]
+func (x *Review_TrustedFastPath) Reset() {
+	*x = Review_TrustedFastPath{}
+	mi := &file_conf_conf_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Server_GRPC) String() string {
+func (x *Review_TrustedFastPath) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Server_GRPC) ProtoMessage() {}
+func (*Review_TrustedFastPath) ProtoMessage() {}
 
-func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[8]
+func (x *Review_TrustedFastPath) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -488,55 +2267,67 @@ func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Server_GRPC.ProtoReflect.Descriptor instead.
-func (*Server_GRPC) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{1, 1}
+// Deprecated: Use Review_TrustedFastPath.ProtoReflect.Descriptor instead.
+func (*Review_TrustedFastPath) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 3}
 }
 
-func (x *Server_GRPC) GetNetwork() string {
+func (x *Review_TrustedFastPath) GetEnabled() bool {
 	if x != nil {
-		return x.Network
+		return x.Enabled
 	}
-	return ""
+	return false
 }
 
-func (x *Server_GRPC) GetAddr() string {
+func (x *Review_TrustedFastPath) GetMinApproved() int32 {
 	if x != nil {
-		return x.Addr
+		return x.MinApproved
 	}
-	return ""
+	return 0
 }
 
-func (x *Server_GRPC) GetTimeout() *durationpb.Duration {
+func (x *Review_TrustedFastPath) GetBlockedWords() []string {
 	if x != nil {
-		return x.Timeout
+		return x.BlockedWords
 	}
 	return nil
 }
 
-type Data_Database struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Driver        string                 `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
-	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+// ModerationCache 按内容缓存AI审核结论，相同内容的评论直接复用结论，不再调用AI；默认关闭
+type Review_ModerationCache struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Enabled bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// mode 缓存匹配方式：exact 按原文的哈希匹配（默认）；fuzzy 另外按归一化文本的SimHash指纹匹配，
+	// 与已驳回内容近似（指纹汉明距离不超过 fuzzy_distance）的评论直接驳回。近似匹配只复用驳回结论，
+	// 通过的结论仍只按原文匹配，避免在已通过的内容中夹带违规信息
+	Mode string `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	// ttl 结论的缓存时间，默认 24h
+	Ttl *durationpb.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// fuzzy_distance 近似匹配允许的最大汉明距离，取值 0~3，默认 3
+	FuzzyDistance int32 `protobuf:"varint,4,opt,name=fuzzy_distance,json=fuzzyDistance,proto3" json:"fuzzy_distance,omitempty"`
+	// max_entries 缓存结论数的近似上限，超出时淘汰最早写入的结论，0 表示只按 ttl 过期。
+	// 结论数通过一个有序集合计数，与结论一样按 ttl 清理；需要严格限制内存时，
+	// 可以另外为缓存使用单独的 Redis 实例并配置 maxmemory 和 volatile-ttl / allkeys-lru 淘汰策略
+	MaxEntries    int32 `protobuf:"varint,5,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Data_Database) Reset() {
-	*x = Data_Database{}
-	mi := &file_conf_conf_proto_msgTypes[9]
+func (x *Review_ModerationCache) Reset() {
+	*x = Review_ModerationCache{}
+	mi := &file_conf_conf_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Data_Database) String() string {
+func (x *Review_ModerationCache) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Data_Database) ProtoMessage() {}
+func (*Review_ModerationCache) ProtoMessage() {}
 
-func (x *Data_Database) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[9]
+func (x *Review_ModerationCache) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -547,50 +2338,75 @@ func (x *Data_Database) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Data_Database.ProtoReflect.Descriptor instead.
-func (*Data_Database) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{2, 0}
+// Deprecated: Use Review_ModerationCache.ProtoReflect.Descriptor instead.
+func (*Review_ModerationCache) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 4}
 }
 
-func (x *Data_Database) GetDriver() string {
+func (x *Review_ModerationCache) GetEnabled() bool {
 	if x != nil {
-		return x.Driver
+		return x.Enabled
 	}
-	return ""
+	return false
 }
 
-func (x *Data_Database) GetSource() string {
+func (x *Review_ModerationCache) GetMode() string {
 	if x != nil {
-		return x.Source
+		return x.Mode
 	}
 	return ""
 }
 
-type Data_Redis struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
-	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
-	ReadTimeout   *durationpb.Duration   `protobuf:"bytes,3,opt,name=read_timeout,json=readTimeout,proto3" json:"read_timeout,omitempty"`
-	WriteTimeout  *durationpb.Duration   `protobuf:"bytes,4,opt,name=write_timeout,json=writeTimeout,proto3" json:"write_timeout,omitempty"`
+func (x *Review_ModerationCache) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *Review_ModerationCache) GetFuzzyDistance() int32 {
+	if x != nil {
+		return x.FuzzyDistance
+	}
+	return 0
+}
+
+func (x *Review_ModerationCache) GetMaxEntries() int32 {
+	if x != nil {
+		return x.MaxEntries
+	}
+	return 0
+}
+
+// LanguagePolicy 评论语言策略：按本地检测的语言（zh/en/ja/ko）检查评论内容，与内容审核相互独立；默认关闭。
+// 无法判断语言的内容（如只有数字或表情）不受限制
+type Review_LanguagePolicy struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Enabled bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// allowed 允许的语言（ISO 639-1 代码，如 zh、en），为空时不限制
+	Allowed []string `protobuf:"bytes,2,rep,name=allowed,proto3" json:"allowed,omitempty"`
+	// action 语言不在允许范围内时的处理方式：reject 创建、重新提交时直接拒绝（默认）；
+	// human_review 正常创建，审核时不调用AI，保持待审核并在审核备注中标记待人工审核
+	Action        string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Data_Redis) Reset() {
-	*x = Data_Redis{}
-	mi := &file_conf_conf_proto_msgTypes[10]
+func (x *Review_LanguagePolicy) Reset() {
+	*x = Review_LanguagePolicy{}
+	mi := &file_conf_conf_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Data_Redis) String() string {
+func (x *Review_LanguagePolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Data_Redis) ProtoMessage() {}
+func (*Review_LanguagePolicy) ProtoMessage() {}
 
-func (x *Data_Redis) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[10]
+func (x *Review_LanguagePolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -601,62 +2417,58 @@ func (x *Data_Redis) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Data_Redis.ProtoReflect.Descriptor instead.
-func (*Data_Redis) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{2, 1}
-}
-
-func (x *Data_Redis) GetNetwork() string {
-	if x != nil {
-		return x.Network
-	}
-	return ""
+// Deprecated: Use Review_LanguagePolicy.ProtoReflect.Descriptor instead.
+func (*Review_LanguagePolicy) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 5}
 }
 
-func (x *Data_Redis) GetAddr() string {
+func (x *Review_LanguagePolicy) GetEnabled() bool {
 	if x != nil {
-		return x.Addr
+		return x.Enabled
 	}
-	return ""
+	return false
 }
 
-func (x *Data_Redis) GetReadTimeout() *durationpb.Duration {
+func (x *Review_LanguagePolicy) GetAllowed() []string {
 	if x != nil {
-		return x.ReadTimeout
+		return x.Allowed
 	}
 	return nil
 }
 
-func (x *Data_Redis) GetWriteTimeout() *durationpb.Duration {
+func (x *Review_LanguagePolicy) GetAction() string {
 	if x != nil {
-		return x.WriteTimeout
+		return x.Action
 	}
-	return nil
+	return ""
 }
 
-type Registry_Consul struct {
+// AppendFormat 追加评论单独保存，不再拼接到评论内容中；审核时按该格式将原文和各条追加评论拼接成完整内容。
+// separator 每条追加评论前的分隔文本，{time} 替换为追加时间，默认为 "\n\n[追加评论 {time}]:\n"；
+// time_layout 追加时间的格式（Go 时间格式），默认为 "2006-01-02 15:04:05"
+type Review_AppendFormat struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	Scheme        string                 `protobuf:"bytes,2,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	Separator     string                 `protobuf:"bytes,1,opt,name=separator,proto3" json:"separator,omitempty"`
+	TimeLayout    string                 `protobuf:"bytes,2,opt,name=time_layout,json=timeLayout,proto3" json:"time_layout,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Registry_Consul) Reset() {
-	*x = Registry_Consul{}
-	mi := &file_conf_conf_proto_msgTypes[11]
+func (x *Review_AppendFormat) Reset() {
+	*x = Review_AppendFormat{}
+	mi := &file_conf_conf_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Registry_Consul) String() string {
+func (x *Review_AppendFormat) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Registry_Consul) ProtoMessage() {}
+func (*Review_AppendFormat) ProtoMessage() {}
 
-func (x *Registry_Consul) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[11]
+func (x *Review_AppendFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -667,21 +2479,21 @@ func (x *Registry_Consul) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Registry_Consul.ProtoReflect.Descriptor instead.
-func (*Registry_Consul) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{4, 0}
+// Deprecated: Use Review_AppendFormat.ProtoReflect.Descriptor instead.
+func (*Review_AppendFormat) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{9, 6}
 }
 
-func (x *Registry_Consul) GetAddress() string {
+func (x *Review_AppendFormat) GetSeparator() string {
 	if x != nil {
-		return x.Address
+		return x.Separator
 	}
 	return ""
 }
 
-func (x *Registry_Consul) GetScheme() string {
+func (x *Review_AppendFormat) GetTimeLayout() string {
 	if x != nil {
-		return x.Scheme
+		return x.TimeLayout
 	}
 	return ""
 }
@@ -691,16 +2503,24 @@ var File_conf_conf_proto protoreflect.FileDescriptor
 const file_conf_conf_proto_rawDesc = "" +
 	"\n" +
 	"\x0fconf/conf.proto\x12\n" +
-	"kratos.api\x1a\x1egoogle/protobuf/duration.proto\"\xf3\x01\n" +
+	"kratos.api\x1a\x1egoogle/protobuf/duration.proto\"\xe8\x02\n" +
 	"\tBootstrap\x12*\n" +
 	"\x06server\x18\x01 \x01(\v2\x12.kratos.api.ServerR\x06server\x12$\n" +
 	"\x04data\x18\x02 \x01(\v2\x10.kratos.api.DataR\x04data\x123\n" +
 	"\tsnowflake\x18\x03 \x01(\v2\x15.kratos.api.SnowflakeR\tsnowflake\x12?\n" +
 	"\relasticsearch\x18\x04 \x01(\v2\x19.kratos.api.ElasticsearchR\relasticsearch\x12\x1e\n" +
-	"\x02ai\x18\x05 \x01(\v2\x0e.kratos.api.AIR\x02ai\"\xb8\x02\n" +
+	"\x02ai\x18\x05 \x01(\v2\x0e.kratos.api.AIR\x02ai\x12$\n" +
+	"\x04auth\x18\x06 \x01(\v2\x10.kratos.api.AuthR\x04auth\x12*\n" +
+	"\x06review\x18\a \x01(\v2\x12.kratos.api.ReviewR\x06review\x12!\n" +
+	"\x03log\x18\b \x01(\v2\x0f.kratos.api.LogR\x03log\"n\n" +
+	"\x03Log\x12\x16\n" +
+	"\x06redact\x18\x01 \x01(\bR\x06redact\x12,\n" +
+	"\x12max_content_length\x18\x02 \x01(\x05R\x10maxContentLength\x12!\n" +
+	"\ffull_content\x18\x03 \x01(\bR\vfullContent\"\x9a\x04\n" +
 	"\x06Server\x12+\n" +
 	"\x04http\x18\x01 \x01(\v2\x17.kratos.api.Server.HTTPR\x04http\x12+\n" +
-	"\x04grpc\x18\x02 \x01(\v2\x17.kratos.api.Server.GRPCR\x04grpc\x1ai\n" +
+	"\x04grpc\x18\x02 \x01(\v2\x17.kratos.api.Server.GRPCR\x04grpc\x121\n" +
+	"\x06static\x18\x03 \x01(\v2\x19.kratos.api.Server.StaticR\x06static\x1ai\n" +
 	"\x04HTTP\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
@@ -708,18 +2528,46 @@ const file_conf_conf_proto_rawDesc = "" +
 	"\x04GRPC\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
-	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"\xdd\x02\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x1a\xac\x01\n" +
+	"\x06Static\x12\x1a\n" +
+	"\bdisabled\x18\x01 \x01(\bR\bdisabled\x12\x1a\n" +
+	"\bembedded\x18\x02 \x01(\bR\bembedded\x127\n" +
+	"\x06mounts\x18\x03 \x03(\v2\x1f.kratos.api.Server.Static.MountR\x06mounts\x1a1\n" +
+	"\x05Mount\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x10\n" +
+	"\x03dir\x18\x02 \x01(\tR\x03dir\"\x92\b\n" +
 	"\x04Data\x125\n" +
 	"\bdatabase\x18\x01 \x01(\v2\x19.kratos.api.Data.DatabaseR\bdatabase\x12,\n" +
-	"\x05redis\x18\x02 \x01(\v2\x16.kratos.api.Data.RedisR\x05redis\x1a:\n" +
+	"\x05redis\x18\x02 \x01(\v2\x16.kratos.api.Data.RedisR\x05redis\x12,\n" +
+	"\x05async\x18\x03 \x01(\v2\x16.kratos.api.Data.AsyncR\x05async\x122\n" +
+	"\astartup\x18\x04 \x01(\v2\x18.kratos.api.Data.StartupR\astartup\x1a:\n" +
 	"\bDatabase\x12\x16\n" +
 	"\x06driver\x18\x01 \x01(\tR\x06driver\x12\x16\n" +
-	"\x06source\x18\x02 \x01(\tR\x06source\x1a\xb3\x01\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x1a\xdb\x01\n" +
 	"\x05Redis\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x12<\n" +
 	"\fread_timeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\vreadTimeout\x12>\n" +
-	"\rwrite_timeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\fwriteTimeout\"I\n" +
+	"\rwrite_timeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\fwriteTimeout\x12&\n" +
+	"\x0fmax_value_bytes\x18\x05 \x01(\x03R\rmaxValueBytes\x1a\x9c\x03\n" +
+	"\x05Async\x12\x18\n" +
+	"\aworkers\x18\x01 \x01(\x05R\aworkers\x12\x1d\n" +
+	"\n" +
+	"queue_size\x18\x02 \x01(\x05R\tqueueSize\x12\x14\n" +
+	"\x05order\x18\x03 \x01(\tR\x05order\x123\n" +
+	"\atimeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12;\n" +
+	"\bpriority\x18\x05 \x01(\v2\x1f.kratos.api.Data.Async.PriorityR\bpriority\x1a\xd1\x01\n" +
+	"\bPriority\x12!\n" +
+	"\ftrusted_user\x18\x01 \x01(\x05R\vtrustedUser\x12C\n" +
+	"\x06stores\x18\x02 \x03(\v2+.kratos.api.Data.Async.Priority.StoresEntryR\x06stores\x12\"\n" +
+	"\fresubmission\x18\x03 \x01(\x05R\fresubmission\x1a9\n" +
+	"\vStoresEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a\x89\x01\n" +
+	"\aStartup\x12\x12\n" +
+	"\x04mode\x18\x01 \x01(\tR\x04mode\x123\n" +
+	"\atimeout\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x125\n" +
+	"\binterval\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\binterval\"I\n" +
 	"\tSnowflake\x12\x1d\n" +
 	"\n" +
 	"start_time\x18\x01 \x01(\tR\tstartTime\x12\x1d\n" +
@@ -729,12 +2577,149 @@ const file_conf_conf_proto_rawDesc = "" +
 	"\x06consul\x18\x01 \x01(\v2\x1b.kratos.api.Registry.ConsulR\x06consul\x1a:\n" +
 	"\x06Consul\x12\x18\n" +
 	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
-	"\x06scheme\x18\x02 \x01(\tR\x06scheme\"-\n" +
+	"\x06scheme\x18\x02 \x01(\tR\x06scheme\"\xb9\a\n" +
 	"\rElasticsearch\x12\x1c\n" +
-	"\taddresses\x18\x01 \x03(\tR\taddresses\"3\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses\x12\x18\n" +
+	"\arefresh\x18\x02 \x01(\tR\arefresh\x12(\n" +
+	"\x10track_total_hits\x18\x03 \x01(\bR\x0etrackTotalHits\x123\n" +
+	"\atimeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12?\n" +
+	"\x1callow_partial_search_results\x18\x05 \x01(\bR\x19allowPartialSearchResults\x12A\n" +
+	"\treconcile\x18\x06 \x01(\v2#.kratos.api.Elasticsearch.ReconcileR\treconcile\x12\x1a\n" +
+	"\banalyzer\x18\a \x01(\tR\banalyzer\x12'\n" +
+	"\x0fsearch_analyzer\x18\b \x01(\tR\x0esearchAnalyzer\x122\n" +
+	"\x04bulk\x18\t \x01(\v2\x1e.kratos.api.Elasticsearch.BulkR\x04bulk\x12*\n" +
+	"\x11max_result_window\x18\n" +
+	" \x01(\x05R\x0fmaxResultWindow\x12,\n" +
+	"\x12max_document_bytes\x18\v \x01(\x03R\x10maxDocumentBytes\x12\x1f\n" +
+	"\von_oversize\x18\f \x01(\tR\n" +
+	"onOversize\x128\n" +
+	"\x06outbox\x18\r \x01(\v2 .kratos.api.Elasticsearch.OutboxR\x06outbox\x1aa\n" +
+	"\tReconcile\x125\n" +
+	"\binterval\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x02 \x01(\x05R\tbatchSize\x1ag\n" +
+	"\x04Bulk\x12\x1d\n" +
+	"\n" +
+	"flush_size\x18\x01 \x01(\x05R\tflushSize\x12@\n" +
+	"\x0eflush_interval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\rflushInterval\x1a\x92\x01\n" +
+	"\x06Outbox\x125\n" +
+	"\binterval\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x02 \x01(\x05R\tbatchSize\x122\n" +
+	"\amin_age\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x06minAge\"\xcf\n" +
+	"\n" +
 	"\x02AI\x12\x17\n" +
 	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x14\n" +
-	"\x05model\x18\x02 \x01(\tR\x05modelB\x1bZ\x19review/internal/conf;confb\x06proto3"
+	"\x05model\x18\x02 \x01(\tR\x05model\x12,\n" +
+	"\x12system_prompt_file\x18\x03 \x01(\tR\x10systemPromptFile\x12.\n" +
+	"\x13summary_prompt_file\x18\x04 \x01(\tR\x11summaryPromptFile\x12\"\n" +
+	"\rmax_in_flight\x18\x05 \x01(\x05R\vmaxInFlight\x12\x17\n" +
+	"\amax_qps\x18\x06 \x01(\x01R\x06maxQps\x12>\n" +
+	"\rqueue_timeout\x18\a \x01(\v2\x19.google.protobuf.DurationR\fqueueTimeout\x12)\n" +
+	"\x10moderation_model\x18\b \x01(\tR\x0fmoderationModel\x12\x1f\n" +
+	"\vagent_model\x18\t \x01(\tR\n" +
+	"agentModel\x12#\n" +
+	"\rsummary_model\x18\n" +
+	" \x01(\tR\fsummaryModel\x122\n" +
+	"\x15moderation_guide_file\x18\v \x01(\tR\x13moderationGuideFile\x12(\n" +
+	"\x10max_query_length\x18\f \x01(\x05R\x0emaxQueryLength\x12*\n" +
+	"\x11max_prompt_length\x18\r \x01(\x05R\x0fmaxPromptLength\x120\n" +
+	"\x14max_context_messages\x18\x0e \x01(\x05R\x12maxContextMessages\x12<\n" +
+	"\n" +
+	"role_tools\x18\x0f \x03(\v2\x1d.kratos.api.AI.RoleToolsEntryR\troleTools\x12!\n" +
+	"\fmax_sessions\x18\x10 \x01(\x05R\vmaxSessions\x12<\n" +
+	"\ftool_timeout\x18\x11 \x01(\v2\x19.google.protobuf.DurationR\vtoolTimeout\x12E\n" +
+	"\rtool_timeouts\x18\x12 \x03(\v2 .kratos.api.AI.ToolTimeoutsEntryR\ftoolTimeouts\x12Q\n" +
+	"\x11moderation_routes\x18\x13 \x03(\v2$.kratos.api.AI.ModerationRoutesEntryR\x10moderationRoutes\x12\x19\n" +
+	"\bapi_keys\x18\x14 \x03(\tR\aapiKeys\x12<\n" +
+	"\fkey_cooldown\x18\x15 \x01(\v2\x19.google.protobuf.DurationR\vkeyCooldown\x1a \n" +
+	"\bToolList\x12\x14\n" +
+	"\x05tools\x18\x01 \x03(\tR\x05tools\x1aU\n" +
+	"\x0eRoleToolsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\x05value\x18\x02 \x01(\v2\x17.kratos.api.AI.ToolListR\x05value:\x028\x01\x1aZ\n" +
+	"\x11ToolTimeoutsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12/\n" +
+	"\x05value\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x05value:\x028\x01\x1aF\n" +
+	"\x0fModerationRoute\x12\x1d\n" +
+	"\n" +
+	"guide_file\x18\x01 \x01(\tR\tguideFile\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x1ac\n" +
+	"\x15ModerationRoutesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x124\n" +
+	"\x05value\x18\x02 \x01(\v2\x1e.kratos.api.AI.ModerationRouteR\x05value:\x028\x01\"\xda\x02\n" +
+	"\x04Auth\x12\x1d\n" +
+	"\n" +
+	"jwt_secret\x18\x01 \x01(\tR\tjwtSecret\x12\x16\n" +
+	"\x06issuer\x18\x02 \x01(\tR\x06issuer\x12\x1a\n" +
+	"\baudience\x18\x03 \x01(\tR\baudience\x12!\n" +
+	"\fdefault_role\x18\x04 \x01(\tR\vdefaultRole\x126\n" +
+	"\ttoken_ttl\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\btokenTtl\x12H\n" +
+	"\x0erole_token_ttl\x18\x06 \x03(\v2\".kratos.api.Auth.RoleTokenTtlEntryR\froleTokenTtl\x1aZ\n" +
+	"\x11RoleTokenTtlEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12/\n" +
+	"\x05value\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x05value:\x028\x01\"\xcc\x0f\n" +
+	"\x06Review\x12,\n" +
+	"\x12content_min_length\x18\x01 \x01(\x05R\x10contentMinLength\x12,\n" +
+	"\x12content_max_length\x18\x02 \x01(\x05R\x10contentMaxLength\x12-\n" +
+	"\x12deletable_statuses\x18\x03 \x03(\x05R\x11deletableStatuses\x12\x1f\n" +
+	"\vmax_appends\x18\x04 \x01(\x05R\n" +
+	"maxAppends\x12-\n" +
+	"\x12pending_visibility\x18\x05 \x01(\tR\x11pendingVisibility\x129\n" +
+	"\x19appeal_content_max_length\x18\x06 \x01(\x05R\x16appealContentMaxLength\x12&\n" +
+	"\x0fappeal_max_pics\x18\a \x01(\x05R\rappealMaxPics\x12*\n" +
+	"\x11appeal_max_videos\x18\b \x01(\x05R\x0fappealMaxVideos\x12\x1f\n" +
+	"\vmedia_hosts\x18\t \x03(\tR\n" +
+	"mediaHosts\x12(\n" +
+	"\x10appeal_ai_assist\x18\n" +
+	" \x01(\bR\x0eappealAiAssist\x12*\n" +
+	"\x04tags\x18\v \x03(\v2\x16.kratos.api.Review.TagR\x04tags\x12#\n" +
+	"\rmax_resubmits\x18\f \x01(\x05R\fmaxResubmits\x12\x1e\n" +
+	"\von_ai_error\x18\r \x01(\tR\tonAiError\x125\n" +
+	"\x17preview_rate_per_minute\x18\x0e \x01(\x05R\x14previewRatePerMinute\x12E\n" +
+	"\x11score_edit_window\x18\x0f \x01(\v2\x19.google.protobuf.DurationR\x0fscoreEditWindow\x12>\n" +
+	"\vscore_scale\x18\x11 \x01(\v2\x1d.kratos.api.Review.ScoreScaleR\n" +
+	"scoreScale\x12K\n" +
+	"\x10media_size_check\x18\x12 \x01(\v2!.kratos.api.Review.MediaSizeCheckR\x0emediaSizeCheck\x12N\n" +
+	"\x11trusted_fast_path\x18\x10 \x01(\v2\".kratos.api.Review.TrustedFastPathR\x0ftrustedFastPath\x122\n" +
+	"\x15allow_rejected_appeal\x18\x13 \x01(\bR\x13allowRejectedAppeal\x12M\n" +
+	"\x10moderation_cache\x18\x14 \x01(\v2\".kratos.api.Review.ModerationCacheR\x0fmoderationCache\x12J\n" +
+	"\x0flanguage_policy\x18\x15 \x01(\v2!.kratos.api.Review.LanguagePolicyR\x0elanguagePolicy\x12D\n" +
+	"\rappend_format\x18\x16 \x01(\v2\x1f.kratos.api.Review.AppendFormatR\fappendFormat\x12*\n" +
+	"\x11default_page_size\x18\x17 \x01(\x05R\x0fdefaultPageSize\x1a5\n" +
+	"\x03Tag\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bkeywords\x18\x02 \x03(\tR\bkeywords\x1a0\n" +
+	"\n" +
+	"ScoreScale\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x05R\x03min\x12\x10\n" +
+	"\x03max\x18\x02 \x01(\x05R\x03max\x1a\xbd\x01\n" +
+	"\x0eMediaSizeCheck\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1b\n" +
+	"\tmax_bytes\x18\x02 \x01(\x03R\bmaxBytes\x123\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12 \n" +
+	"\vconcurrency\x18\x04 \x01(\x05R\vconcurrency\x12\x1d\n" +
+	"\n" +
+	"on_unknown\x18\x05 \x01(\tR\tonUnknown\x1as\n" +
+	"\x0fTrustedFastPath\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12!\n" +
+	"\fmin_approved\x18\x02 \x01(\x05R\vminApproved\x12#\n" +
+	"\rblocked_words\x18\x03 \x03(\tR\fblockedWords\x1a\xb4\x01\n" +
+	"\x0fModerationCache\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode\x12+\n" +
+	"\x03ttl\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x12%\n" +
+	"\x0efuzzy_distance\x18\x04 \x01(\x05R\rfuzzyDistance\x12\x1f\n" +
+	"\vmax_entries\x18\x05 \x01(\x05R\n" +
+	"maxEntries\x1a\\\n" +
+	"\x0eLanguagePolicy\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x18\n" +
+	"\aallowed\x18\x02 \x03(\tR\aallowed\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x1aM\n" +
+	"\fAppendFormat\x12\x1c\n" +
+	"\tseparator\x18\x01 \x01(\tR\tseparator\x12\x1f\n" +
+	"\vtime_layout\x18\x02 \x01(\tR\n" +
+	"timeLayoutB\x1bZ\x19review/internal/conf;confb\x06proto3"
 
 var (
 	file_conf_conf_proto_rawDescOnce sync.Once
@@ -748,42 +2733,109 @@ func file_conf_conf_proto_rawDescGZIP() []byte {
 	return file_conf_conf_proto_rawDescData
 }
 
-var file_conf_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_conf_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
 var file_conf_conf_proto_goTypes = []any{
-	(*Bootstrap)(nil),           // 0: kratos.api.Bootstrap
-	(*Server)(nil),              // 1: kratos.api.Server
-	(*Data)(nil),                // 2: kratos.api.Data
-	(*Snowflake)(nil),           // 3: kratos.api.Snowflake
-	(*Registry)(nil),            // 4: kratos.api.Registry
-	(*Elasticsearch)(nil),       // 5: kratos.api.Elasticsearch
-	(*AI)(nil),                  // 6: kratos.api.AI
-	(*Server_HTTP)(nil),         // 7: kratos.api.Server.HTTP
-	(*Server_GRPC)(nil),         // 8: kratos.api.Server.GRPC
-	(*Data_Database)(nil),       // 9: kratos.api.Data.Database
-	(*Data_Redis)(nil),          // 10: kratos.api.Data.Redis
-	(*Registry_Consul)(nil),     // 11: kratos.api.Registry.Consul
-	(*durationpb.Duration)(nil), // 12: google.protobuf.Duration
+	(*Bootstrap)(nil),               // 0: kratos.api.Bootstrap
+	(*Log)(nil),                     // 1: kratos.api.Log
+	(*Server)(nil),                  // 2: kratos.api.Server
+	(*Data)(nil),                    // 3: kratos.api.Data
+	(*Snowflake)(nil),               // 4: kratos.api.Snowflake
+	(*Registry)(nil),                // 5: kratos.api.Registry
+	(*Elasticsearch)(nil),           // 6: kratos.api.Elasticsearch
+	(*AI)(nil),                      // 7: kratos.api.AI
+	(*Auth)(nil),                    // 8: kratos.api.Auth
+	(*Review)(nil),                  // 9: kratos.api.Review
+	(*Server_HTTP)(nil),             // 10: kratos.api.Server.HTTP
+	(*Server_GRPC)(nil),             // 11: kratos.api.Server.GRPC
+	(*Server_Static)(nil),           // 12: kratos.api.Server.Static
+	(*Server_Static_Mount)(nil),     // 13: kratos.api.Server.Static.Mount
+	(*Data_Database)(nil),           // 14: kratos.api.Data.Database
+	(*Data_Redis)(nil),              // 15: kratos.api.Data.Redis
+	(*Data_Async)(nil),              // 16: kratos.api.Data.Async
+	(*Data_Startup)(nil),            // 17: kratos.api.Data.Startup
+	(*Data_Async_Priority)(nil),     // 18: kratos.api.Data.Async.Priority
+	nil,                             // 19: kratos.api.Data.Async.Priority.StoresEntry
+	(*Registry_Consul)(nil),         // 20: kratos.api.Registry.Consul
+	(*Elasticsearch_Reconcile)(nil), // 21: kratos.api.Elasticsearch.Reconcile
+	(*Elasticsearch_Bulk)(nil),      // 22: kratos.api.Elasticsearch.Bulk
+	(*Elasticsearch_Outbox)(nil),    // 23: kratos.api.Elasticsearch.Outbox
+	(*AI_ToolList)(nil),             // 24: kratos.api.AI.ToolList
+	nil,                             // 25: kratos.api.AI.RoleToolsEntry
+	nil,                             // 26: kratos.api.AI.ToolTimeoutsEntry
+	(*AI_ModerationRoute)(nil),      // 27: kratos.api.AI.ModerationRoute
+	nil,                             // 28: kratos.api.AI.ModerationRoutesEntry
+	nil,                             // 29: kratos.api.Auth.RoleTokenTtlEntry
+	(*Review_Tag)(nil),              // 30: kratos.api.Review.Tag
+	(*Review_ScoreScale)(nil),       // 31: kratos.api.Review.ScoreScale
+	(*Review_MediaSizeCheck)(nil),   // 32: kratos.api.Review.MediaSizeCheck
+	(*Review_TrustedFastPath)(nil),  // 33: kratos.api.Review.TrustedFastPath
+	(*Review_ModerationCache)(nil),  // 34: kratos.api.Review.ModerationCache
+	(*Review_LanguagePolicy)(nil),   // 35: kratos.api.Review.LanguagePolicy
+	(*Review_AppendFormat)(nil),     // 36: kratos.api.Review.AppendFormat
+	(*durationpb.Duration)(nil),     // 37: google.protobuf.Duration
 }
 var file_conf_conf_proto_depIdxs = []int32{
-	1,  // 0: kratos.api.Bootstrap.server:type_name -> kratos.api.Server
-	2,  // 1: kratos.api.Bootstrap.data:type_name -> kratos.api.Data
-	3,  // 2: kratos.api.Bootstrap.snowflake:type_name -> kratos.api.Snowflake
-	5,  // 3: kratos.api.Bootstrap.elasticsearch:type_name -> kratos.api.Elasticsearch
-	6,  // 4: kratos.api.Bootstrap.ai:type_name -> kratos.api.AI
-	7,  // 5: kratos.api.Server.http:type_name -> kratos.api.Server.HTTP
-	8,  // 6: kratos.api.Server.grpc:type_name -> kratos.api.Server.GRPC
-	9,  // 7: kratos.api.Data.database:type_name -> kratos.api.Data.Database
-	10, // 8: kratos.api.Data.redis:type_name -> kratos.api.Data.Redis
-	11, // 9: kratos.api.Registry.consul:type_name -> kratos.api.Registry.Consul
-	12, // 10: kratos.api.Server.HTTP.timeout:type_name -> google.protobuf.Duration
-	12, // 11: kratos.api.Server.GRPC.timeout:type_name -> google.protobuf.Duration
-	12, // 12: kratos.api.Data.Redis.read_timeout:type_name -> google.protobuf.Duration
-	12, // 13: kratos.api.Data.Redis.write_timeout:type_name -> google.protobuf.Duration
-	14, // [14:14] is the sub-list for method output_type
-	14, // [14:14] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	2,  // 0: kratos.api.Bootstrap.server:type_name -> kratos.api.Server
+	3,  // 1: kratos.api.Bootstrap.data:type_name -> kratos.api.Data
+	4,  // 2: kratos.api.Bootstrap.snowflake:type_name -> kratos.api.Snowflake
+	6,  // 3: kratos.api.Bootstrap.elasticsearch:type_name -> kratos.api.Elasticsearch
+	7,  // 4: kratos.api.Bootstrap.ai:type_name -> kratos.api.AI
+	8,  // 5: kratos.api.Bootstrap.auth:type_name -> kratos.api.Auth
+	9,  // 6: kratos.api.Bootstrap.review:type_name -> kratos.api.Review
+	1,  // 7: kratos.api.Bootstrap.log:type_name -> kratos.api.Log
+	10, // 8: kratos.api.Server.http:type_name -> kratos.api.Server.HTTP
+	11, // 9: kratos.api.Server.grpc:type_name -> kratos.api.Server.GRPC
+	12, // 10: kratos.api.Server.static:type_name -> kratos.api.Server.Static
+	14, // 11: kratos.api.Data.database:type_name -> kratos.api.Data.Database
+	15, // 12: kratos.api.Data.redis:type_name -> kratos.api.Data.Redis
+	16, // 13: kratos.api.Data.async:type_name -> kratos.api.Data.Async
+	17, // 14: kratos.api.Data.startup:type_name -> kratos.api.Data.Startup
+	20, // 15: kratos.api.Registry.consul:type_name -> kratos.api.Registry.Consul
+	37, // 16: kratos.api.Elasticsearch.timeout:type_name -> google.protobuf.Duration
+	21, // 17: kratos.api.Elasticsearch.reconcile:type_name -> kratos.api.Elasticsearch.Reconcile
+	22, // 18: kratos.api.Elasticsearch.bulk:type_name -> kratos.api.Elasticsearch.Bulk
+	23, // 19: kratos.api.Elasticsearch.outbox:type_name -> kratos.api.Elasticsearch.Outbox
+	37, // 20: kratos.api.AI.queue_timeout:type_name -> google.protobuf.Duration
+	25, // 21: kratos.api.AI.role_tools:type_name -> kratos.api.AI.RoleToolsEntry
+	37, // 22: kratos.api.AI.tool_timeout:type_name -> google.protobuf.Duration
+	26, // 23: kratos.api.AI.tool_timeouts:type_name -> kratos.api.AI.ToolTimeoutsEntry
+	28, // 24: kratos.api.AI.moderation_routes:type_name -> kratos.api.AI.ModerationRoutesEntry
+	37, // 25: kratos.api.AI.key_cooldown:type_name -> google.protobuf.Duration
+	37, // 26: kratos.api.Auth.token_ttl:type_name -> google.protobuf.Duration
+	29, // 27: kratos.api.Auth.role_token_ttl:type_name -> kratos.api.Auth.RoleTokenTtlEntry
+	30, // 28: kratos.api.Review.tags:type_name -> kratos.api.Review.Tag
+	37, // 29: kratos.api.Review.score_edit_window:type_name -> google.protobuf.Duration
+	31, // 30: kratos.api.Review.score_scale:type_name -> kratos.api.Review.ScoreScale
+	32, // 31: kratos.api.Review.media_size_check:type_name -> kratos.api.Review.MediaSizeCheck
+	33, // 32: kratos.api.Review.trusted_fast_path:type_name -> kratos.api.Review.TrustedFastPath
+	34, // 33: kratos.api.Review.moderation_cache:type_name -> kratos.api.Review.ModerationCache
+	35, // 34: kratos.api.Review.language_policy:type_name -> kratos.api.Review.LanguagePolicy
+	36, // 35: kratos.api.Review.append_format:type_name -> kratos.api.Review.AppendFormat
+	37, // 36: kratos.api.Server.HTTP.timeout:type_name -> google.protobuf.Duration
+	37, // 37: kratos.api.Server.GRPC.timeout:type_name -> google.protobuf.Duration
+	13, // 38: kratos.api.Server.Static.mounts:type_name -> kratos.api.Server.Static.Mount
+	37, // 39: kratos.api.Data.Redis.read_timeout:type_name -> google.protobuf.Duration
+	37, // 40: kratos.api.Data.Redis.write_timeout:type_name -> google.protobuf.Duration
+	37, // 41: kratos.api.Data.Async.timeout:type_name -> google.protobuf.Duration
+	18, // 42: kratos.api.Data.Async.priority:type_name -> kratos.api.Data.Async.Priority
+	37, // 43: kratos.api.Data.Startup.timeout:type_name -> google.protobuf.Duration
+	37, // 44: kratos.api.Data.Startup.interval:type_name -> google.protobuf.Duration
+	19, // 45: kratos.api.Data.Async.Priority.stores:type_name -> kratos.api.Data.Async.Priority.StoresEntry
+	37, // 46: kratos.api.Elasticsearch.Reconcile.interval:type_name -> google.protobuf.Duration
+	37, // 47: kratos.api.Elasticsearch.Bulk.flush_interval:type_name -> google.protobuf.Duration
+	37, // 48: kratos.api.Elasticsearch.Outbox.interval:type_name -> google.protobuf.Duration
+	37, // 49: kratos.api.Elasticsearch.Outbox.min_age:type_name -> google.protobuf.Duration
+	24, // 50: kratos.api.AI.RoleToolsEntry.value:type_name -> kratos.api.AI.ToolList
+	37, // 51: kratos.api.AI.ToolTimeoutsEntry.value:type_name -> google.protobuf.Duration
+	27, // 52: kratos.api.AI.ModerationRoutesEntry.value:type_name -> kratos.api.AI.ModerationRoute
+	37, // 53: kratos.api.Auth.RoleTokenTtlEntry.value:type_name -> google.protobuf.Duration
+	37, // 54: kratos.api.Review.MediaSizeCheck.timeout:type_name -> google.protobuf.Duration
+	37, // 55: kratos.api.Review.ModerationCache.ttl:type_name -> google.protobuf.Duration
+	56, // [56:56] is the sub-list for method output_type
+	56, // [56:56] is the sub-list for method input_type
+	56, // [56:56] is the sub-list for extension type_name
+	56, // [56:56] is the sub-list for extension extendee
+	0,  // [0:56] is the sub-list for field type_name
 }
 
 func init() { file_conf_conf_proto_init() }
@@ -797,7 +2849,7 @@ func file_conf_conf_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_conf_conf_proto_rawDesc), len(file_conf_conf_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   37,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
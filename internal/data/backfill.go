@@ -0,0 +1,193 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/client/ai"
+	"review/internal/conf"
+	"review/internal/data/model"
+	"review/internal/data/query"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultBackfillBatchSize = 100
+	defaultBackfillQPS       = 1
+)
+
+// BackfillOptions 补全驳回类别的参数
+type BackfillOptions struct {
+	// BatchSize 每批读取的评论数, 未设置时为100
+	BatchSize int
+	// QPS 调用AI重新审核的速率上限, 未设置时为每秒1次; 同时受 ai.max_qps 限制
+	QPS float64
+	// Limit 最多处理的评论数, 0表示不限制
+	Limit int
+	// DryRun 只记录将要写入的类别, 不修改数据
+	DryRun bool
+}
+
+// BackfillStats 补全驳回类别的结果统计
+type BackfillStats struct {
+	Checked int
+	// FromLog 由已有审核日志中的类别补全, 未调用AI
+	FromLog int
+	// Moderated 调用AI重新审核的次数
+	Moderated int
+	Filled    int
+	// Skipped 重新审核后通过或没有给出类别, 以及处理期间评论已被修改的评论, 均保持不变
+	Skipped int
+	Failed  int
+}
+
+// CategoryBackfill 为驳回类别为空的已驳回评论补全驳回类别, 供 cmd/backfill 使用
+// 优先使用该评论最近一次AI驳回的审核日志中的类别; 日志中也没有类别时重新调用AI审核, 只取驳回类别和置信度,
+// 不改变评论的状态和驳回理由。评论按版本号更新并写入ES同步发件箱, 由服务中的发件箱或定时对账同步到ES
+type CategoryBackfill struct {
+	repo *reviewRepo
+	log  *log.Helper
+}
+
+func NewCategoryBackfill(db *gorm.DB, aiClient *ai.AIClient, esConf *conf.Elasticsearch, reviewConf *conf.Review, logger log.Logger) *CategoryBackfill {
+	helper := log.NewHelper(logger)
+	return &CategoryBackfill{
+		repo: &reviewRepo{
+			data:         &Data{q: query.Use(db)},
+			log:          helper,
+			ai:           aiClient,
+			esConf:       esConf,
+			appendFormat: reviewConf.GetAppendFormat(),
+		},
+		log: helper,
+	}
+}
+
+// Run 按ID顺序分批处理, 每批结束后记录进度; 单条评论失败只计数, 读取失败或 ctx 取消时返回已完成部分的统计
+func (b *CategoryBackfill) Run(ctx context.Context, opts BackfillOptions) (BackfillStats, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBackfillBatchSize
+	}
+	if opts.QPS <= 0 {
+		opts.QPS = defaultBackfillQPS
+	}
+	limiter := rate.NewLimiter(rate.Limit(opts.QPS), 1)
+	started := time.Now()
+
+	var stats BackfillStats
+	var afterID int64
+	ri := b.repo.data.q.ReviewInfo
+	for opts.Limit <= 0 || stats.Checked < opts.Limit {
+		size := opts.BatchSize
+		if opts.Limit > 0 && opts.Limit-stats.Checked < size {
+			size = opts.Limit - stats.Checked
+		}
+		reviews, err := ri.WithContext(ctx).
+			Where(ri.ID.Gt(afterID), ri.Status.Eq(30), ri.RejectCategory.Eq(""), ri.DeleteAt.IsNull()).
+			Order(ri.ID).
+			Limit(size).
+			Find()
+		if err != nil {
+			return stats, err
+		}
+		if len(reviews) == 0 {
+			break
+		}
+		afterID = reviews[len(reviews)-1].ID
+		for _, review := range reviews {
+			if err := ctx.Err(); err != nil {
+				return stats, err
+			}
+			stats.Checked++
+			if err := b.backfill(ctx, review, limiter, opts.DryRun, &stats); err != nil {
+				stats.Failed++
+				b.log.Errorf("backfill: review ID %d failed: %v", review.ReviewID, err)
+			}
+		}
+		b.log.Infof("backfill: checked %d, filled %d (%d from audit logs, %d moderated), skipped %d, failed %d, elapsed %v",
+			stats.Checked, stats.Filled, stats.FromLog, stats.Moderated, stats.Skipped, stats.Failed, time.Since(started))
+		if len(reviews) < size {
+			break
+		}
+	}
+	return stats, nil
+}
+
+// backfill 补全一条评论的驳回类别
+func (b *CategoryBackfill) backfill(ctx context.Context, review *model.ReviewInfo, limiter *rate.Limiter, dryRun bool, stats *BackfillStats) error {
+	al := b.repo.data.q.ReviewAuditLog
+	lastLog, err := al.WithContext(ctx).
+		Where(al.ReviewID.Eq(review.ReviewID), al.Source.Eq(biz.AuditSourceAI), al.ToStatus.Eq(30)).
+		Order(al.ID.Desc()).
+		First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	var category string
+	var confidence float64
+	if lastLog != nil && lastLog.Category != "" {
+		category = lastLog.Category
+		stats.FromLog++
+	} else {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		text, err := b.repo.moderationText(ctx, review)
+		if err != nil {
+			return err
+		}
+		stats.Moderated++
+		res, err := b.repo.ai.Moderate(ctx, text)
+		if err != nil {
+			return err
+		}
+		if res.Reason == ai.ModerationErrorReason {
+			return errors.New("unparsable moderation output")
+		}
+		if res.Approved || res.Category == "" {
+			stats.Skipped++
+			b.log.Infof("backfill: review ID %d skipped, moderation now returns approved=%v with no category", review.ReviewID, res.Approved)
+			return nil
+		}
+		category, confidence = res.Category, res.Confidence
+	}
+
+	if dryRun {
+		stats.Filled++
+		b.log.Infof("backfill: [dry-run] review ID %d would get category %q", review.ReviewID, category)
+		return nil
+	}
+	err = b.repo.data.q.Transaction(func(tx *query.Query) error {
+		if err := updateReviewVersioned(ctx, tx, review, map[string]interface{}{"reject_category": category}); err != nil {
+			return err
+		}
+		if err := b.repo.enqueueOutbox(ctx, tx, review.ReviewID); err != nil {
+			return err
+		}
+		// 重新审核得到的类别同时补到最近一次AI驳回的审核日志上, 供按类别和置信度统计
+		if lastLog == nil || lastLog.Category != "" {
+			return nil
+		}
+		_, err := tx.ReviewAuditLog.WithContext(ctx).Where(tx.ReviewAuditLog.ID.Eq(lastLog.ID)).Updates(map[string]interface{}{
+			"category":   category,
+			"confidence": confidence,
+		})
+		return err
+	})
+	if errors.Is(err, biz.ErrReviewConflict) {
+		stats.Skipped++
+		b.log.Infof("backfill: review ID %d skipped, it changed while being processed", review.ReviewID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	stats.Filled++
+	return nil
+}
@@ -0,0 +1,99 @@
+package data
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// rejectedReviews is a batch of rejected reviews without a category.
+func rejectedReviews(ids ...int64) *resultRows {
+	rows := &resultRows{columns: []string{"id", "review_id", "status", "version"}}
+	for _, id := range ids {
+		rows.values = append(rows.values, []driver.Value{id, id * 100, int64(30), int64(1)})
+	}
+	return rows
+}
+
+// aiRejection is the audit log of an AI rejection with category.
+func aiRejection(category string) *resultRows {
+	return &resultRows{columns: []string{"id", "category"}, values: [][]driver.Value{{int64(1), category}}}
+}
+
+func TestCategoryBackfillFromAuditLog(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         BackfillOptions
+		rowsAffected int64
+		results      []*resultRows
+		want         BackfillStats
+		wantUpdates  int
+	}{
+		{
+			name:    "dry run",
+			opts:    BackfillOptions{DryRun: true},
+			results: []*resultRows{rejectedReviews(1), aiRejection("广告")},
+			want:    BackfillStats{Checked: 1, FromLog: 1, Filled: 1},
+		},
+		{
+			name:         "filled",
+			rowsAffected: 1,
+			results:      []*resultRows{rejectedReviews(1, 2), aiRejection("广告"), aiRejection("辱骂")},
+			want:         BackfillStats{Checked: 2, FromLog: 2, Filled: 2},
+			wantUpdates:  2,
+		},
+		{
+			name:        "review changed meanwhile",
+			results:     []*resultRows{rejectedReviews(1), aiRejection("广告")},
+			want:        BackfillStats{Checked: 1, FromLog: 1, Skipped: 1},
+			wantUpdates: 1,
+		},
+		{
+			name:    "limit",
+			opts:    BackfillOptions{Limit: 1, DryRun: true},
+			results: []*resultRows{rejectedReviews(1), aiRejection("广告")},
+			want:    BackfillStats{Checked: 1, FromLog: 1, Filled: 1},
+		},
+		{
+			name: "nothing to backfill",
+			// An empty batch ends the run.
+			results: []*resultRows{rejectedReviews()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &execConn{rowsAffected: tt.rowsAffected, results: tt.results}
+			b := &CategoryBackfill{
+				repo: &reviewRepo{data: &Data{q: newExecQuery(t, conn)}, log: log.NewHelper(log.DefaultLogger), esConf: &conf.Elasticsearch{}},
+				log:  log.NewHelper(log.DefaultLogger),
+			}
+			stats, err := b.Run(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if stats != tt.want {
+				t.Errorf("Run() = %+v, want %+v", stats, tt.want)
+			}
+			updates := 0
+			for _, stmt := range conn.stmts {
+				if strings.HasPrefix(stmt, "UPDATE `review_info` SET") {
+					updates++
+					if !strings.Contains(stmt, "`reject_category`=?") {
+						t.Errorf("UPDATE = %s, want it to set reject_category", stmt)
+					}
+				}
+			}
+			if updates != tt.wantUpdates {
+				t.Errorf("%d review updates, want %d", updates, tt.wantUpdates)
+			}
+			if len(conn.results) != 0 {
+				t.Errorf("%d result sets left unread", len(conn.results))
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/versiontype"
+)
+
+// bulk写入的默认值
+const (
+	defaultBulkFlushSize     = 500
+	defaultBulkFlushInterval = time.Second
+)
+
+// bulkFailure 批量写入中失败的一条评论
+type bulkFailure struct {
+	ReviewID int64
+	Status   int
+	Reason   string
+}
+
+func (f bulkFailure) String() string {
+	return fmt.Sprintf("review %d: status %d, %s", f.ReviewID, f.Status, f.Reason)
+}
+
+// bulkIndexer 缓冲评论文档, 按数量或时间通过ES _bulk 接口批量写入
+// 只用于对账、重建索引等后台批量任务, 非并发安全; 使用完毕后需要调用 Flush 写入剩余的文档
+type bulkIndexer struct {
+	repo     *reviewRepo
+	size     int
+	interval time.Duration
+
+	docs []*model.ReviewInfo
+	// first 缓冲区中第一条文档加入的时间
+	first time.Time
+
+	// indexed, failed 已写入成功和失败的文档数; skipped ES中已有相同或更新版本而跳过的文档数
+	indexed int
+	failed  int
+	skipped int
+}
+
+func newBulkIndexer(r *reviewRepo, c *conf.Elasticsearch_Bulk) *bulkIndexer {
+	b := &bulkIndexer{
+		repo:     r,
+		size:     int(c.GetFlushSize()),
+		interval: c.GetFlushInterval().AsDuration(),
+	}
+	if b.size <= 0 {
+		b.size = defaultBulkFlushSize
+	}
+	if b.interval <= 0 {
+		b.interval = defaultBulkFlushInterval
+	}
+	return b
+}
+
+// Add 缓冲一条评论, 达到 flush_size 或超过 flush_interval 时写入
+// 返回写入失败的评论; error 只表示整个请求失败, 此时缓冲的文档全部计为失败
+func (b *bulkIndexer) Add(ctx context.Context, review *model.ReviewInfo) ([]bulkFailure, error) {
+	if len(b.docs) == 0 {
+		b.first = time.Now()
+	}
+	b.docs = append(b.docs, review)
+	if len(b.docs) < b.size && time.Since(b.first) < b.interval {
+		return nil, nil
+	}
+	return b.Flush(ctx)
+}
+
+// Flush 写入缓冲区中的全部文档
+func (b *bulkIndexer) Flush(ctx context.Context) ([]bulkFailure, error) {
+	if len(b.docs) == 0 {
+		return nil, nil
+	}
+	docs := b.docs
+	b.docs = nil
+
+	appends, err := b.repo.documentAppends(ctx, docs)
+	if err != nil {
+		b.failed += len(docs)
+		return nil, err
+	}
+	req := b.repo.data.es.Bulk().Index(reviewIndex)
+	// 超过 max_document_bytes 且不能截断的文档不写入, 计为跳过
+	sent := docs[:0]
+	for _, review := range docs {
+		doc := b.repo.guardDocument(ctx, esDocument(review, appends[review.ReviewID]))
+		if doc == nil {
+			b.skipped++
+			continue
+		}
+		id := strconv.FormatInt(review.ReviewID, 10)
+		version := esVersion(review)
+		op := types.IndexOperation{Id_: &id, Version: &version, VersionType: &versiontype.External}
+		if err := req.IndexOp(op, doc); err != nil {
+			b.failed += len(docs)
+			return nil, err
+		}
+		sent = append(sent, review)
+	}
+	docs = sent
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	resp, err := req.Do(ctx)
+	if err != nil {
+		b.failed += len(docs)
+		return nil, err
+	}
+	var failures []bulkFailure
+	// conflicts 版本冲突的评论, 写入时的版本号
+	conflicts := make(map[int64]int32)
+	for i, item := range resp.Items {
+		for _, result := range item {
+			if result.Error == nil {
+				continue
+			}
+			f := bulkFailure{Status: result.Status, Reason: result.Error.Type}
+			if result.Error.Reason != nil {
+				f.Reason += ": " + *result.Error.Reason
+			}
+			// 结果与请求按顺序一一对应, _id 缺失时按位置取评论ID
+			if result.Id_ != nil {
+				f.ReviewID, _ = strconv.ParseInt(*result.Id_, 10, 64)
+			} else if i < len(docs) {
+				f.ReviewID = docs[i].ReviewID
+			}
+			if result.Status == http.StatusConflict && i < len(docs) {
+				conflicts[f.ReviewID] = docs[i].Version
+				continue
+			}
+			failures = append(failures, f)
+		}
+	}
+	skipped, conflicted := b.resolveConflicts(ctx, conflicts)
+	failures = append(failures, conflicted...)
+	b.failed += len(failures)
+	b.skipped += skipped
+	b.indexed += len(docs) - len(failures) - skipped
+	return failures, nil
+}
+
+// resolveConflicts 读取版本冲突的评论在ES中的版本号, 不低于写入版本号的说明ES中已是相同或更新的评论, 计为跳过;
+// 其余的计为失败, 由调用方保留重试; 读取失败时全部计为失败
+func (b *bulkIndexer) resolveConflicts(ctx context.Context, conflicts map[int64]int32) (skipped int, failures []bulkFailure) {
+	if len(conflicts) == 0 {
+		return 0, nil
+	}
+	ids := make([]int64, 0, len(conflicts))
+	for id := range conflicts {
+		ids = append(ids, id)
+	}
+	stored, err := b.repo.esStoredVersions(ctx, ids)
+	if err != nil {
+		for _, id := range ids {
+			failures = append(failures, bulkFailure{ReviewID: id, Status: http.StatusConflict, Reason: "version_conflict: read es version: " + err.Error()})
+		}
+		return 0, failures
+	}
+	return splitConflicts(conflicts, stored)
+}
+
+// splitConflicts 按ES中的版本号区分版本冲突的评论: 不低于写入版本号的计为跳过, 其余计为失败
+func splitConflicts(conflicts map[int64]int32, stored map[int64]int32) (skipped int, failures []bulkFailure) {
+	for id, version := range conflicts {
+		if v, ok := stored[id]; ok && v >= version {
+			skipped++
+			continue
+		}
+		failures = append(failures, bulkFailure{
+			ReviewID: id,
+			Status:   http.StatusConflict,
+			Reason:   fmt.Sprintf("version_conflict: es document is older than version %d", version),
+		})
+	}
+	return skipped, failures
+}
@@ -0,0 +1,211 @@
+package data
+
+import (
+	"bufio"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestSplitConflicts(t *testing.T) {
+	tests := []struct {
+		name        string
+		conflicts   map[int64]int32
+		stored      map[int64]int32
+		wantSkipped int
+		wantFailed  []int64
+	}{
+		{
+			name:        "es has the same version",
+			conflicts:   map[int64]int32{1: 3},
+			stored:      map[int64]int32{1: 3},
+			wantSkipped: 1,
+		},
+		{
+			name:        "es has a newer version",
+			conflicts:   map[int64]int32{1: 3},
+			stored:      map[int64]int32{1: 5},
+			wantSkipped: 1,
+		},
+		{
+			name:       "es has an older version",
+			conflicts:  map[int64]int32{1: 3},
+			stored:     map[int64]int32{1: 2},
+			wantFailed: []int64{1},
+		},
+		{
+			name:       "document missing from es",
+			conflicts:  map[int64]int32{1: 3},
+			stored:     map[int64]int32{},
+			wantFailed: []int64{1},
+		},
+		{
+			name:        "mixed",
+			conflicts:   map[int64]int32{1: 3, 2: 4, 3: 1},
+			stored:      map[int64]int32{1: 3, 2: 1},
+			wantSkipped: 1,
+			wantFailed:  []int64{2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skipped, failures := splitConflicts(tt.conflicts, tt.stored)
+			if skipped != tt.wantSkipped {
+				t.Errorf("skipped = %d, want %d", skipped, tt.wantSkipped)
+			}
+			var failed []int64
+			for _, f := range failures {
+				if f.Status != http.StatusConflict {
+					t.Errorf("review %d: status = %d, want %d", f.ReviewID, f.Status, http.StatusConflict)
+				}
+				failed = append(failed, f.ReviewID)
+			}
+			sort.Slice(failed, func(i, j int) bool { return failed[i] < failed[j] })
+			if len(failed) != len(tt.wantFailed) {
+				t.Fatalf("failed = %v, want %v", failed, tt.wantFailed)
+			}
+			for i := range failed {
+				if failed[i] != tt.wantFailed[i] {
+					t.Fatalf("failed = %v, want %v", failed, tt.wantFailed)
+				}
+			}
+		})
+	}
+}
+
+func TestBulkIndexer(t *testing.T) {
+	// review 2 is rejected by ES; every other document is indexed
+	var requests [][]string
+	r := newTestRepo(newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+		var ids, items []string
+		sc := bufio.NewScanner(req.Body)
+		for sc.Scan() {
+			var action struct {
+				Index struct {
+					ID string `json:"_id"`
+				} `json:"index"`
+			}
+			if err := json.Unmarshal(sc.Bytes(), &action); err != nil || action.Index.ID == "" {
+				continue // document line
+			}
+			id := action.Index.ID
+			ids = append(ids, id)
+			if id == "2" {
+				items = append(items, fmt.Sprintf(`{"index":{"_index":"review","_id":%q,"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}`, id))
+				continue
+			}
+			items = append(items, fmt.Sprintf(`{"index":{"_index":"review","_id":%q,"status":201}}`, id))
+		}
+		requests = append(requests, ids)
+		fmt.Fprintf(w, `{"took":1,"errors":true,"items":[%s]}`, strings.Join(items, ","))
+	}))
+	b := newBulkIndexer(r, &conf.Elasticsearch_Bulk{FlushSize: 2, FlushInterval: durationpb.New(time.Hour)})
+	ctx := context.Background()
+
+	var failures []bulkFailure
+	for id := int64(1); id <= 3; id++ {
+		f, err := b.Add(ctx, &model.ReviewInfo{ReviewID: id, Version: 1})
+		if err != nil {
+			t.Fatalf("Add(%d) error = %v", id, err)
+		}
+		failures = append(failures, f...)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("bulk requests after 3 adds = %v, want one flush of 2 documents", requests)
+	}
+	f, err := b.Flush(ctx)
+	if err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	failures = append(failures, f...)
+
+	if got := fmt.Sprint(requests); got != "[[1 2] [3]]" {
+		t.Errorf("bulk requests = %s, want [[1 2] [3]]", got)
+	}
+	if len(failures) != 1 || failures[0].ReviewID != 2 || failures[0].Status != http.StatusBadRequest ||
+		failures[0].Reason != "mapper_parsing_exception: failed to parse" {
+		t.Errorf("failures = %v, want review 2 rejected with its reason", failures)
+	}
+	if b.indexed != 2 || b.failed != 1 || b.skipped != 0 {
+		t.Errorf("indexed, failed, skipped = %d, %d, %d, want 2, 1, 0", b.indexed, b.failed, b.skipped)
+	}
+	if f, err := b.Flush(ctx); f != nil || err != nil || len(requests) != 2 {
+		t.Errorf("Flush() on an empty buffer = %v, %v after %d requests, want no request", f, err, len(requests))
+	}
+}
+
+func TestBulkIndexerAppends(t *testing.T) {
+	appends := map[string][]string{}
+	r := newTestRepo(newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+		var id string
+		var items []string
+		sc := bufio.NewScanner(req.Body)
+		for sc.Scan() {
+			var line struct {
+				Index *struct {
+					ID string `json:"_id"`
+				} `json:"index"`
+				Appends []struct {
+					Content string `json:"content"`
+				} `json:"appends"`
+			}
+			if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+				t.Errorf("decode bulk line: %v", err)
+				continue
+			}
+			if line.Index != nil {
+				id = line.Index.ID
+				items = append(items, fmt.Sprintf(`{"index":{"_index":"review","_id":%q,"status":201}}`, id))
+				continue
+			}
+			appends[id] = []string{}
+			for _, a := range line.Appends {
+				appends[id] = append(appends[id], a.Content)
+			}
+		}
+		fmt.Fprintf(w, `{"took":1,"errors":false,"items":[%s]}`, strings.Join(items, ","))
+	}))
+	conn := &execConn{results: []*resultRows{{
+		columns: []string{"id", "review_id", "content"},
+		values: [][]driver.Value{
+			{int64(1), int64(1), "第一次追加"},
+			{int64(2), int64(3), "另一条评论的追加"},
+			{int64(3), int64(1), "第二次追加"},
+		},
+	}}}
+	r.data.q = newExecQuery(t, conn)
+	b := newBulkIndexer(r, &conf.Elasticsearch_Bulk{FlushSize: 10, FlushInterval: durationpb.New(time.Hour)})
+	ctx := context.Background()
+	for id := int64(1); id <= 3; id++ {
+		review := &model.ReviewInfo{ReviewID: id, Version: 1}
+		if id != 2 {
+			review.ExtJSON = biz.WithAppendCount("", 1)
+		}
+		if _, err := b.Add(ctx, review); err != nil {
+			t.Fatalf("Add(%d) error = %v", id, err)
+		}
+	}
+	if _, err := b.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	// One query loads the appends of every appended review in the batch.
+	if len(conn.stmts) != 1 || !strings.Contains(conn.stmts[0], "`review_id` IN (?,?)") {
+		t.Errorf("queries = %q, want one batched lookup of reviews 1 and 3", conn.stmts)
+	}
+	want := map[string][]string{"1": {"第一次追加", "第二次追加"}, "2": {}, "3": {"另一条评论的追加"}}
+	if fmt.Sprint(appends) != fmt.Sprint(want) {
+		t.Errorf("indexed appends = %v, want %v", appends, want)
+	}
+}
@@ -1,10 +1,13 @@
 package data
 
 import (
+	"context"
+	"fmt"
 	"review/internal/client/ai"
 	"review/internal/conf"
 	"review/internal/data/query"
 	"strings"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/go-kratos/kratos/v2/log"
@@ -19,6 +22,10 @@ var ProviderSet = wire.NewSet(
 	NewData,
 	NewReviewRepo,
 	NewUserRepo,
+	NewTokenDenylist,
+	NewReconciler,
+	NewOutboxRelay,
+	NewStartup,
 	NewDB,
 	NewESClient,
 	NewRedisClient,
@@ -33,20 +40,50 @@ type Data struct {
 	es  *elasticsearch.TypedClient
 	rdb *redis.Client
 	ai  *ai.AIClient
+	// async 评论异步审核/同步任务池
+	async *taskPool
+	// syncFirst 为 true 时先写入ES再审核, 见 conf.Data.Async.order
+	syncFirst bool
+	// asyncTimeout 每条评论异步审核和同步的总时长上限
+	asyncTimeout time.Duration
+	// priority 评论审核任务的优先级规则, 见 conf.Data.Async.priority
+	priority *conf.Data_Async_Priority
+	// maxCacheBytes 查询结果写入缓存的大小上限, 见 conf.Data.Redis.max_value_bytes
+	maxCacheBytes int64
 }
 
 // NewData .
-func NewData(db *gorm.DB, esClient *elasticsearch.TypedClient, rdb *redis.Client, logger log.Logger, ai *ai.AIClient) (*Data, func(), error) {
+func NewData(c *conf.Data, db *gorm.DB, esClient *elasticsearch.TypedClient, rdb *redis.Client, logger log.Logger, ai *ai.AIClient) (*Data, func(), error) {
+	var syncFirst bool
+	switch c.GetAsync().GetOrder() {
+	case "", asyncOrderAuditFirst:
+	case asyncOrderSyncFirst:
+		syncFirst = true
+	default:
+		return nil, nil, fmt.Errorf("invalid async order: %q", c.GetAsync().GetOrder())
+	}
+	asyncTimeout := c.GetAsync().GetTimeout().AsDuration()
+	if asyncTimeout <= 0 {
+		asyncTimeout = defaultAsyncTimeout
+	}
+	async := newTaskPool(c.GetAsync(), logger)
 	cleanup := func() {
 		log.NewHelper(logger).Info("closing the data resources")
+		// 等待已排队的审核任务执行完, 避免评论停留在待审核状态
+		async.Close()
 	}
 	query.SetDefault(db)
 	return &Data{
-		q:   query.Use(db),
-		log: log.NewHelper(logger),
-		es:  esClient,
-		rdb: rdb,
-		ai:  ai,
+		q:             query.Use(db),
+		log:           log.NewHelper(logger),
+		es:            esClient,
+		rdb:           rdb,
+		ai:            ai,
+		async:         async,
+		syncFirst:     syncFirst,
+		asyncTimeout:  asyncTimeout,
+		priority:      c.GetAsync().GetPriority(),
+		maxCacheBytes: c.GetRedis().GetMaxValueBytes(),
 	}, cleanup, nil
 }
 
@@ -59,12 +96,21 @@ func NewDB(c *conf.Data) (*gorm.DB, error) {
 	}
 }
 
-func NewESClient(c *conf.Elasticsearch) (*elasticsearch.TypedClient, error) {
+func NewESClient(c *conf.Elasticsearch, logger log.Logger) (*elasticsearch.TypedClient, error) {
+	switch c.Refresh {
+	case "", "false", "wait_for", "true":
+	default:
+		return nil, fmt.Errorf("invalid elasticsearch refresh policy: %q", c.Refresh)
+	}
 	cfg := elasticsearch.Config{
 		Addresses: c.Addresses,
 	}
-	return elasticsearch.NewTypedClient(cfg)
-
+	es, err := elasticsearch.NewTypedClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ensureReviewIndex(context.Background(), es, c, log.NewHelper(logger))
+	return es, nil
 }
 
 func NewRedisClient(c *conf.Data) *redis.Client {
@@ -0,0 +1,115 @@
+package data
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"testing"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/refresh"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestESRefresh(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   refresh.Refresh
+	}{
+		{"", refresh.False},
+		{"false", refresh.False},
+		{"true", refresh.True},
+		{"wait_for", refresh.Waitfor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			if got := esRefresh(tt.policy); got != tt.want {
+				t.Errorf("esRefresh(%q) = %v, want %v", tt.policy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestESWritesSendRefresh(t *testing.T) {
+	writes := []struct {
+		name  string
+		write func(r *reviewRepo) error
+	}{
+		{name: "save", write: func(r *reviewRepo) error {
+			return r.SaveToES(context.Background(), &model.ReviewInfo{ReviewID: 42, Version: 1})
+		}},
+		{name: "manual audit", write: func(r *reviewRepo) error {
+			_, err := r.ManualAuditReview(context.Background(), &biz.AuditReviewParam{ReviewID: 42, Status: 20, OpUser: "admin"})
+			return err
+		}},
+		{name: "delete", write: func(r *reviewRepo) error {
+			return r.DeleteReview(context.Background(), 42, []int32{20})
+		}},
+	}
+	for _, policy := range []string{"wait_for", "true", "false"} {
+		for _, w := range writes {
+			t.Run(policy+"/"+w.name, func(t *testing.T) {
+				var got []string
+				r := newTestRepo(newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+					got = append(got, req.URL.Query().Get("refresh"))
+					w.Write([]byte(`{"_index":"review","_id":"42","_version":1,"result":"created"}`))
+				}))
+				r.esConf = &conf.Elasticsearch{Refresh: policy}
+				conn := &execConn{rowsAffected: 1, results: []*resultRows{{
+					columns: []string{"review_id", "status", "version"},
+					values:  [][]driver.Value{{int64(42), int64(20), int64(2)}},
+				}}}
+				r.data.q = newExecQuery(t, conn)
+				if err := w.write(r); err != nil {
+					t.Fatalf("write error = %v", err)
+				}
+				if len(got) != 1 || got[0] != policy {
+					t.Errorf("ES requests = %q, want one write with refresh=%s", got, policy)
+				}
+			})
+		}
+	}
+}
+
+func TestNewESClientRejectsInvalidRefresh(t *testing.T) {
+	if _, err := NewESClient(&conf.Elasticsearch{Refresh: "always"}, log.DefaultLogger); err == nil {
+		t.Error("NewESClient() with refresh \"always\" = nil error, want an error")
+	}
+}
+
+func TestNewDataRejectsInvalidAsyncOrder(t *testing.T) {
+	c := &conf.Data{Async: &conf.Data_Async{Order: "random"}}
+	if _, _, err := NewData(c, nil, nil, nil, log.DefaultLogger, nil); err == nil {
+		t.Error("NewData() with async order \"random\" = nil error, want an error")
+	}
+}
+
+func TestNewDataAsyncTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout *durationpb.Duration
+		want    time.Duration
+	}{
+		{name: "default", want: defaultAsyncTimeout},
+		{name: "configured", timeout: durationpb.New(30 * time.Second), want: 30 * time.Second},
+		{name: "non-positive falls back", timeout: durationpb.New(-time.Second), want: defaultAsyncTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &conf.Data{Async: &conf.Data_Async{Timeout: tt.timeout}}
+			d, cleanup, err := NewData(c, newExecDB(t, &execConn{}), nil, nil, log.DefaultLogger, nil)
+			if err != nil {
+				t.Fatalf("NewData() error = %v", err)
+			}
+			defer cleanup()
+			if d.asyncTimeout != tt.want {
+				t.Errorf("asyncTimeout = %v, want %v", d.asyncTimeout, tt.want)
+			}
+		})
+	}
+}
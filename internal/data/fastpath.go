@@ -0,0 +1,102 @@
+package data
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/data/model"
+	"review/internal/data/query"
+)
+
+// defaultFastPathMinApproved 受信用户默认需要的已通过评论数
+const defaultFastPathMinApproved = 5
+
+// fastPathAudit 作者为受信用户且内容(含追加评论)通过本地敏感词检查时直接通过评论, 返回更新后的评论
+// 未开启、作者不受信、本地检查不通过或更新失败时返回 false, 由调用方继续AI审核
+func (r *reviewRepo) fastPathAudit(ctx context.Context, review *model.ReviewInfo, text string) (*model.ReviewInfo, bool) {
+	if !r.fastPath.GetEnabled() {
+		return nil, false
+	}
+	score, err := r.trustScore(ctx, review)
+	if err != nil {
+		r.log.WithContext(ctx).Warnf("trust score for review ID %d failed, fall back to AI audit: %v", review.ReviewID, err)
+		return nil, false
+	}
+	if score < r.fastPathMinApproved() || containsBlockedWord(r.fastPath.GetBlockedWords(), text) {
+		return nil, false
+	}
+
+	remarks := "受信用户, 已通过评论数 " + strconv.FormatInt(score, 10)
+	err = r.data.q.Transaction(func(tx *query.Query) error {
+		if err := updateReviewVersioned(ctx, tx, review, map[string]interface{}{
+			"status":     20,
+			"op_reason":  biz.AuditSourceTrusted,
+			"op_remarks": remarks,
+			"update_by":  "system",
+			"update_at":  time.Now(),
+		}); err != nil {
+			return err
+		}
+		if err := r.enqueueOutbox(ctx, tx, review.ReviewID); err != nil {
+			return err
+		}
+		return r.saveAuditLog(ctx, tx, &model.ReviewAuditLog{
+			ReviewID:   review.ReviewID,
+			FromStatus: review.Status,
+			ToStatus:   20,
+			Source:     biz.AuditSourceTrusted,
+			OpUser:     "system",
+			Reason:     biz.AuditSourceTrusted,
+			Remarks:    remarks,
+		})
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Warnf("trusted fast path for review ID %d failed, fall back to AI audit: %v", review.ReviewID, err)
+		return nil, false
+	}
+	approved, err := r.GetReviewByReviewID(ctx, review.ReviewID)
+	if err != nil {
+		return nil, false
+	}
+	return approved, true
+}
+
+// fastPathMinApproved 受信用户需要的已通过评论数
+func (r *reviewRepo) fastPathMinApproved() int64 {
+	if n := int64(r.fastPath.GetMinApproved()); n > 0 {
+		return n
+	}
+	return defaultFastPathMinApproved
+}
+
+// trustScore 作者的信任分: 除本条外已通过(20)的评论数; 作者有被驳回(30)或隐藏(40)的评论时为0
+// 只看评论的当前状态, 驳回后重新提交并通过的评论不再计为驳回
+func (r *reviewRepo) trustScore(ctx context.Context, review *model.ReviewInfo) (int64, error) {
+	ri := r.data.q.ReviewInfo
+	flagged, err := ri.WithContext(ctx).
+		Where(ri.UserID.Eq(review.UserID), ri.Status.In(30, 40)).
+		Count()
+	if err != nil {
+		return 0, err
+	}
+	if flagged > 0 {
+		return 0, nil
+	}
+	return ri.WithContext(ctx).
+		Where(ri.UserID.Eq(review.UserID), ri.Status.Eq(20), ri.DeleteAt.IsNull(), ri.ReviewID.Neq(review.ReviewID)).
+		Count()
+}
+
+// containsBlockedWord 内容是否包含敏感词表中的任一词, 不区分大小写
+func containsBlockedWord(words []string, content string) bool {
+	content = strings.ToLower(content)
+	for _, w := range words {
+		if w != "" && strings.Contains(content, strings.ToLower(w)) {
+			return true
+		}
+	}
+	return false
+}
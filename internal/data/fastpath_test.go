@@ -0,0 +1,66 @@
+package data
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+func TestContainsBlockedWord(t *testing.T) {
+	words := []string{"加微信", "WeChat", ""}
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"味道不错, 下次还来", false},
+		{"优惠请加微信", true},
+		{"add me on wechat", true},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			if got := containsBlockedWord(words, tt.content); got != tt.want {
+				t.Errorf("containsBlockedWord(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFastPathAuditFallsBack(t *testing.T) {
+	enabled := &conf.Review_TrustedFastPath{Enabled: true, MinApproved: 3, BlockedWords: []string{"加微信"}}
+	tests := []struct {
+		name     string
+		fastPath *conf.Review_TrustedFastPath
+		text     string
+		// counts answer the flagged and approved review counts, in that order
+		counts []int64
+	}{
+		{name: "disabled", fastPath: &conf.Review_TrustedFastPath{}, text: "好评"},
+		{name: "previously flagged", fastPath: enabled, text: "好评", counts: []int64{1}},
+		{name: "too few approved reviews", fastPath: enabled, text: "好评", counts: []int64{0, 2}},
+		{name: "blocked word", fastPath: enabled, text: "优惠加微信", counts: []int64{0, 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &execConn{counts: tt.counts}
+			r := &reviewRepo{data: &Data{q: newExecQuery(t, conn)}, log: log.NewHelper(log.DefaultLogger), fastPath: tt.fastPath}
+			if _, ok := r.fastPathAudit(context.Background(), &model.ReviewInfo{ReviewID: 1, UserID: 7}, tt.text); ok {
+				t.Fatal("fastPathAudit() approved the review, want the AI audit")
+			}
+			if len(conn.counts) != 0 {
+				t.Errorf("%d trust score queries not made", len(conn.counts))
+			}
+			// Falling back must not write anything.
+			for _, stmt := range conn.stmts {
+				if !strings.HasPrefix(stmt, "SELECT") {
+					t.Errorf("unexpected statement %s", stmt)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"unicode/utf8"
+)
+
+// ES文档超过大小上限时的处理方式, 见 conf.Elasticsearch.on_oversize
+const (
+	esOversizeTruncate = "truncate"
+	esOversizeReject   = "reject"
+)
+
+// payloadGuardTriggered 大小限制生效的次数, 按 es_truncated/es_rejected/cache_skipped 统计, 通过 /debug/vars 暴露
+var payloadGuardTriggered = expvar.NewMap("payload_guard_triggered")
+
+// guardDocument 按 max_document_bytes 检查写入ES的文档, 返回可以写入的文档, 不能写入时返回nil
+// truncate 时按需要从末尾截掉评论内容, 先截最后一条追加评论, 再依次向前; 截断后的文档只用于搜索, MySQL中的评论不受影响
+func (r *reviewRepo) guardDocument(ctx context.Context, doc *esReview) *esReview {
+	limit := r.esConf.GetMaxDocumentBytes()
+	if limit <= 0 {
+		return doc
+	}
+	size, err := documentSize(doc)
+	if err != nil || size <= limit {
+		return doc
+	}
+	if r.esConf.GetOnOversize() != esOversizeReject {
+		original := size
+		// JSON转义会使文档比内容本身多出一些字节, 多轮截断直到文档不超过上限
+		for text := lastText(doc); size > limit && text != nil; text = lastText(doc) {
+			*text = truncateBytes(*text, len(*text)-int(size-limit))
+			if size, err = documentSize(doc); err != nil {
+				break
+			}
+		}
+		if err == nil && size <= limit {
+			payloadGuardTriggered.Add("es_truncated", 1)
+			r.log.WithContext(ctx).Warnf("review ID %d: es document truncated from %d to %d bytes (max_document_bytes %d)", doc.ReviewID, original, size, limit)
+			return doc
+		}
+	}
+	payloadGuardTriggered.Add("es_rejected", 1)
+	r.log.WithContext(ctx).Warnf("review ID %d: es document of %d bytes exceeds max_document_bytes %d, not indexed", doc.ReviewID, size, limit)
+	return nil
+}
+
+// lastText 文档中最后一段非空的评论内容: 最后一条非空的追加评论, 没有时为评论原文; 全部为空时返回nil
+func lastText(doc *esReview) *string {
+	for i := len(doc.Appends) - 1; i >= 0; i-- {
+		if doc.Appends[i].Content != "" {
+			return &doc.Appends[i].Content
+		}
+	}
+	if doc.Content != "" {
+		return &doc.Content
+	}
+	return nil
+}
+
+func documentSize(doc *esReview) (int64, error) {
+	b, err := json.Marshal(doc)
+	return int64(len(b)), err
+}
+
+// truncateBytes 截取不超过n字节的前缀, 不截断多字节字符
+func truncateBytes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// cacheValueAllowed 缓存值是否不超过 max_value_bytes, 超过时记录日志, 调用方不写缓存, 该key之后仍查询ES
+func (r *reviewRepo) cacheValueAllowed(ctx context.Context, key string, value []byte) bool {
+	limit := r.data.maxCacheBytes
+	if limit <= 0 || int64(len(value)) <= limit {
+		return true
+	}
+	payloadGuardTriggered.Add("cache_skipped", 1)
+	r.log.WithContext(ctx).Warnf("cache value for key %s is %d bytes, exceeds max_value_bytes %d, not cached", key, len(value), limit)
+	return false
+}
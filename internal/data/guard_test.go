@@ -0,0 +1,130 @@
+package data
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+func TestTruncateBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{name: "shorter than n", s: "abc", n: 5, want: "abc"},
+		{name: "ascii", s: "abcdef", n: 3, want: "abc"},
+		{name: "cut inside a rune", s: "好评如潮", n: 7, want: "好评"},
+		{name: "rune boundary", s: "好评如潮", n: 6, want: "好评"},
+		{name: "zero", s: "abc", n: 0, want: ""},
+		{name: "negative", s: "abc", n: -1, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateBytes(tt.s, tt.n); got != tt.want {
+				t.Errorf("truncateBytes(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuardDocument(t *testing.T) {
+	content := strings.Repeat("好", 100)
+	base, _ := documentSize(esDocument(&model.ReviewInfo{ReviewID: 1}, nil))
+	tests := []struct {
+		name       string
+		c          *conf.Elasticsearch
+		wantNil    bool
+		wantLength int
+	}{
+		{name: "no limit", c: &conf.Elasticsearch{}, wantLength: len(content)},
+		{name: "within the limit", c: &conf.Elasticsearch{MaxDocumentBytes: base + int64(len(content))}, wantLength: len(content)},
+		{name: "truncated", c: &conf.Elasticsearch{MaxDocumentBytes: base + 31}, wantLength: 30},
+		{name: "rejected", c: &conf.Elasticsearch{MaxDocumentBytes: base + 31, OnOversize: esOversizeReject}, wantNil: true},
+		{name: "too small even without content", c: &conf.Elasticsearch{MaxDocumentBytes: base - 1}, wantNil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reviewRepo{esConf: tt.c, log: log.NewHelper(log.DefaultLogger)}
+			doc := r.guardDocument(context.Background(), esDocument(&model.ReviewInfo{ReviewID: 1, Content: content}, nil))
+			if tt.wantNil {
+				if doc != nil {
+					t.Errorf("guardDocument() kept a %d-byte content, want the document rejected", len(doc.Content))
+				}
+				return
+			}
+			if doc == nil {
+				t.Fatal("guardDocument() = nil, want the document indexed")
+			}
+			if len(doc.Content) != tt.wantLength || !utf8.ValidString(doc.Content) {
+				t.Errorf("content is %d bytes (valid UTF-8: %v), want %d", len(doc.Content), utf8.ValidString(doc.Content), tt.wantLength)
+			}
+			if size, _ := documentSize(doc); tt.c.MaxDocumentBytes > 0 && size > tt.c.MaxDocumentBytes {
+				t.Errorf("document is %d bytes, want at most %d", size, tt.c.MaxDocumentBytes)
+			}
+		})
+	}
+}
+
+func TestGuardDocumentAppends(t *testing.T) {
+	appends := []*model.ReviewAppendInfo{{Content: strings.Repeat("好", 10)}, {Content: strings.Repeat("差", 10)}}
+	base, _ := documentSize(esDocument(&model.ReviewInfo{ReviewID: 1}, []*model.ReviewAppendInfo{{}, {}}))
+	tests := []struct {
+		name  string
+		limit int64
+		want  []int // bytes left in the content and each append
+	}{
+		{name: "within the limit", limit: base + 90, want: []int{30, 30, 30}},
+		{name: "last append truncated first", limit: base + 75, want: []int{30, 30, 15}},
+		{name: "then the earlier ones", limit: base + 45, want: []int{30, 15, 0}},
+		{name: "content last", limit: base + 15, want: []int{15, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reviewRepo{esConf: &conf.Elasticsearch{MaxDocumentBytes: tt.limit}, log: log.NewHelper(log.DefaultLogger)}
+			doc := r.guardDocument(context.Background(), esDocument(&model.ReviewInfo{ReviewID: 1, Content: strings.Repeat("好", 10)}, appends))
+			if doc == nil {
+				t.Fatal("guardDocument() = nil, want the document indexed")
+			}
+			got := []int{len(doc.Content)}
+			for _, a := range doc.Appends {
+				got = append(got, len(a.Content))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("content and append bytes = %v, want %v", got, tt.want)
+			}
+			if len(appends[1].Content) != 30 {
+				t.Error("guardDocument() changed the append records, want only the document truncated")
+			}
+		})
+	}
+}
+
+func TestCacheValueAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int64
+		size  int
+		want  bool
+	}{
+		{name: "no limit", size: 1 << 20, want: true},
+		{name: "at the limit", limit: 100, size: 100, want: true},
+		{name: "over the limit", limit: 100, size: 101, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reviewRepo{data: &Data{maxCacheBytes: tt.limit}, log: log.NewHelper(log.DefaultLogger)}
+			if got := r.cacheValueAllowed(context.Background(), "review:store:1:0:10", make([]byte, tt.size)); got != tt.want {
+				t.Errorf("cacheValueAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
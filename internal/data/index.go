@@ -0,0 +1,125 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/conf"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+const (
+	reviewIndex = "review"
+	// ensureIndexTimeout 启动时检查/创建索引的超时时间, ES不可用时不阻塞启动
+	ensureIndexTimeout = 10 * time.Second
+)
+
+// analyzedFields 按配置的分词器建立映射的全文字段
+// reply 字段目前未写入ES文档, 预先建立映射, 以后同步回复内容时无需重建索引
+var analyzedFields = []string{"content", "reply"}
+
+// appendContentField 追加评论内容的字段, 与 content 使用相同的分词器
+const appendContentField = "appends.content"
+
+// ensureReviewIndex 配置了分词器且 review 索引不存在时, 按分词器创建索引
+// 分词插件未安装时回退为 standard 分词器; 其余字段仍使用动态映射
+// ES不可用或创建失败只记录日志, 索引会在第一次写入时按动态映射自动创建
+func ensureReviewIndex(ctx context.Context, es *elasticsearch.TypedClient, c *conf.Elasticsearch, logger *log.Helper) {
+	if c.GetAnalyzer() == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, ensureIndexTimeout)
+	defer cancel()
+
+	exists, err := es.Indices.Exists(reviewIndex).Do(ctx)
+	if err != nil {
+		logger.Warnf("check elasticsearch index %s failed, skip creating it: %v", reviewIndex, err)
+		return
+	}
+	if exists {
+		logger.Infof("elasticsearch index %s already exists, analyzer %q is only applied when the index is created", reviewIndex, c.GetAnalyzer())
+		return
+	}
+
+	if err := createReviewIndex(ctx, es, c, logger); err != nil {
+		logger.Warnf("create elasticsearch index %s failed: %v", reviewIndex, err)
+		return
+	}
+	logger.Infof("created elasticsearch index %s", reviewIndex)
+}
+
+// createReviewIndex 按配置的分词器创建 review 索引, 分词插件未安装时回退为 standard 分词器
+func createReviewIndex(ctx context.Context, es *elasticsearch.TypedClient, c *conf.Elasticsearch, logger *log.Helper) error {
+	_, err := es.Indices.Create(reviewIndex).Mappings(reviewMapping(c.GetAnalyzer(), c.GetSearchAnalyzer())).Do(ctx)
+	if err != nil && c.GetAnalyzer() != "" && isUnknownAnalyzer(err) {
+		logger.Warnf("elasticsearch analyzer %q is not available (is the analysis plugin installed?), fall back to standard: %v", c.GetAnalyzer(), err)
+		_, err = es.Indices.Create(reviewIndex).Mappings(reviewMapping("", "")).Do(ctx)
+	}
+	return err
+}
+
+// isIndexNotFound ES请求失败是否因为索引不存在
+func isIndexNotFound(err error) bool {
+	var esErr *types.ElasticsearchError
+	return errors.As(err, &esErr) && esErr.ErrorCause.Type == "index_not_found_exception"
+}
+
+// searchError 查询ES失败时, 索引不存在转换为 biz.ErrSearchIndexMissing, 其余错误原样返回
+// 索引不存在说明部署有问题, 按错误级别记录; 配置了分词器时同[ensureReviewIndex]在后台创建索引, 并发的查询只创建一次
+func (r *reviewRepo) searchError(ctx context.Context, index string, err error) error {
+	if !isIndexNotFound(err) {
+		return err
+	}
+	r.log.WithContext(ctx).Errorf("elasticsearch index %s does not exist, searches fail until it is created (enable data.startup or reindex): %v", index, err)
+	if index == reviewIndex && r.esConf.GetAnalyzer() != "" {
+		go r.sf.Do("ensure-index:"+index, func() (interface{}, error) {
+			ensureReviewIndex(context.Background(), r.data.es, r.esConf, r.log)
+			return nil, nil
+		})
+	}
+	return biz.ErrSearchIndexMissing
+}
+
+// reviewMapping 全文字段映射为 text 并保留与动态映射相同的 keyword 子字段, analyzer 为空时使用默认分词器
+// 追加评论 appends 映射为对象数组, 其中的 content 同样按分词器映射
+func reviewMapping(analyzer, searchAnalyzer string) *types.TypeMapping {
+	ignoreAbove := 256
+	text := func() *types.TextProperty {
+		p := types.NewTextProperty()
+		if analyzer != "" {
+			p.Analyzer = &analyzer
+			if searchAnalyzer != "" {
+				p.SearchAnalyzer = &searchAnalyzer
+			}
+		}
+		keyword := types.NewKeywordProperty()
+		keyword.IgnoreAbove = &ignoreAbove
+		p.Fields = map[string]types.Property{"keyword": keyword}
+		return p
+	}
+	props := make(map[string]types.Property, len(analyzedFields)+1)
+	for _, field := range analyzedFields {
+		props[field] = text()
+	}
+	appends := types.NewObjectProperty()
+	appends.Properties = map[string]types.Property{strings.TrimPrefix(appendContentField, "appends."): text()}
+	props["appends"] = appends
+	return &types.TypeMapping{Properties: props}
+}
+
+// isUnknownAnalyzer 判断创建索引失败是否因为分词器不存在, 如
+// "Failed to parse mapping: analyzer [ik_max_word] has not been configured in mappings"
+func isUnknownAnalyzer(err error) bool {
+	var esErr *types.ElasticsearchError
+	if !errors.As(err, &esErr) || esErr.ErrorCause.Reason == nil {
+		return false
+	}
+	reason := *esErr.ErrorCause.Reason
+	return strings.Contains(reason, "analyzer") && strings.Contains(reason, "not been configured")
+}
@@ -0,0 +1,167 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/conf"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+const unknownAnalyzerBody = `{"error":{"root_cause":[],"type":"mapper_parsing_exception",` +
+	`"reason":"Failed to parse mapping: analyzer [ik_max_word] has not been configured in mappings"},"status":400}`
+
+func TestEnsureReviewIndex(t *testing.T) {
+	tests := []struct {
+		name          string
+		analyzer      string
+		exists        bool
+		pluginMissing bool
+		// wantAnalyzers is the content analyzer of each create request, "" for the default one.
+		wantAnalyzers []string
+	}{
+		{name: "analyzer not configured", exists: false},
+		{name: "index exists", analyzer: "ik_max_word", exists: true},
+		{name: "created with the analyzer", analyzer: "ik_max_word", wantAnalyzers: []string{"ik_max_word"}},
+		{name: "plugin missing falls back to standard", analyzer: "ik_max_word", pluginMissing: true, wantAnalyzers: []string{"ik_max_word", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created []string
+			es := newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+				switch req.Method {
+				case http.MethodHead:
+					if !tt.exists {
+						w.WriteHeader(http.StatusNotFound)
+					}
+				case http.MethodPut:
+					var body struct {
+						Mappings struct {
+							Properties map[string]struct {
+								Analyzer string `json:"analyzer"`
+							} `json:"properties"`
+						} `json:"mappings"`
+					}
+					if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+						t.Errorf("decode create index: %v", err)
+					}
+					analyzer := body.Mappings.Properties["content"].Analyzer
+					created = append(created, analyzer)
+					if tt.pluginMissing && analyzer != "" {
+						w.WriteHeader(http.StatusBadRequest)
+						io.WriteString(w, unknownAnalyzerBody)
+						return
+					}
+					io.WriteString(w, `{"acknowledged":true,"shards_acknowledged":true,"index":"review"}`)
+				default:
+					t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+				}
+			})
+			ensureReviewIndex(context.Background(), es, &conf.Elasticsearch{Analyzer: tt.analyzer}, log.NewHelper(log.DefaultLogger))
+			if len(created) != len(tt.wantAnalyzers) {
+				t.Fatalf("create requests = %q, want %q", created, tt.wantAnalyzers)
+			}
+			for i := range created {
+				if created[i] != tt.wantAnalyzers[i] {
+					t.Errorf("create requests = %q, want %q", created, tt.wantAnalyzers)
+				}
+			}
+		})
+	}
+}
+
+func TestReviewMapping(t *testing.T) {
+	m := reviewMapping("ik_max_word", "ik_smart")
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type property struct {
+		Type           string `json:"type"`
+		Analyzer       string `json:"analyzer"`
+		SearchAnalyzer string `json:"search_analyzer"`
+		Fields         map[string]struct {
+			Type string `json:"type"`
+		} `json:"fields"`
+		Properties map[string]property `json:"properties"`
+	}
+	var got property
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	// Append entries are analyzed like the review content.
+	if p := got.Properties["appends"]; p.Type != "object" {
+		t.Errorf("appends mapping = %+v, want an object", p)
+	} else {
+		got.Properties[appendContentField] = p.Properties["content"]
+	}
+	for _, field := range append(analyzedFields, appendContentField) {
+		p, ok := got.Properties[field]
+		if !ok {
+			t.Errorf("mapping has no %s field", field)
+			continue
+		}
+		if p.Type != "text" || p.Analyzer != "ik_max_word" || p.SearchAnalyzer != "ik_smart" || p.Fields["keyword"].Type != "keyword" {
+			t.Errorf("%s mapping = %+v, want text analyzed by ik_max_word/ik_smart with a keyword sub-field", field, p)
+		}
+	}
+}
+
+const indexNotFoundBody = `{"error":{"root_cause":[],"type":"index_not_found_exception",` +
+	`"reason":"no such index [review]","index":"review"},"status":404}`
+
+func TestSearchIndexMissing(t *testing.T) {
+	tests := []struct {
+		name        string
+		analyzer    string
+		status      int
+		body        string
+		wantMissing bool
+		wantCreate  bool
+	}{
+		{name: "index missing", status: http.StatusNotFound, body: indexNotFoundBody, wantMissing: true},
+		{name: "index missing is created with the analyzer", analyzer: "ik_max_word", status: http.StatusNotFound, body: indexNotFoundBody, wantMissing: true, wantCreate: true},
+		{name: "other errors pass through", status: http.StatusBadRequest, body: unknownAnalyzerBody},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := make(chan struct{}, 1)
+			r := newTestRepo(newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+				switch req.Method {
+				case http.MethodHead:
+					w.WriteHeader(http.StatusNotFound)
+				case http.MethodPut:
+					created <- struct{}{}
+					io.WriteString(w, `{"acknowledged":true,"shards_acknowledged":true,"index":"review"}`)
+				default:
+					w.WriteHeader(tt.status)
+					io.WriteString(w, tt.body)
+				}
+			}))
+			r.esConf = &conf.Elasticsearch{Analyzer: tt.analyzer}
+			_, _, err := r.GetDataFromES(context.Background(), listCacheKey("store", "1", 0, 10), "store")
+			if missing := errors.Is(err, biz.ErrSearchIndexMissing); missing != tt.wantMissing {
+				t.Fatalf("GetDataFromES() error = %v, want ErrSearchIndexMissing: %v", err, tt.wantMissing)
+			}
+			if tt.wantMissing && kerrors.FromError(err).Code != http.StatusServiceUnavailable {
+				t.Errorf("code = %d, want 503", kerrors.FromError(err).Code)
+			}
+			if !tt.wantCreate {
+				return
+			}
+			select {
+			case <-created:
+			case <-time.After(time.Second):
+				t.Error("index was not created in the background")
+			}
+		})
+	}
+}
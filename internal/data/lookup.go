@@ -0,0 +1,24 @@
+package data
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"gorm.io/gorm"
+)
+
+// lookupError 将查询错误转换为对外的错误: 记录不存在时返回 notFound（404）,
+// 数据库连接失败或超时返回503, 其它错误返回500; 原始错误保留为cause, 只写日志不返回给客户端
+func lookupError(err error, notFound *kerrors.Error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return kerrors.ServiceUnavailable("DB_UNAVAILABLE", "数据库暂时不可用，请稍后重试").WithCause(err)
+	}
+	return kerrors.InternalServer("DB_FAILED", "数据库查询失败").WithCause(err)
+}
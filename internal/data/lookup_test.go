@@ -0,0 +1,44 @@
+package data
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"review/internal/biz"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"gorm.io/gorm"
+)
+
+func TestLookupError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   int32
+		wantReason string
+	}{
+		{name: "not found", err: gorm.ErrRecordNotFound, wantCode: 404, wantReason: biz.ErrReviewNotFound.Reason},
+		{name: "wrapped not found", err: fmt.Errorf("find review: %w", gorm.ErrRecordNotFound), wantCode: 404, wantReason: biz.ErrReviewNotFound.Reason},
+		{name: "bad connection", err: driver.ErrBadConn, wantCode: 503, wantReason: "DB_UNAVAILABLE"},
+		{name: "timeout", err: context.DeadlineExceeded, wantCode: 503, wantReason: "DB_UNAVAILABLE"},
+		{name: "network", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, wantCode: 503, wantReason: "DB_UNAVAILABLE"},
+		{name: "other", err: errors.New("Error 1054: Unknown column"), wantCode: 500, wantReason: "DB_FAILED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := lookupError(tt.err, biz.ErrReviewNotFound)
+			se := kerrors.FromError(err)
+			if se.Code != tt.wantCode || se.Reason != tt.wantReason {
+				t.Fatalf("lookupError(%v) = %v, want %d %s", tt.err, err, tt.wantCode, tt.wantReason)
+			}
+			// The database error is kept for logs but never shown to the client.
+			if tt.wantCode != 404 && (!errors.Is(err, tt.err) || se.Message == tt.err.Error()) {
+				t.Errorf("lookupError(%v) = %v, want the cause kept out of the message", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"strconv"
+	"time"
+
+	"review/internal/client/ai"
+	"review/internal/conf"
+	"review/pkg/fingerprint"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+)
+
+// 审核结论缓存的匹配方式, 见 conf.Review.ModerationCache.mode
+const (
+	moderationCacheExact = "exact"
+	moderationCacheFuzzy = "fuzzy"
+)
+
+const (
+	defaultModerationCacheTTL = 24 * time.Hour
+	// maxFuzzyDistance 指纹按4段索引, 汉明距离不超过3时才能保证至少一段相同
+	maxFuzzyDistance = 3
+)
+
+// moderationCacheIndexKey 记录已缓存结论的有序集合, 成员为结论的key, 分数为写入时间(Unix秒), 用于统计结论数和按写入顺序淘汰
+const moderationCacheIndexKey = "moderation:index"
+
+// 审核结论缓存指标, 通过 /debug/vars 暴露
+var (
+	// moderationCacheHits 命中次数, 按匹配方式(exact/fuzzy)统计
+	moderationCacheHits = expvar.NewMap("moderation_cache_hits")
+	// moderationCacheMisses 未命中次数
+	moderationCacheMisses = expvar.NewInt("moderation_cache_misses")
+	// moderationCacheEntries 最近一次写入后缓存的结论数(近似值)
+	moderationCacheEntries = expvar.NewInt("moderation_cache_entries")
+	// moderationCacheEvicted 因超出 max_entries 被淘汰的结论数
+	moderationCacheEvicted = expvar.NewInt("moderation_cache_evicted")
+)
+
+// moderationCache 按内容缓存AI审核结论
+// exact 模式按原文的SHA-256匹配; fuzzy 模式另外为驳回的内容记录SimHash指纹, 指纹按4段16位建立索引,
+// 查找时取与任一段相同的已驳回指纹, 汉明距离不超过 distance 即视为近似重复
+// 每个结论的key记录在 moderationCacheIndexKey 中, 写入时清理已过期的记录, 超出 maxEntries 时淘汰最早写入的结论
+type moderationCache struct {
+	rdb      *redis.Client
+	log      *log.Helper
+	fuzzy    bool
+	ttl      time.Duration
+	distance int
+	// maxEntries 结论数的近似上限, 0 表示不限制
+	maxEntries int64
+}
+
+// newModerationCache 未开启时返回nil, nil的缓存不命中也不写入
+func newModerationCache(c *conf.Review_ModerationCache, rdb *redis.Client, logger *log.Helper) *moderationCache {
+	if !c.GetEnabled() {
+		return nil
+	}
+	mc := &moderationCache{
+		rdb:      rdb,
+		log:      logger,
+		fuzzy:    c.GetMode() == moderationCacheFuzzy,
+		ttl:      c.GetTtl().AsDuration(),
+		distance: int(c.GetFuzzyDistance()),
+		// 负数按不限制处理
+		maxEntries: max(int64(c.GetMaxEntries()), 0),
+	}
+	if mc.ttl <= 0 {
+		mc.ttl = defaultModerationCacheTTL
+	}
+	if mc.distance <= 0 || mc.distance > maxFuzzyDistance {
+		mc.distance = maxFuzzyDistance
+	}
+	return mc
+}
+
+func exactModerationKey(text string) string {
+	return "moderation:exact:" + fingerprint.Exact(text)
+}
+
+func fuzzyModerationKey(fp uint64) string {
+	return "moderation:fuzzy:" + strconv.FormatUint(fp, 16)
+}
+
+func moderationBandKey(i int, band uint16) string {
+	return fmt.Sprintf("moderation:band:%d:%04x", i, band)
+}
+
+// Get 查找内容的审核结论, 第二个返回值为匹配方式, 未命中时为空
+// 读缓存失败按未命中处理
+func (mc *moderationCache) Get(ctx context.Context, text string) (*ai.ModerationResult, string) {
+	if mc == nil {
+		return nil, ""
+	}
+	res, match := mc.get(ctx, text)
+	if res == nil {
+		moderationCacheMisses.Add(1)
+	}
+	return res, match
+}
+
+func (mc *moderationCache) get(ctx context.Context, text string) (*ai.ModerationResult, string) {
+	if res := mc.load(ctx, exactModerationKey(text)); res != nil {
+		moderationCacheHits.Add(moderationCacheExact, 1)
+		return res, moderationCacheExact
+	}
+	if !mc.fuzzy {
+		return nil, ""
+	}
+	fp := fingerprint.SimHash(text)
+	if fp == 0 {
+		return nil, ""
+	}
+	seen := make(map[uint64]bool)
+	for i, band := range fingerprint.Bands(fp) {
+		members, err := mc.rdb.SMembers(ctx, moderationBandKey(i, band)).Result()
+		if err != nil {
+			mc.fail(ctx, "read", err)
+			return nil, ""
+		}
+		for _, m := range members {
+			candidate, err := strconv.ParseUint(m, 16, 64)
+			if err != nil || seen[candidate] {
+				continue
+			}
+			seen[candidate] = true
+			if fingerprint.Distance(fp, candidate) > mc.distance {
+				continue
+			}
+			// 索引中的指纹可能比结论先过期, 结论不存在时继续查找
+			if res := mc.load(ctx, fuzzyModerationKey(candidate)); res != nil && !res.Approved {
+				moderationCacheHits.Add(moderationCacheFuzzy, 1)
+				return res, moderationCacheFuzzy
+			}
+		}
+	}
+	return nil, ""
+}
+
+// Set 缓存内容的审核结论, fuzzy 模式下驳回的结论同时按指纹记录; 模型调用失败或输出无法解析的结论不缓存
+// 写缓存失败只记录日志
+func (mc *moderationCache) Set(ctx context.Context, text string, res *ai.ModerationResult) {
+	if mc == nil || res == nil || res.Reason == ai.ModerationErrorReason {
+		return
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return
+	}
+	exactKey := exactModerationKey(text)
+	if err := mc.rdb.Set(ctx, exactKey, b, mc.ttl).Err(); err != nil {
+		mc.fail(ctx, "write", err)
+		return
+	}
+	if !mc.fuzzy || res.Approved {
+		mc.track(ctx, exactKey)
+		return
+	}
+	fp := fingerprint.SimHash(text)
+	if fp == 0 {
+		return
+	}
+	member := strconv.FormatUint(fp, 16)
+	_, err = mc.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, fuzzyModerationKey(fp), b, mc.ttl)
+		for i, band := range fingerprint.Bands(fp) {
+			key := moderationBandKey(i, band)
+			pipe.SAdd(ctx, key, member)
+			pipe.Expire(ctx, key, mc.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		mc.fail(ctx, "write", err)
+	}
+	mc.track(ctx, exactKey, fuzzyModerationKey(fp))
+}
+
+// track 记录新写入的结论key, 清理索引中已过期的记录, 超出 maxEntries 时删除最早写入的结论
+// 与写入结论不在同一事务中, 失败只记录日志: 索引偏差只影响计数和淘汰, 结论本身仍按 ttl 过期
+func (mc *moderationCache) track(ctx context.Context, keys ...string) {
+	now := time.Now()
+	members := make([]redis.Z, 0, len(keys))
+	for _, key := range keys {
+		members = append(members, redis.Z{Score: float64(now.Unix()), Member: key})
+	}
+	var size *redis.IntCmd
+	_, err := mc.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, moderationCacheIndexKey, members...)
+		pipe.ZRemRangeByScore(ctx, moderationCacheIndexKey, "-inf", strconv.FormatInt(now.Add(-mc.ttl).Unix(), 10))
+		pipe.Expire(ctx, moderationCacheIndexKey, mc.ttl)
+		size = pipe.ZCard(ctx, moderationCacheIndexKey)
+		return nil
+	})
+	if err != nil {
+		mc.fail(ctx, "write", err)
+		return
+	}
+	n := size.Val()
+	if mc.maxEntries > 0 && n > mc.maxEntries {
+		evicted, err := mc.rdb.ZPopMin(ctx, moderationCacheIndexKey, n-mc.maxEntries).Result()
+		if err != nil {
+			mc.fail(ctx, "evict", err)
+			return
+		}
+		stale := make([]string, 0, len(evicted))
+		for _, z := range evicted {
+			stale = append(stale, z.Member.(string))
+		}
+		if len(stale) > 0 {
+			if err := mc.rdb.Del(ctx, stale...).Err(); err != nil {
+				mc.fail(ctx, "evict", err)
+				return
+			}
+		}
+		moderationCacheEvicted.Add(int64(len(stale)))
+		n -= int64(len(stale))
+	}
+	moderationCacheEntries.Set(n)
+}
+
+func (mc *moderationCache) load(ctx context.Context, key string) *ai.ModerationResult {
+	b, err := mc.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			mc.fail(ctx, "read", err)
+		}
+		return nil
+	}
+	res := new(ai.ModerationResult)
+	if err := json.Unmarshal(b, res); err != nil {
+		return nil
+	}
+	return res
+}
+
+func (mc *moderationCache) fail(ctx context.Context, op string, err error) {
+	cacheUnavailable.Add(1)
+	mc.log.WithContext(ctx).Warnf("moderation cache %s failed: %v", op, err)
+}
@@ -0,0 +1,296 @@
+package data
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"review/internal/client/ai"
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// memRedis is an in-memory RESP2 server implementing only the commands the
+// moderation cache and the startup checks send. Expirations are ignored.
+type memRedis struct {
+	mu      sync.Mutex
+	strings map[string]string
+	sets    map[string]map[string]bool
+	zsets   map[string]map[string]float64
+}
+
+func newMemRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	m := &memRedis{strings: map[string]string{}, sets: map[string]map[string]bool{}, zsets: map[string]map[string]float64{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go m.serve(conn)
+		}
+	}()
+	rdb := redis.NewClient(&redis.Options{Addr: ln.Addr().String(), Protocol: 2, DisableIndentity: true})
+	t.Cleanup(func() { rdb.Close() })
+	return rdb
+}
+
+func (m *memRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	var queued [][]string
+	inTx := false
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "MULTI":
+			inTx, queued = true, nil
+			io.WriteString(conn, "+OK\r\n")
+		case cmd == "EXEC":
+			replies := make([]string, 0, len(queued))
+			for _, q := range queued {
+				replies = append(replies, m.exec(q))
+			}
+			inTx = false
+			fmt.Fprintf(conn, "*%d\r\n%s", len(replies), strings.Join(replies, ""))
+		case inTx:
+			queued = append(queued, args)
+			io.WriteString(conn, "+QUEUED\r\n")
+		default:
+			io.WriteString(conn, m.exec(args))
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func bulk(s string) string { return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s) }
+
+func (m *memRedis) exec(args []string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		v, ok := m.strings[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(v)
+	case "SET":
+		m.strings[args[1]] = args[2]
+		return "+OK\r\n"
+	case "SADD":
+		if m.sets[args[1]] == nil {
+			m.sets[args[1]] = map[string]bool{}
+		}
+		for _, member := range args[2:] {
+			m.sets[args[1]][member] = true
+		}
+		return ":1\r\n"
+	case "SMEMBERS":
+		var b strings.Builder
+		fmt.Fprintf(&b, "*%d\r\n", len(m.sets[args[1]]))
+		for member := range m.sets[args[1]] {
+			b.WriteString(bulk(member))
+		}
+		return b.String()
+	case "ZADD":
+		if m.zsets[args[1]] == nil {
+			m.zsets[args[1]] = map[string]float64{}
+		}
+		for i := 2; i+1 < len(args); i += 2 {
+			score, _ := strconv.ParseFloat(args[i], 64)
+			m.zsets[args[1]][args[i+1]] = score
+		}
+		return ":1\r\n"
+	case "ZCARD":
+		return fmt.Sprintf(":%d\r\n", len(m.zsets[args[1]]))
+	case "EXPIRE":
+		return ":1\r\n"
+	case "ZREMRANGEBYSCORE":
+		max, _ := strconv.ParseFloat(args[3], 64)
+		n := 0
+		for member, score := range m.zsets[args[1]] {
+			if score <= max {
+				delete(m.zsets[args[1]], member)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "ZPOPMIN":
+		// Lowest score first, ties by member, as in Redis.
+		set := m.zsets[args[1]]
+		members := make([]string, 0, len(set))
+		for member := range set {
+			members = append(members, member)
+		}
+		sort.Slice(members, func(i, j int) bool {
+			if set[members[i]] != set[members[j]] {
+				return set[members[i]] < set[members[j]]
+			}
+			return members[i] < members[j]
+		})
+		n, _ := strconv.Atoi(args[2])
+		members = members[:min(n, len(members))]
+		var b strings.Builder
+		fmt.Fprintf(&b, "*%d\r\n", 2*len(members))
+		for _, member := range members {
+			b.WriteString(bulk(member) + bulk(strconv.FormatFloat(set[member], 'f', -1, 64)))
+			delete(set, member)
+		}
+		return b.String()
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := m.strings[key]; ok {
+				delete(m.strings, key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	}
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+func TestModerationCache(t *testing.T) {
+	const (
+		spam     = "加微信领取优惠券，全场商品一律五折，名额有限先到先得，关注店铺还送精美礼品一份，每天前一百名下单的顾客另有惊喜红包，错过今天再等一年"
+		nearSpam = "加薇信领取优惠券!!全场商品一律五折，名额有限先到先得，关注店铺还送精美礼品一份，每天前一百名下单的顾客另有惊喜红包，错过今天再等一年。"
+		fine     = "包装很结实，物流也快，下次还会再来买"
+	)
+	rejected := &ai.ModerationResult{Category: "广告", Reason: "引流"}
+	tests := []struct {
+		name      string
+		mode      string
+		cached    string
+		result    *ai.ModerationResult
+		lookup    string
+		wantMatch string
+	}{
+		{name: "exact hit", cached: spam, result: rejected, lookup: spam, wantMatch: moderationCacheExact},
+		{name: "exact mode misses variations", cached: spam, result: rejected, lookup: nearSpam},
+		{name: "fuzzy hit on a near-duplicate rejection", mode: moderationCacheFuzzy, cached: spam, result: rejected, lookup: nearSpam, wantMatch: moderationCacheFuzzy},
+		{name: "fuzzy mode ignores unrelated text", mode: moderationCacheFuzzy, cached: spam, result: rejected, lookup: fine},
+		{name: "approvals are only reused exactly", mode: moderationCacheFuzzy, cached: fine, result: &ai.ModerationResult{Approved: true}, lookup: fine + "!"},
+		{name: "model errors are not cached", cached: spam, result: &ai.ModerationResult{Reason: ai.ModerationErrorReason}, lookup: spam},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mc := newModerationCache(&conf.Review_ModerationCache{Enabled: true, Mode: tt.mode}, newMemRedis(t), log.NewHelper(log.DefaultLogger))
+			mc.Set(ctx, tt.cached, tt.result)
+			res, match := mc.Get(ctx, tt.lookup)
+			if match != tt.wantMatch {
+				t.Fatalf("Get() match = %q, want %q", match, tt.wantMatch)
+			}
+			if tt.wantMatch != "" && (res == nil || *res != *tt.result) {
+				t.Errorf("Get() = %+v, want %+v", res, tt.result)
+			}
+		})
+	}
+}
+
+func TestNewModerationCache(t *testing.T) {
+	if mc := newModerationCache(&conf.Review_ModerationCache{}, nil, nil); mc != nil {
+		t.Fatalf("newModerationCache() = %+v, want nil when disabled", mc)
+	}
+	// A nil cache never hits.
+	var mc *moderationCache
+	if res, match := mc.Get(context.Background(), "x"); res != nil || match != "" {
+		t.Errorf("nil cache Get() = %v, %q, want a miss", res, match)
+	}
+	// A negative max_entries means no limit.
+	if mc := newModerationCache(&conf.Review_ModerationCache{Enabled: true, Ttl: durationpb.New(time.Hour), MaxEntries: -1}, nil, nil); mc.ttl != time.Hour || mc.maxEntries != 0 {
+		t.Errorf("ttl, max entries = %v, %d, want 1h, unlimited", mc.ttl, mc.maxEntries)
+	}
+	tests := []struct {
+		name         string
+		distance     int32
+		wantDistance int
+	}{
+		{name: "default", wantDistance: maxFuzzyDistance},
+		{name: "configured", distance: 1, wantDistance: 1},
+		{name: "capped at the band guarantee", distance: 10, wantDistance: maxFuzzyDistance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := newModerationCache(&conf.Review_ModerationCache{Enabled: true, FuzzyDistance: tt.distance}, nil, nil)
+			if mc.distance != tt.wantDistance || mc.ttl != defaultModerationCacheTTL {
+				t.Errorf("distance, ttl = %d, %v, want %d, %v", mc.distance, mc.ttl, tt.wantDistance, defaultModerationCacheTTL)
+			}
+		})
+	}
+}
+
+func TestModerationCacheMaxEntries(t *testing.T) {
+	ctx := context.Background()
+	rdb := newMemRedis(t)
+	mc := newModerationCache(&conf.Review_ModerationCache{Enabled: true, MaxEntries: 2}, rdb, log.NewHelper(log.DefaultLogger))
+	evicted := moderationCacheEvicted.Value()
+
+	texts := []string{"第一条评论", "第二条评论", "第三条评论"}
+	for _, text := range texts {
+		mc.Set(ctx, text, &ai.ModerationResult{Approved: true})
+	}
+	hits := 0
+	for _, text := range texts {
+		if res, _ := mc.Get(ctx, text); res != nil {
+			hits++
+		}
+	}
+	if hits != 2 {
+		t.Errorf("%d cached verdicts, want max_entries (2)", hits)
+	}
+	if got := moderationCacheEvicted.Value() - evicted; got != 1 {
+		t.Errorf("moderationCacheEvicted increased by %d, want 1", got)
+	}
+	if got := moderationCacheEntries.Value(); got != 2 {
+		t.Errorf("moderationCacheEntries = %d, want 2", got)
+	}
+	if n, err := rdb.ZCard(ctx, moderationCacheIndexKey).Result(); err != nil || n != 2 {
+		t.Errorf("index holds %d keys (%v), want 2", n, err)
+	}
+}
@@ -40,6 +40,9 @@ type ReviewInfo struct {
 	OpReason       string     `gorm:"column:op_reason;not null" json:"op_reason"`
 	OpRemarks      string     `gorm:"column:op_remarks;not null" json:"op_remarks"`
 	OpUser         string     `gorm:"column:op_user;not null" json:"op_user"`
+	RejectCategory string     `gorm:"column:reject_category;not null" json:"reject_category"`
+	ClientIP       string     `gorm:"column:client_ip;not null" json:"client_ip"`
+	UserAgent      string     `gorm:"column:user_agent;not null" json:"user_agent"`
 	GoodsSnapshoot string     `gorm:"column:goods_snapshoot;not null" json:"goods_snapshoot"`
 	ExtJSON        string     `gorm:"column:ext_json;not null;comment:JSON" json:"ext_json"`   // JSON
 	CtrlJSON       string     `gorm:"column:ctrl_json;not null;comment:JSON" json:"ctrl_json"` // JSON
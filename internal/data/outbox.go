@@ -0,0 +1,274 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"review/internal/conf"
+	"review/internal/data/model"
+	"review/internal/data/query"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+const (
+	defaultOutboxBatchSize = 200
+	defaultOutboxMinAge    = time.Minute
+	outboxLockKey          = "review:outbox:lock"
+	// maxOutboxErrorLength 与 es_outbox.last_error varchar(512) 保持一致
+	maxOutboxErrorLength = 512
+)
+
+// outboxEnabled 是否开启ES同步发件箱, 未开启时写入评论不记录发件箱
+func (r *reviewRepo) outboxEnabled() bool {
+	return r.esConf.GetOutbox().GetInterval().AsDuration() > 0
+}
+
+// enqueueOutbox 在写入评论的事务中记录一条待同步记录, 版本号为本次写入后的评论版本号
+// 与评论写入同时提交或回滚, 保证每次写入最终都会同步到ES
+func (r *reviewRepo) enqueueOutbox(ctx context.Context, tx *query.Query, reviewID int64) error {
+	if !r.outboxEnabled() {
+		return nil
+	}
+	review, err := tx.ReviewInfo.WithContext(ctx).Select(tx.ReviewInfo.Version).Where(tx.ReviewInfo.ReviewID.Eq(reviewID)).First()
+	if err != nil {
+		return err
+	}
+	return tx.EsOutbox.WithContext(ctx).Create(&model.EsOutbox{ReviewID: reviewID, Version: review.Version})
+}
+
+// ackOutbox ES中已有版本号不低于 version 的文档, 删除评论版本号不超过 version 的待同步记录
+// 删除失败只记录日志, 记录会由后台再同步一次
+func (r *reviewRepo) ackOutbox(ctx context.Context, reviewID int64, version int32) {
+	if !r.outboxEnabled() {
+		return
+	}
+	eo := r.data.q.EsOutbox
+	if _, err := eo.WithContext(ctx).Where(eo.ReviewID.Eq(reviewID), eo.Version.Lte(version)).Delete(); err != nil {
+		r.log.WithContext(ctx).Warnf("failed to ack es outbox for review ID %d: %v", reviewID, err)
+	}
+}
+
+// OutboxRelay 定时将发件箱中的评论写入ES
+// 实现了 transport.Server, 随应用启动和停止
+type OutboxRelay struct {
+	repo      *reviewRepo
+	log       *log.Helper
+	interval  time.Duration
+	batchSize int
+	minAge    time.Duration
+	// owner 持有Redis锁时写入的值, 便于排查是哪一个副本在执行
+	owner string
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewOutboxRelay(data *Data, logger log.Logger, esConf *conf.Elasticsearch) *OutboxRelay {
+	c := esConf.GetOutbox()
+	o := &OutboxRelay{
+		repo:      &reviewRepo{data: data, log: log.NewHelper(logger), esConf: esConf},
+		log:       log.NewHelper(logger),
+		interval:  c.GetInterval().AsDuration(),
+		batchSize: int(c.GetBatchSize()),
+		minAge:    c.GetMinAge().AsDuration(),
+		owner:     strconv.FormatInt(time.Now().UnixNano(), 10),
+		stop:      make(chan struct{}),
+	}
+	if o.batchSize <= 0 {
+		o.batchSize = defaultOutboxBatchSize
+	}
+	if o.minAge <= 0 {
+		o.minAge = defaultOutboxMinAge
+	}
+	return o
+}
+
+// Start 按配置的间隔处理发件箱, 未配置间隔时不启动
+func (o *OutboxRelay) Start(context.Context) error {
+	if o.interval <= 0 {
+		return nil
+	}
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		ticker := time.NewTicker(o.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-o.stop:
+				return
+			case <-ticker.C:
+				o.runOnce(context.Background())
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop 停止处理, 等待正在执行的一轮结束
+func (o *OutboxRelay) Stop(context.Context) error {
+	close(o.stop)
+	o.wg.Wait()
+	return nil
+}
+
+// runOnce 执行一轮处理, 直到没有到期的记录; 没有抢到锁说明其他副本正在执行, 直接跳过
+func (o *OutboxRelay) runOnce(ctx context.Context) {
+	defer recoverPanic(o.log, "outbox relay")
+	// 锁在一个间隔后自动过期, 不主动释放, 保证每个间隔只有一个副本执行
+	ok, err := o.repo.data.rdb.SetNX(ctx, outboxLockKey, o.owner, o.interval).Result()
+	if err != nil {
+		o.log.Errorf("outbox: failed to acquire lock: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	started := time.Now()
+	synced, failed := 0, 0
+	var afterID int64
+	for {
+		select {
+		case <-o.stop:
+			return
+		default:
+		}
+		rows, err := o.due(ctx, started, afterID)
+		if err != nil {
+			o.log.Errorf("outbox: failed to read outbox: %v", err)
+			return
+		}
+		if len(rows) == 0 {
+			break
+		}
+		afterID = rows[len(rows)-1].ID
+		s, f, err := o.relay(ctx, rows)
+		synced, failed = synced+s, failed+f
+		if err != nil {
+			o.log.Errorf("outbox: stopped after syncing %d reviews, %d failed: %v", synced, failed, err)
+			return
+		}
+		if len(rows) < o.batchSize {
+			break
+		}
+	}
+	if synced+failed > 0 {
+		o.log.Infof("outbox: synced %d reviews, %d failed and kept for retry, in %v", synced, failed, time.Since(started))
+	}
+}
+
+// due 按ID顺序读取本轮开始前已超过 min_age 的记录
+// 失败的记录保留在发件箱中, 按ID向后读取, 同一轮内不会重复处理
+func (o *OutboxRelay) due(ctx context.Context, started time.Time, afterID int64) ([]*model.EsOutbox, error) {
+	eo := o.repo.data.q.EsOutbox
+	return eo.WithContext(ctx).
+		Where(eo.ID.Gt(afterID), eo.CreateAt.Lte(started.Add(-o.minAge))).
+		Order(eo.ID).
+		Limit(o.batchSize).
+		Find()
+}
+
+// relay 将一批记录对应的评论的当前数据写入ES, 已删除的评论从ES中删除
+// 成功的记录被删除; 失败的记录增加失败次数并保留, 在下一轮重试
+func (o *OutboxRelay) relay(ctx context.Context, rows []*model.EsOutbox) (synced, failed int, err error) {
+	ids := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ReviewID)
+	}
+	ri := o.repo.data.q.ReviewInfo
+	reviews, err := ri.WithContext(ctx).Where(ri.ReviewID.In(ids...)).Find()
+	if err != nil {
+		return 0, 0, err
+	}
+	byID := make(map[int64]*model.ReviewInfo, len(reviews))
+	for _, review := range reviews {
+		byID[review.ReviewID] = review
+	}
+
+	// 同一评论的多条记录只写入一次当前数据
+	errs := make(map[int64]string)
+	seen := make(map[int64]bool, len(rows))
+	bulk := newBulkIndexer(o.repo, o.repo.esConf.GetBulk())
+	for _, row := range rows {
+		if seen[row.ReviewID] {
+			continue
+		}
+		seen[row.ReviewID] = true
+		review, ok := byID[row.ReviewID]
+		if !ok || review.DeleteAt != nil {
+			if err := o.deleteFromES(ctx, row.ReviewID); err != nil {
+				errs[row.ReviewID] = err.Error()
+			}
+			continue
+		}
+		failures, err := bulk.Add(ctx, review)
+		if err != nil {
+			return synced, failed, err
+		}
+		collectFailures(errs, failures)
+	}
+	failures, err := bulk.Flush(ctx)
+	if err != nil {
+		return synced, failed, err
+	}
+	collectFailures(errs, failures)
+
+	eo := o.repo.data.q.EsOutbox
+	done, retry := splitOutbox(rows, errs)
+	for _, row := range retry {
+		failed++
+		if _, err := eo.WithContext(ctx).Where(eo.ID.Eq(row.ID)).Updates(map[string]interface{}{
+			"attempts":   row.Attempts + 1,
+			"last_error": truncateBytes(errs[row.ReviewID], maxOutboxErrorLength),
+		}); err != nil {
+			o.log.Warnf("outbox: failed to record error for review ID %d: %v", row.ReviewID, err)
+		}
+	}
+	if len(done) > 0 {
+		if _, err := eo.WithContext(ctx).Where(eo.ID.In(done...)).Delete(); err != nil {
+			return synced, failed, err
+		}
+	}
+	return len(done), failed, nil
+}
+
+// splitOutbox 按同步结果区分记录: done 为可删除的记录ID, retry 为同步失败需要保留重试的记录
+// errs 中的评论(包括ES中版本不比本次写入新的版本冲突)同步失败, 其所有记录都保留
+func splitOutbox(rows []*model.EsOutbox, errs map[int64]string) (done []int64, retry []*model.EsOutbox) {
+	for _, row := range rows {
+		if _, bad := errs[row.ReviewID]; bad {
+			retry = append(retry, row)
+			continue
+		}
+		done = append(done, row.ID)
+	}
+	return done, retry
+}
+
+func collectFailures(errs map[int64]string, failures []bulkFailure) {
+	for _, f := range failures {
+		errs[f.ReviewID] = f.String()
+	}
+}
+
+// deleteFromES 删除ES中的评论文档, 文档不存在时视为成功
+func (o *OutboxRelay) deleteFromES(ctx context.Context, reviewID int64) error {
+	_, err := o.repo.data.es.Delete(reviewIndex, strconv.FormatInt(reviewID, 10)).Do(ctx)
+	var esErr *types.ElasticsearchError
+	if errors.As(err, &esErr) && esErr.Status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// ackAllOutbox 评论已从ES中删除, 删除该评论的全部待同步记录
+func (r *reviewRepo) ackAllOutbox(ctx context.Context, reviewID int64) {
+	r.ackOutbox(ctx, reviewID, math.MaxInt32)
+}
@@ -0,0 +1,146 @@
+package data
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestSplitOutbox(t *testing.T) {
+	rows := []*model.EsOutbox{
+		{ID: 1, ReviewID: 100, Version: 1},
+		{ID: 2, ReviewID: 200, Version: 1},
+		{ID: 3, ReviewID: 100, Version: 2},
+		{ID: 4, ReviewID: 300, Version: 5},
+	}
+	tests := []struct {
+		name      string
+		errs      map[int64]string
+		wantDone  []int64
+		wantRetry []int64
+	}{
+		{
+			name:     "all synced",
+			errs:     map[int64]string{},
+			wantDone: []int64{1, 2, 3, 4},
+		},
+		{
+			name:      "failed review keeps all of its rows",
+			errs:      map[int64]string{100: "review 100: status 500, es_rejected_execution_exception"},
+			wantDone:  []int64{2, 4},
+			wantRetry: []int64{1, 3},
+		},
+		{
+			name:      "unresolved version conflict is kept for retry",
+			errs:      map[int64]string{300: "review 300: status 409, version_conflict: es document is older than version 5"},
+			wantDone:  []int64{1, 2, 3},
+			wantRetry: []int64{4},
+		},
+		{
+			name:      "all failed",
+			errs:      map[int64]string{100: "x", 200: "y", 300: "z"},
+			wantRetry: []int64{1, 2, 3, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, retry := splitOutbox(rows, tt.errs)
+			var retryIDs []int64
+			for _, row := range retry {
+				retryIDs = append(retryIDs, row.ID)
+			}
+			if !reflect.DeepEqual(done, tt.wantDone) {
+				t.Errorf("done = %v, want %v", done, tt.wantDone)
+			}
+			if !reflect.DeepEqual(retryIDs, tt.wantRetry) {
+				t.Errorf("retry = %v, want %v", retryIDs, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestEnqueueOutbox(t *testing.T) {
+	tests := []struct {
+		name      string
+		outbox    *conf.Elasticsearch_Outbox
+		wantStmts []string
+	}{
+		{name: "disabled", outbox: &conf.Elasticsearch_Outbox{}},
+		{name: "enabled", outbox: &conf.Elasticsearch_Outbox{Interval: durationpb.New(time.Second)}, wantStmts: []string{"SELECT", "INSERT INTO `es_outbox`"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &execConn{rowsAffected: 1, counts: []int64{3}, column: "version"}
+			q := newExecQuery(t, conn)
+			r := &reviewRepo{data: &Data{q: q}, esConf: &conf.Elasticsearch{Outbox: tt.outbox}}
+			if err := r.enqueueOutbox(context.Background(), q, 9); err != nil {
+				t.Fatalf("enqueueOutbox() error = %v", err)
+			}
+			if len(conn.stmts) != len(tt.wantStmts) {
+				t.Fatalf("statements = %q, want %q", conn.stmts, tt.wantStmts)
+			}
+			for i, prefix := range tt.wantStmts {
+				if !strings.HasPrefix(conn.stmts[i], prefix) {
+					t.Errorf("statement %d = %s, want %s...", i, conn.stmts[i], prefix)
+				}
+			}
+			// The row records the version the write produced.
+			if len(conn.args) == 2 && !hasArg(conn.args[1], int64(3)) {
+				t.Errorf("INSERT args = %v, want version 3", conn.args[1])
+			}
+		})
+	}
+}
+
+// hasArg reports whether a statement was executed with v as one of its arguments.
+func hasArg(args []driver.NamedValue, v any) bool {
+	for _, a := range args {
+		if reflect.DeepEqual(a.Value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAckOutbox(t *testing.T) {
+	conn := &execConn{rowsAffected: 2}
+	r := &reviewRepo{
+		data:   &Data{q: newExecQuery(t, conn)},
+		log:    log.NewHelper(log.DefaultLogger),
+		esConf: &conf.Elasticsearch{Outbox: &conf.Elasticsearch_Outbox{Interval: durationpb.New(time.Second)}},
+	}
+	r.ackOutbox(context.Background(), 9, 4)
+	if len(conn.stmts) != 1 || !strings.HasPrefix(conn.stmts[0], "DELETE FROM `es_outbox`") || !strings.Contains(conn.stmts[0], "`version` <= ?") {
+		t.Fatalf("statements = %q, want one DELETE of rows up to the synced version", conn.stmts)
+	}
+	var args []any
+	for _, a := range conn.args[0] {
+		args = append(args, a.Value)
+	}
+	if !reflect.DeepEqual(args, []any{int64(9), int64(4)}) {
+		t.Errorf("DELETE args = %v, want review 9 and version 4", args)
+	}
+}
+
+func TestNewOutboxRelayDefaults(t *testing.T) {
+	o := NewOutboxRelay(&Data{}, log.DefaultLogger, &conf.Elasticsearch{})
+	if o.interval != 0 || o.batchSize != defaultOutboxBatchSize || o.minAge != defaultOutboxMinAge {
+		t.Errorf("interval, batch size, min age = %v, %d, %v, want 0, %d, %v", o.interval, o.batchSize, o.minAge, defaultOutboxBatchSize, defaultOutboxMinAge)
+	}
+	// Without an interval the relay does not run, so Stop returns at once.
+	if err := o.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
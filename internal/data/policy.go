@@ -0,0 +1,35 @@
+package data
+
+import (
+	"context"
+	"slices"
+
+	"review/internal/biz"
+)
+
+// ModerationPolicy 返回数据层生效的审核配置, 未配置的项按默认值填充
+func (r *reviewRepo) ModerationPolicy(_ context.Context) *biz.ModerationPolicy {
+	p := &biz.ModerationPolicy{
+		AI:              r.ai.ModerationPolicy(),
+		OnAIError:       r.onAIError,
+		StorePriorities: r.data.priority.GetStores(),
+	}
+	switch p.OnAIError {
+	case onAIErrorApprove, onAIErrorReject, onAIErrorHumanReview:
+	default:
+		p.OnAIError = onAIErrorHold
+	}
+	if r.fastPath.GetEnabled() {
+		p.TrustedFastPath = &biz.FastPathPolicy{
+			MinApproved:  r.fastPathMinApproved(),
+			BlockedWords: slices.Clone(r.fastPath.GetBlockedWords()),
+		}
+	}
+	if mc := r.modCache; mc != nil {
+		p.ModerationCache = &biz.ModerationCachePolicy{Mode: moderationCacheExact, TTL: mc.ttl, FuzzyDistance: mc.distance, MaxEntries: mc.maxEntries}
+		if mc.fuzzy {
+			p.ModerationCache.Mode = moderationCacheFuzzy
+		}
+	}
+	return p
+}
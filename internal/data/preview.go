@@ -0,0 +1,37 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"review/internal/biz"
+)
+
+// PreviewModeration 预审评论内容, 只调用AI审核, 不读写数据库和ES
+func (r *reviewRepo) PreviewModeration(ctx context.Context, content string) (*biz.ModerationVerdict, error) {
+	result, err := r.ai.Moderate(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	return &biz.ModerationVerdict{
+		Approved:   result.Approved,
+		Category:   result.Category,
+		Reason:     result.Reason,
+		Confidence: result.Confidence,
+	}, nil
+}
+
+// AllowPreview 按用户每分钟固定窗口计数, 多副本共享Redis中的计数
+func (r *reviewRepo) AllowPreview(ctx context.Context, userID int64, limit int) (bool, error) {
+	key := fmt.Sprintf("preview:rate:%d:%d", userID, time.Now().Unix()/60)
+	n, err := r.data.rdb.Incr(ctx, key).Result()
+	if err != nil {
+		cacheUnavailable.Add(1)
+		return false, err
+	}
+	if n == 1 {
+		r.data.rdb.Expire(ctx, key, time.Minute)
+	}
+	return n <= int64(limit), nil
+}
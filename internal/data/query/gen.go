@@ -17,7 +17,10 @@ import (
 
 var (
 	Q                = new(Query)
+	EsOutbox         *esOutbox
 	ReviewAppealInfo *reviewAppealInfo
+	ReviewAppendInfo *reviewAppendInfo
+	ReviewAuditLog   *reviewAuditLog
 	ReviewInfo       *reviewInfo
 	ReviewReplyInfo  *reviewReplyInfo
 	Store            *store
@@ -26,7 +29,10 @@ var (
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	*Q = *Use(db, opts...)
+	EsOutbox = &Q.EsOutbox
 	ReviewAppealInfo = &Q.ReviewAppealInfo
+	ReviewAppendInfo = &Q.ReviewAppendInfo
+	ReviewAuditLog = &Q.ReviewAuditLog
 	ReviewInfo = &Q.ReviewInfo
 	ReviewReplyInfo = &Q.ReviewReplyInfo
 	Store = &Q.Store
@@ -36,7 +42,10 @@ func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
 		db:               db,
+		EsOutbox:         newEsOutbox(db, opts...),
 		ReviewAppealInfo: newReviewAppealInfo(db, opts...),
+		ReviewAppendInfo: newReviewAppendInfo(db, opts...),
+		ReviewAuditLog:   newReviewAuditLog(db, opts...),
 		ReviewInfo:       newReviewInfo(db, opts...),
 		ReviewReplyInfo:  newReviewReplyInfo(db, opts...),
 		Store:            newStore(db, opts...),
@@ -47,7 +56,10 @@ func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 type Query struct {
 	db *gorm.DB
 
+	EsOutbox         esOutbox
 	ReviewAppealInfo reviewAppealInfo
+	ReviewAppendInfo reviewAppendInfo
+	ReviewAuditLog   reviewAuditLog
 	ReviewInfo       reviewInfo
 	ReviewReplyInfo  reviewReplyInfo
 	Store            store
@@ -59,7 +71,10 @@ func (q *Query) Available() bool { return q.db != nil }
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
 		db:               db,
+		EsOutbox:         q.EsOutbox.clone(db),
 		ReviewAppealInfo: q.ReviewAppealInfo.clone(db),
+		ReviewAppendInfo: q.ReviewAppendInfo.clone(db),
+		ReviewAuditLog:   q.ReviewAuditLog.clone(db),
 		ReviewInfo:       q.ReviewInfo.clone(db),
 		ReviewReplyInfo:  q.ReviewReplyInfo.clone(db),
 		Store:            q.Store.clone(db),
@@ -78,7 +93,10 @@ func (q *Query) WriteDB() *Query {
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
 		db:               db,
+		EsOutbox:         q.EsOutbox.replaceDB(db),
 		ReviewAppealInfo: q.ReviewAppealInfo.replaceDB(db),
+		ReviewAppendInfo: q.ReviewAppendInfo.replaceDB(db),
+		ReviewAuditLog:   q.ReviewAuditLog.replaceDB(db),
 		ReviewInfo:       q.ReviewInfo.replaceDB(db),
 		ReviewReplyInfo:  q.ReviewReplyInfo.replaceDB(db),
 		Store:            q.Store.replaceDB(db),
@@ -87,7 +105,10 @@ func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 }
 
 type queryCtx struct {
+	EsOutbox         IEsOutboxDo
 	ReviewAppealInfo IReviewAppealInfoDo
+	ReviewAppendInfo IReviewAppendInfoDo
+	ReviewAuditLog   IReviewAuditLogDo
 	ReviewInfo       IReviewInfoDo
 	ReviewReplyInfo  IReviewReplyInfoDo
 	Store            IStoreDo
@@ -96,7 +117,10 @@ type queryCtx struct {
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
+		EsOutbox:         q.EsOutbox.WithContext(ctx),
 		ReviewAppealInfo: q.ReviewAppealInfo.WithContext(ctx),
+		ReviewAppendInfo: q.ReviewAppendInfo.WithContext(ctx),
+		ReviewAuditLog:   q.ReviewAuditLog.WithContext(ctx),
 		ReviewInfo:       q.ReviewInfo.WithContext(ctx),
 		ReviewReplyInfo:  q.ReviewReplyInfo.WithContext(ctx),
 		Store:            q.Store.WithContext(ctx),
@@ -39,6 +39,7 @@ func newReviewAppealInfo(db *gorm.DB, opts ...gen.DOOption) reviewAppealInfo {
 	_reviewAppealInfo.ReviewID = field.NewInt64(tableName, "review_id")
 	_reviewAppealInfo.StoreID = field.NewInt64(tableName, "store_id")
 	_reviewAppealInfo.Status = field.NewInt32(tableName, "status")
+	_reviewAppealInfo.AppealType = field.NewString(tableName, "appeal_type")
 	_reviewAppealInfo.Reason = field.NewString(tableName, "reason")
 	_reviewAppealInfo.Content = field.NewString(tableName, "content")
 	_reviewAppealInfo.PicInfo = field.NewString(tableName, "pic_info")
@@ -56,26 +57,27 @@ func newReviewAppealInfo(db *gorm.DB, opts ...gen.DOOption) reviewAppealInfo {
 type reviewAppealInfo struct {
 	reviewAppealInfoDo reviewAppealInfoDo
 
-	ALL       field.Asterisk
-	ID        field.Int64
-	CreateBy  field.String
-	UpdateBy  field.String
-	CreateAt  field.Time
-	UpdateAt  field.Time
-	DeleteAt  field.Time
-	Version   field.Int32
-	AppealID  field.Int64 // id
-	ReviewID  field.Int64 // id
-	StoreID   field.Int64 // id
-	Status    field.Int32 // :102030
-	Reason    field.String
-	Content   field.String
-	PicInfo   field.String
-	VideoInfo field.String
-	OpRemarks field.String
-	OpUser    field.String
-	ExtJSON   field.String
-	CtrlJSON  field.String
+	ALL        field.Asterisk
+	ID         field.Int64
+	CreateBy   field.String
+	UpdateBy   field.String
+	CreateAt   field.Time
+	UpdateAt   field.Time
+	DeleteAt   field.Time
+	Version    field.Int32
+	AppealID   field.Int64 // id
+	ReviewID   field.Int64 // id
+	StoreID    field.Int64 // id
+	Status     field.Int32 // :102030
+	AppealType field.String
+	Reason     field.String
+	Content    field.String
+	PicInfo    field.String
+	VideoInfo  field.String
+	OpRemarks  field.String
+	OpUser     field.String
+	ExtJSON    field.String
+	CtrlJSON   field.String
 
 	fieldMap map[string]field.Expr
 }
@@ -103,6 +105,7 @@ func (r *reviewAppealInfo) updateTableName(table string) *reviewAppealInfo {
 	r.ReviewID = field.NewInt64(table, "review_id")
 	r.StoreID = field.NewInt64(table, "store_id")
 	r.Status = field.NewInt32(table, "status")
+	r.AppealType = field.NewString(table, "appeal_type")
 	r.Reason = field.NewString(table, "reason")
 	r.Content = field.NewString(table, "content")
 	r.PicInfo = field.NewString(table, "pic_info")
@@ -139,7 +142,7 @@ func (r *reviewAppealInfo) GetFieldByName(fieldName string) (field.OrderExpr, bo
 }
 
 func (r *reviewAppealInfo) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 19)
+	r.fieldMap = make(map[string]field.Expr, 20)
 	r.fieldMap["id"] = r.ID
 	r.fieldMap["create_by"] = r.CreateBy
 	r.fieldMap["update_by"] = r.UpdateBy
@@ -151,6 +154,7 @@ func (r *reviewAppealInfo) fillFieldMap() {
 	r.fieldMap["review_id"] = r.ReviewID
 	r.fieldMap["store_id"] = r.StoreID
 	r.fieldMap["status"] = r.Status
+	r.fieldMap["appeal_type"] = r.AppealType
 	r.fieldMap["reason"] = r.Reason
 	r.fieldMap["content"] = r.Content
 	r.fieldMap["pic_info"] = r.PicInfo
@@ -56,6 +56,9 @@ func newReviewInfo(db *gorm.DB, opts ...gen.DOOption) reviewInfo {
 	_reviewInfo.OpReason = field.NewString(tableName, "op_reason")
 	_reviewInfo.OpRemarks = field.NewString(tableName, "op_remarks")
 	_reviewInfo.OpUser = field.NewString(tableName, "op_user")
+	_reviewInfo.RejectCategory = field.NewString(tableName, "reject_category")
+	_reviewInfo.ClientIP = field.NewString(tableName, "client_ip")
+	_reviewInfo.UserAgent = field.NewString(tableName, "user_agent")
 	_reviewInfo.GoodsSnapshoot = field.NewString(tableName, "goods_snapshoot")
 	_reviewInfo.ExtJSON = field.NewString(tableName, "ext_json")
 	_reviewInfo.CtrlJSON = field.NewString(tableName, "ctrl_json")
@@ -97,6 +100,9 @@ type reviewInfo struct {
 	OpReason       field.String
 	OpRemarks      field.String
 	OpUser         field.String
+	RejectCategory field.String
+	ClientIP       field.String
+	UserAgent      field.String
 	GoodsSnapshoot field.String
 	ExtJSON        field.String // JSON
 	CtrlJSON       field.String // JSON
@@ -144,6 +150,9 @@ func (r *reviewInfo) updateTableName(table string) *reviewInfo {
 	r.OpReason = field.NewString(table, "op_reason")
 	r.OpRemarks = field.NewString(table, "op_remarks")
 	r.OpUser = field.NewString(table, "op_user")
+	r.RejectCategory = field.NewString(table, "reject_category")
+	r.ClientIP = field.NewString(table, "client_ip")
+	r.UserAgent = field.NewString(table, "user_agent")
 	r.GoodsSnapshoot = field.NewString(table, "goods_snapshoot")
 	r.ExtJSON = field.NewString(table, "ext_json")
 	r.CtrlJSON = field.NewString(table, "ctrl_json")
@@ -173,7 +182,7 @@ func (r *reviewInfo) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (r *reviewInfo) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 31)
+	r.fieldMap = make(map[string]field.Expr, 34)
 	r.fieldMap["id"] = r.ID
 	r.fieldMap["create_by"] = r.CreateBy
 	r.fieldMap["update_by"] = r.UpdateBy
@@ -202,6 +211,9 @@ func (r *reviewInfo) fillFieldMap() {
 	r.fieldMap["op_reason"] = r.OpReason
 	r.fieldMap["op_remarks"] = r.OpRemarks
 	r.fieldMap["op_user"] = r.OpUser
+	r.fieldMap["reject_category"] = r.RejectCategory
+	r.fieldMap["client_ip"] = r.ClientIP
+	r.fieldMap["user_agent"] = r.UserAgent
 	r.fieldMap["goods_snapshoot"] = r.GoodsSnapshoot
 	r.fieldMap["ext_json"] = r.ExtJSON
 	r.fieldMap["ctrl_json"] = r.CtrlJSON
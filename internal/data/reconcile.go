@@ -0,0 +1,296 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+const (
+	defaultReconcileBatchSize = 200
+	reconcileLockKey          = "review:reconcile:lock"
+	// reconcileCursorKey 上一次对账成功开始的时间, 下一次只检查此后更新过的评论
+	reconcileCursorKey = "review:reconcile:since"
+	// reconcileOverlap 向前多检查一段时间, 覆盖上一轮对账期间写入MySQL但尚未同步到ES的评论
+	reconcileOverlap = time.Minute
+)
+
+// errReconcileStopped 对账在本轮完成前被停止, 游标不前进
+var errReconcileStopped = errors.New("reconciler stopped")
+
+// Reconciler 定时对账ES与MySQL中的评论
+// 实现了 transport.Server, 随应用启动和停止
+type Reconciler struct {
+	repo      *reviewRepo
+	log       *log.Helper
+	interval  time.Duration
+	batchSize int
+	// owner 持有Redis锁时写入的值, 便于排查是哪一个副本在执行
+	owner string
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewReconciler(data *Data, logger log.Logger, esConf *conf.Elasticsearch) *Reconciler {
+	c := esConf.GetReconcile()
+	r := &Reconciler{
+		repo:      &reviewRepo{data: data, log: log.NewHelper(logger), esConf: esConf},
+		log:       log.NewHelper(logger),
+		interval:  c.GetInterval().AsDuration(),
+		batchSize: int(c.GetBatchSize()),
+		owner:     strconv.FormatInt(time.Now().UnixNano(), 10),
+		stop:      make(chan struct{}),
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = defaultReconcileBatchSize
+	}
+	return r
+}
+
+// Start 按配置的间隔执行对账, 未配置间隔时不启动
+func (r *Reconciler) Start(context.Context) error {
+	if r.interval <= 0 {
+		return nil
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.runOnce(context.Background())
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop 停止对账, 等待正在执行的一轮结束
+func (r *Reconciler) Stop(context.Context) error {
+	close(r.stop)
+	r.wg.Wait()
+	return nil
+}
+
+// runOnce 执行一轮对账; 没有抢到锁说明其他副本正在执行, 直接跳过
+func (r *Reconciler) runOnce(ctx context.Context) {
+	defer recoverPanic(r.log, "reconciler")
+	rdb := r.repo.data.rdb
+	// 锁在一个间隔后自动过期, 不主动释放, 保证每个间隔只有一个副本执行
+	ok, err := rdb.SetNX(ctx, reconcileLockKey, r.owner, r.interval).Result()
+	if err != nil {
+		r.log.Errorf("reconcile: failed to acquire lock: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	started := time.Now()
+	var since time.Time
+	if sec, err := rdb.Get(ctx, reconcileCursorKey).Int64(); err == nil {
+		since = time.Unix(sec, 0).Add(-reconcileOverlap)
+	}
+	checked, reindexed, err := r.reconcile(ctx, since)
+	// 未检查完或有评论写入失败时不保存游标, 下一轮从原游标重新检查
+	if errors.Is(err, errReconcileStopped) {
+		r.log.Infof("reconcile: stopped after checking %d reviews, reindexed %d, cursor not advanced", checked, reindexed)
+		return
+	}
+	if err != nil {
+		r.log.Errorf("reconcile: stopped after checking %d reviews, reindexed %d: %v", checked, reindexed, err)
+		return
+	}
+	if err := rdb.Set(ctx, reconcileCursorKey, started.Unix(), 0).Err(); err != nil {
+		r.log.Errorf("reconcile: failed to save cursor: %v", err)
+	}
+	r.log.Infof("reconcile: checked %d reviews updated since %v, reindexed %d in %v", checked, since, reindexed, time.Since(started))
+}
+
+// reconcile 分批读取 since 之后更新过的评论, 通过bulk重新写入ES中缺失或过期的文档
+// 被停止时返回 errReconcileStopped, 有评论写入失败时返回错误, 调用方据此不前进游标
+func (r *Reconciler) reconcile(ctx context.Context, since time.Time) (checked, reindexed int, err error) {
+	ri := r.repo.data.q.ReviewInfo
+	bulk := newBulkIndexer(r.repo, r.repo.esConf.GetBulk())
+	defer func() {
+		failures, flushErr := bulk.Flush(ctx)
+		r.logFailures(failures)
+		if err == nil {
+			err = flushErr
+		}
+		if err == nil && bulk.failed > 0 {
+			err = fmt.Errorf("%d reviews failed to reindex", bulk.failed)
+		}
+		reindexed = bulk.indexed
+	}()
+	var lastID int64
+	for {
+		select {
+		case <-r.stop:
+			return checked, reindexed, errReconcileStopped
+		default:
+		}
+		batch, err := ri.WithContext(ctx).
+			Where(ri.UpdateAt.Gte(since), ri.DeleteAt.IsNull(), ri.ID.Gt(lastID)).
+			Order(ri.ID).
+			Limit(r.batchSize).
+			Find()
+		if err != nil {
+			return checked, reindexed, err
+		}
+		if len(batch) == 0 {
+			return checked, reindexed, nil
+		}
+		lastID = batch[len(batch)-1].ID
+		checked += len(batch)
+
+		indexed, err := r.esUpdateTimes(ctx, batch)
+		if err != nil {
+			return checked, reindexed, err
+		}
+		for _, review := range staleReviews(batch, indexed) {
+			failures, err := bulk.Add(ctx, review)
+			if err != nil {
+				return checked, reindexed, err
+			}
+			r.logFailures(failures)
+		}
+	}
+}
+
+// logFailures 记录bulk写入失败的评论, 这些评论会在下一轮对账时重试
+func (r *Reconciler) logFailures(failures []bulkFailure) {
+	for _, f := range failures {
+		r.log.Errorf("reconcile: failed to reindex %s", f)
+	}
+}
+
+// esUpdateTimes 批量读取评论在ES中的update_at, ES中不存在的评论不在结果中
+func (r *Reconciler) esUpdateTimes(ctx context.Context, reviews []*model.ReviewInfo) (map[int64]time.Time, error) {
+	ids := make([]string, 0, len(reviews))
+	for _, review := range reviews {
+		ids = append(ids, strconv.FormatInt(review.ReviewID, 10))
+	}
+	resp, err := r.repo.data.es.Mget().Index("review").Ids(ids...).SourceIncludes_("update_at").Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	times := make(map[int64]time.Time, len(resp.Docs))
+	for _, item := range resp.Docs {
+		doc, ok := item.(*types.GetResult)
+		if !ok || !doc.Found {
+			continue
+		}
+		id, err := strconv.ParseInt(doc.Id_, 10, 64)
+		if err != nil {
+			continue
+		}
+		var src struct {
+			UpdateAt time.Time `json:"update_at"`
+		}
+		if err := json.Unmarshal(doc.Source_, &src); err != nil {
+			continue
+		}
+		times[id] = src.UpdateAt
+	}
+	return times, nil
+}
+
+// staleReviews 返回ES中缺失, 或ES文档的update_at早于MySQL的评论
+// MySQL的timestamp精度为秒, 比较前统一截断到秒
+func staleReviews(reviews []*model.ReviewInfo, indexed map[int64]time.Time) []*model.ReviewInfo {
+	var stale []*model.ReviewInfo
+	for _, review := range reviews {
+		esUpdateAt, ok := indexed[review.ReviewID]
+		if !ok || esUpdateAt.Truncate(time.Second).Before(review.UpdateAt.Truncate(time.Second)) {
+			stale = append(stale, review)
+		}
+	}
+	return stale
+}
+
+// ReindexReviews 提交后台任务, 将创建时间在 [from, to) 内的评论重新写入ES
+// 不比较update_at, 范围内的评论全部重写, 用于修改索引映射后的增量重建
+func (r *reviewRepo) ReindexReviews(ctx context.Context, from, to time.Time) error {
+	ok := r.data.async.Submit(fmt.Sprintf("reindex reviews created in [%v, %v)", from, to), func() {
+		started := time.Now()
+		reindexed, failed, err := r.reindexRange(context.Background(), from, to)
+		if err != nil {
+			r.log.Errorf("reindex: stopped after reindexing %d reviews created in [%v, %v), %d failed: %v", reindexed, from, to, failed, err)
+			return
+		}
+		r.log.Infof("reindex: reindexed %d reviews created in [%v, %v), %d failed, in %v", reindexed, from, to, failed, time.Since(started))
+	})
+	if !ok {
+		return biz.ErrReindexBusy
+	}
+	return nil
+}
+
+// reindexRange 按 (create_at, id) 做键集分页, 分批读取范围内的评论并写入ES
+// 依赖 review_info 的 idx_create_at(create_at, id) 索引, 每批都从上一批的最后一条继续, 不使用 OFFSET
+// 文档通过bulk批量写入ES
+func (r *reviewRepo) reindexRange(ctx context.Context, from, to time.Time) (reindexed, failed int, err error) {
+	bulk := newBulkIndexer(r, r.esConf.GetBulk())
+	defer func() {
+		failures, flushErr := bulk.Flush(ctx)
+		r.logReindexFailures(failures)
+		if err == nil {
+			err = flushErr
+		}
+		reindexed, failed = bulk.indexed, bulk.failed
+	}()
+	batchSize := int(r.esConf.GetReconcile().GetBatchSize())
+	if batchSize <= 0 {
+		batchSize = defaultReconcileBatchSize
+	}
+	ri := r.data.q.ReviewInfo
+	lastAt, lastID := from, int64(0)
+	for {
+		// (create_at, id) > (lastAt, lastID); 第一批 lastID 为0, 即 create_at >= from
+		batch, err := ri.WithContext(ctx).
+			Where(ri.CreateAt.Lt(to), ri.DeleteAt.IsNull()).
+			Where(ri.WithContext(ctx).Where(ri.CreateAt.Gt(lastAt)).Or(ri.CreateAt.Eq(lastAt), ri.ID.Gt(lastID))).
+			Order(ri.CreateAt, ri.ID).
+			Limit(batchSize).
+			Find()
+		if err != nil {
+			return reindexed, failed, err
+		}
+		if len(batch) == 0 {
+			return reindexed, failed, nil
+		}
+		last := batch[len(batch)-1]
+		lastAt, lastID = last.CreateAt, last.ID
+		for _, review := range batch {
+			failures, err := bulk.Add(ctx, review)
+			if err != nil {
+				return reindexed, failed, err
+			}
+			r.logReindexFailures(failures)
+		}
+	}
+}
+
+// logReindexFailures 记录bulk写入失败的评论
+func (r *reviewRepo) logReindexFailures(failures []bulkFailure) {
+	for _, f := range failures {
+		r.log.Errorf("reindex: failed to reindex %s", f)
+	}
+}
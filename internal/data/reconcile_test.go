@@ -0,0 +1,60 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"review/internal/data/model"
+)
+
+func TestStaleReviews(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	reviews := []*model.ReviewInfo{
+		{ReviewID: 1, UpdateAt: now},
+		{ReviewID: 2, UpdateAt: now},
+		{ReviewID: 3, UpdateAt: now.Add(600 * time.Millisecond)},
+		{ReviewID: 4, UpdateAt: now},
+	}
+	tests := []struct {
+		name    string
+		indexed map[int64]time.Time
+		want    []int64
+	}{
+		{
+			name:    "all up to date",
+			indexed: map[int64]time.Time{1: now, 2: now.Add(time.Second), 3: now, 4: now},
+		},
+		{
+			name:    "missing from es",
+			indexed: map[int64]time.Time{1: now, 3: now, 4: now},
+			want:    []int64{2},
+		},
+		{
+			name:    "es document is older",
+			indexed: map[int64]time.Time{1: now.Add(-time.Second), 2: now, 3: now, 4: now.Add(-time.Hour)},
+			want:    []int64{1, 4},
+		},
+		// MySQL的timestamp只精确到秒, 同一秒内的差异不算过期
+		{
+			name:    "sub-second difference is ignored",
+			indexed: map[int64]time.Time{1: now, 2: now, 3: now.Add(100 * time.Millisecond), 4: now},
+		},
+		{
+			name:    "empty es",
+			indexed: map[int64]time.Time{},
+			want:    []int64{1, 2, 3, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int64
+			for _, review := range staleReviews(reviews, tt.indexed) {
+				got = append(got, review.ReviewID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("staleReviews() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
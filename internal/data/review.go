@@ -4,64 +4,230 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"expvar"
 	"fmt"
+	"net/http"
+	"regexp"
 	"review/internal/biz"
 	"review/internal/client/ai"
+	"review/internal/conf"
 	"review/internal/data/model"
 	"review/internal/data/query"
+	"review/pkg/redact"
 	"review/pkg/snowflake"
+	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/refresh"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/versiontype"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/redis/go-redis/v9"
 	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
 )
 
+// ES索引与MySQL评论数的差异, 每次查询索引状态时更新, 通过 /debug/vars 暴露
+var esIndexDrift = expvar.NewInt("review_es_index_drift")
+
+// Redis读写失败的次数, 失败时跳过缓存直接查询ES, 通过 /debug/vars 暴露
+var cacheUnavailable = expvar.NewInt("review_cache_unavailable")
+
+// singleflight的调用次数, key为"<target>.leader"或"<target>.shared"
+// leader: 实际执行查询的调用; shared: 合并到其他调用、直接复用其结果的调用
+var singleflightCalls = expvar.NewMap("review_singleflight_calls")
+
 type reviewRepo struct {
-	data *Data
-	log  *log.Helper
-	ai   *ai.AIClient
+	data   *Data
+	log    *log.Helper
+	ai     *ai.AIClient
+	esConf *conf.Elasticsearch
+	// onAIError AI审核失败时的处理方式, 见 conf.Review.on_ai_error
+	onAIError string
+	// fastPath 受信用户快速通道, 见 conf.Review.trusted_fast_path
+	fastPath *conf.Review_TrustedFastPath
+	// modCache AI审核结论缓存, 未开启时为nil, 见 conf.Review.moderation_cache
+	modCache *moderationCache
+	// langPolicy 评论语言策略, 见 conf.Review.language_policy
+	langPolicy *conf.Review_LanguagePolicy
+	// appendFormat 审核时拼接追加评论的格式, 见 conf.Review.append_format
+	appendFormat *conf.Review_AppendFormat
+	// sf 合并同一个缓存key的并发查询, 防止缓存失效时大量请求同时打到ES
+	sf singleflight.Group
 }
 
 // NewReviewRepo 新建评论仓库
-func NewReviewRepo(data *Data, logger log.Logger, ai *ai.AIClient) biz.ReviewRepo {
+func NewReviewRepo(data *Data, logger log.Logger, ai *ai.AIClient, esConf *conf.Elasticsearch, reviewConf *conf.Review) biz.ReviewRepo {
+	helper := log.NewHelper(logger)
 	return &reviewRepo{
-		data: data,
-		log:  log.NewHelper(logger),
-		ai:   ai,
+		data:         data,
+		log:          helper,
+		ai:           ai,
+		esConf:       esConf,
+		onAIError:    reviewConf.GetOnAiError(),
+		fastPath:     reviewConf.GetTrustedFastPath(),
+		modCache:     newModerationCache(reviewConf.GetModerationCache(), data.rdb, helper),
+		langPolicy:   reviewConf.GetLanguagePolicy(),
+		appendFormat: reviewConf.GetAppendFormat(),
+	}
+}
+
+// AI审核失败时的处理方式
+const (
+	onAIErrorHold        = "hold"
+	onAIErrorApprove     = "approve"
+	onAIErrorReject      = "reject"
+	onAIErrorHumanReview = "human_review"
+)
+
+// aiUnavailableReason AI审核失败时写入审核日志的原因
+const aiUnavailableReason = "AI unavailable"
+
+// aiErrorRecordTimeout 记录AI审核失败的时长上限
+const aiErrorRecordTimeout = 5 * time.Second
+
+// handleAIError 按配置处理AI审核失败的评论, 并记录审核日志
+// hold 时返回原错误, 评论保持待审核; 其它策略更新评论后返回更新后的评论
+// 异步任务超时导致的失败也需要记录, 因此脱离调用方的截止时间, 另给 aiErrorRecordTimeout 完成写入
+func (r *reviewRepo) handleAIError(ctx context.Context, review *model.ReviewInfo, aiErr error) (*model.ReviewInfo, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), aiErrorRecordTimeout)
+	defer cancel()
+	policy, status, remarks := aiErrorDecision(r.onAIError, review.Status)
+	err := r.data.q.Transaction(func(tx *query.Query) error {
+		if policy != onAIErrorHold {
+			// 按版本号更新, 防止覆盖并发的人工审核结果
+			if err := updateReviewVersioned(ctx, tx, review, map[string]interface{}{
+				"status":     status,
+				"op_reason":  aiUnavailableReason,
+				"op_remarks": remarks,
+				"update_by":  "system",
+				"update_at":  time.Now(),
+			}); err != nil {
+				return err
+			}
+			if err := r.enqueueOutbox(ctx, tx, review.ReviewID); err != nil {
+				return err
+			}
+		}
+		return r.saveAuditLog(ctx, tx, &model.ReviewAuditLog{
+			ReviewID:   review.ReviewID,
+			FromStatus: review.Status,
+			ToStatus:   status,
+			Source:     biz.AuditSourceAI,
+			OpUser:     "system",
+			Reason:     aiUnavailableReason,
+			Remarks:    policy,
+		})
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Errorf("failed to apply on_ai_error policy %s for review ID %d: %v", policy, review.ReviewID, err)
+		return review, aiErr
+	}
+	if policy == onAIErrorHold {
+		return review, aiErr
+	}
+	return r.GetReviewByReviewID(ctx, review.ReviewID)
+}
+
+// aiErrorDecision 返回AI审核失败时生效的策略、评论的新状态及审核备注, 未配置或取值无效时为 hold, 状态不变
+func aiErrorDecision(policy string, status int32) (string, int32, string) {
+	switch policy {
+	case onAIErrorApprove:
+		return policy, 20, "AI不可用，自动通过"
+	case onAIErrorReject:
+		return policy, 30, "AI不可用，自动驳回"
+	case onAIErrorHumanReview:
+		return policy, status, "AI不可用，待人工审核"
+	default:
+		return onAIErrorHold, status, ""
+	}
+}
+
+// languageHold 语言策略为 human_review 且评论语言不在允许范围内时, 保持待审核并标记待人工审核, 返回更新后的评论
+// 语言允许、策略为 reject(创建时已拒绝)或更新失败时返回 false, 由调用方继续审核
+func (r *reviewRepo) languageHold(ctx context.Context, review *model.ReviewInfo, text string) (*model.ReviewInfo, bool) {
+	lang, ok := biz.CheckLanguage(r.langPolicy, text)
+	if ok || biz.LanguageAction(r.langPolicy) != biz.LanguageActionHumanReview {
+		return nil, false
+	}
+	remarks := "评论语言(" + lang + ")不在允许范围内，待人工审核"
+	err := r.data.q.Transaction(func(tx *query.Query) error {
+		if err := updateReviewVersioned(ctx, tx, review, map[string]interface{}{
+			"op_reason":  biz.AuditSourceLanguage,
+			"op_remarks": remarks,
+			"update_by":  "system",
+			"update_at":  time.Now(),
+		}); err != nil {
+			return err
+		}
+		if err := r.enqueueOutbox(ctx, tx, review.ReviewID); err != nil {
+			return err
+		}
+		return r.saveAuditLog(ctx, tx, &model.ReviewAuditLog{
+			ReviewID:   review.ReviewID,
+			FromStatus: review.Status,
+			ToStatus:   review.Status,
+			Source:     biz.AuditSourceLanguage,
+			OpUser:     "system",
+			Reason:     biz.AuditSourceLanguage,
+			Remarks:    remarks,
+			Language:   lang,
+		})
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Warnf("language policy for review ID %d failed, fall back to AI audit: %v", review.ReviewID, err)
+		return nil, false
 	}
+	held, err := r.GetReviewByReviewID(ctx, review.ReviewID)
+	if err != nil {
+		return nil, false
+	}
+	return held, true
 }
 
 // SaveReview 保存评论
 func (r *reviewRepo) SaveReview(ctx context.Context, review *model.ReviewInfo) (*model.ReviewInfo, error) {
 	// 1. 数据校验
-	// 同一条订单如果已存在评论，则在原内容基础上追加新评论；否则创建新评论
-	existingReviews, err := r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.OrderID.Eq(review.OrderID)).Find()
+	// 同一条订单如果已存在评论，则为原评论追加一条评论；否则创建新评论
+	existingReviews, err := r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.OrderID.Eq(review.OrderID), r.data.q.ReviewInfo.DeleteAt.IsNull()).Find()
 	if err != nil {
 		return nil, err
 	}
 
 	if len(existingReviews) > 0 {
-		// 追加评论内容到原有评论
 		existingReview := existingReviews[0]
 
-		// 追加评论内容，添加时间戳和标识
-		appendedContent := existingReview.Content + "\n\n" +
-			"[追加评论 " + time.Now().Format("2006-01-02 15:04:05") + "]:\n" +
-			review.Content
-
-		// 更新评论内容和其他可能的字段
-		_, err = r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.ReviewID.Eq(existingReview.ReviewID)).Updates(map[string]interface{}{
-			"content":       appendedContent,
-			"score":         review.Score, // 更新评分（如果需要）
-			"service_score": review.ServiceScore,
-			"express_score": review.ExpressScore,
-			"pic_info":      review.PicInfo,   // 更新图片信息（如果需要）
-			"video_info":    review.VideoInfo, // 更新视频信息（如果需要）
+		// 追加评论单独保存, 评论内容保持原文; 审核时再按 append_format 拼接成完整内容
+		// 追加后的完整内容需要重新审核, 状态重置为待审核(10), 并记录追加次数
+		// 按版本号更新, 避免覆盖读取后并发写入的追加内容或审核结果
+		err = r.data.q.Transaction(func(tx *query.Query) error {
+			if err := updateReviewVersioned(ctx, tx, existingReview, map[string]interface{}{
+				"tags":          biz.MergeTags(existingReview.Tags, review.Tags),
+				"status":        10,
+				"ext_json":      biz.WithAppendCount(existingReview.ExtJSON, biz.AppendCount(existingReview.ExtJSON)+1),
+				"score":         review.Score, // 更新评分（如果需要）
+				"service_score": review.ServiceScore,
+				"express_score": review.ExpressScore,
+				"pic_info":      review.PicInfo,   // 更新图片信息（如果需要）
+				"video_info":    review.VideoInfo, // 更新视频信息（如果需要）
+			}); err != nil {
+				return err
+			}
+			if err := tx.ReviewAppendInfo.WithContext(ctx).Create(&model.ReviewAppendInfo{
+				ReviewID: existingReview.ReviewID,
+				Content:  review.Content,
+			}); err != nil {
+				return err
+			}
+			return r.enqueueOutbox(ctx, tx, existingReview.ReviewID)
 		})
+		if errors.Is(err, biz.ErrReviewConflict) {
+			return nil, err
+		}
 		if err != nil {
 			return nil, errors.New("追加评论失败")
 		}
@@ -74,26 +240,92 @@ func (r *reviewRepo) SaveReview(ctx context.Context, review *model.ReviewInfo) (
 			return existingReview, nil // 返回追加前的数据
 		}
 		// 异步处理
-		go r.syncAndAudit(updatedReview)
+		r.submitSyncAndAudit(ctx, updatedReview)
 		return updatedReview, nil
 	} else {
 		// 创建新评论
-		err = r.data.q.ReviewInfo.WithContext(ctx).Create(review)
+		err = r.data.q.Transaction(func(tx *query.Query) error {
+			if err := tx.ReviewInfo.WithContext(ctx).Create(review); err != nil {
+				return err
+			}
+			return r.enqueueOutbox(ctx, tx, review.ReviewID)
+		})
 		if err != nil {
 			return nil, errors.New("创建评论失败")
 		}
 
 		// 异步处理
-		go r.syncAndAudit(review) // 此时的review对象包含了数据库生成的ID和时间戳
+		r.submitSyncAndAudit(ctx, review) // 此时的review对象包含了数据库生成的ID和时间戳
 		return review, nil
 	}
 }
 
+// submitSyncAndAudit 按 conf.Data.Async.priority 计算优先级, 将审核和同步任务提交到有界任务池
+// 任务池已满时任务被丢弃, 评论保持待审核状态, 需由审核员人工处理
+func (r *reviewRepo) submitSyncAndAudit(ctx context.Context, review *model.ReviewInfo) {
+	r.data.async.SubmitPriority(fmt.Sprintf("syncAndAudit review %d", review.ReviewID), r.auditPriority(ctx, review), func() {
+		r.syncAndAudit(review)
+	})
+}
+
+// auditPriority 评论审核任务的优先级, 为命中的各项规则之和
+func (r *reviewRepo) auditPriority(ctx context.Context, review *model.ReviewInfo) int {
+	rules := r.data.priority
+	priority := int(rules.GetStores()[review.StoreID])
+	if rules.GetResubmission() != 0 && biz.ResubmitCount(review.ExtJSON) > 0 {
+		priority += int(rules.GetResubmission())
+	}
+	// 信任分需要查询数据库, 只在配置了该规则时计算; 查询失败时不加分
+	if rules.GetTrustedUser() != 0 {
+		score, err := r.trustScore(ctx, review)
+		if err != nil {
+			r.log.WithContext(ctx).Warnf("trust score for review ID %d failed, ignore trusted_user priority: %v", review.ReviewID, err)
+		} else if score >= r.fastPathMinApproved() {
+			priority += int(rules.GetTrustedUser())
+		}
+	}
+	return priority
+}
+
 // syncAndAudit 封装了需要异步执行的同步和审核任务
+// 整个任务共用 conf.Data.Async.timeout 的时长上限, 避免Gemini或ES无响应时任务一直占用worker和连接
 func (r *reviewRepo) syncAndAudit(review *model.ReviewInfo) {
 	// 为后台任务创建一个新的上下文
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(context.Background(), r.data.asyncTimeout)
+	defer cancel()
+	defer r.recoverAudit(ctx, review)
+	if r.data.syncFirst {
+		r.syncThenAudit(ctx, review)
+		return
+	}
+	r.auditThenSync(ctx, review)
+}
+
+// recoverAudit 捕获审核任务中的 panic(如解析异常的模型输出), 记录堆栈和评论ID
+// 评论仍为待审核时按AI审核失败处理(on_ai_error), 记录审核日志, 不让 panic 影响其它任务
+func (r *reviewRepo) recoverAudit(ctx context.Context, review *model.ReviewInfo) {
+	p := recover()
+	if p == nil {
+		return
+	}
+	asyncPanics.Add(1)
+	r.log.WithContext(ctx).Errorf("syncAndAudit panicked for review ID %d: %v\n%s", review.ReviewID, p, debug.Stack())
+	// panic 可能发生在审核写入之后, 以数据库中的当前状态为准
+	readCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), aiErrorRecordTimeout)
+	defer cancel()
+	current, err := r.data.q.ReviewInfo.WithContext(readCtx).Where(r.data.q.ReviewInfo.ReviewID.Eq(review.ReviewID)).First()
+	if err != nil {
+		r.log.WithContext(ctx).Errorf("failed to load review ID %d after panic: %v", review.ReviewID, err)
+		return
+	}
+	if current.Status != 10 {
+		return
+	}
+	r.handleAIError(ctx, current, fmt.Errorf("audit panicked: %v", p))
+}
 
+// auditThenSync 先审核后同步, 评论在审核完成后才能被搜索到
+func (r *reviewRepo) auditThenSync(ctx context.Context, review *model.ReviewInfo) {
 	// 1. 先进行AI审核，审核过程会更新DB中的状态
 	auditedReview, auditErr := r.AuditReview(ctx, &biz.AuditReviewParam{ReviewID: review.ReviewID})
 	if auditErr != nil {
@@ -103,6 +335,11 @@ func (r *reviewRepo) syncAndAudit(review *model.ReviewInfo) {
 	} else {
 		r.log.WithContext(ctx).Infof("Async AI audit successful for review ID: %d", review.ReviewID)
 	}
+	// 已超时则不再写ES, 数据库中的评论比ES新, 由定时对账补写
+	if err := ctx.Err(); err != nil {
+		r.log.WithContext(ctx).Errorf("Async task for review ID %d abandoned before SaveToES: %v", review.ReviewID, err)
+		return
+	}
 
 	// 2. 将最终状态的评论同步到ES
 	if err := r.SaveToES(ctx, auditedReview); err != nil {
@@ -112,16 +349,178 @@ func (r *reviewRepo) syncAndAudit(review *model.ReviewInfo) {
 	}
 }
 
+// syncThenAudit 先将待审核的评论写入ES使其立即可被搜索到, 审核完成后再更新ES中的状态
+func (r *reviewRepo) syncThenAudit(ctx context.Context, review *model.ReviewInfo) {
+	// 1. 先同步待审核状态的评论
+	if err := r.SaveToES(ctx, review); err != nil {
+		r.log.WithContext(ctx).Errorf("Async SaveToES failed for review ID %d: %v", review.ReviewID, err)
+	}
+
+	// 2. AI审核, 审核失败时不再写ES, 保留第一步写入的文档
+	auditedReview, err := r.AuditReview(ctx, &biz.AuditReviewParam{ReviewID: review.ReviewID})
+	if err != nil {
+		r.log.WithContext(ctx).Errorf("Async AI audit failed for review ID %d: %v", review.ReviewID, err)
+		return
+	}
+	r.log.WithContext(ctx).Infof("Async AI audit successful for review ID: %d", review.ReviewID)
+
+	// 3. 更新ES中的审核状态
+	if err := r.SaveToES(ctx, auditedReview); err != nil {
+		r.log.WithContext(ctx).Errorf("Async SaveToES failed for review ID %d: %v", auditedReview.ReviewID, err)
+	}
+}
+
 // SaveToES 保存到ES
+// 客户端IP和User-Agent只保存在数据库中, 不写入ES
+// 按 esVersion 使用外部版本号写入; 版本冲突时确认ES中的文档版本不低于本次写入才跳过(重试与对账重复同步、乱序的旧同步), 否则返回错误
 func (r *reviewRepo) SaveToES(ctx context.Context, review *model.ReviewInfo) error {
-	_, err := r.data.es.Index("review").
+	appends, err := r.documentAppends(ctx, []*model.ReviewInfo{review})
+	if err != nil {
+		r.log.WithContext(ctx).Errorf("failed to load appends of review ID %d for ES: %v", review.ReviewID, err)
+		return err
+	}
+	doc := r.guardDocument(ctx, esDocument(review, appends[review.ReviewID]))
+	if doc == nil {
+		return nil
+	}
+	_, err = r.data.es.Index("review").
 		Id(strconv.FormatInt(review.ReviewID, 10)).
-		Request(review).
+		Request(doc).
+		Version(strconv.FormatInt(esVersion(review), 10)).
+		VersionType(versiontype.External).
+		Refresh(esRefresh(r.esConf.GetRefresh())).
 		Do(ctx)
+	if isVersionConflict(err) {
+		stored, err := r.esStoredVersions(ctx, []int64{review.ReviewID})
+		if err != nil {
+			r.log.WithContext(ctx).Errorf("failed to read ES version of review ID %d after version conflict: %v", review.ReviewID, err)
+			return err
+		}
+		if v, ok := stored[review.ReviewID]; !ok || v < review.Version {
+			r.log.WithContext(ctx).Errorf("failed to save review ID %d to ES: version %d conflicts with an older document (%d, found %v), recreate the review index and reindex",
+				review.ReviewID, review.Version, v, ok)
+			return errESVersionConflict
+		}
+		r.log.WithContext(ctx).Infof("skip SaveToES for review ID %d: ES already has version %d or newer", review.ReviewID, review.Version)
+		r.ackOutbox(ctx, review.ReviewID, review.Version)
+		return nil
+	}
 	if err != nil {
 		r.log.WithContext(ctx).Errorf("failed to save review to ES: %v", err)
+		return err
+	}
+	// ES中已是本次写入的版本, 发件箱中不再需要同步该版本及之前的写入
+	r.ackOutbox(ctx, review.ReviewID, review.Version)
+	return nil
+}
+
+// errESVersionConflict ES拒绝写入, 但其中的文档并不比本次写入新
+// 通常是按其他方式生成版本号时写入的文档, 需要重建索引
+var errESVersionConflict = errors.New("es version conflict with an older document")
+
+// esVersion 评论写入ES时使用的外部版本号, 即评论的版本号
+// 版本号在数据库中随每次更新递增, 较新的评论总是得到更大的版本号; 不使用更新时间, 应用与MySQL的时钟偏差不影响写入顺序
+func esVersion(review *model.ReviewInfo) int64 {
+	return int64(review.Version)
+}
+
+// isVersionConflict 判断ES写入失败是否因为已有相同或更高外部版本号的文档
+func isVersionConflict(err error) bool {
+	var esErr *types.ElasticsearchError
+	return errors.As(err, &esErr) && esErr.Status == http.StatusConflict
+}
+
+// esStoredVersions 批量读取ES文档中评论的版本号, 用于确认版本冲突是否因为ES中已是更新的评论; ES中不存在的评论不在结果中
+func (r *reviewRepo) esStoredVersions(ctx context.Context, reviewIDs []int64) (map[int64]int32, error) {
+	ids := make([]string, 0, len(reviewIDs))
+	for _, id := range reviewIDs {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	resp, err := r.data.es.Mget().Index(reviewIndex).Ids(ids...).SourceIncludes_("version").Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[int64]int32, len(resp.Docs))
+	for _, item := range resp.Docs {
+		doc, ok := item.(*types.GetResult)
+		if !ok || !doc.Found {
+			continue
+		}
+		id, err := strconv.ParseInt(doc.Id_, 10, 64)
+		if err != nil {
+			continue
+		}
+		var src struct {
+			Version int32 `json:"version"`
+		}
+		if err := json.Unmarshal(doc.Source_, &src); err != nil {
+			continue
+		}
+		versions[id] = src.Version
+	}
+	return versions, nil
+}
+
+// esReview 写入ES的评论文档, tags 以数组索引, 便于过滤和聚合
+type esReview struct {
+	*model.ReviewInfo
+	Tags []string `json:"tags"`
+	// AppealStatus 最近一次申诉的状态, 没有申诉时为0, 用于按申诉状态筛选
+	AppealStatus int32 `json:"appeal_status"`
+	// Appends 追加评论, 按追加顺序排列; 与 content 一起构成审核时的完整内容, 可被搜索
+	Appends []*biz.AppendView `json:"appends"`
+}
+
+// esDocument 写入ES的评论文档, appends 为评论的追加评论, 需按追加顺序排列
+// ES结果会直接用于列表接口, 不索引审核人、审核备注、客户端信息等内部字段;
+// reject_category 用于驳回类别统计的聚合, 需要保留
+func esDocument(review *model.ReviewInfo, appends []*model.ReviewAppendInfo) *esReview {
+	doc := *review
+	doc.OpUser, doc.OpRemarks = "", ""
+	doc.ClientIP, doc.UserAgent = "", ""
+	doc.CtrlJSON = ""
+	return &esReview{
+		ReviewInfo:   &doc,
+		Tags:         biz.DecodeTags(review.Tags),
+		AppealStatus: biz.AppealStatus(review.ExtJSON),
+		Appends:      biz.NewAppendViews(appends),
+	}
+}
+
+// documentAppends 批量读取写入ES的评论的追加评论, 按评论ID分组, 每组按追加顺序排列
+// 只查询有追加记录的评论, 大多数评论写入ES时不需要额外查询
+func (r *reviewRepo) documentAppends(ctx context.Context, reviews []*model.ReviewInfo) (map[int64][]*model.ReviewAppendInfo, error) {
+	var ids []int64
+	for _, review := range reviews {
+		if biz.AppendCount(review.ExtJSON) > 0 {
+			ids = append(ids, review.ReviewID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	ra := r.data.q.ReviewAppendInfo
+	rows, err := ra.WithContext(ctx).Where(ra.ReviewID.In(ids...)).Order(ra.ID).Find()
+	if err != nil {
+		return nil, err
+	}
+	appends := make(map[int64][]*model.ReviewAppendInfo, len(ids))
+	for _, a := range rows {
+		appends[a.ReviewID] = append(appends[a.ReviewID], a)
+	}
+	return appends, nil
+}
+
+// esRefresh 将配置的刷新策略转换为ES的refresh参数，未配置时使用false
+func esRefresh(policy string) refresh.Refresh {
+	switch policy {
+	case "true":
+		return refresh.True
+	case "wait_for":
+		return refresh.Waitfor
+	default:
+		return refresh.False
 	}
-	return err
 }
 
 // 自动ai审核, 异步执行
@@ -141,9 +540,13 @@ func (r *reviewRepo) AutoAuditReview(reviewToAudit *model.ReviewInfo) {
 	}
 }
 
-// GetReviewByOrderID 根据订单ID查询评论
+// GetReviewByOrderID 根据订单ID查询评论, 订单没有评论时返回空列表
 func (r *reviewRepo) GetReviewByOrderID(ctx context.Context, orderID int64) ([]*model.ReviewInfo, error) {
-	return r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.OrderID.Eq(orderID)).Find()
+	reviews, err := r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.OrderID.Eq(orderID), r.data.q.ReviewInfo.DeleteAt.IsNull()).Find()
+	if err != nil {
+		return nil, lookupError(err, biz.ErrReviewNotFound)
+	}
+	return reviews, nil
 }
 
 // SaveReply 保存回复
@@ -153,7 +556,7 @@ func (r *reviewRepo) SaveReply(ctx context.Context, reply *model.ReviewReplyInfo
 	// 1.2 水平越权校验：商家不能回复其他商家的评论
 	review, err := r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.ReviewID.Eq(reply.ReviewID)).First()
 	if err != nil {
-		return nil, fmt.Errorf("评论 (ID: %d) 不存在，无法回复", reply.ReviewID)
+		return nil, lookupError(err, biz.ErrReviewNotFound)
 	}
 
 	// 1.1 检查是否已经回复
@@ -166,32 +569,54 @@ func (r *reviewRepo) SaveReply(ctx context.Context, reply *model.ReviewReplyInfo
 		return nil, errors.New("商家不能回复其他商家的评论")
 	}
 	// 2. 更新数据库中的数据，评价表和评价回复表要同时更新，涉及到事务操作
-	r.data.q.Transaction(func(tx *query.Query) error {
-		// 更新评价表has_reply字段
-		if _, err := tx.ReviewInfo.WithContext(ctx).Where(tx.ReviewInfo.ReviewID.Eq(reply.ReviewID)).Update(
-			tx.ReviewInfo.HasReply, 1); err != nil {
+	err = r.data.q.Transaction(func(tx *query.Query) error {
+		// 更新评价表has_reply字段, 按版本号更新, 并发回复时只有一个成功
+		if err := updateReviewVersioned(ctx, tx, review, map[string]interface{}{"has_reply": 1}); err != nil {
 			return err
 		}
 		// 更新评价回复表
 		if err := tx.ReviewReplyInfo.WithContext(ctx).Save(reply); err != nil {
 			return err
 		}
-		return nil
+		return r.enqueueOutbox(ctx, tx, review.ReviewID)
 	})
-	// 3. 返回结果
+	if err != nil {
+		return nil, err
+	}
+	// 3. 同步has_reply到ES, 商家"待回复"列表依赖该字段过滤
+	review.HasReply = 1
+	review.Version++
+	if err := r.SaveToES(ctx, review); err != nil {
+		r.log.WithContext(ctx).Errorf("SaveToES after reply failed for review ID %d: %v", review.ReviewID, err)
+	}
+	// 4. 返回结果
 	return reply, nil
 }
 
 // GetReviewByReviewID 根据评论ID查询评论
+// 已删除的评论不再返回; 评论不存在时返回 biz.ErrReviewNotFound
 func (r *reviewRepo) GetReviewByReviewID(ctx context.Context, reviewID int64) (*model.ReviewInfo, error) {
-	return r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.ReviewID.Eq(reviewID)).First()
+	review, err := r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.ReviewID.Eq(reviewID), r.data.q.ReviewInfo.DeleteAt.IsNull()).First()
+	if err != nil {
+		return nil, lookupError(err, biz.ErrReviewNotFound)
+	}
+	return review, nil
+}
+
+// GetReviewsByReviewIDs 根据评论ID批量查询评论
+func (r *reviewRepo) GetReviewsByReviewIDs(ctx context.Context, reviewIDs []int64) ([]*model.ReviewInfo, error) {
+	if len(reviewIDs) == 0 {
+		return nil, nil
+	}
+	return r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.ReviewID.In(reviewIDs...)).Find()
 }
 
 // AuditReview 审核评论
 func (r *reviewRepo) AuditReview(ctx context.Context, param *biz.AuditReviewParam) (*model.ReviewInfo, error) {
 	// 1. 数据校验
-	// 评论状态校验：只有待审核状态(10)的评论才能进行审核
-	review, err := r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.ReviewID.Eq(param.ReviewID)).First()
+	// 评论状态校验：只有待审核状态(10)的评论才能进行审核, 已删除的评论不再调用AI
+	ri := r.data.q.ReviewInfo
+	review, err := ri.WithContext(ctx).Where(ri.ReviewID.Eq(param.ReviewID), ri.DeleteAt.IsNull()).First()
 	if err != nil {
 		return nil, err
 	}
@@ -199,27 +624,74 @@ func (r *reviewRepo) AuditReview(ctx context.Context, param *biz.AuditReviewPara
 		return nil, errors.New("只有待审核状态的评论才能进行审核")
 	}
 
-	// 2. 调用AI审核
-	approved, reason, err := r.ai.ModerateText(ctx, review.Content)
+	// 审核原文和追加评论拼接成的完整内容
+	text, err := r.moderationText(ctx, review)
 	if err != nil {
-		r.log.Errorf("AI审核失败: %v", err)
-		return review, err
+		return nil, err
+	}
+
+	// 语言不在允许范围内的评论不调用AI, 转人工审核
+	if held, ok := r.languageHold(ctx, review, text); ok {
+		return held, nil
+	}
+
+	// 受信用户的评论经本地检查后直接通过, 不调用AI
+	if approved, ok := r.fastPathAudit(ctx, review, text); ok {
+		return approved, nil
+	}
+
+	// 2. 调用AI审核, 开启审核结论缓存时相同(fuzzy模式下还包括与已驳回内容近似)的内容直接复用缓存的结论
+	result, cacheHit := r.modCache.Get(ctx, text)
+	if result == nil {
+		result, err = r.ai.Moderate(ctx, text)
+		if err != nil {
+			r.log.Errorf("AI审核失败: %v", err)
+			return r.handleAIError(ctx, review, err)
+		}
+		r.modCache.Set(ctx, text, result)
 	}
+	reason := result.Reason
 	var status int32
 	var remarks string
-	if !approved {
+	if !result.Approved {
 		status = 30
 		remarks = "AI审核不通过"
 	} else {
 		status = 20
 		remarks = "AI审核通过"
 	}
-	_, err = r.data.q.ReviewInfo.WithContext(ctx).Where(r.data.q.ReviewInfo.ReviewID.Eq(param.ReviewID)).Updates(map[string]interface{}{
-		"status":     status,
-		"op_reason":  reason,
-		"op_remarks": remarks,
-		"update_by":  "Gemini",
-		"update_at":  time.Now(),
+	if cacheHit != "" {
+		remarks += "（复用缓存结论: " + cacheHit + "）"
+	}
+	// 更新评论状态并记录审核日志
+	// AI审核耗时较长, 按审核前读取的版本号更新, 期间评论被追加或人工审核时放弃本次结果
+	err = r.data.q.Transaction(func(tx *query.Query) error {
+		if err := updateReviewVersioned(ctx, tx, review, map[string]interface{}{
+			"status":          status,
+			"op_reason":       reason,
+			"op_remarks":      remarks,
+			"reject_category": result.Category,
+			"update_by":       "Gemini",
+			"update_at":       time.Now(),
+		}); err != nil {
+			return err
+		}
+		if err := r.enqueueOutbox(ctx, tx, param.ReviewID); err != nil {
+			return err
+		}
+		return r.saveAuditLog(ctx, tx, &model.ReviewAuditLog{
+			ReviewID:   param.ReviewID,
+			FromStatus: review.Status,
+			ToStatus:   status,
+			Source:     biz.AuditSourceAI,
+			OpUser:     "Gemini",
+			Reason:     reason,
+			Remarks:    remarks,
+			Category:   result.Category,
+			Confidence: result.Confidence,
+			Language:   result.Language,
+			Template:   result.Template,
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -241,21 +713,247 @@ func (r *reviewRepo) AuditReview(ctx context.Context, param *biz.AuditReviewPara
 	return r.GetReviewByReviewID(ctx, param.ReviewID)
 }
 
+// ManualAuditReview 人工审核评论
+// 只允许审核待审核状态(10)的评论, 状态更新和审核日志在同一事务中完成
+func (r *reviewRepo) ManualAuditReview(ctx context.Context, param *biz.AuditReviewParam) (*model.ReviewInfo, error) {
+	err := r.data.q.Transaction(func(tx *query.Query) error {
+		// 带上状态条件更新, 防止与异步AI审核并发时重复审核; 已删除的评论不能审核
+		result, err := tx.ReviewInfo.WithContext(ctx).
+			Where(tx.ReviewInfo.ReviewID.Eq(param.ReviewID), tx.ReviewInfo.Status.Eq(10), tx.ReviewInfo.DeleteAt.IsNull()).
+			Updates(map[string]interface{}{
+				"status":     param.Status,
+				"op_user":    param.OpUser,
+				"op_reason":  param.OpReason,
+				"op_remarks": param.OpRemarks,
+				"update_by":  param.OpUser,
+				"version":    versionIncr,
+			})
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("评论不存在或不是待审核状态")
+		}
+		if err := r.enqueueOutbox(ctx, tx, param.ReviewID); err != nil {
+			return err
+		}
+		return r.saveAuditLog(ctx, tx, &model.ReviewAuditLog{
+			ReviewID:   param.ReviewID,
+			FromStatus: 10,
+			ToStatus:   param.Status,
+			Source:     biz.AuditSourceHuman,
+			OpUser:     param.OpUser,
+			Reason:     param.OpReason,
+			Remarks:    param.OpRemarks,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := r.GetReviewByReviewID(ctx, param.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	// 同步最新状态到ES, 失败只记录日志, 不影响审核结果
+	if err := r.SaveToES(ctx, review); err != nil {
+		r.log.WithContext(ctx).Errorf("SaveToES after manual audit failed for review ID %d: %v", review.ReviewID, err)
+	}
+	return review, nil
+}
+
+// DeleteReview 软删除评论, 只删除状态在statuses中的评论, 并从ES中移除
+func (r *reviewRepo) DeleteReview(ctx context.Context, reviewID int64, statuses []int32) error {
+	errNotDeletable := errors.New("评论不存在或当前状态不允许删除")
+	err := r.data.q.Transaction(func(tx *query.Query) error {
+		ri := tx.ReviewInfo
+		result, err := ri.WithContext(ctx).
+			Where(ri.ReviewID.Eq(reviewID), ri.Status.In(statuses...), ri.DeleteAt.IsNull()).
+			Updates(map[string]interface{}{"delete_at": time.Now(), "version": versionIncr})
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected == 0 {
+			return errNotDeletable
+		}
+		return r.enqueueOutbox(ctx, tx, reviewID)
+	})
+	if err != nil {
+		return err
+	}
+	// 从ES中移除, 失败只记录日志, 数据库中已删除, 由发件箱重试
+	if _, err := r.data.es.Delete("review", strconv.FormatInt(reviewID, 10)).
+		Refresh(esRefresh(r.esConf.GetRefresh())).
+		Do(ctx); err != nil {
+		r.log.WithContext(ctx).Errorf("failed to delete review %d from ES: %v", reviewID, err)
+		return nil
+	}
+	r.ackAllOutbox(ctx, reviewID)
+	return nil
+}
+
+// ResubmitReview 重新提交被驳回的评论
+// 按读取时的版本号更新, 防止并发重复提交; 修改前的内容写入审核日志备注, 保留历史
+func (r *reviewRepo) ResubmitReview(ctx context.Context, param *biz.ResubmitReviewParam) (*model.ReviewInfo, error) {
+	prev := param.Review
+	err := r.data.q.Transaction(func(tx *query.Query) error {
+		if err := updateReviewVersioned(ctx, tx, prev, map[string]interface{}{
+			"content":   param.Content,
+			"tags":      param.Tags,
+			"status":    10,
+			"ext_json":  biz.WithResubmitCount(prev.ExtJSON, biz.ResubmitCount(prev.ExtJSON)+1),
+			"update_by": param.OpUser,
+		}); err != nil {
+			return err
+		}
+		if err := r.enqueueOutbox(ctx, tx, prev.ReviewID); err != nil {
+			return err
+		}
+		return r.saveAuditLog(ctx, tx, &model.ReviewAuditLog{
+			ReviewID:   prev.ReviewID,
+			FromStatus: 30,
+			ToStatus:   10,
+			Source:     biz.AuditSourceAuthor,
+			OpUser:     param.OpUser,
+			Reason:     prev.OpReason,
+			Remarks:    prev.Content,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := r.GetReviewByReviewID(ctx, prev.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	// 异步处理
+	r.submitSyncAndAudit(ctx, review)
+	return review, nil
+}
+
+// UpdateReviewScore 按读取时的版本号更新评论的评分, 同步到ES并清理所在店铺的列表缓存
+func (r *reviewRepo) UpdateReviewScore(ctx context.Context, prev *model.ReviewInfo, score, serviceScore, expressScore int32) (*model.ReviewInfo, error) {
+	err := r.data.q.Transaction(func(tx *query.Query) error {
+		if err := updateReviewVersioned(ctx, tx, prev, map[string]interface{}{
+			"score":         score,
+			"service_score": serviceScore,
+			"express_score": expressScore,
+		}); err != nil {
+			return err
+		}
+		return r.enqueueOutbox(ctx, tx, prev.ReviewID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	reviewID := prev.ReviewID
+	review, err := r.GetReviewByReviewID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	// 同步ES和清理缓存失败只记录日志, 数据库中已更新, 缓存最多60秒后过期
+	if err := r.SaveToES(ctx, review); err != nil {
+		r.log.WithContext(ctx).Errorf("SaveToES after score update failed for review ID %d: %v", reviewID, err)
+	}
+	r.invalidateStoreCache(ctx, review.StoreID)
+	return review, nil
+}
+
+// invalidateStoreCache 删除店铺评论列表(包括含该店铺的多店铺列表)和平均评分的缓存, 使页面上的评分立即刷新
+func (r *reviewRepo) invalidateStoreCache(ctx context.Context, storeID int64) {
+	id := strconv.FormatInt(storeID, 10)
+	var keys []string
+	for _, pattern := range []string{listCacheKeyPrefix("store", id) + "*", listCacheKeyPrefix("stores", "") + "*"} {
+		iter := r.data.rdb.Scan(ctx, 0, pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			if key := iter.Val(); listCacheKeyHasStore(key, id) {
+				keys = append(keys, key)
+			}
+		}
+		if err := iter.Err(); err != nil {
+			cacheUnavailable.Add(1)
+			r.log.WithContext(ctx).Warnf("invalidateStoreCache scan failed, storeID: %d, err: %v", storeID, err)
+			return
+		}
+	}
+	keys = append(keys, fmt.Sprintf("store_rating:%d", storeID))
+	if err := r.data.rdb.Del(ctx, keys...).Err(); err != nil {
+		cacheUnavailable.Add(1)
+		r.log.WithContext(ctx).Warnf("invalidateStoreCache delete failed, storeID: %d, err: %v", storeID, err)
+	}
+}
+
+// ListAppends 按追加顺序返回评论的追加评论
+func (r *reviewRepo) ListAppends(ctx context.Context, reviewID int64) ([]*model.ReviewAppendInfo, error) {
+	ra := r.data.q.ReviewAppendInfo
+	return ra.WithContext(ctx).Where(ra.ReviewID.Eq(reviewID)).Order(ra.ID).Find()
+}
+
+// moderationText 评论原文和追加评论按 append_format 拼接成的完整内容
+func (r *reviewRepo) moderationText(ctx context.Context, review *model.ReviewInfo) (string, error) {
+	appends, err := r.ListAppends(ctx, review.ReviewID)
+	if err != nil {
+		return "", err
+	}
+	return biz.RenderContent(r.appendFormat, review.Content, appends), nil
+}
+
+// ListAuditLogs 按写入顺序返回评论的全部审核日志
+func (r *reviewRepo) ListAuditLogs(ctx context.Context, reviewID int64) ([]*model.ReviewAuditLog, error) {
+	al := r.data.q.ReviewAuditLog
+	return al.WithContext(ctx).Where(al.ReviewID.Eq(reviewID)).Order(al.ID).Find()
+}
+
+// ScanAuditLogs 按ID做键集分页, 返回创建时间在 [from, to) 内、ID大于 afterID 的至多 limit 条审核日志
+func (r *reviewRepo) ScanAuditLogs(ctx context.Context, from, to time.Time, afterID int64, limit int) ([]*model.ReviewAuditLog, error) {
+	al := r.data.q.ReviewAuditLog
+	return al.WithContext(ctx).
+		Where(al.ID.Gt(afterID), al.CreateAt.Gte(from), al.CreateAt.Lt(to)).
+		Order(al.ID).
+		Limit(limit).
+		Find()
+}
+
+// saveAuditLog 在事务中写入一条审核日志
+func (r *reviewRepo) saveAuditLog(ctx context.Context, tx *query.Query, entry *model.ReviewAuditLog) error {
+	return tx.ReviewAuditLog.WithContext(ctx).Create(entry)
+}
+
+// versionIncr 每次更新评论时递增版本号, 使基于旧版本的并发更新失败
+var versionIncr = gorm.Expr("version + 1")
+
+// updateReviewVersioned 乐观锁更新评论: 只有版本号仍等于读取时的 review.Version 才更新, 同时递增版本号
+// 评论在读取后已被其他操作修改时返回 biz.ErrReviewConflict
+func updateReviewVersioned(ctx context.Context, tx *query.Query, review *model.ReviewInfo, values map[string]interface{}) error {
+	values["version"] = versionIncr
+	result, err := tx.ReviewInfo.WithContext(ctx).
+		Where(tx.ReviewInfo.ReviewID.Eq(review.ReviewID), tx.ReviewInfo.Version.Eq(review.Version)).
+		Updates(values)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return biz.ErrReviewConflict
+	}
+	return nil
+}
+
 // AppealReview 申诉评论
 func (r *reviewRepo) AppealReview(ctx context.Context, param *biz.AppealReviewParam) (*model.ReviewAppealInfo, error) {
 	// 1. 数据校验
 	// 1.1 评论存在性校验
 	review, err := r.GetReviewByReviewID(ctx, param.ReviewID)
 	if err != nil {
-		return nil, fmt.Errorf("评论 (ID: %d) 不存在，无法申诉", param.ReviewID)
+		return nil, err
 	}
 	// 1.2 权限校验：商家只能申诉自己店铺的评论
 	if review.StoreID != param.StoreID {
 		return nil, errors.New("商家不能申诉其他商家的评论")
 	}
 
-	// 1.3 申诉状态校验：只有待审核(10)状态的申诉可以更新申诉，其他状态不允许重复申诉
-	existingAppeals, err := r.data.q.ReviewAppealInfo.WithContext(ctx).Where(r.data.q.ReviewAppealInfo.ReviewID.Eq(param.ReviewID)).Find()
+	// 1.3 申诉状态校验：只有待审核(10)状态的申诉可以更新申诉，其他状态不允许重复申诉；隐藏申诉和恢复申诉分别校验
+	existingAppeals, err := r.data.q.ReviewAppealInfo.WithContext(ctx).Where(r.data.q.ReviewAppealInfo.ReviewID.Eq(param.ReviewID), r.data.q.ReviewAppealInfo.AppealType.Eq(param.Type)).Find()
 	if err != nil {
 		return nil, errors.New("查询申诉记录失败")
 	}
@@ -274,66 +972,177 @@ func (r *reviewRepo) AppealReview(ctx context.Context, param *biz.AppealReviewPa
 		appealID = snowflake.GenID()
 	}
 	appeal := &model.ReviewAppealInfo{
-		AppealID:  appealID,
-		ReviewID:  param.ReviewID,
-		StoreID:   param.StoreID,
-		Status:    10, // 待审核状态
-		Reason:    param.Reason,
-		Content:   param.Content,
-		PicInfo:   param.PicInfo,
-		VideoInfo: param.VideoInfo,
+		AppealID:   appealID,
+		ReviewID:   param.ReviewID,
+		StoreID:    param.StoreID,
+		Status:     10, // 待审核状态
+		AppealType: param.Type,
+		Reason:     param.Reason,
+		Content:    param.Content,
+		PicInfo:    param.PicInfo,
+		VideoInfo:  param.VideoInfo,
 	}
 
 	// 3. 保存申诉记录
-	// 如果已存在待审核状态的申诉记录，则更新；否则创建新记录
-	if len(existingAppeals) > 0 {
-		// 更新现有待审核状态的申诉记录
-		_, err = r.data.q.ReviewAppealInfo.WithContext(ctx).Where(r.data.q.ReviewAppealInfo.AppealID.Eq(existingAppeals[0].AppealID)).Updates(map[string]interface{}{
-			"reason":     appeal.Reason,
-			"content":    appeal.Content,
-			"pic_info":   appeal.PicInfo,
-			"video_info": appeal.VideoInfo,
-		})
-		if err != nil {
-			return nil, errors.New("更新申诉记录失败")
-		}
-		// 返回更新后的申诉记录
-		appeal.AppealID = existingAppeals[0].AppealID
-	} else {
-		// 创建新的申诉记录
-		err = r.data.q.ReviewAppealInfo.WithContext(ctx).Create(appeal)
-		if err != nil {
-			return nil, errors.New("创建申诉记录失败")
+	// 如果已存在待审核状态的申诉记录，则更新；否则创建新记录; 同时在评论上记录申诉状态, 供按申诉状态筛选评论
+	errSaveAppeal := errors.New("更新申诉记录失败")
+	if len(existingAppeals) == 0 {
+		errSaveAppeal = errors.New("创建申诉记录失败")
+	}
+	err = r.data.q.Transaction(func(tx *query.Query) error {
+		if len(existingAppeals) > 0 {
+			// 更新现有待审核状态的申诉记录
+			_, err := tx.ReviewAppealInfo.WithContext(ctx).Where(tx.ReviewAppealInfo.AppealID.Eq(existingAppeals[0].AppealID)).Updates(map[string]interface{}{
+				"reason":     appeal.Reason,
+				"content":    appeal.Content,
+				"pic_info":   appeal.PicInfo,
+				"video_info": appeal.VideoInfo,
+			})
+			if err != nil {
+				return errSaveAppeal
+			}
+		} else if err := tx.ReviewAppealInfo.WithContext(ctx).Create(appeal); err != nil {
+			// 创建新的申诉记录
+			return errSaveAppeal
 		}
+		return r.setAppealStatus(ctx, tx, param.ReviewID, 10, nil)
+	})
+	if errors.Is(err, errSaveAppeal) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.New("更新评论申诉状态失败")
 	}
+	r.syncAppealStatus(ctx, param.ReviewID)
 
 	// 4. 返回申诉信息
 	return appeal, nil
 }
 
-// AuditAppeal 审核申诉
-func (r *reviewRepo) AuditAppeal(ctx context.Context, param *biz.AuditAppealParam) (*model.ReviewAppealInfo, error) {
-	// 1. 数据校验
-	// 1.1 申诉记录存在性校验
-	appeal, err := r.data.q.ReviewAppealInfo.WithContext(ctx).Where(r.data.q.ReviewAppealInfo.AppealID.Eq(param.AppealID)).First()
+// setAppealStatus 在申诉的事务中将评论最近一次申诉的状态写入评论的ext_json, 写入ES后可按申诉状态筛选
+// values 为同时更新的其它评论字段, 可以为nil
+func (r *reviewRepo) setAppealStatus(ctx context.Context, tx *query.Query, reviewID int64, status int32, values map[string]interface{}) error {
+	review, err := tx.ReviewInfo.WithContext(ctx).Where(tx.ReviewInfo.ReviewID.Eq(reviewID)).First()
 	if err != nil {
-		return nil, errors.New("无法获取申诉记录")
+		return err
 	}
-	// 1.2 申诉状态校验：只有待审核状态(10)的申诉才能进行审核
-	if appeal.Status != 10 {
-		return nil, errors.New("只有待审核状态的申诉才能进行审核")
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	values["ext_json"] = biz.WithAppealStatus(review.ExtJSON, status)
+	if err := updateReviewVersioned(ctx, tx, review, values); err != nil {
+		return err
+	}
+	return r.enqueueOutbox(ctx, tx, reviewID)
+}
+
+// syncAppealStatus 申诉状态变化后立即同步ES并清理店铺列表缓存
+// 失败只记录日志, 由发件箱重试同步
+func (r *reviewRepo) syncAppealStatus(ctx context.Context, reviewID int64) {
+	review, err := r.GetReviewByReviewID(ctx, reviewID)
+	if err != nil {
+		r.log.WithContext(ctx).Errorf("failed to load review ID %d after appeal change: %v", reviewID, err)
+		return
+	}
+	if err := r.SaveToES(ctx, review); err != nil {
+		r.log.WithContext(ctx).Errorf("SaveToES after appeal change failed for review ID %d: %v", reviewID, err)
+	}
+	r.invalidateStoreCache(ctx, review.StoreID)
+}
+
+// GetAppealByReviewID 查询评论最近一次的申诉, 不存在时返回nil
+func (r *reviewRepo) GetAppealByReviewID(ctx context.Context, reviewID int64) (*model.ReviewAppealInfo, error) {
+	appeals, err := r.data.q.ReviewAppealInfo.WithContext(ctx).
+		Where(r.data.q.ReviewAppealInfo.ReviewID.Eq(reviewID)).
+		Order(r.data.q.ReviewAppealInfo.ID.Desc()).
+		Limit(1).
+		Find()
+	if err != nil {
+		return nil, lookupError(err, biz.ErrAppealNotFound)
+	}
+	if len(appeals) == 0 {
+		return nil, nil
+	}
+	return appeals[0], nil
+}
+
+// ListAppealsByReviewID 按提交顺序返回评论的全部申诉
+func (r *reviewRepo) ListAppealsByReviewID(ctx context.Context, reviewID int64) ([]*model.ReviewAppealInfo, error) {
+	ap := r.data.q.ReviewAppealInfo
+	appeals, err := ap.WithContext(ctx).Where(ap.ReviewID.Eq(reviewID), ap.DeleteAt.IsNull()).Order(ap.ID).Find()
+	if err != nil {
+		return nil, lookupError(err, biz.ErrAppealNotFound)
+	}
+	return appeals, nil
+}
+
+// GetReplyByReviewID 查询评论的商家回复, 没有回复时返回 nil
+func (r *reviewRepo) GetReplyByReviewID(ctx context.Context, reviewID int64) (*model.ReviewReplyInfo, error) {
+	rp := r.data.q.ReviewReplyInfo
+	replies, err := rp.WithContext(ctx).Where(rp.ReviewID.Eq(reviewID), rp.DeleteAt.IsNull()).Order(rp.ID.Desc()).Limit(1).Find()
+	if err != nil {
+		return nil, lookupError(err, biz.ErrReviewNotFound)
+	}
+	if len(replies) == 0 {
+		return nil, nil
+	}
+	return replies[0], nil
+}
+
+// RecommendAppeal 提交异步任务, 请求AI给出申诉建议并写入申诉的ext_json
+// 建议只在申诉仍为待审核(10)时保存, 失败时仅记录日志, 不影响申诉流程
+func (r *reviewRepo) RecommendAppeal(_ context.Context, appeal *model.ReviewAppealInfo, review *model.ReviewInfo) {
+	r.data.async.Submit(fmt.Sprintf("recommendAppeal %d", appeal.AppealID), func() {
+		ctx := context.Background()
+		text, err := r.moderationText(ctx, review)
+		if err != nil {
+			r.log.WithContext(ctx).Errorf("failed to load appends for appeal ID %d: %v", appeal.AppealID, err)
+			return
+		}
+		rec, err := r.ai.RecommendAppeal(ctx, text, review.OpReason, appeal.Reason, appeal.Content)
+		if err != nil {
+			r.log.WithContext(ctx).Errorf("AI appeal recommendation failed for appeal ID %d: %v", appeal.AppealID, err)
+			return
+		}
+		q := r.data.q.ReviewAppealInfo
+		current, err := q.WithContext(ctx).Where(q.AppealID.Eq(appeal.AppealID)).First()
+		if err != nil {
+			r.log.WithContext(ctx).Errorf("failed to load appeal ID %d for AI recommendation: %v", appeal.AppealID, err)
+			return
+		}
+		ext := biz.WithAppealRecommendation(current.ExtJSON, &biz.AppealRecommendation{Decision: rec.Decision, Rationale: rec.Rationale})
+		if _, err := q.WithContext(ctx).Where(q.AppealID.Eq(appeal.AppealID), q.Status.Eq(10)).Update(q.ExtJSON, ext); err != nil {
+			r.log.WithContext(ctx).Errorf("failed to save AI recommendation for appeal ID %d: %v", appeal.AppealID, err)
+		}
+	})
+}
+
+// AuditAppeal 审核申诉
+func (r *reviewRepo) AuditAppeal(ctx context.Context, param *biz.AuditAppealParam) (*model.ReviewAppealInfo, error) {
+	// 1. 数据校验
+	// 1.1 申诉记录存在性校验
+	appeal, err := r.data.q.ReviewAppealInfo.WithContext(ctx).Where(r.data.q.ReviewAppealInfo.AppealID.Eq(param.AppealID)).First()
+	if err != nil {
+		return nil, lookupError(err, biz.ErrAppealNotFound)
+	}
+	// 1.2 申诉状态校验：只有待审核状态(10)的申诉才能进行审核
+	if appeal.Status != 10 {
+		return nil, errors.New("只有待审核状态的申诉才能进行审核")
 	}
 
 	// 2. 更新申诉记录和评论状态
-	// 2.1 根据申诉审核结果确定申诉状态和评论状态
+	// 2.1 根据申诉类型和审核结果确定申诉状态和评论状态
 	var appeal_status, review_status int32
 	switch param.Status {
 	case 20: // 申诉通过
 		appeal_status = 20 // 申诉通过状态
 		review_status = 40 // 评论隐藏状态
+		if appeal.AppealType == biz.AppealTypeReinstate {
+			review_status = 20 // 恢复申诉通过, 评论恢复为已通过状态
+		}
 	case 30: // 申诉驳回
 		appeal_status = 30 // 申诉驳回状态
-		review_status = 30 // 评论拒绝状态
+		review_status = 30 // 评论拒绝状态, 恢复申诉驳回时评论保持驳回
 	default:
 		return nil, errors.New("无效的申诉审核状态")
 	}
@@ -351,19 +1160,39 @@ func (r *reviewRepo) AuditAppeal(ctx context.Context, param *biz.AuditAppealPara
 			return err
 		}
 
-		// 更新评论状态
-		_, err = tx.ReviewInfo.WithContext(ctx).Where(tx.ReviewInfo.ReviewID.Eq(appeal.ReviewID)).Updates(map[string]interface{}{
+		// 更新评论状态, 评论状态须仍与申诉类型一致
+		review, err := tx.ReviewInfo.WithContext(ctx).Where(tx.ReviewInfo.ReviewID.Eq(appeal.ReviewID)).First()
+		if err != nil {
+			return err
+		}
+		if review.Status != biz.AppealReviewStatus(appeal.AppealType) {
+			return biz.ErrAppealStatusChanged
+		}
+		// 评论状态和申诉状态一起更新
+		if err := r.setAppealStatus(ctx, tx, appeal.ReviewID, appeal_status, map[string]interface{}{
 			"status":    review_status,
 			"update_by": param.OpUser,
-		})
-		if err != nil {
+		}); err != nil {
 			return err
 		}
-		return nil
+		// 申诉处理结果同样记录审核日志, 用于审核轨迹和审核员工作量统计
+		return r.saveAuditLog(ctx, tx, &model.ReviewAuditLog{
+			ReviewID:   appeal.ReviewID,
+			FromStatus: review.Status,
+			ToStatus:   review_status,
+			Source:     biz.AuditSourceAppeal,
+			OpUser:     param.OpUser,
+			Reason:     param.OpReason,
+			Remarks:    param.OpRemarks,
+		})
 	})
+	if errors.Is(err, biz.ErrAppealStatusChanged) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, errors.New("更新申诉记录和评论状态失败")
 	}
+	r.syncAppealStatus(ctx, appeal.ReviewID)
 
 	// 3. 查询并返回更新后的申诉信息
 	updatedAppeal, err := r.data.q.ReviewAppealInfo.WithContext(ctx).Where(r.data.q.ReviewAppealInfo.AppealID.Eq(param.AppealID)).First()
@@ -396,111 +1225,343 @@ func (r *reviewRepo) ReplyReview(ctx context.Context, param *biz.ReplyReviewPara
 }
 
 // ListReviewByStoreID 根据商家ID获取评论列表（分页）
-func (r *reviewRepo) ListReviewByStoreID(ctx context.Context, storeID int64, offset int32, limit int32) ([]*biz.MyReviewInfo, error) {
-	return r.ListReviewByStoreID1(ctx, storeID, offset, limit)
+// onlyUnreplied 为 true 时只返回商家未回复的评论
+func (r *reviewRepo) ListReviewByStoreID(ctx context.Context, storeID int64, offset int32, limit int32, onlyUnreplied bool, tag string, v biz.ReviewVisibility) (*biz.ReviewList, error) {
+	return r.ListReviewByStoreID1(ctx, storeID, offset, limit, onlyUnreplied, tag, v)
+}
+
+// CountUnrepliedByStoreID 统计商家已发布但未回复的评论数, 口径见 unrepliedStatus
+func (r *reviewRepo) CountUnrepliedByStoreID(ctx context.Context, storeID int64) (int64, error) {
+	ri := r.data.q.ReviewInfo
+	return ri.WithContext(ctx).Where(ri.StoreID.Eq(storeID), ri.Status.Eq(unrepliedStatus), ri.HasReply.Eq(0), ri.DeleteAt.IsNull()).Count()
+}
+
+// GetIndexStats 查询review索引的文档数、大小、健康状态, 并与MySQL中未删除的评论数对比
+// ES近实时刷新, 刚写入的评论可能短暂造成少量差异; 差异持续增长说明SaveToES失败在累积
+func (r *reviewRepo) GetIndexStats(ctx context.Context) (*biz.IndexStats, error) {
+	const index = "review"
+	stats, err := r.data.es.Indices.Stats().Index(index).Do(ctx)
+	if err != nil {
+		r.log.WithContext(ctx).Errorf("failed to get ES index stats: %v", err)
+		return nil, err
+	}
+	health, err := r.data.es.Cluster.Health().Index(index).Do(ctx)
+	if err != nil {
+		r.log.WithContext(ctx).Errorf("failed to get ES index health: %v", err)
+		return nil, err
+	}
+	ri := r.data.q.ReviewInfo
+	dbCount, err := ri.WithContext(ctx).Where(ri.DeleteAt.IsNull()).Count()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &biz.IndexStats{Index: index, Health: health.Status.String(), DBCount: dbCount}
+	if is, ok := stats.Indices[index]; ok && is.Primaries != nil {
+		if is.Primaries.Docs != nil {
+			res.DocCount = is.Primaries.Docs.Count
+		}
+		if is.Primaries.Store != nil {
+			res.SizeBytes = is.Primaries.Store.SizeInBytes
+		}
+	}
+	res.Drift = res.DBCount - res.DocCount
+	esIndexDrift.Set(res.Drift)
+	return res, nil
 }
 
-func (r *reviewRepo) ListReviewByUserID(ctx context.Context, userID int64, offset int32, limit int32) ([]*biz.MyReviewInfo, error) {
-	return r.ListReviewByUserID1(ctx, userID, offset, limit)
+func (r *reviewRepo) ListReviewByUserID(ctx context.Context, userID int64, offset int32, limit int32, v biz.ReviewVisibility) (*biz.ReviewList, error) {
+	return r.ListReviewByUserID1(ctx, userID, offset, limit, v)
 }
 
-func (r *reviewRepo) ListReviewsByStatus(ctx context.Context, status int32, offset int32, limit int32) ([]*biz.MyReviewInfo, error) {
+func (r *reviewRepo) ListReviewsByStatus(ctx context.Context, status int32, appealStatus int32, offset int32, limit int32) (*biz.ReviewList, error) {
 	// For simplicity, we create a new function for ES query by status, bypassing the generic cache layer for now.
 	// A more robust implementation might involve a more flexible caching key.
-	return r.listReviewsByStatusFromES(ctx, status, offset, limit)
+	return r.listReviewsByStatusFromES(ctx, status, appealStatus, offset, limit)
 }
 
 // ListAppealsByStatus lists appeal records by status with pagination.
-func (r *reviewRepo) ListAppealsByStatus(ctx context.Context, status int32, offset int32, limit int32) ([]*model.ReviewAppealInfo, error) {
+func (r *reviewRepo) ListAppealsByStatus(ctx context.Context, status int32, offset int32, limit int32) ([]*model.ReviewAppealInfo, int64, error) {
 	// Directly query DB for now. If needed, we can add ES indexing later for appeals.
 	appeals, err := r.data.q.ReviewAppealInfo.WithContext(ctx).
 		Where(r.data.q.ReviewAppealInfo.Status.Eq(status)).
+		Order(r.data.q.ReviewAppealInfo.ID).
 		Offset(int(offset)).
 		Limit(int(limit)).
 		Find()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return appeals, nil
+	total, err := r.data.q.ReviewAppealInfo.WithContext(ctx).
+		Where(r.data.q.ReviewAppealInfo.Status.Eq(status)).
+		Count()
+	if err != nil {
+		return nil, 0, err
+	}
+	return appeals, total, nil
 }
 
-var g = singleflight.Group{}
-
 // 升级版带缓存的查询函数, 根据商家ID获取评论列表（分页）
-func (r *reviewRepo) ListReviewByStoreID1(ctx context.Context, storeID int64, offset int32, limit int32) ([]*biz.MyReviewInfo, error) {
+func (r *reviewRepo) ListReviewByStoreID1(ctx context.Context, storeID int64, offset int32, limit int32, onlyUnreplied bool, tag string, v biz.ReviewVisibility) (*biz.ReviewList, error) {
 	// 1. 从redis中获取数据
 	// 2. 如果redis中没有数据，则从ES中获取数据
 	// 3. 通过singleflight.Group合并并发请求
-	key := fmt.Sprintf("review:%d:%d:%d", storeID, offset, limit)
+	key := listCacheKey("store", strconv.FormatInt(storeID, 10), offset, limit) + ":" + visibilityKey(v)
+	if onlyUnreplied {
+		key += ":" + esFilterUnreplied
+	}
+	if tag != "" {
+		key += ":" + tagPrefix + tag
+	}
 	b, err := r.GetDataBySingleFlight(ctx, key, "store")
 	if err != nil {
 		return nil, err
 	}
-	hm := new(types.HitsMetadata)
-	if err := json.Unmarshal(b, hm); err != nil {
+	// 4. 反序列化
+	return r.parseReviewHits(b)
+}
+
+// ListReviewsByStoreIDs 查询多个店铺的评论列表（分页）
+// 以排序后的店铺ID集合作为缓存key, 同一组店铺共用缓存; 清理其中任一店铺的缓存时一并删除
+func (r *reviewRepo) ListReviewsByStoreIDs(ctx context.Context, storeIDs []int64, offset int32, limit int32, v biz.ReviewVisibility) (*biz.ReviewList, error) {
+	ids := make([]string, 0, len(storeIDs))
+	for _, id := range storeIDs {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	key := listCacheKey("stores", strings.Join(ids, ","), offset, limit) + ":" + visibilityKey(v)
+	b, err := r.GetDataBySingleFlight(ctx, key, "stores")
+	if err != nil {
 		return nil, err
 	}
-	// 4. 反序列化
-	list := make([]*biz.MyReviewInfo, 0, hm.Total.Value)
-	for _, hit := range hm.Hits {
-		tmp := &biz.MyReviewInfo{}
-		if err := json.Unmarshal(hit.Source_, tmp); err != nil {
-			r.log.Errorf("es search result unmarshal error: %v", err)
-			continue
-		}
-		list = append(list, tmp)
+	return r.parseReviewHits(b)
+}
+
+// ListStoreIDsByUserID 返回商家用户名下的全部店铺ID
+func (r *reviewRepo) ListStoreIDsByUserID(ctx context.Context, userID int64) ([]int64, error) {
+	var storeIDs []int64
+	err := r.data.q.Store.WithContext(ctx).Where(r.data.q.Store.UserID.Eq(userID)).Pluck(r.data.q.Store.StoreID, &storeIDs)
+	return storeIDs, err
+}
+
+// ListRecentReviews 查询全平台已通过的评论（分页）, 按创建时间倒序, 结果缓存60秒
+func (r *reviewRepo) ListRecentReviews(ctx context.Context, offset int32, limit int32) (*biz.ReviewList, error) {
+	key := listCacheKey("recent", "all", offset, limit)
+	b, err := r.GetDataBySingleFlight(ctx, key, "recent")
+	if err != nil {
+		return nil, err
 	}
-	return list, nil
+	return r.parseReviewHits(b)
+}
+
+// GetStoreNames 查询店铺名称
+func (r *reviewRepo) GetStoreNames(ctx context.Context, storeIDs []int64) (map[int64]string, error) {
+	names := make(map[int64]string, len(storeIDs))
+	if len(storeIDs) == 0 {
+		return names, nil
+	}
+	s := r.data.q.Store
+	stores, err := s.WithContext(ctx).Select(s.StoreID, s.Name).Where(s.StoreID.In(storeIDs...)).Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, store := range stores {
+		names[store.StoreID] = store.Name
+	}
+	return names, nil
 }
 
 // 升级版带缓存的查询函数, 根据用户ID获取评论列表（分页）
-func (r *reviewRepo) ListReviewByUserID1(ctx context.Context, userID int64, offset int32, limit int32) ([]*biz.MyReviewInfo, error) {
+func (r *reviewRepo) ListReviewByUserID1(ctx context.Context, userID int64, offset int32, limit int32, v biz.ReviewVisibility) (*biz.ReviewList, error) {
 	// 1. 从redis中获取数据
 	// 2. 如果redis中没有数据，则从ES中获取数据
 	// 3. 通过singleflight.Group合并并发请求
-	key := fmt.Sprintf("review:%d:%d:%d", userID, offset, limit)
+	key := listCacheKey("user", strconv.FormatInt(userID, 10), offset, limit) + ":" + visibilityKey(v)
 	b, err := r.GetDataBySingleFlight(ctx, key, "user")
 	if err != nil {
 		return nil, err
 	}
-	hm := new(types.HitsMetadata)
-	if err := json.Unmarshal(b, hm); err != nil {
+	// 4. 反序列化
+	return r.parseReviewHits(b)
+}
+
+// defaultMaxResultWindow ES index.max_result_window 的默认值
+const defaultMaxResultWindow = 10000
+
+// maxResultWindow 返回允许的最大 offset+size, 未配置时使用ES的默认值
+func (r *reviewRepo) maxResultWindow() int {
+	if w := int(r.esConf.GetMaxResultWindow()); w > 0 {
+		return w
+	}
+	return defaultMaxResultWindow
+}
+
+// esSearchResult 缓存的ES查询结果, Partial 标记是否有分片超时或失败
+type esSearchResult struct {
+	Hits    types.HitsMetadata `json:"hits"`
+	Partial bool               `json:"partial"`
+}
+
+// esFilterUnreplied 缓存key中"只看未回复"过滤条件的标记
+const esFilterUnreplied = "unreplied"
+
+// unrepliedStatus 未回复评论只统计和列出已通过(20)的评论, 待审核的评论即使可见也不能回复
+// CountUnrepliedByStoreID 与"只看未回复"列表使用同一口径, 保证角标数与列表一致
+const unrepliedStatus = 20
+
+// visibilityPrefix 缓存key中可见性规则段的前缀
+const visibilityPrefix = "v="
+
+// tagPrefix 缓存key中话题标签过滤条件段的前缀
+const tagPrefix = "tag="
+
+// appealPrefix 缓存key中申诉状态过滤条件段的前缀
+const appealPrefix = "appeal="
+
+// visibilityKey 将可见性规则编码为缓存key的一段, ES查询条件由key还原
+func visibilityKey(v biz.ReviewVisibility) string {
+	switch {
+	case v.All:
+		return visibilityPrefix + "all"
+	case v.PendingPublic:
+		return visibilityPrefix + "pub"
+	case v.PendingAuthorID > 0:
+		return visibilityPrefix + "a" + strconv.FormatInt(v.PendingAuthorID, 10)
+	default:
+		return visibilityPrefix + "approved"
+	}
+}
+
+// visibilityQuery 由缓存key中的可见性段构造状态过滤条件, 不需要过滤时返回nil
+// 已通过(20)总是可见, 按规则额外放开待审核(10)的评论
+func visibilityQuery(seg string) *types.Query {
+	approved := types.Query{Term: map[string]types.TermQuery{"status": {Value: 20}}}
+	pending := types.Query{Term: map[string]types.TermQuery{"status": {Value: 10}}}
+	val := strings.TrimPrefix(seg, visibilityPrefix)
+	switch {
+	case val == "all":
+		return nil
+	case val == "pub":
+		return &types.Query{Bool: &types.BoolQuery{Should: []types.Query{approved, pending}, MinimumShouldMatch: 1}}
+	case strings.HasPrefix(val, "a"):
+		authorID, err := strconv.ParseInt(strings.TrimPrefix(val, "a"), 10, 64)
+		if err != nil {
+			return &approved
+		}
+		ownPending := types.Query{Bool: &types.BoolQuery{Filter: []types.Query{
+			pending,
+			{Term: map[string]types.TermQuery{"user_id": {Value: authorID}}},
+		}}}
+		return &types.Query{Bool: &types.BoolQuery{Should: []types.Query{approved, ownPending}, MinimumShouldMatch: 1}}
+	default:
+		return &approved
+	}
+}
+
+// esClientTimeoutMargin 客户端超时在ES服务端超时基础上多等待的时间
+const esClientTimeoutMargin = time.Second
+
+// segmentFilters 把缓存key中的可见性规则和过滤条件段转换为ES过滤条件, 无法识别的段忽略
+func segmentFilters(segs []string) []types.Query {
+	var filters []types.Query
+	for _, seg := range segs {
+		switch {
+		case seg == esFilterUnreplied:
+			filters = append(filters, types.Query{
+				Term: map[string]types.TermQuery{
+					"has_reply": {Value: 0},
+				},
+			}, types.Query{
+				Term: map[string]types.TermQuery{
+					"status": {Value: unrepliedStatus},
+				},
+			})
+		case strings.HasPrefix(seg, visibilityPrefix):
+			if q := visibilityQuery(seg); q != nil {
+				filters = append(filters, *q)
+			}
+		case strings.HasPrefix(seg, tagPrefix):
+			filters = append(filters, types.Query{
+				Term: map[string]types.TermQuery{
+					"tags.keyword": {Value: strings.TrimPrefix(seg, tagPrefix)},
+				},
+			})
+		case strings.HasPrefix(seg, appealPrefix):
+			filters = append(filters, types.Query{
+				Term: map[string]types.TermQuery{
+					"appeal_status": {Value: strings.TrimPrefix(seg, appealPrefix)},
+				},
+			})
+		}
+	}
+	return filters
+}
+
+// parseReviewHits 反序列化ES命中结果, 同时带回命中总数及其relation
+func (r *reviewRepo) parseReviewHits(b []byte) (*biz.ReviewList, error) {
+	sr := new(esSearchResult)
+	if err := json.Unmarshal(b, sr); err != nil {
 		return nil, err
 	}
-	// 4. 反序列化
-	list := make([]*biz.MyReviewInfo, 0, hm.Total.Value)
+	hm := sr.Hits
+	res := &biz.ReviewList{
+		List:    make([]*biz.MyReviewInfo, 0, len(hm.Hits)),
+		Partial: sr.Partial,
+	}
+	if hm.Total != nil {
+		res.Total = hm.Total.Value
+		res.TotalRelation = hm.Total.Relation.String()
+	}
 	for _, hit := range hm.Hits {
 		tmp := &biz.MyReviewInfo{}
 		if err := json.Unmarshal(hit.Source_, tmp); err != nil {
 			r.log.Errorf("es search result unmarshal error: %v", err)
 			continue
 		}
-		list = append(list, tmp)
+		res.List = append(res.List, tmp)
 	}
-	return list, nil
+	return res, nil
 }
 
 // 通过singleflight获取数据
 func (r *reviewRepo) GetDataBySingleFlight(ctx context.Context, key string, target string) ([]byte, error) {
-	v, err, _ := g.Do(key, func() (interface{}, error) {
+	leader := false
+	v, err, shared := r.sf.Do(key, func() (interface{}, error) {
+		leader = true
 		// 1. 从redis中获取数据
 		data, err := r.GetDataFromCache(ctx, key)
 		if err == nil {
-			r.log.Debugf("GetDataBySingleFlight(from redis cache), key: %s, data: %s", key, string(data))
+			r.log.Debugf("GetDataBySingleFlight(from redis cache), key: %s, data: %s", key, redact.Text(string(data)))
 			return data, nil
 		}
-		// 2. 如果redis中没有数据，则从ES中获取数据
-		if errors.Is(err, redis.Nil) {
-			data, err = r.GetDataFromES(ctx, key, target)
-			if err == nil {
-				r.log.Debugf("GetDataBySingleFlight(from es), key: %s, data: %s", key, string(data))
-				return data, r.SetCache(ctx, key, data)
-			}
+		// 2. 查询redis报错时降级: 记录日志后直接查询ES, 本次不写缓存
+		cacheDown := !errors.Is(err, redis.Nil)
+		if cacheDown {
+			cacheUnavailable.Add(1)
+			r.log.WithContext(ctx).Warnf("GetDataBySingleFlight read cache failed, fall back to es, key: %s, err: %v", key, err)
+		}
+		// 3. 从ES中获取数据
+		data, partial, err := r.GetDataFromES(ctx, key, target)
+		if err != nil {
 			return nil, err
 		}
-		// 3. 如果查询redis报错，则返回错误
-		return nil, err
+		r.log.Debugf("GetDataBySingleFlight(from es), key: %s, data: %s", key, redact.Text(string(data)))
+		// 部分结果不写缓存, 避免集群恢复后仍返回不完整的列表
+		if partial || cacheDown {
+			return data, nil
+		}
+		// 写缓存失败不影响本次查询结果
+		if err := r.SetCache(ctx, key, data); err != nil {
+			cacheUnavailable.Add(1)
+			r.log.WithContext(ctx).Warnf("GetDataBySingleFlight set cache failed, key: %s, err: %v", key, err)
+		}
+		return data, nil
 	})
+	// shared对执行查询的调用也可能为true, 以是否执行了查询函数区分
+	if leader {
+		singleflightCalls.Add(target+".leader", 1)
+	} else if shared {
+		singleflightCalls.Add(target+".shared", 1)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -513,24 +1574,52 @@ func (r *reviewRepo) GetDataFromCache(ctx context.Context, key string) ([]byte,
 	return r.data.rdb.Get(ctx, key).Bytes()
 }
 
-// 从ES中获取数据，target: store or user
-func (r *reviewRepo) GetDataFromES(ctx context.Context, key string, target string) ([]byte, error) {
+// listCacheKey 评论列表的缓存key: <索引>:<列表类型>:<id>:<offset>:<limit>, 其后可追加可见性规则和过滤条件
+// 每种列表使用各自的类型段, 相同数字的店铺ID和用户ID不会共用缓存
+func listCacheKey(target, id string, offset, limit int32) string {
+	return fmt.Sprintf("%s%d:%d", listCacheKeyPrefix(target, id), offset, limit)
+}
+
+// listCacheKeyPrefix 列表类型和id对应的缓存key前缀, id为空时为该类型全部列表的前缀
+func listCacheKeyPrefix(target, id string) string {
+	if id == "" {
+		return reviewIndex + ":" + target + ":"
+	}
+	return reviewIndex + ":" + target + ":" + id + ":"
+}
+
+// listCacheKeyHasStore 店铺列表或多店铺列表的缓存key是否包含该店铺
+func listCacheKeyHasStore(key, storeID string) bool {
 	values := strings.Split(key, ":")
-	if len(values) < 4 {
-		return nil, errors.New("key format error")
+	if len(values) < 3 || (values[1] != "store" && values[1] != "stores") {
+		return false
+	}
+	return slices.Contains(strings.Split(values[2], ","), storeID)
+}
+
+// 从ES中获取数据，target 为缓存key中的列表类型, 见 listCacheKey
+// 第二个返回值表示结果是否只是部分结果（有分片超时或失败）
+func (r *reviewRepo) GetDataFromES(ctx context.Context, key string, target string) ([]byte, bool, error) {
+	values := strings.Split(key, ":")
+	if len(values) < 5 || values[1] != target {
+		return nil, false, errors.New("key format error")
 	}
 	index := values[0]
-	id := values[1] // storeID or userID
-	offsetStr := values[2]
-	limitStr := values[3]
+	id := values[2] // storeID, userID, status or comma-separated storeIDs
+	offsetStr := values[3]
+	limitStr := values[4]
 
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+	// 超出结果窗口的深分页ES会直接报错, 提前返回明确的参数错误
+	if window := r.maxResultWindow(); offset+limit > window {
+		return nil, false, biz.ErrResultWindow(window)
 	}
 
 	// 去ES查询
@@ -541,64 +1630,359 @@ func (r *reviewRepo) GetDataFromES(ctx context.Context, key string, target strin
 		fieldName = "user_id"
 	} else if target == "status" {
 		fieldName = "status"
+	} else if target != "stores" && target != "recent" {
+		return nil, false, errors.New("invalid target")
+	}
+
+	var filters []types.Query
+	if target == "stores" {
+		// 多店铺列表的id段为逗号分隔的店铺ID
+		var storeIDs []types.FieldValue
+		for _, s := range strings.Split(id, ",") {
+			storeIDs = append(storeIDs, s)
+		}
+		filters = append(filters, types.Query{
+			Terms: &types.TermsQuery{TermsQuery: map[string]types.TermsQueryField{"store_id": storeIDs}},
+		})
+	} else if target == "recent" {
+		// 全平台最新评论只包含已通过的评论, id段固定为 all
+		filters = append(filters, types.Query{
+			Term: map[string]types.TermQuery{
+				"status": {Value: 20},
+			},
+		})
 	} else {
-		return nil, errors.New("invalid target")
+		filters = append(filters, types.Query{
+			Term: map[string]types.TermQuery{
+				fieldName: {Value: id},
+			},
+		})
 	}
+	// key的第6段起为可选的可见性规则和过滤条件
+	filters = append(filters, segmentFilters(values[5:])...)
 
-	resp, err := r.data.es.Search().
+	search := r.data.es.Search().
 		Index(index).
 		Query(&types.Query{
 			Bool: &types.BoolQuery{
-				Filter: []types.Query{
-					{
-						Term: map[string]types.TermQuery{
-							fieldName: {Value: id},
-						},
-					},
-				},
+				Filter: filters,
 			},
 		}).
 		From(offset).
-		Size(limit).
+		Size(limit)
+	if target == "recent" {
+		search = search.Sort(types.SortOptions{SortOptions: map[string]types.FieldSort{"create_at": {Order: &sortorder.Desc}}})
+	}
+	if r.esConf.GetTrackTotalHits() {
+		// 需要精确总数时显式开启，否则ES最多统计10000条并返回relation=gte
+		search = search.TrackTotalHits(true)
+	}
+	if timeout := r.esConf.GetTimeout().AsDuration(); timeout > 0 {
+		// ES服务端超时: 慢分片不再等待, 已完成的分片照常返回并标记timed_out
+		search = search.Timeout(fmt.Sprintf("%dms", timeout.Milliseconds()))
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout+esClientTimeoutMargin)
+		defer cancel()
+	}
+	if r.esConf.GetAllowPartialSearchResults() {
+		search = search.AllowPartialSearchResults(true)
+	}
+	resp, err := search.Do(ctx)
+	if err != nil {
+		return nil, false, r.searchError(ctx, index, err)
+	}
+
+	partial := resp.TimedOut || resp.Shards_.Failed > 0
+	if partial {
+		r.log.WithContext(ctx).Warnf("es search returned partial results, key: %s, timed_out: %v, failed shards: %d/%d",
+			key, resp.TimedOut, resp.Shards_.Failed, resp.Shards_.Total)
+	}
+	b, _ := json.Marshal(esSearchResult{Hits: resp.Hits, Partial: partial})
+
+	return b, partial, nil
+}
+
+// GetModerationStats 按驳回类别统计时间范围内被驳回的评论数, storeID为0时统计全部店铺
+// 结果缓存60秒
+func (r *reviewRepo) GetModerationStats(ctx context.Context, storeID int64, start, end time.Time) (*biz.ModerationStats, error) {
+	key := fmt.Sprintf("moderation_stats:%d:%d:%d", storeID, start.Unix(), end.Unix())
+	if b, err := r.GetDataFromCache(ctx, key); err == nil {
+		stats := new(biz.ModerationStats)
+		if err := json.Unmarshal(b, stats); err == nil {
+			return stats, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		cacheUnavailable.Add(1)
+		r.log.WithContext(ctx).Warnf("GetModerationStats read cache failed, key: %s, err: %v", key, err)
+	}
+
+	filters := []types.Query{
+		{Term: map[string]types.TermQuery{"status": {Value: 30}}},
+		{Range: map[string]types.RangeQuery{"create_at": types.DateRangeQuery{
+			Gte: esString(start.Format(time.RFC3339)),
+			Lt:  esString(end.Format(time.RFC3339)),
+		}}},
+	}
+	if storeID > 0 {
+		filters = append(filters, types.Query{Term: map[string]types.TermQuery{"store_id": {Value: storeID}}})
+	}
+	// 人工驳回的评论没有类别, 统计在空类别下
+	field, size := "reject_category.keyword", 50
+	resp, err := r.data.es.Search().
+		Index("review").
+		Query(&types.Query{Bool: &types.BoolQuery{Filter: filters}}).
+		Size(0).
+		TrackTotalHits(true).
+		TypedKeys(true).
+		Aggregations(map[string]types.Aggregations{
+			"by_category": {Terms: &types.TermsAggregation{Field: &field, Size: &size}},
+		}).
 		Do(ctx)
+	if err != nil {
+		return nil, r.searchError(ctx, reviewIndex, err)
+	}
+
+	stats := &biz.ModerationStats{Categories: make([]*biz.CategoryCount, 0)}
+	if resp.Hits.Total != nil {
+		stats.Total = resp.Hits.Total.Value
+	}
+	if agg, ok := resp.Aggregations["by_category"].(*types.StringTermsAggregate); ok {
+		if buckets, ok := agg.Buckets.([]types.StringTermsBucket); ok {
+			for _, b := range buckets {
+				stats.Categories = append(stats.Categories, &biz.CategoryCount{
+					Category: fmt.Sprint(b.Key),
+					Count:    b.DocCount,
+				})
+			}
+		}
+	}
+
+	if b, err := json.Marshal(stats); err == nil {
+		if err := r.SetCache(ctx, key, b); err != nil {
+			cacheUnavailable.Add(1)
+			r.log.WithContext(ctx).Warnf("GetModerationStats set cache failed, key: %s, err: %v", key, err)
+		}
+	}
+	return stats, nil
+}
+
+// GetStoreRating 统计店铺已通过(20)评论的平均评分, 其它状态的评论不计入
+func (r *reviewRepo) GetStoreRating(ctx context.Context, storeID int64) (*biz.StoreRating, error) {
+	key := fmt.Sprintf("store_rating:%d", storeID)
+	if b, err := r.GetDataFromCache(ctx, key); err == nil {
+		rating := new(biz.StoreRating)
+		if err := json.Unmarshal(b, rating); err == nil {
+			return rating, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		cacheUnavailable.Add(1)
+		r.log.WithContext(ctx).Warnf("GetStoreRating read cache failed, key: %s, err: %v", key, err)
+	}
 
+	filters := []types.Query{
+		{Term: map[string]types.TermQuery{"store_id": {Value: storeID}}},
+		{Term: map[string]types.TermQuery{"status": {Value: 20}}},
+	}
+	score, serviceScore, expressScore := "score", "service_score", "express_score"
+	resp, err := r.data.es.Search().
+		Index("review").
+		Query(&types.Query{Bool: &types.BoolQuery{Filter: filters}}).
+		Size(0).
+		TrackTotalHits(true).
+		TypedKeys(true).
+		Aggregations(map[string]types.Aggregations{
+			"avg_score":         {Avg: &types.AverageAggregation{Field: &score}},
+			"avg_service_score": {Avg: &types.AverageAggregation{Field: &serviceScore}},
+			"avg_express_score": {Avg: &types.AverageAggregation{Field: &expressScore}},
+		}).
+		Do(ctx)
 	if err != nil {
-		return nil, err
+		return nil, r.searchError(ctx, reviewIndex, err)
+	}
+
+	rating := &biz.StoreRating{StoreID: storeID}
+	if resp.Hits.Total != nil {
+		rating.Count = resp.Hits.Total.Value
+	}
+	rating.Score = esAvg(resp.Aggregations["avg_score"])
+	rating.ServiceScore = esAvg(resp.Aggregations["avg_service_score"])
+	rating.ExpressScore = esAvg(resp.Aggregations["avg_express_score"])
+
+	if b, err := json.Marshal(rating); err == nil {
+		if err := r.SetCache(ctx, key, b); err != nil {
+			cacheUnavailable.Add(1)
+			r.log.WithContext(ctx).Warnf("GetStoreRating set cache failed, key: %s, err: %v", key, err)
+		}
+	}
+	return rating, nil
+}
+
+// esAvg 读取avg聚合的结果, 没有文档时ES返回null, 按0处理
+func esAvg(agg types.Aggregate) float64 {
+	avg, ok := agg.(*types.AvgAggregate)
+	if !ok || avg.Value == nil {
+		return 0
+	}
+	return float64(*avg.Value)
+}
+
+// GetTagStats 统计已发布评论的话题标签分布, storeID为0时统计全部店铺
+func (r *reviewRepo) GetTagStats(ctx context.Context, storeID int64) (*biz.TagStats, error) {
+	key := fmt.Sprintf("tag_stats:%d", storeID)
+	if b, err := r.GetDataFromCache(ctx, key); err == nil {
+		stats := new(biz.TagStats)
+		if err := json.Unmarshal(b, stats); err == nil {
+			return stats, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		cacheUnavailable.Add(1)
+		r.log.WithContext(ctx).Warnf("GetTagStats read cache failed, key: %s, err: %v", key, err)
+	}
+
+	filters := []types.Query{
+		{Term: map[string]types.TermQuery{"status": {Value: 20}}},
+	}
+	if storeID > 0 {
+		filters = append(filters, types.Query{Term: map[string]types.TermQuery{"store_id": {Value: storeID}}})
+	}
+	field, size := "tags.keyword", 50
+	resp, err := r.data.es.Search().
+		Index("review").
+		Query(&types.Query{Bool: &types.BoolQuery{Filter: filters}}).
+		Size(0).
+		TrackTotalHits(true).
+		TypedKeys(true).
+		Aggregations(map[string]types.Aggregations{
+			"by_tag": {Terms: &types.TermsAggregation{Field: &field, Size: &size}},
+		}).
+		Do(ctx)
+	if err != nil {
+		return nil, r.searchError(ctx, reviewIndex, err)
+	}
+
+	stats := &biz.TagStats{Tags: make([]*biz.TagCount, 0)}
+	if resp.Hits.Total != nil {
+		stats.Total = resp.Hits.Total.Value
+	}
+	if agg, ok := resp.Aggregations["by_tag"].(*types.StringTermsAggregate); ok {
+		if buckets, ok := agg.Buckets.([]types.StringTermsBucket); ok {
+			for _, b := range buckets {
+				stats.Tags = append(stats.Tags, &biz.TagCount{Tag: fmt.Sprint(b.Key), Count: b.DocCount})
+			}
+		}
+	}
+
+	if b, err := json.Marshal(stats); err == nil {
+		if err := r.SetCache(ctx, key, b); err != nil {
+			cacheUnavailable.Add(1)
+			r.log.WithContext(ctx).Warnf("GetTagStats set cache failed, key: %s, err: %v", key, err)
+		}
+	}
+	return stats, nil
+}
+
+// 搜索输入提示的数量及每个分片参与统计的评论数
+const (
+	suggestSize      = 10
+	suggestShardSize = 200
+)
+
+// SuggestReviewTerms 返回店铺已通过评论中以 prefix 开头的常见词, 结果缓存60秒
+// 先用 match_phrase_prefix 找出原文或追加评论包含该前缀的评论, 取每个分片最相关的200条, 再对 content 做
+// significant_text 聚合, 只保留以 prefix 开头的词; significant_text 从 _source 重新分词, 不需要开启 fielddata
+func (r *reviewRepo) SuggestReviewTerms(ctx context.Context, storeID int64, prefix string) ([]*biz.TermSuggestion, error) {
+	key := fmt.Sprintf("suggest:%d:%s", storeID, prefix)
+	if b, err := r.GetDataFromCache(ctx, key); err == nil {
+		var suggestions []*biz.TermSuggestion
+		if err := json.Unmarshal(b, &suggestions); err == nil {
+			return suggestions, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		cacheUnavailable.Add(1)
+		r.log.WithContext(ctx).Warnf("SuggestReviewTerms read cache failed, key: %s, err: %v", key, err)
+	}
+
+	field, size, shardSize, filterDuplicate := "content", suggestSize, suggestShardSize, true
+	resp, err := r.data.es.Search().
+		Index("review").
+		Query(&types.Query{Bool: &types.BoolQuery{
+			Filter: []types.Query{
+				{Term: map[string]types.TermQuery{"status": {Value: 20}}},
+				{Term: map[string]types.TermQuery{"store_id": {Value: storeID}}},
+			},
+			// 前缀出现在评论原文或任一追加评论中即可
+			Must: []types.Query{{Bool: &types.BoolQuery{
+				Should: []types.Query{
+					{MatchPhrasePrefix: map[string]types.MatchPhrasePrefixQuery{"content": {Query: prefix}}},
+					{MatchPhrasePrefix: map[string]types.MatchPhrasePrefixQuery{appendContentField: {Query: prefix}}},
+				},
+			}}},
+		}}).
+		Size(0).
+		TypedKeys(true).
+		Aggregations(map[string]types.Aggregations{
+			"sample": {
+				Sampler: &types.SamplerAggregation{ShardSize: &shardSize},
+				Aggregations: map[string]types.Aggregations{
+					"terms": {SignificantText: &types.SignificantTextAggregation{
+						Field:               &field,
+						Include:             regexp.QuoteMeta(prefix) + ".*",
+						Size:                &size,
+						FilterDuplicateText: &filterDuplicate,
+					}},
+				},
+			},
+		}).
+		Do(ctx)
+	if err != nil {
+		return nil, r.searchError(ctx, reviewIndex, err)
+	}
+
+	suggestions := make([]*biz.TermSuggestion, 0, suggestSize)
+	if sample, ok := resp.Aggregations["sample"].(*types.SamplerAggregate); ok {
+		if agg, ok := sample.Aggregations["terms"].(*types.SignificantStringTermsAggregate); ok {
+			if buckets, ok := agg.Buckets.([]types.SignificantStringTermsBucket); ok {
+				for _, b := range buckets {
+					suggestions = append(suggestions, &biz.TermSuggestion{Term: b.Key, Count: b.DocCount})
+				}
+			}
+		}
 	}
 
-	b, _ := json.Marshal(resp.Hits)
+	if b, err := json.Marshal(suggestions); err == nil {
+		if err := r.SetCache(ctx, key, b); err != nil {
+			cacheUnavailable.Add(1)
+			r.log.WithContext(ctx).Warnf("SuggestReviewTerms set cache failed, key: %s, err: %v", key, err)
+		}
+	}
+	return suggestions, nil
+}
 
-	return b, nil
+func esString(s string) *string {
+	return &s
 }
 
-// 设置缓存
+// 设置缓存, 超过 max_value_bytes 的值不写缓存
 func (r *reviewRepo) SetCache(ctx context.Context, key string, value []byte) error {
+	if !r.cacheValueAllowed(ctx, key, value) {
+		return nil
+	}
 	return r.data.rdb.Set(ctx, key, value, time.Second*60).Err()
 }
 
 // listReviewsByStatusFromES directly queries Elasticsearch for reviews by their status.
-func (r *reviewRepo) listReviewsByStatusFromES(ctx context.Context, status int32, offset int32, limit int32) ([]*biz.MyReviewInfo, error) {
+func (r *reviewRepo) listReviewsByStatusFromES(ctx context.Context, status int32, appealStatus int32, offset int32, limit int32) (*biz.ReviewList, error) {
 
-	key := fmt.Sprintf("review:%d:%d:%d", status, offset, limit)
+	key := listCacheKey("status", strconv.Itoa(int(status)), offset, limit)
+	if appealStatus > 0 {
+		key += ":" + appealPrefix + strconv.Itoa(int(appealStatus))
+	}
 	b, err := r.GetDataBySingleFlight(ctx, key, "status")
 	if err != nil {
 		return nil, err
 	}
-	hm := new(types.HitsMetadata)
-	if err := json.Unmarshal(b, hm); err != nil {
-		return nil, err
-	}
 	// 4. 反序列化
-	list := make([]*biz.MyReviewInfo, 0, hm.Total.Value)
-	for _, hit := range hm.Hits {
-		tmp := &biz.MyReviewInfo{}
-		if err := json.Unmarshal(hit.Source_, tmp); err != nil {
-			r.log.Errorf("es search result unmarshal error: %v", err)
-			continue
-		}
-		list = append(list, tmp)
-	}
-	return list, nil
+	return r.parseReviewHits(b)
 }
 
 // // 旧版不带缓存的查询函数
@@ -0,0 +1,635 @@
+package data
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/client/ai"
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestESVersionIgnoresUpdateTime(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		older, newer *model.ReviewInfo
+	}{
+		{
+			name:  "same update time",
+			older: &model.ReviewInfo{Version: 1, UpdateAt: now},
+			newer: &model.ReviewInfo{Version: 2, UpdateAt: now},
+		},
+		// 应用时钟落后于MySQL时, 较新的写入可能带有更早的更新时间
+		{
+			name:  "newer write with an earlier update time",
+			older: &model.ReviewInfo{Version: 3, UpdateAt: now},
+			newer: &model.ReviewInfo{Version: 4, UpdateAt: now.Add(-5 * time.Second)},
+		},
+		{
+			name:  "newly created review without update time",
+			older: &model.ReviewInfo{Version: 1},
+			newer: &model.ReviewInfo{Version: 2, UpdateAt: now},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if esVersion(tt.newer) <= esVersion(tt.older) {
+				t.Errorf("esVersion(newer) = %d, want greater than esVersion(older) = %d", esVersion(tt.newer), esVersion(tt.older))
+			}
+		})
+	}
+}
+
+func TestListCacheKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		id     string
+		want   string
+	}{
+		{name: "store", target: "store", id: "123", want: "review:store:123:0:10"},
+		{name: "user with the same ID", target: "user", id: "123", want: "review:user:123:0:10"},
+		{name: "multiple stores", target: "stores", id: "1,2", want: "review:stores:1,2:0:10"},
+		{name: "recent", target: "recent", id: "all", want: "review:recent:all:0:10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listCacheKey(tt.target, tt.id, 0, 10); got != tt.want {
+				t.Errorf("listCacheKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListCacheKeyHasStore(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "review:store:123:0:10:vis=approved", want: true},
+		{key: "review:store:1234:0:10:vis=approved", want: false},
+		{key: "review:stores:1,123,7:0:10:vis=approved", want: true},
+		{key: "review:stores:1,1234:0:10:vis=approved", want: false},
+		{key: "review:user:123:0:10:vis=approved", want: false},
+		{key: "review:status:123:0:10", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := listCacheKeyHasStore(tt.key, "123"); got != tt.want {
+				t.Errorf("listCacheKeyHasStore(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReviewHits(t *testing.T) {
+	r := &reviewRepo{log: log.NewHelper(log.DefaultLogger)}
+	tests := []struct {
+		name         string
+		body         string
+		wantTotal    int64
+		wantRelation string
+		wantIDs      []int64
+		wantPartial  bool
+	}{
+		{
+			name:         "exact total",
+			body:         `{"hits":{"total":{"value":2,"relation":"eq"},"hits":[{"_index":"review","_id":"1","_source":{"review_id":1}},{"_index":"review","_id":"2","_source":{"review_id":2}}]}}`,
+			wantTotal:    2,
+			wantRelation: "eq",
+			wantIDs:      []int64{1, 2},
+		},
+		{
+			name:         "total is a lower bound",
+			body:         `{"hits":{"total":{"value":10000,"relation":"gte"},"hits":[{"_index":"review","_id":"1","_source":{"review_id":1}}]}}`,
+			wantTotal:    10000,
+			wantRelation: "gte",
+			wantIDs:      []int64{1},
+		},
+		{
+			name:         "unparsable hit is skipped",
+			body:         `{"hits":{"total":{"value":2,"relation":"eq"},"hits":[{"_index":"review","_id":"1","_source":{"review_id":"x"}},{"_index":"review","_id":"2","_source":{"review_id":2}}]}}`,
+			wantTotal:    2,
+			wantRelation: "eq",
+			wantIDs:      []int64{2},
+		},
+		{
+			name:         "partial results are flagged",
+			body:         `{"hits":{"total":{"value":1,"relation":"eq"},"hits":[{"_index":"review","_id":"1","_source":{"review_id":1}}]},"partial":true}`,
+			wantTotal:    1,
+			wantRelation: "eq",
+			wantIDs:      []int64{1},
+			wantPartial:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := r.parseReviewHits([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("parseReviewHits() = %v", err)
+			}
+			if list.Total != tt.wantTotal || list.TotalRelation != tt.wantRelation {
+				t.Errorf("total = %d %q, want %d %q", list.Total, list.TotalRelation, tt.wantTotal, tt.wantRelation)
+			}
+			if list.TotalIsLowerBound() != (tt.wantRelation == "gte") {
+				t.Errorf("TotalIsLowerBound() = %v", list.TotalIsLowerBound())
+			}
+			if list.Partial != tt.wantPartial {
+				t.Errorf("Partial = %v, want %v", list.Partial, tt.wantPartial)
+			}
+			var ids []int64
+			for _, review := range list.List {
+				ids = append(ids, review.ReviewID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("review IDs = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+// termFilters collects the field/value pairs of the term queries in filters.
+func termFilters(filters []types.Query) map[string]types.FieldValue {
+	terms := make(map[string]types.FieldValue)
+	for _, f := range filters {
+		for field, term := range f.Term {
+			terms[field] = term.Value
+		}
+	}
+	return terms
+}
+
+func TestSegmentFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		segs []string
+		want map[string]types.FieldValue
+	}{
+		{name: "no segments", want: map[string]types.FieldValue{}},
+		{name: "unknown segment is ignored", segs: []string{"bogus"}, want: map[string]types.FieldValue{}},
+		{
+			name: "unreplied matches the count's definition",
+			segs: []string{esFilterUnreplied},
+			want: map[string]types.FieldValue{"has_reply": 0, "status": unrepliedStatus},
+		},
+		{name: "tag", segs: []string{tagPrefix + "物流"}, want: map[string]types.FieldValue{"tags.keyword": "物流"}},
+		{name: "appeal status", segs: []string{appealPrefix + "10"}, want: map[string]types.FieldValue{"appeal_status": "10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := termFilters(segmentFilters(tt.segs)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("segmentFilters(%v) terms = %v, want %v", tt.segs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibilityQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		v    biz.ReviewVisibility
+		want string
+	}{
+		{name: "all is unfiltered", v: biz.ReviewVisibility{All: true}, want: `null`},
+		{name: "approved only", v: biz.ReviewVisibility{}, want: `{"term":{"status":{"value":20}}}`},
+		{
+			name: "pending public",
+			v:    biz.ReviewVisibility{PendingPublic: true},
+			want: `{"bool":{"minimum_should_match":1,"should":[{"term":{"status":{"value":20}}},{"term":{"status":{"value":10}}}]}}`,
+		},
+		{
+			name: "pending to its author",
+			v:    biz.ReviewVisibility{PendingAuthorID: 5},
+			want: `{"bool":{"minimum_should_match":1,"should":[{"term":{"status":{"value":20}}},{"bool":{"filter":[{"term":{"status":{"value":10}}},{"term":{"user_id":{"value":5}}}]}}]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(visibilityQuery(visibilityKey(tt.v)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("visibilityQuery(visibilityKey(%+v)) = %s, want %s", tt.v, b, tt.want)
+			}
+		})
+	}
+}
+
+// esSearchBody is a minimal successful search response with one hit.
+const esSearchBody = `{"took":1,"timed_out":false,"_shards":{"total":1,"successful":1,"skipped":0,"failed":0},` +
+	`"hits":{"total":{"value":1,"relation":"eq"},"hits":[{"_index":"review","_id":"1","_source":{"review_id":1}}]}}`
+
+// newTestES starts a fake Elasticsearch answering every request with handler.
+func newTestES(t *testing.T, handler http.HandlerFunc) *elasticsearch.TypedClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, req)
+	}))
+	t.Cleanup(srv.Close)
+	es, err := elasticsearch.NewTypedClient(elasticsearch.Config{Addresses: []string{srv.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return es
+}
+
+// newTestRepo returns a repo backed by es and by a Redis that is not reachable.
+func newTestRepo(es *elasticsearch.TypedClient) *reviewRepo {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	return &reviewRepo{
+		data:   &Data{es: es, rdb: rdb},
+		log:    log.NewHelper(log.DefaultLogger),
+		esConf: &conf.Elasticsearch{},
+	}
+}
+
+func TestGetDataBySingleFlightRedisDown(t *testing.T) {
+	var searches atomic.Int32
+	r := newTestRepo(newTestES(t, func(w http.ResponseWriter, _ *http.Request) {
+		searches.Add(1)
+		io.WriteString(w, esSearchBody)
+	}))
+	before := cacheUnavailable.Value()
+
+	b, err := r.GetDataBySingleFlight(context.Background(), listCacheKey("store", "1", 0, 10), "store")
+	if err != nil {
+		t.Fatalf("GetDataBySingleFlight() error = %v, want the ES result", err)
+	}
+	list, err := r.parseReviewHits(b)
+	if err != nil || len(list.List) != 1 {
+		t.Fatalf("parseReviewHits() = %v, %v, want one review", list, err)
+	}
+	if searches.Load() != 1 {
+		t.Errorf("ES searched %d times, want 1", searches.Load())
+	}
+	if got := cacheUnavailable.Value() - before; got != 1 {
+		t.Errorf("cacheUnavailable increased by %d, want 1", got)
+	}
+}
+
+// expvarInt reads a counter from an expvar map, 0 when it is not set yet.
+func expvarInt(m *expvar.Map, key string) int64 {
+	if v, ok := m.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestGetDataBySingleFlightShared(t *testing.T) {
+	const callers = 5
+	var searches atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	r := newTestRepo(newTestES(t, func(w http.ResponseWriter, _ *http.Request) {
+		if searches.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		io.WriteString(w, esSearchBody)
+	}))
+	// a distinct target keeps the counters separate from other tests
+	key := listCacheKey("user", "1", 0, 10)
+	leaders, shared := expvarInt(singleflightCalls, "user.leader"), expvarInt(singleflightCalls, "user.shared")
+
+	var wg sync.WaitGroup
+	call := func() {
+		defer wg.Done()
+		if _, err := r.GetDataBySingleFlight(context.Background(), key, "user"); err != nil {
+			t.Errorf("GetDataBySingleFlight() error = %v", err)
+		}
+	}
+	wg.Add(callers)
+	go call()
+	<-started
+	for i := 1; i < callers; i++ {
+		go call()
+	}
+	// give the other callers time to join the in-flight search
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	leaders = expvarInt(singleflightCalls, "user.leader") - leaders
+	shared = expvarInt(singleflightCalls, "user.shared") - shared
+	if int64(searches.Load()) != leaders || leaders+shared != callers {
+		t.Errorf("searches = %d, leader = %d, shared = %d, want one search per leader and %d calls", searches.Load(), leaders, shared, callers)
+	}
+	if shared == 0 {
+		t.Errorf("no caller shared the in-flight search")
+	}
+}
+
+func TestAIErrorDecision(t *testing.T) {
+	tests := []struct {
+		policy     string
+		wantPolicy string
+		wantStatus int32
+	}{
+		{policy: "", wantPolicy: onAIErrorHold, wantStatus: 10},
+		{policy: "bogus", wantPolicy: onAIErrorHold, wantStatus: 10},
+		{policy: onAIErrorHold, wantPolicy: onAIErrorHold, wantStatus: 10},
+		{policy: onAIErrorApprove, wantPolicy: onAIErrorApprove, wantStatus: 20},
+		{policy: onAIErrorReject, wantPolicy: onAIErrorReject, wantStatus: 30},
+		{policy: onAIErrorHumanReview, wantPolicy: onAIErrorHumanReview, wantStatus: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			policy, status, remarks := aiErrorDecision(tt.policy, 10)
+			if policy != tt.wantPolicy || status != tt.wantStatus {
+				t.Errorf("aiErrorDecision(%q) = %q, %d, want %q, %d", tt.policy, policy, status, tt.wantPolicy, tt.wantStatus)
+			}
+			if (remarks == "") != (policy == onAIErrorHold) {
+				t.Errorf("aiErrorDecision(%q) remarks = %q", tt.policy, remarks)
+			}
+		})
+	}
+}
+
+func TestGetStoreRating(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want biz.StoreRating
+	}{
+		{
+			name: "approved reviews",
+			body: `{"took":1,"timed_out":false,"_shards":{"total":1,"successful":1,"skipped":0,"failed":0},` +
+				`"hits":{"total":{"value":4,"relation":"eq"},"hits":[]},"aggregations":{` +
+				`"avg#avg_score":{"value":4.5},"avg#avg_service_score":{"value":4},"avg#avg_express_score":{"value":3.25}}}`,
+			want: biz.StoreRating{StoreID: 9, Count: 4, Score: 4.5, ServiceScore: 4, ExpressScore: 3.25},
+		},
+		{
+			name: "no reviews",
+			body: `{"took":1,"timed_out":false,"_shards":{"total":1,"successful":1,"skipped":0,"failed":0},` +
+				`"hits":{"total":{"value":0,"relation":"eq"},"hits":[]},"aggregations":{` +
+				`"avg#avg_score":{"value":null},"avg#avg_service_score":{"value":null},"avg#avg_express_score":{"value":null}}}`,
+			want: biz.StoreRating{StoreID: 9},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query types.Query
+			r := newTestRepo(newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+				var search struct {
+					Query types.Query `json:"query"`
+				}
+				if err := json.NewDecoder(req.Body).Decode(&search); err != nil {
+					t.Errorf("decode search: %v", err)
+				}
+				query = search.Query
+				io.WriteString(w, tt.body)
+			}))
+			got, err := r.GetStoreRating(context.Background(), 9)
+			if err != nil {
+				t.Fatalf("GetStoreRating() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("GetStoreRating() = %+v, want %+v", *got, tt.want)
+			}
+			// Only approved reviews count: rejected, hidden and pending ones are filtered out by ES.
+			if query.Bool == nil || len(query.Bool.Filter) != 2 || query.Bool.Filter[1].Term["status"].Value != float64(20) {
+				t.Errorf("query = %+v, want store_id and status=20 filters", query)
+			}
+		})
+	}
+}
+
+func TestGetDataFromESResultWindow(t *testing.T) {
+	tests := []struct {
+		name          string
+		window        int32
+		offset, limit int32
+		wantReason    string
+	}{
+		{name: "last page of the default window", offset: 9990, limit: 10},
+		{name: "beyond the default window", offset: 9991, limit: 10, wantReason: "RESULT_WINDOW_EXCEEDED"},
+		{name: "configured window", window: 100, offset: 90, limit: 10},
+		{name: "beyond the configured window", window: 100, offset: 100, limit: 10, wantReason: "RESULT_WINDOW_EXCEEDED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var searches atomic.Int32
+			r := newTestRepo(newTestES(t, func(w http.ResponseWriter, _ *http.Request) {
+				searches.Add(1)
+				io.WriteString(w, esSearchBody)
+			}))
+			r.esConf = &conf.Elasticsearch{MaxResultWindow: tt.window}
+			_, _, err := r.GetDataFromES(context.Background(), listCacheKey("store", "1", tt.offset, tt.limit), "store")
+			if tt.wantReason == "" {
+				if err != nil || searches.Load() != 1 {
+					t.Errorf("GetDataFromES() error = %v after %d searches, want one search", err, searches.Load())
+				}
+				return
+			}
+			if kerrors.Reason(err) != tt.wantReason || kerrors.FromError(err).Code != http.StatusBadRequest {
+				t.Errorf("GetDataFromES() error = %v, want a 400 %s", err, tt.wantReason)
+			}
+			if searches.Load() != 0 {
+				t.Errorf("ES searched %d times, want the page rejected before searching", searches.Load())
+			}
+		})
+	}
+}
+
+func TestGetDataFromESRecent(t *testing.T) {
+	var body map[string]any
+	r := newTestRepo(newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("decode search body: %v", err)
+		}
+		io.WriteString(w, esSearchBody)
+	}))
+	if _, _, err := r.GetDataFromES(context.Background(), listCacheKey("recent", "all", 0, 10), "recent"); err != nil {
+		t.Fatalf("GetDataFromES() error = %v", err)
+	}
+	filter, _ := json.Marshal(body["query"].(map[string]any)["bool"].(map[string]any)["filter"])
+	if want := `[{"term":{"status":{"value":20}}}]`; string(filter) != want {
+		t.Errorf("filter = %s, want only approved reviews %s", filter, want)
+	}
+	sort, _ := json.Marshal(body["sort"])
+	if want := `[{"create_at":{"order":"desc"}}]`; string(sort) != want {
+		t.Errorf("sort = %s, want newest first %s", sort, want)
+	}
+}
+
+func TestSuggestReviewTerms(t *testing.T) {
+	var body map[string]any
+	r := newTestRepo(newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("decode search body: %v", err)
+		}
+		io.WriteString(w, `{"took":1,"timed_out":false,"_shards":{"total":1,"successful":1,"skipped":0,"failed":0},`+
+			`"hits":{"total":{"value":7,"relation":"eq"},"hits":[]},"aggregations":{"sampler#sample":{"doc_count":7,`+
+			`"sigsterms#terms":{"doc_count":7,"bg_count":100,"buckets":[`+
+			`{"key":"物流快","doc_count":5,"score":1.5,"bg_count":6},{"key":"物流慢","doc_count":2,"score":0.5,"bg_count":9}]}}}}`)
+	}))
+	got, err := r.SuggestReviewTerms(context.Background(), 9, "物流")
+	if err != nil {
+		t.Fatalf("SuggestReviewTerms() error = %v", err)
+	}
+	want := []*biz.TermSuggestion{{Term: "物流快", Count: 5}, {Term: "物流慢", Count: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SuggestReviewTerms() = %v, want %v", got, want)
+	}
+	filter, _ := json.Marshal(body["query"].(map[string]any)["bool"].(map[string]any)["filter"])
+	if want := `[{"term":{"status":{"value":20}}},{"term":{"store_id":{"value":9}}}]`; string(filter) != want {
+		t.Errorf("filter = %s, want approved reviews of the store %s", filter, want)
+	}
+	terms := body["aggregations"].(map[string]any)["sample"].(map[string]any)["aggregations"].(map[string]any)["terms"]
+	if include := terms.(map[string]any)["significant_text"].(map[string]any)["include"]; include != "物流.*" {
+		t.Errorf("include = %v, want terms starting with the prefix", include)
+	}
+}
+
+func TestESDocumentAppealStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		extJSON string
+		want    int32
+	}{
+		{name: "never appealed", want: 0},
+		{name: "pending appeal", extJSON: biz.WithAppealStatus(`{"append_count":1}`, 10), want: 10},
+		{name: "rejected appeal", extJSON: biz.WithAppealStatus("", 30), want: 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := esDocument(&model.ReviewInfo{ReviewID: 1, ExtJSON: tt.extJSON}, nil).AppealStatus; got != tt.want {
+				t.Errorf("esDocument().AppealStatus = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncAndAuditOrder(t *testing.T) {
+	const content = "包装很结实，物流也快"
+	pending := []driver.Value{int64(42), content, int64(10), int64(1)}
+	published := []driver.Value{int64(42), content, int64(20), int64(2)}
+	tests := []struct {
+		name      string
+		syncFirst bool
+		// writeFails makes the versioned status update match no row, as when the review changed during the audit.
+		writeFails bool
+		want       []int32 // statuses of the documents written to ES, in order
+	}{
+		{name: "audit first", want: []int32{20}},
+		{name: "audit first, audit write fails", writeFails: true, want: []int32{10}},
+		{name: "sync first", syncFirst: true, want: []int32{10, 20}},
+		// The pending document stays; the status that was never applied is not indexed.
+		{name: "sync first, audit write fails", syncFirst: true, writeFails: true, want: []int32{10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var indexed []int32
+			r := newTestRepo(newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+				var doc struct {
+					Status int32 `json:"status"`
+				}
+				if err := json.NewDecoder(req.Body).Decode(&doc); err != nil {
+					t.Errorf("decode %s %s: %v", req.Method, req.URL.Path, err)
+				}
+				mu.Lock()
+				indexed = append(indexed, doc.Status)
+				mu.Unlock()
+				io.WriteString(w, `{"_index":"review","_id":"42","_version":1,"result":"created"}`)
+			}))
+			columns := []string{"review_id", "content", "status", "version"}
+			var rowsAffected int64 = 1
+			if tt.writeFails {
+				rowsAffected = 0
+			}
+			r.data.q = newExecQuery(t, &execConn{rowsAffected: rowsAffected, results: []*resultRows{
+				{columns: columns, values: [][]driver.Value{pending}},
+				{columns: []string{"id"}},
+				{columns: columns, values: [][]driver.Value{published}},
+			}})
+			r.data.syncFirst, r.data.asyncTimeout = tt.syncFirst, time.Second
+			// A cached verdict stands in for the AI call.
+			r.modCache = newModerationCache(&conf.Review_ModerationCache{Enabled: true}, newMemRedis(t), r.log)
+			r.modCache.Set(context.Background(), content, &ai.ModerationResult{Approved: true})
+
+			r.syncAndAudit(&model.ReviewInfo{ReviewID: 42, Content: content, Status: 10, Version: 1})
+			if !reflect.DeepEqual(indexed, tt.want) {
+				t.Errorf("indexed statuses = %v, want %v", indexed, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveToESAppends(t *testing.T) {
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		extJSON     string
+		rows        [][]driver.Value
+		wantQueries int
+		want        []string
+	}{
+		{name: "not appended", wantQueries: 0, want: []string{}},
+		{
+			name:    "appended",
+			extJSON: biz.WithAppendCount("", 2),
+			rows: [][]driver.Value{
+				{int64(1), at, int64(42), "用了一周, 质量不错"},
+				{int64(2), at.Add(time.Hour), int64(42), "客服回复也很及时"},
+			},
+			wantQueries: 1,
+			want:        []string{"用了一周, 质量不错", "客服回复也很及时"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc struct {
+				Content string `json:"content"`
+				Appends []struct {
+					Content  string    `json:"content"`
+					CreateAt time.Time `json:"create_at"`
+				} `json:"appends"`
+			}
+			r := newTestRepo(newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+				if err := json.NewDecoder(req.Body).Decode(&doc); err != nil {
+					t.Errorf("decode document: %v", err)
+				}
+				io.WriteString(w, `{"_index":"review","_id":"42","_version":1,"result":"created"}`)
+			}))
+			conn := &execConn{results: []*resultRows{{columns: []string{"id", "create_at", "review_id", "content"}, values: tt.rows}}}
+			r.data.q = newExecQuery(t, conn)
+			if err := r.SaveToES(context.Background(), &model.ReviewInfo{ReviewID: 42, Version: 1, Content: "包装很结实", ExtJSON: tt.extJSON}); err != nil {
+				t.Fatalf("SaveToES() error = %v", err)
+			}
+			if len(conn.stmts) != tt.wantQueries {
+				t.Errorf("queries = %q, want %d", conn.stmts, tt.wantQueries)
+			}
+			got := []string{}
+			for _, a := range doc.Appends {
+				got = append(got, a.Content)
+			}
+			if doc.Content != "包装很结实" || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("indexed content, appends = %q, %q, want the original content and %q", doc.Content, got, tt.want)
+			}
+			if len(doc.Appends) == 2 && !doc.Appends[1].CreateAt.Equal(at.Add(time.Hour)) {
+				t.Errorf("second append create_at = %v, want %v", doc.Appends[1].CreateAt, at.Add(time.Hour))
+			}
+		})
+	}
+}
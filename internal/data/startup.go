@@ -0,0 +1,205 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"review/internal/conf"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// 启动检查方式, 见 conf.Data.Startup.mode
+const (
+	startupOff      = "off"
+	startupFailFast = "fail_fast"
+	startupWait     = "wait"
+)
+
+const (
+	defaultStartupTimeout  = time.Minute
+	defaultStartupInterval = 2 * time.Second
+	// dependencyCheckTimeout 单个依赖检查的超时时间
+	dependencyCheckTimeout = 5 * time.Second
+)
+
+// errStartupPending 启动检查尚未完成, /readyz 返回不可用
+var errStartupPending = errors.New("startup checks have not passed yet")
+
+// Startup 启动前检查 MySQL、Redis、ES 是否可用, 并确保 review 索引存在
+// Wait 作为 kratos.BeforeStart 在各个 server 开始监听之前执行; Checks 同时提供给 /readyz
+type Startup struct {
+	db       *gorm.DB
+	rdb      *redis.Client
+	es       *elasticsearch.TypedClient
+	esConf   *conf.Elasticsearch
+	log      *log.Helper
+	mode     string
+	timeout  time.Duration
+	interval time.Duration
+	// ready 启动检查已通过或未开启
+	ready atomic.Bool
+}
+
+func NewStartup(c *conf.Data, db *gorm.DB, rdb *redis.Client, es *elasticsearch.TypedClient, esConf *conf.Elasticsearch, logger log.Logger) (*Startup, error) {
+	s := &Startup{
+		db:       db,
+		rdb:      rdb,
+		es:       es,
+		esConf:   esConf,
+		log:      log.NewHelper(logger),
+		mode:     c.GetStartup().GetMode(),
+		timeout:  c.GetStartup().GetTimeout().AsDuration(),
+		interval: c.GetStartup().GetInterval().AsDuration(),
+	}
+	switch s.mode {
+	case "":
+		s.mode = startupOff
+	case startupOff, startupFailFast, startupWait:
+	default:
+		return nil, fmt.Errorf("invalid startup mode: %q", s.mode)
+	}
+	if s.timeout <= 0 {
+		s.timeout = defaultStartupTimeout
+	}
+	if s.interval <= 0 {
+		s.interval = defaultStartupInterval
+	}
+	return s, nil
+}
+
+// Checks 各依赖的就绪检查, 供 /readyz 使用; startup 一项在启动检查通过前不可用
+// 未开启启动检查时索引可能在第一次写入时才创建, ES只检查是否可达
+func (s *Startup) Checks() map[string]func(context.Context) error {
+	esCheck := s.pingES
+	if s.mode != startupOff {
+		esCheck = func(ctx context.Context) error { return s.checkES(ctx, false) }
+	}
+	return map[string]func(context.Context) error{
+		"mysql":         s.checkMySQL,
+		"redis":         s.checkRedis,
+		"elasticsearch": esCheck,
+		"startup":       s.checkStarted,
+	}
+}
+
+func (s *Startup) checkStarted(context.Context) error {
+	if !s.ready.Load() {
+		return errStartupPending
+	}
+	return nil
+}
+
+// Wait 按配置的方式检查全部依赖, 逐项记录结果; 有依赖不可用时返回错误, 应用不再启动
+func (s *Startup) Wait(ctx context.Context) error {
+	if s.mode == startupOff {
+		s.ready.Store(true)
+		return nil
+	}
+	deadline := time.Now().Add(s.timeout)
+	for attempt := 1; ; attempt++ {
+		failed := s.checkAll(ctx, attempt)
+		if len(failed) == 0 {
+			s.ready.Store(true)
+			s.log.Infof("startup: all dependencies are ready")
+			return nil
+		}
+		if s.mode == startupFailFast || time.Now().Add(s.interval).After(deadline) {
+			return fmt.Errorf("startup: dependencies not ready: %s", strings.Join(failed, ", "))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+// checkAll 检查一轮, 返回不可用的依赖; ES可用但 review 索引不存在时创建索引
+func (s *Startup) checkAll(ctx context.Context, attempt int) []string {
+	checks := map[string]func(context.Context) error{
+		"mysql":         s.checkMySQL,
+		"redis":         s.checkRedis,
+		"elasticsearch": func(ctx context.Context) error { return s.checkES(ctx, true) },
+	}
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var failed []string
+	for _, name := range names {
+		if err := checks[name](ctx); err != nil {
+			failed = append(failed, name)
+			s.log.Warnf("startup: %s is not ready (attempt %d): %v", name, attempt, err)
+			continue
+		}
+		s.log.Infof("startup: %s is ready", name)
+	}
+	return failed
+}
+
+func (s *Startup) checkMySQL(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+	defer cancel()
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func (s *Startup) checkRedis(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+	defer cancel()
+	return s.rdb.Ping(ctx).Err()
+}
+
+func (s *Startup) pingES(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+	defer cancel()
+	ok, err := s.es.Ping().Do(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("ping failed")
+	}
+	return nil
+}
+
+// checkES 检查ES可用且 review 索引存在, create 为 true 时创建不存在的索引
+func (s *Startup) checkES(ctx context.Context, create bool) error {
+	ctx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+	defer cancel()
+	exists, err := s.es.Indices.Exists(reviewIndex).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if !create {
+		return fmt.Errorf("index %s does not exist", reviewIndex)
+	}
+	if err := createReviewIndex(ctx, s.es, s.esConf, s.log); err != nil && !isIndexExists(err) {
+		return fmt.Errorf("create index %s: %w", reviewIndex, err)
+	}
+	s.log.Infof("startup: created elasticsearch index %s", reviewIndex)
+	return nil
+}
+
+// isIndexExists 创建索引失败是否因为索引已被其他副本创建
+func isIndexExists(err error) bool {
+	var esErr *types.ElasticsearchError
+	return errors.As(err, &esErr) && esErr.ErrorCause.Type == "resource_already_exists_exception"
+}
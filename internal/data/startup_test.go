@@ -0,0 +1,134 @@
+package data
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const indexExistsBody = `{"error":{"root_cause":[],"type":"resource_already_exists_exception",` +
+	`"reason":"index [review/abc] already exists"},"status":400}`
+
+func TestNewStartup(t *testing.T) {
+	tests := []struct {
+		name         string
+		startup      *conf.Data_Startup
+		wantMode     string
+		wantTimeout  time.Duration
+		wantInterval time.Duration
+		wantErr      bool
+	}{
+		{name: "unset", wantMode: startupOff, wantTimeout: defaultStartupTimeout, wantInterval: defaultStartupInterval},
+		{
+			name:         "configured",
+			startup:      &conf.Data_Startup{Mode: startupWait, Timeout: durationpb.New(time.Second), Interval: durationpb.New(time.Millisecond)},
+			wantMode:     startupWait,
+			wantTimeout:  time.Second,
+			wantInterval: time.Millisecond,
+		},
+		{name: "invalid mode", startup: &conf.Data_Startup{Mode: "eventually"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewStartup(&conf.Data{Startup: tt.startup}, nil, nil, nil, &conf.Elasticsearch{}, log.DefaultLogger)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewStartup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (s.mode != tt.wantMode || s.timeout != tt.wantTimeout || s.interval != tt.wantInterval) {
+				t.Errorf("mode, timeout, interval = %s, %v, %v, want %s, %v, %v", s.mode, s.timeout, s.interval, tt.wantMode, tt.wantTimeout, tt.wantInterval)
+			}
+		})
+	}
+}
+
+func TestStartupCheckES(t *testing.T) {
+	tests := []struct {
+		name        string
+		exists      bool
+		create      bool
+		createBody  string
+		wantErr     bool
+		wantCreates int
+	}{
+		{name: "index exists", exists: true},
+		{name: "missing index is only reported", wantErr: true},
+		{name: "missing index is created", create: true, wantCreates: 1},
+		{name: "created by another replica", create: true, createBody: indexExistsBody, wantCreates: 1},
+		{name: "create fails", create: true, createBody: unknownAnalyzerBody, wantErr: true, wantCreates: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creates := 0
+			es := newTestES(t, func(w http.ResponseWriter, req *http.Request) {
+				switch req.Method {
+				case http.MethodHead:
+					if !tt.exists {
+						w.WriteHeader(http.StatusNotFound)
+					}
+				case http.MethodPut:
+					creates++
+					if tt.createBody != "" {
+						w.WriteHeader(http.StatusBadRequest)
+						io.WriteString(w, tt.createBody)
+						return
+					}
+					io.WriteString(w, `{"acknowledged":true,"shards_acknowledged":true,"index":"review"}`)
+				}
+			})
+			s := &Startup{es: es, esConf: &conf.Elasticsearch{}, log: log.NewHelper(log.DefaultLogger)}
+			if err := s.checkES(context.Background(), tt.create); (err != nil) != tt.wantErr {
+				t.Errorf("checkES() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if creates != tt.wantCreates {
+				t.Errorf("%d create requests, want %d", creates, tt.wantCreates)
+			}
+		})
+	}
+}
+
+func TestStartupWait(t *testing.T) {
+	es := newTestES(t, func(http.ResponseWriter, *http.Request) {})
+	down := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	tests := []struct {
+		name      string
+		mode      string
+		rdb       *redis.Client
+		wantErr   string
+		wantReady bool
+	}{
+		{name: "off", mode: startupOff, rdb: down, wantReady: true},
+		{name: "all ready", mode: startupFailFast, rdb: newMemRedis(t), wantReady: true},
+		{name: "redis down", mode: startupFailFast, rdb: down, wantErr: "dependencies not ready: redis"},
+		{name: "wait gives up at the timeout", mode: startupWait, rdb: down, wantErr: "dependencies not ready: redis"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Startup{
+				db:       newExecDB(t, &execConn{}),
+				rdb:      tt.rdb,
+				es:       es,
+				esConf:   &conf.Elasticsearch{},
+				log:      log.NewHelper(log.DefaultLogger),
+				mode:     tt.mode,
+				timeout:  30 * time.Millisecond,
+				interval: 10 * time.Millisecond,
+			}
+			err := s.Wait(context.Background())
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Wait() error = %v, want %q", err, tt.wantErr)
+			}
+			if ready := s.checkStarted(context.Background()) == nil; ready != tt.wantReady {
+				t.Errorf("ready = %v, want %v", ready, tt.wantReady)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/data/model"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GetReviewerStats 按操作人统计时间范围 [start, end) 内审核日志中的人工审核和申诉处理数
+// 结果包含全部审核员(没有审核记录时计数为0), 以及其他操作人(如管理员); 操作人只按用户ID匹配, 见 aggregateReviewerStats; 结果缓存60秒
+func (r *reviewRepo) GetReviewerStats(ctx context.Context, start, end time.Time) ([]*biz.ReviewerStats, error) {
+	key := fmt.Sprintf("reviewer_stats:%d:%d", start.Unix(), end.Unix())
+	if b, err := r.GetDataFromCache(ctx, key); err == nil {
+		var stats []*biz.ReviewerStats
+		if err := json.Unmarshal(b, &stats); err == nil {
+			return stats, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		cacheUnavailable.Add(1)
+		r.log.WithContext(ctx).Warnf("GetReviewerStats read cache failed, key: %s, err: %v", key, err)
+	}
+
+	var rows []reviewerStatsRow
+	al := r.data.q.ReviewAuditLog
+	err := al.WithContext(ctx).
+		Select(al.OpUser, al.Source, al.ToStatus, al.ID.Count().As("count")).
+		Where(al.Source.In(biz.AuditSourceHuman, biz.AuditSourceAppeal), al.CreateAt.Gte(start), al.CreateAt.Lt(end)).
+		Group(al.OpUser, al.Source, al.ToStatus).
+		Scan(&rows)
+	if err != nil {
+		return nil, err
+	}
+	u := r.data.q.User
+	reviewers, err := u.WithContext(ctx).Where(u.Role.Eq("reviewer")).Find()
+	if err != nil {
+		return nil, err
+	}
+	stats := aggregateReviewerStats(reviewers, rows)
+
+	if b, err := json.Marshal(stats); err == nil {
+		if err := r.SetCache(ctx, key, b); err != nil {
+			cacheUnavailable.Add(1)
+			r.log.WithContext(ctx).Warnf("GetReviewerStats set cache failed, key: %s, err: %v", key, err)
+		}
+	}
+	return stats, nil
+}
+
+// reviewerStatsRow 审核日志按操作人、来源、目标状态分组的计数
+type reviewerStatsRow struct {
+	OpUser   string
+	Source   string
+	ToStatus int32
+	Count    int64
+}
+
+// aggregateReviewerStats 先为每个审核员建立一条记录, 再按操作人累加审核日志中的计数, 结果按操作人排序
+// 人工审核的操作人是登录用户的ID, 只按用户ID归到对应的审核员; 其余操作人(管理员、改为取登录用户之前的历史记录)单独列出
+func aggregateReviewerStats(reviewers []*model.User, rows []reviewerStatsRow) []*biz.ReviewerStats {
+	byOpUser := make(map[string]*biz.ReviewerStats)
+	stats := make([]*biz.ReviewerStats, 0, len(reviewers))
+	for _, u := range reviewers {
+		s := &biz.ReviewerStats{OpUser: strconv.FormatInt(u.ID, 10), UserID: u.ID, Username: u.Username}
+		stats = append(stats, s)
+		byOpUser[s.OpUser] = s
+	}
+	for _, row := range rows {
+		s, ok := byOpUser[row.OpUser]
+		if !ok {
+			s = &biz.ReviewerStats{OpUser: row.OpUser}
+			stats = append(stats, s)
+			byOpUser[row.OpUser] = s
+		}
+		switch {
+		case row.Source == biz.AuditSourceAppeal:
+			s.AppealsHandled += row.Count
+		case row.ToStatus == 20:
+			s.Approved += row.Count
+		case row.ToStatus == 30:
+			s.Rejected += row.Count
+		}
+	}
+	slices.SortFunc(stats, func(a, b *biz.ReviewerStats) int {
+		return strings.Compare(a.OpUser, b.OpUser)
+	})
+	return stats
+}
@@ -0,0 +1,75 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	"review/internal/biz"
+	"review/internal/data/model"
+)
+
+func TestAggregateReviewerStats(t *testing.T) {
+	reviewers := []*model.User{
+		{ID: 7, Username: "alice"},
+		// 用户名恰好是另一个审核员的ID
+		{ID: 8, Username: "7"},
+	}
+	tests := []struct {
+		name string
+		rows []reviewerStatsRow
+		want []*biz.ReviewerStats
+	}{
+		{
+			name: "no audit logs",
+			want: []*biz.ReviewerStats{
+				{OpUser: "7", UserID: 7, Username: "alice"},
+				{OpUser: "8", UserID: 8, Username: "7"},
+			},
+		},
+		{
+			name: "counts matched by user ID only",
+			rows: []reviewerStatsRow{
+				{OpUser: "7", Source: biz.AuditSourceHuman, ToStatus: 20, Count: 3},
+				{OpUser: "7", Source: biz.AuditSourceHuman, ToStatus: 30, Count: 1},
+				{OpUser: "8", Source: biz.AuditSourceAppeal, ToStatus: 20, Count: 2},
+			},
+			want: []*biz.ReviewerStats{
+				{OpUser: "7", UserID: 7, Username: "alice", Approved: 3, Rejected: 1},
+				{OpUser: "8", UserID: 8, Username: "7", AppealsHandled: 2},
+			},
+		},
+		{
+			name: "username is not matched",
+			rows: []reviewerStatsRow{
+				{OpUser: "alice", Source: biz.AuditSourceHuman, ToStatus: 20, Count: 5},
+			},
+			want: []*biz.ReviewerStats{
+				{OpUser: "7", UserID: 7, Username: "alice"},
+				{OpUser: "8", UserID: 8, Username: "7"},
+				{OpUser: "alice", Approved: 5},
+			},
+		},
+		{
+			name: "other operators are listed separately",
+			rows: []reviewerStatsRow{
+				{OpUser: "1", Source: biz.AuditSourceHuman, ToStatus: 30, Count: 4},
+			},
+			want: []*biz.ReviewerStats{
+				{OpUser: "1", Rejected: 4},
+				{OpUser: "7", UserID: 7, Username: "alice"},
+				{OpUser: "8", UserID: 8, Username: "7"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := aggregateReviewerStats(reviewers, tt.rows)
+			if !reflect.DeepEqual(got, tt.want) {
+				for _, s := range got {
+					t.Logf("got %+v", *s)
+				}
+				t.Errorf("aggregateReviewerStats() mismatch")
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"review/internal/biz"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+)
+
+type tokenDenylist struct {
+	data  *Data
+	log   *log.Helper
+	token *biz.TokenConfig
+}
+
+func NewTokenDenylist(data *Data, logger log.Logger, token *biz.TokenConfig) biz.TokenDenylist {
+	return &tokenDenylist{
+		data:  data,
+		log:   log.NewHelper(logger),
+		token: token,
+	}
+}
+
+func tokenRevokedKey(userID int64) string {
+	return "token:revoked:" + strconv.FormatInt(userID, 10)
+}
+
+// RevokeUserTokens records the revocation time. It only needs to outlive the tokens it revokes.
+func (d *tokenDenylist) RevokeUserTokens(ctx context.Context, userID int64) error {
+	if err := d.data.rdb.Set(ctx, tokenRevokedKey(userID), time.Now().Unix(), d.token.MaxTTL()).Err(); err != nil {
+		d.log.WithContext(ctx).Errorf("failed to revoke tokens for user_id: %d, error: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+func (d *tokenDenylist) TokensRevokedAt(ctx context.Context, userID int64) (time.Time, error) {
+	sec, err := d.data.rdb.Get(ctx, tokenRevokedKey(userID)).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, nil
+		}
+		d.log.WithContext(ctx).Errorf("failed to read token revocation for user_id: %d, error: %v", userID, err)
+		return time.Time{}, err
+	}
+	return time.Unix(sec, 0), nil
+}
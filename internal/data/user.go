@@ -16,14 +16,16 @@ import (
 )
 
 type userRepo struct {
-	data *Data
-	log  *log.Helper
+	data  *Data
+	log   *log.Helper
+	token *biz.TokenConfig
 }
 
-func NewUserRepo(data *Data, logger log.Logger) biz.UserRepo {
+func NewUserRepo(data *Data, logger log.Logger, token *biz.TokenConfig) biz.UserRepo {
 	return &userRepo{
-		data: data,
-		log:  log.NewHelper(logger),
+		data:  data,
+		log:   log.NewHelper(logger),
+		token: token,
 	}
 }
 
@@ -63,45 +65,59 @@ func (r *userRepo) Register(ctx context.Context, u *biz.User) error {
 	})
 }
 
-func (r *userRepo) Login(ctx context.Context, username, password string) (string, error) {
+func (r *userRepo) Login(ctx context.Context, username, password string) (*biz.LoginResult, error) {
 	dbUser, err := r.data.q.WithContext(ctx).User.Where(r.data.q.User.Username.Eq(username)).First()
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", errors.New("user not found")
+			return nil, errors.New("user not found")
 		}
 		r.log.WithContext(ctx).Errorf("failed to find user: %v", err)
-		return "", err
+		return nil, err
 	}
 	if err := bcrypt.CompareHashAndPassword([]byte(dbUser.PasswordHash), []byte(password)); err != nil {
-		return "", errors.New("invalid password")
+		return nil, errors.New("invalid password")
 	}
 
+	now := time.Now()
+	res := &biz.LoginResult{
+		UserID:    dbUser.ID,
+		Role:      dbUser.Role,
+		ExpiresAt: now.Add(r.token.TTL(dbUser.Role)).Truncate(time.Second),
+	}
 	claims := jwt.MapClaims{
 		"user_id":  dbUser.ID,
 		"username": dbUser.Username,
 		"role":     dbUser.Role,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(),
+		"iss":      r.token.Issuer,
+		"aud":      r.token.Audience,
+		"iat":      now.Unix(),
+		"exp":      res.ExpiresAt.Unix(),
 	}
 
-	// If the user is a merchant, find their store_id and add it to the claims
+	// If the user is a merchant, find their store_id and add it to the claims.
+	// A merchant without a store is an inconsistent data state, so refuse to issue a token
+	// instead of letting every store-scoped call fail later with a confusing Forbidden.
 	if dbUser.Role == "merchant" {
 		store, err := r.data.q.WithContext(ctx).Store.Where(r.data.q.Store.UserID.Eq(dbUser.ID)).First()
 		if err != nil {
-			// If store not found, it's an inconsistent data state, but we can choose to proceed without store_id
-			r.log.WithContext(ctx).Warnf("could not find store for merchant user_id: %d, error: %v", dbUser.ID, err)
-		} else {
-			claims["store_id"] = store.StoreID
+			r.log.WithContext(ctx).Errorf("could not find store for merchant user_id: %d, error: %v", dbUser.ID, err)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, biz.ErrMerchantStoreMissing
+			}
+			return nil, err
 		}
+		claims["store_id"] = store.StoreID
+		res.StoreID = store.StoreID
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	// In a real application, the secret key should be loaded from config.
-	signedToken, err := token.SignedString([]byte("your-secret-key"))
+	signedToken, err := token.SignedString(r.token.Secret)
 	if err != nil {
 		r.log.WithContext(ctx).Errorf("failed to sign token: %v", err)
-		return "", err
+		return nil, err
 	}
-	return signedToken, nil
+	res.Token = signedToken
+	return res, nil
 }
 
 func (r *userRepo) GetUserInfo(ctx context.Context, id int64) (*biz.User, error) {
@@ -122,18 +138,22 @@ func (r *userRepo) GetUserInfo(ctx context.Context, id int64) (*biz.User, error)
 	}, nil
 }
 
-func (r *userRepo) UpdateUserInfo(ctx context.Context, u *biz.User) error {
-	result, err := r.data.q.WithContext(ctx).User.Where(r.data.q.User.ID.Eq(u.ID)).Updates(
-		&model.User{
-			Username: u.Username,
-			Email:    u.Email,
-		},
-	)
+// UpdateUserInfo updates only the columns set in u.
+// A map is used instead of a model so that zero values are written as well.
+func (r *userRepo) UpdateUserInfo(ctx context.Context, u *biz.UserUpdate) error {
+	result, err := r.data.q.WithContext(ctx).User.Where(r.data.q.User.ID.Eq(u.ID)).Updates(u.Fields())
 	if err != nil {
 		return err
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("user not found or no changes made")
+		// MySQL reports 0 affected rows when the values are unchanged, so check whether the user exists.
+		n, err := r.data.q.WithContext(ctx).User.Where(r.data.q.User.ID.Eq(u.ID)).Count()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errors.New("user not found")
+		}
 	}
 	return nil
 }
@@ -174,3 +194,44 @@ func (r *userRepo) GetUserList(ctx context.Context, offset, limit int32) ([]*biz
 
 	return bizUsers, total, nil
 }
+
+func (r *userRepo) SetUserRole(ctx context.Context, id int64, role string) error {
+	return r.data.q.Transaction(func(tx *query.Query) error {
+		dbUser, err := tx.User.WithContext(ctx).Where(tx.User.ID.Eq(id)).First()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return err
+		}
+		if dbUser.Role == role {
+			return nil
+		}
+		if _, err := tx.User.WithContext(ctx).Where(tx.User.ID.Eq(id)).Update(tx.User.Role, role); err != nil {
+			return err
+		}
+
+		// Keep the store in step with the role: every merchant has exactly one store, and nobody else has one.
+		if role == "merchant" {
+			count, err := tx.Store.WithContext(ctx).Where(tx.Store.UserID.Eq(id)).Count()
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				store := &model.Store{
+					StoreID: snowflake.GenID(),
+					UserID:  id,
+					Name:    dbUser.Username + "'s Store", // Default store name
+				}
+				if err := tx.Store.WithContext(ctx).Create(store); err != nil {
+					return err
+				}
+			}
+		} else if dbUser.Role == "merchant" {
+			if _, err := tx.Store.WithContext(ctx).Where(tx.Store.UserID.Eq(id)).Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/conf"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginResultMatchesClaims(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := biz.NewTokenConfig(&conf.Auth{JwtSecret: "test-secret", Issuer: "review", Audience: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := func(role string) *resultRows {
+		return &resultRows{
+			columns: []string{"id", "username", "password_hash", "role"},
+			values:  [][]driver.Value{{int64(1834567890123456789), "alice", string(hash), role}},
+		}
+	}
+	tests := []struct {
+		name        string
+		results     []*resultRows
+		wantStoreID int64
+	}{
+		{name: "customer", results: []*resultRows{user("customer")}},
+		{name: "merchant", results: []*resultRows{user("merchant"), {
+			columns: []string{"store_id", "user_id"},
+			values:  [][]driver.Value{{int64(1834567890123456790), int64(1834567890123456789)}},
+		}}, wantStoreID: 1834567890123456790},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &userRepo{data: &Data{q: newExecQuery(t, &execConn{results: tt.results})}, token: token}
+			res, err := r.Login(context.Background(), "alice", "secret")
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			claims := jwt.MapClaims{}
+			if _, err := jwt.ParseWithClaims(res.Token, claims, func(*jwt.Token) (interface{}, error) { return token.Secret, nil },
+				jwt.WithJSONNumber(), jwt.WithIssuer("review"), jwt.WithAudience("web")); err != nil {
+				t.Fatalf("issued token does not verify: %v", err)
+			}
+			claim := func(key string) int64 {
+				n, ok := claims[key].(json.Number)
+				if !ok {
+					return 0
+				}
+				v, _ := n.Int64()
+				return v
+			}
+			if res.UserID != claim("user_id") {
+				t.Errorf("UserID = %d, want the user_id claim %v", res.UserID, claims["user_id"])
+			}
+			if res.Role != claims["role"] {
+				t.Errorf("Role = %q, want the role claim %v", res.Role, claims["role"])
+			}
+			if res.StoreID != claim("store_id") || res.StoreID != tt.wantStoreID {
+				t.Errorf("StoreID = %d, want the store_id claim %v (%d)", res.StoreID, claims["store_id"], tt.wantStoreID)
+			}
+			if !res.ExpiresAt.Equal(time.Unix(claim("exp"), 0)) {
+				t.Errorf("ExpiresAt = %v, want the exp claim %v", res.ExpiresAt, claims["exp"])
+			}
+		})
+	}
+}
+
+func TestUpdateUserInfo(t *testing.T) {
+	email, username := "new@example.com", "alice"
+	tests := []struct {
+		name         string
+		update       *biz.UserUpdate
+		rowsAffected int64
+		counts       []int64
+		wantSet      []string
+		wantUnset    []string
+		wantErr      bool
+	}{
+		{name: "email only", update: &biz.UserUpdate{ID: 1, Email: &email}, rowsAffected: 1, wantSet: []string{"`email`"}, wantUnset: []string{"`username`"}},
+		{name: "username only", update: &biz.UserUpdate{ID: 1, Username: &username}, rowsAffected: 1, wantSet: []string{"`username`"}, wantUnset: []string{"`email`"}},
+		{name: "both", update: &biz.UserUpdate{ID: 1, Username: &username, Email: &email}, rowsAffected: 1, wantSet: []string{"`username`", "`email`"}},
+		{name: "unchanged values of an existing user", update: &biz.UserUpdate{ID: 1, Email: &email}, counts: []int64{1}, wantSet: []string{"`email`"}},
+		{name: "missing user", update: &biz.UserUpdate{ID: 2, Email: &email}, counts: []int64{0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &execConn{rowsAffected: tt.rowsAffected, counts: tt.counts}
+			r := &userRepo{data: &Data{q: newExecQuery(t, conn)}}
+			err := r.UpdateUserInfo(context.Background(), tt.update)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateUserInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			update := conn.stmts[0]
+			set := update[strings.Index(update, " SET ")+len(" SET ") : strings.Index(update, " WHERE ")]
+			for _, col := range tt.wantSet {
+				if !strings.Contains(set, col) {
+					t.Errorf("UPDATE sets %s, want it to set %s", set, col)
+				}
+			}
+			for _, col := range tt.wantUnset {
+				if strings.Contains(set, col) {
+					t.Errorf("UPDATE sets %s, want it to leave %s unchanged", set, col)
+				}
+			}
+		})
+	}
+}
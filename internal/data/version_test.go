@@ -0,0 +1,167 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"review/internal/biz"
+	"review/internal/data/model"
+	"review/internal/data/query"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// execConn is a database/sql connection that records statements and reports rowsAffected for each.
+// Queries answer with the next value of counts as a single-column row, e.g. for COUNT(*).
+// The column is named column, or "count" when it is empty.
+// Once results is set, queries answer with its result sets in order instead.
+type execConn struct {
+	rowsAffected int64
+	counts       []int64
+	column       string
+	results      []*resultRows
+	stmts        []string
+	args         [][]driver.NamedValue
+}
+
+func (c *execConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *execConn) Driver() driver.Driver                        { return nil }
+func (c *execConn) Prepare(string) (driver.Stmt, error)          { return nil, errors.New("not supported") }
+func (c *execConn) Close() error                                 { return nil }
+func (c *execConn) Begin() (driver.Tx, error)                    { return c, nil }
+func (c *execConn) Commit() error                                { return nil }
+func (c *execConn) Rollback() error                              { return nil }
+
+func (c *execConn) ExecContext(_ context.Context, stmt string, args []driver.NamedValue) (driver.Result, error) {
+	c.stmts = append(c.stmts, stmt)
+	c.args = append(c.args, args)
+	return execResult(c.rowsAffected), nil
+}
+
+// execResult reports the rows affected by a statement; inserts get no generated ID.
+type execResult int64
+
+func (r execResult) LastInsertId() (int64, error) { return 0, nil }
+func (r execResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func (c *execConn) QueryContext(_ context.Context, stmt string, args []driver.NamedValue) (driver.Rows, error) {
+	c.stmts = append(c.stmts, stmt)
+	c.args = append(c.args, args)
+	if c.results != nil {
+		if len(c.results) == 0 {
+			return nil, errors.New("unexpected query: " + stmt)
+		}
+		rows := c.results[0]
+		c.results = c.results[1:]
+		return rows, nil
+	}
+	if len(c.counts) == 0 {
+		return nil, errors.New("unexpected query: " + stmt)
+	}
+	rows := &countRows{n: c.counts[0], column: c.column}
+	c.counts = c.counts[1:]
+	return rows, nil
+}
+
+// countRows is a result with a single row holding n.
+type countRows struct {
+	n      int64
+	column string
+	done   bool
+}
+
+func (r *countRows) Columns() []string {
+	if r.column == "" {
+		return []string{"count"}
+	}
+	return []string{r.column}
+}
+
+func (r *countRows) Close() error { return nil }
+
+func (r *countRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.n
+	return nil
+}
+
+// resultRows is a result set with the given columns and rows.
+type resultRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *resultRows) Columns() []string { return r.columns }
+func (r *resultRows) Close() error      { return nil }
+
+func (r *resultRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// newExecDB returns a gorm DB bound to conn through the MySQL dialect.
+func newExecDB(t *testing.T, conn *execConn) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sql.OpenDB(conn), SkipInitializeWithVersion: true}),
+		&gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// newExecQuery returns a gorm query bound to conn.
+func newExecQuery(t *testing.T, conn *execConn) *query.Query {
+	return query.Use(newExecDB(t, conn))
+}
+
+func TestUpdateReviewVersioned(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      error
+	}{
+		{name: "version matches", rowsAffected: 1},
+		{name: "modified since read", rowsAffected: 0, wantErr: biz.ErrReviewConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &execConn{rowsAffected: tt.rowsAffected}
+			review := &model.ReviewInfo{ReviewID: 42, Version: 3}
+			err := updateReviewVersioned(context.Background(), newExecQuery(t, conn), review, map[string]interface{}{"has_reply": 1})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("updateReviewVersioned() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(conn.stmts) != 1 {
+				t.Fatalf("statements = %q, want one UPDATE", conn.stmts)
+			}
+			stmt := conn.stmts[0]
+			if !strings.Contains(stmt, "`version`=version + 1") || !strings.Contains(stmt, "`review_info`.`version` = ?") {
+				t.Errorf("UPDATE = %s, want it to bump the version and match the version read", stmt)
+			}
+			var matched bool
+			for _, arg := range conn.args[0] {
+				if arg.Value == int64(3) {
+					matched = true
+				}
+			}
+			if !matched {
+				t.Errorf("UPDATE args = %v, want the version read (3)", conn.args[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package data
+
+import (
+	"container/heap"
+	"expvar"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+const (
+	defaultAsyncWorkers   = 8
+	defaultAsyncQueueSize = 1000
+	defaultAsyncTimeout   = time.Minute
+)
+
+// 审核与ES同步的先后顺序
+const (
+	asyncOrderAuditFirst = "audit_first"
+	asyncOrderSyncFirst  = "sync_first"
+)
+
+// 异步任务池指标, 通过 /debug/vars 暴露
+var (
+	asyncQueueDepth = expvar.NewInt("review_async_queue_depth")
+	asyncDropped    = expvar.NewInt("review_async_tasks_dropped")
+	asyncPanics     = expvar.NewInt("review_async_tasks_panicked")
+)
+
+// recoverPanic 在后台 goroutine 中 defer 调用, 捕获 panic 并记录堆栈, 避免一个任务的 panic 导致整个进程退出
+// what 描述发生 panic 的任务, 用于排查
+func recoverPanic(logger *log.Helper, what string) {
+	if p := recover(); p != nil {
+		asyncPanics.Add(1)
+		logger.Errorf("%s panicked: %v\n%s", what, p, debug.Stack())
+	}
+}
+
+// task 排队中的异步任务
+type task struct {
+	name     string
+	priority int
+	// seq 提交顺序, 同优先级的任务先提交先执行
+	seq uint64
+	run func()
+}
+
+// taskQueue 按优先级从高到低、同优先级按提交顺序排列的任务堆, 实现 heap.Interface
+type taskQueue []*task
+
+func (q taskQueue) Len() int { return len(q) }
+func (q taskQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+func (q taskQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *taskQueue) Push(x any)   { *q = append(*q, x.(*task)) }
+func (q *taskQueue) Pop() any {
+	old := *q
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return t
+}
+
+// taskPool 有界的异步任务池
+// 固定数量的worker消费有界的优先级队列, 限制同时调用Gemini和ES的并发数; 积压时优先级高的任务先执行,
+// 同优先级按提交顺序执行; 队列满时丢弃新任务并记录错误
+type taskPool struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	queue    taskQueue
+	capacity int
+	seq      uint64
+	closed   bool
+
+	wg  sync.WaitGroup
+	log *log.Helper
+}
+
+func newTaskPool(c *conf.Data_Async, logger log.Logger) *taskPool {
+	workers, queueSize := defaultAsyncWorkers, defaultAsyncQueueSize
+	if c.GetWorkers() > 0 {
+		workers = int(c.GetWorkers())
+	}
+	if c.GetQueueSize() > 0 {
+		queueSize = int(c.GetQueueSize())
+	}
+	p := &taskPool{
+		capacity: queueSize,
+		log:      log.NewHelper(logger),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.run()
+	}
+	return p
+}
+
+func (p *taskPool) run() {
+	defer p.wg.Done()
+	for {
+		p.mu.Lock()
+		for len(p.queue) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		// 关闭后仍执行完已排队的任务
+		if len(p.queue) == 0 {
+			p.mu.Unlock()
+			return
+		}
+		t := heap.Pop(&p.queue).(*task)
+		p.mu.Unlock()
+		asyncQueueDepth.Add(-1)
+		p.exec(t)
+	}
+}
+
+// exec 执行一个任务, 任务 panic 时只记录日志, worker继续处理后续任务
+func (p *taskPool) exec(t *task) {
+	defer recoverPanic(p.log, "async task "+t.name)
+	t.run()
+}
+
+// Submit 以默认优先级0提交任务, 不阻塞调用方; 队列已满时丢弃任务并返回false
+func (p *taskPool) Submit(name string, run func()) bool {
+	return p.SubmitPriority(name, 0, run)
+}
+
+// SubmitPriority 按优先级提交任务, 数值越大越先执行; 队列已满或任务池已关闭时丢弃任务并返回false
+func (p *taskPool) SubmitPriority(name string, priority int, run func()) bool {
+	p.mu.Lock()
+	if p.closed || len(p.queue) >= p.capacity {
+		closed := p.closed
+		p.mu.Unlock()
+		asyncDropped.Add(1)
+		if closed {
+			p.log.Errorf("async task pool is closed, dropping task: %s", name)
+		} else {
+			p.log.Errorf("async task queue is full (%d), dropping task: %s", p.capacity, name)
+		}
+		return false
+	}
+	p.seq++
+	heap.Push(&p.queue, &task{name: name, priority: priority, seq: p.seq, run: run})
+	p.mu.Unlock()
+	asyncQueueDepth.Add(1)
+	p.cond.Signal()
+	return true
+}
+
+// Close 停止接收新任务, 并等待已排队的任务执行完
+func (p *taskPool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	p.cond.Broadcast()
+	p.wg.Wait()
+}
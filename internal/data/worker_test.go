@@ -0,0 +1,147 @@
+package data
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"review/internal/biz"
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// blockWorker submits a task that holds the pool's only worker until the returned release is called.
+func blockWorker(t *testing.T, p *taskPool) (release func()) {
+	t.Helper()
+	started, done := make(chan struct{}), make(chan struct{})
+	if !p.Submit("block", func() {
+		close(started)
+		<-done
+	}) {
+		t.Fatal("Submit(block) = false")
+	}
+	<-started
+	return func() { close(done) }
+}
+
+func TestTaskPoolBounded(t *testing.T) {
+	p := newTaskPool(&conf.Data_Async{Workers: 1, QueueSize: 1}, log.DefaultLogger)
+	release := blockWorker(t, p)
+
+	var ran atomic.Int32
+	if !p.Submit("queued", func() { ran.Add(1) }) {
+		t.Fatal("Submit() with room in the queue = false")
+	}
+	if p.Submit("overflow", func() { ran.Add(1) }) {
+		t.Error("Submit() on a full queue = true, want the task dropped")
+	}
+	release()
+	p.Close()
+
+	if got := ran.Load(); got != 1 {
+		t.Errorf("ran %d tasks, want 1", got)
+	}
+	if p.Submit("after close", func() {}) {
+		t.Error("Submit() after Close = true, want the task dropped")
+	}
+}
+
+func TestTaskPoolPriority(t *testing.T) {
+	p := newTaskPool(&conf.Data_Async{Workers: 1}, log.DefaultLogger)
+	release := blockWorker(t, p)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+		}
+	}
+	// While the worker is busy the tasks queue up; they then run by priority, in submission order within one.
+	p.Submit("low 1", record("low 1"))
+	p.Submit("low 2", record("low 2"))
+	p.SubmitPriority("high", 10, record("high"))
+	p.SubmitPriority("medium", 5, record("medium"))
+	release()
+	p.Close()
+
+	if want := []string{"high", "medium", "low 1", "low 2"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("run order = %v, want %v", order, want)
+	}
+}
+
+func TestAuditPriority(t *testing.T) {
+	rules := &conf.Data_Async_Priority{TrustedUser: 1, Stores: map[int64]int32{11: 5}, Resubmission: 2}
+	tests := []struct {
+		name   string
+		rules  *conf.Data_Async_Priority
+		review *model.ReviewInfo
+		counts []int64
+		want   int
+	}{
+		{name: "no rules", review: &model.ReviewInfo{StoreID: 11}, want: 0},
+		{name: "untrusted user", rules: rules, review: &model.ReviewInfo{StoreID: 12}, counts: []int64{0, 1}, want: 0},
+		{name: "flagged user", rules: rules, review: &model.ReviewInfo{StoreID: 12}, counts: []int64{1}, want: 0},
+		{name: "trusted user", rules: rules, review: &model.ReviewInfo{StoreID: 12}, counts: []int64{0, 3}, want: 1},
+		{
+			name:   "rules add up",
+			rules:  rules,
+			review: &model.ReviewInfo{StoreID: 11, ExtJSON: biz.WithResubmitCount("", 1)},
+			counts: []int64{0, 3},
+			want:   8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &execConn{counts: tt.counts}
+			r := &reviewRepo{
+				data:     &Data{q: newExecQuery(t, conn), priority: tt.rules},
+				log:      log.NewHelper(log.DefaultLogger),
+				fastPath: &conf.Review_TrustedFastPath{MinApproved: 3},
+			}
+			if got := r.auditPriority(context.Background(), tt.review); got != tt.want {
+				t.Errorf("auditPriority() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskPoolRecoversPanic(t *testing.T) {
+	p := newTaskPool(&conf.Data_Async{Workers: 1}, log.DefaultLogger)
+	before := asyncPanics.Value()
+
+	var ran atomic.Int32
+	p.Submit("panics", func() { panic("bad model output") })
+	p.Submit("after the panic", func() { ran.Add(1) })
+	p.Close()
+
+	if ran.Load() != 1 {
+		t.Error("the task after a panic did not run, want the worker to keep going")
+	}
+	if got := asyncPanics.Value() - before; got != 1 {
+		t.Errorf("asyncPanics increased by %d, want 1", got)
+	}
+}
+
+func TestRecoverAudit(t *testing.T) {
+	// The review was audited before the panic, so it is left as it is.
+	conn := &execConn{counts: []int64{20}, column: "status"}
+	r := &reviewRepo{data: &Data{q: newExecQuery(t, conn)}, log: log.NewHelper(log.DefaultLogger)}
+	before := asyncPanics.Value()
+	func() {
+		defer r.recoverAudit(context.Background(), &model.ReviewInfo{ReviewID: 9})
+		panic("nil pointer")
+	}()
+	if got := asyncPanics.Value() - before; got != 1 {
+		t.Errorf("asyncPanics increased by %d, want 1", got)
+	}
+	if len(conn.stmts) != 1 {
+		t.Errorf("statements = %q, want only the status read", conn.stmts)
+	}
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"context"
+	"expvar"
+
+	"review/internal/biz"
+
+	kratoshttp "github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// operationDebugVars is the operation name of the metrics endpoint, which goes through the server middleware (JWT auth etc.).
+const operationDebugVars = "/debug/vars"
+
+// debugVars serves the expvar metrics (cmdline, memstats and the internal counters) to admins only.
+func debugVars(ctx kratoshttp.Context) error {
+	kratoshttp.SetOperation(ctx, operationDebugVars)
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return nil, biz.RequireAdmin(c)
+	})
+	if _, err := h(ctx, nil); err != nil {
+		return err
+	}
+	expvar.Handler().ServeHTTP(ctx.Response(), ctx.Request())
+	return nil
+}
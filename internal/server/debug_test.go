@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"review/internal/biz"
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/middleware/auth/jwt"
+	kratoshttp "github.com/go-kratos/kratos/v2/transport/http"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// noRevocations is a denylist with no revoked tokens.
+type noRevocations struct{ biz.TokenDenylist }
+
+func (noRevocations) TokensRevokedAt(context.Context, int64) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func TestDebugVarsRequiresAdmin(t *testing.T) {
+	token, err := biz.NewTokenConfig(&conf.Auth{JwtSecret: "test-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(role string) string {
+		s, err := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, jwtv5.MapClaims{
+			"user_id": 1, "role": role, "iss": token.Issuer, "aud": token.Audience,
+			"iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString(token.Secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + s
+	}
+	srv := kratoshttp.NewServer(kratoshttp.Middleware(
+		jwtAuthFilter(jwt.Server(token.Keyfunc, jwt.WithClaims(NewClaimsFactory)), noRevocations{}, nil),
+	))
+	srv.Route("/").GET("/debug/vars", debugVars)
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantCode      int
+	}{
+		{name: "anonymous", wantCode: http.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer not-a-token", wantCode: http.StatusUnauthorized},
+		{name: "reviewer", authorization: sign("reviewer"), wantCode: http.StatusForbidden},
+		{name: "admin", authorization: sign("admin"), wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if leaked := strings.Contains(rec.Body.String(), "memstats"); leaked != (tt.wantCode == http.StatusOK) {
+				t.Errorf("body contains memstats = %v, want %v", leaked, tt.wantCode == http.StatusOK)
+			}
+		})
+	}
+}
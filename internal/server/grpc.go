@@ -9,7 +9,6 @@ import (
 
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/middleware/recovery"
-	"github.com/go-kratos/kratos/v2/middleware/validate"
 	"github.com/go-kratos/kratos/v2/transport/grpc"
 )
 
@@ -18,7 +17,7 @@ func NewGRPCServer(c *conf.Server, review *service.ReviewService, agent *service
 	var opts = []grpc.ServerOption{
 		grpc.Middleware(
 			recovery.Recovery(),
-			validate.Validator(),
+			fieldValidator(),
 		),
 	}
 	if c.Grpc.Network != "" {
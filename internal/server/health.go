@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"review/internal/client/ai"
+	"review/internal/data"
+)
+
+// readinessCheck reports whether a dependency is ready to serve traffic.
+type readinessCheck func(ctx context.Context) error
+
+// readyzHandler serves /readyz: 200 when every check passes, 503 otherwise, with per-check results.
+func readyzHandler(checks map[string]readinessCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, code := "ok", http.StatusOK
+		results := make(map[string]string, len(checks))
+		for name, check := range checks {
+			if err := check(r.Context()); err != nil {
+				results[name] = err.Error()
+				status, code = "unavailable", http.StatusServiceUnavailable
+				continue
+			}
+			results[name] = "ok"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": status, "checks": results})
+	}
+}
+
+// readinessChecks are the dependencies probed by /readyz.
+// Until the startup checks have passed, the "startup" check reports unavailable.
+func readinessChecks(aiClient *ai.AIClient, startup *data.Startup) map[string]readinessCheck {
+	checks := map[string]readinessCheck{
+		"ai": aiClient.Health,
+	}
+	for name, check := range startup.Checks() {
+		checks[name] = check
+	}
+	return checks
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestReadyzHandler(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("API key not valid") }
+	tests := []struct {
+		name       string
+		checks     map[string]readinessCheck
+		wantCode   int
+		wantStatus string
+		wantChecks map[string]string
+	}{
+		{
+			name:       "ready",
+			checks:     map[string]readinessCheck{"ai": ok, "es": ok},
+			wantCode:   http.StatusOK,
+			wantStatus: "ok",
+			wantChecks: map[string]string{"ai": "ok", "es": "ok"},
+		},
+		{
+			name:       "ai unhealthy",
+			checks:     map[string]readinessCheck{"ai": down, "es": ok},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: "unavailable",
+			wantChecks: map[string]string{"ai": "API key not valid", "es": "ok"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			readyzHandler(tt.checks)(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			var body struct {
+				Status string            `json:"status"`
+				Checks map[string]string `json:"checks"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Status != tt.wantStatus || !reflect.DeepEqual(body.Checks, tt.wantChecks) {
+				t.Errorf("body = %+v, want status %q checks %v", body, tt.wantStatus, tt.wantChecks)
+			}
+		})
+	}
+}
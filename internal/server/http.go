@@ -2,13 +2,14 @@ package server
 
 import (
 	"context"
-	"net/http"
-	"strings"
 
 	ai_v1 "review/api/ai/v1"
 	v1 "review/api/review/v1"
 	user_v1 "review/api/user/v1"
+	"review/internal/biz"
+	"review/internal/client/ai"
 	"review/internal/conf"
+	"review/internal/data"
 	"review/internal/service"
 
 	"github.com/go-kratos-ecosystem/components/v2/middleware/cors"
@@ -17,7 +18,6 @@ import (
 	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/middleware/auth/jwt"
 	"github.com/go-kratos/kratos/v2/middleware/recovery"
-	"github.com/go-kratos/kratos/v2/middleware/validate"
 	"github.com/go-kratos/kratos/v2/transport"
 	kratoshttp "github.com/go-kratos/kratos/v2/transport/http"
 	jwtv5 "github.com/golang-jwt/jwt/v5"
@@ -25,7 +25,7 @@ import (
 )
 
 // jwtAuthFilter creates a middleware that selectively applies JWT authentication.
-func jwtAuthFilter(jwt middleware.Middleware) middleware.Middleware {
+func jwtAuthFilter(jwt middleware.Middleware, denylist biz.TokenDenylist, static []*conf.Server_Static_Mount) middleware.Middleware {
 	return func(handler middleware.Handler) middleware.Handler {
 		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
 			// Whitelist for API routes that do not require JWT authentication.
@@ -33,12 +33,16 @@ func jwtAuthFilter(jwt middleware.Middleware) middleware.Middleware {
 				"/api.user.v1.User/Login":    true,
 				"/api.user.v1.User/Register": true,
 			}
+			// Routes open to anonymous callers that still read the user from a token when one is sent.
+			optionalAuth := map[string]bool{
+				"/api.ai.v1.AgentService/ListTools":       true,
+				"/api.review.v1.Review/ListRecentReviews": true,
+			}
 
 			if tr, ok := transport.FromServerContext(ctx); ok {
 				// Check for static file paths via HTTP transporter
 				if httpTr, ok := tr.(kratoshttp.Transporter); ok {
-					path := httpTr.Request().URL.Path
-					if strings.HasPrefix(path, "/user/") || strings.HasPrefix(path, "/agent/") || strings.HasPrefix(path, "/dashboard/") {
+					if isStaticPath(static, httpTr.Request().URL.Path) {
 						return handler(ctx, req) // Skip JWT for static files
 					}
 				}
@@ -48,10 +52,26 @@ func jwtAuthFilter(jwt middleware.Middleware) middleware.Middleware {
 				if _, ok := whitelist[operation]; ok {
 					return handler(ctx, req) // Skip JWT for whitelisted API routes
 				}
+				if optionalAuth[operation] && tr.RequestHeader().Get("Authorization") == "" {
+					return handler(ctx, req) // Anonymous caller, handled as public
+				}
 			}
 
-			// For all other routes, apply the JWT middleware.
-			return jwt(handler)(ctx, req)
+			// For all other routes, apply the JWT middleware, then reject revoked tokens.
+			return jwt(denylistCheck(denylist)(handler))(ctx, req)
+		}
+	}
+}
+
+// denylistCheck rejects tokens revoked after they were issued, e.g. by a role change.
+// It must run inside the JWT middleware so the claims are in the context.
+func denylistCheck(denylist biz.TokenDenylist) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			if err := biz.CheckTokenRevoked(ctx, denylist); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
 		}
 	}
 }
@@ -62,18 +82,14 @@ func NewClaimsFactory() jwtv5.Claims {
 }
 
 // NewHTTPServer new an HTTP server.
-func NewHTTPServer(c *conf.Server, review *service.ReviewService, agent *service.AgentService, user *service.UserService, logger log.Logger) *kratoshttp.Server {
+func NewHTTPServer(c *conf.Server, token *biz.TokenConfig, denylist biz.TokenDenylist, aiClient *ai.AIClient, startup *data.Startup, review *service.ReviewService, agent *service.AgentService, user *service.UserService, logger log.Logger) (*kratoshttp.Server, error) {
 	json.MarshalOptions = protojson.MarshalOptions{
 		EmitUnpopulated: true,
 	}
 
-	jwtSecretKey := "your-secret-key"
-
 	// Create the core JWT middleware instance.
 	jwtAuth := jwt.Server(
-		func(token *jwtv5.Token) (interface{}, error) {
-			return []byte(jwtSecretKey), nil
-		},
+		token.Keyfunc,
 		jwt.WithClaims(NewClaimsFactory),
 	)
 
@@ -85,9 +101,9 @@ func NewHTTPServer(c *conf.Server, review *service.ReviewService, agent *service
 				cors.AllowedMethods("GET", "POST", "PUT", "DELETE", "OPTIONS"),
 				cors.AllowedHeaders("Content-Type", "Authorization"),
 			),
-			validate.Validator(),
+			fieldValidator(),
 			// Apply our custom filter middleware, which wraps the JWT middleware.
-			jwtAuthFilter(jwtAuth),
+			jwtAuthFilter(jwtAuth, denylist, staticMounts(c.Static)),
 		),
 	}
 	if c.Http.Network != "" {
@@ -104,11 +120,18 @@ func NewHTTPServer(c *conf.Server, review *service.ReviewService, agent *service
 	v1.RegisterReviewHTTPServer(srv, review)
 	ai_v1.RegisterAgentServiceHTTPServer(srv, agent)
 	user_v1.RegisterUserHTTPServer(srv, user)
+	// Streaming audit log export (CSV/NDJSON), outside the generated routes so rows are written as they are read
+	srv.Route("/").GET("/o/v1/audit-logs/export", review.ExportAuditLogs)
 
-	// Static file serving for frontend pages
-	srv.HandlePrefix("/user/", http.StripPrefix("/user/", http.FileServer(http.Dir("../../frontend/user"))))
-	srv.HandlePrefix("/agent/", http.StripPrefix("/agent/", http.FileServer(http.Dir("../../frontend/agent"))))
-	srv.HandlePrefix("/dashboard/", http.StripPrefix("/dashboard/", http.FileServer(http.Dir("../../frontend/dashboard"))))
+	// Runtime metrics (expvar), e.g. async task queue depth; admins only
+	srv.Route("/").GET("/debug/vars", debugVars)
+	// Readiness probe, e.g. detects a misconfigured GEMINI_API_KEY or a missing review index before traffic hits them
+	srv.HandleFunc("/readyz", readyzHandler(readinessChecks(aiClient, startup)))
+
+	// Static file serving for frontend pages; a bad mount fails startup instead of serving 404s
+	if err := registerStatic(srv, c.Static); err != nil {
+		return nil, err
+	}
 
-	return srv
+	return srv, nil
 }
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+
+	"review/frontend"
+	"review/internal/conf"
+
+	kratoshttp "github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// defaultStaticMounts keeps the historical layout, relative to cmd/review.
+var defaultStaticMounts = []*conf.Server_Static_Mount{
+	{Prefix: "/user/", Dir: "../../frontend/user"},
+	{Prefix: "/agent/", Dir: "../../frontend/agent"},
+	{Prefix: "/dashboard/", Dir: "../../frontend/dashboard"},
+}
+
+// defaultEmbeddedMounts is the same layout inside the embedded frontend FS.
+var defaultEmbeddedMounts = []*conf.Server_Static_Mount{
+	{Prefix: "/user/", Dir: "user"},
+	{Prefix: "/agent/", Dir: "agent"},
+	{Prefix: "/dashboard/", Dir: "dashboard"},
+}
+
+// staticMounts returns the effective static mounts for the given config.
+func staticMounts(c *conf.Server_Static) []*conf.Server_Static_Mount {
+	if c.GetDisabled() {
+		return nil
+	}
+	if len(c.GetMounts()) > 0 {
+		return c.GetMounts()
+	}
+	if c.GetEmbedded() {
+		return defaultEmbeddedMounts
+	}
+	return defaultStaticMounts
+}
+
+// registerStatic mounts the frontend pages on the HTTP server. It returns an error for an invalid prefix,
+// a configured directory that does not exist, or a directory missing from the embedded FS.
+func registerStatic(srv *kratoshttp.Server, c *conf.Server_Static) error {
+	for _, m := range staticMounts(c) {
+		if !strings.HasPrefix(m.Prefix, "/") || !strings.HasSuffix(m.Prefix, "/") {
+			return fmt.Errorf("static mount prefix %q must start and end with '/'", m.Prefix)
+		}
+		var root http.FileSystem
+		if c.GetEmbedded() {
+			if _, err := fs.Stat(frontend.FS, m.Dir); err != nil {
+				return fmt.Errorf("static mount %q: %w", m.Prefix, err)
+			}
+			sub, err := fs.Sub(frontend.FS, m.Dir)
+			if err != nil {
+				return fmt.Errorf("static mount %q: %w", m.Prefix, err)
+			}
+			root = http.FS(sub)
+		} else {
+			// Only explicitly configured directories are checked; the default layout may be absent, e.g. in the container image
+			if len(c.GetMounts()) > 0 {
+				if err := checkDir(m.Dir); err != nil {
+					return fmt.Errorf("static mount %q: %w", m.Prefix, err)
+				}
+			}
+			root = http.Dir(m.Dir)
+		}
+		srv.HandlePrefix(m.Prefix, http.StripPrefix(m.Prefix, http.FileServer(root)))
+	}
+	return nil
+}
+
+// checkDir returns an error unless dir is an existing directory.
+func checkDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
+
+// isStaticPath reports whether the request path is served by a static mount.
+func isStaticPath(mounts []*conf.Server_Static_Mount, path string) bool {
+	for _, m := range mounts {
+		if strings.HasPrefix(path, m.Prefix) {
+			return true
+		}
+	}
+	return false
+}
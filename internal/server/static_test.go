@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"review/internal/conf"
+
+	kratoshttp "github.com/go-kratos/kratos/v2/transport/http"
+)
+
+func TestRegisterStatic(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "page.html")
+	tests := []struct {
+		name    string
+		c       *conf.Server_Static
+		wantErr bool
+		path    string
+	}{
+		{
+			name: "configured directory",
+			c:    &conf.Server_Static{Mounts: []*conf.Server_Static_Mount{{Prefix: "/site/", Dir: dir}}},
+			path: "/site/page.html",
+		},
+		{
+			name:    "configured directory missing",
+			c:       &conf.Server_Static{Mounts: []*conf.Server_Static_Mount{{Prefix: "/site/", Dir: filepath.Join(dir, "missing")}}},
+			wantErr: true,
+		},
+		{
+			name:    "configured path is a file",
+			c:       &conf.Server_Static{Mounts: []*conf.Server_Static_Mount{{Prefix: "/site/", Dir: file}}},
+			wantErr: true,
+		},
+		{
+			name:    "prefix without trailing slash",
+			c:       &conf.Server_Static{Mounts: []*conf.Server_Static_Mount{{Prefix: "/site", Dir: dir}}},
+			wantErr: true,
+		},
+		{
+			name: "embedded defaults",
+			c:    &conf.Server_Static{Embedded: true},
+			path: "/user/",
+		},
+		{
+			name:    "embedded directory missing",
+			c:       &conf.Server_Static{Embedded: true, Mounts: []*conf.Server_Static_Mount{{Prefix: "/site/", Dir: "missing"}}},
+			wantErr: true,
+		},
+		{
+			name: "disabled",
+			c:    &conf.Server_Static{Disabled: true, Mounts: []*conf.Server_Static_Mount{{Prefix: "/site/", Dir: filepath.Join(dir, "missing")}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := kratoshttp.NewServer()
+			err := registerStatic(srv, tt.c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("registerStatic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil || tt.path == "" {
+				return
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestIsStaticPath(t *testing.T) {
+	mounts := staticMounts(&conf.Server_Static{})
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/user/login.html", true},
+		{"/dashboard/", true},
+		{"/v1/review", false},
+		{"/users", false},
+	}
+	for _, tt := range tests {
+		if got := isStaticPath(mounts, tt.path); got != tt.want {
+			t.Errorf("isStaticPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
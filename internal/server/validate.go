@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+)
+
+// validatorAll is implemented by protoc-gen-validate messages; ValidateAll reports every violation.
+type validatorAll interface {
+	ValidateAll() error
+}
+
+type validator interface {
+	Validate() error
+}
+
+// fieldViolation is implemented by the per-message validation errors generated by protoc-gen-validate.
+type fieldViolation interface {
+	Field() string
+	Reason() string
+	Cause() error
+}
+
+// multiError is implemented by the generated <Message>MultiError types.
+type multiError interface {
+	AllErrors() []error
+}
+
+// fieldValidator validates requests like validate.Validator, but reports every offending field.
+// The 400 response keeps the Kratos error format (reason "VALIDATOR"); its metadata maps each
+// field path, e.g. "content" or "review.score", to the rule it violated.
+func fieldValidator() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			var err error
+			switch v := req.(type) {
+			case validatorAll:
+				err = v.ValidateAll()
+			case validator:
+				err = v.Validate()
+			}
+			if err != nil {
+				return nil, validationError(err)
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// validationError converts a protoc-gen-validate error into a BadRequest with per-field metadata.
+func validationError(err error) *kerrors.Error {
+	fields := make(map[string]string)
+	collectViolations(err, "", fields)
+	if len(fields) == 0 {
+		return kerrors.BadRequest("VALIDATOR", err.Error()).WithCause(err)
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return kerrors.BadRequest("VALIDATOR", "invalid fields: "+strings.Join(names, ", ")).
+		WithCause(err).
+		WithMetadata(fields)
+}
+
+// collectViolations flattens multi-errors and nested message errors into field path -> reason.
+func collectViolations(err error, prefix string, fields map[string]string) {
+	var multi multiError
+	if errors.As(err, &multi) {
+		for _, e := range multi.AllErrors() {
+			collectViolations(e, prefix, fields)
+		}
+		return
+	}
+	var fv fieldViolation
+	if !errors.As(err, &fv) {
+		return
+	}
+	path := fv.Field()
+	if prefix != "" {
+		path = prefix + "." + path
+	}
+	// Embedded message errors carry the nested violation as their cause.
+	if cause := fv.Cause(); cause != nil {
+		nested := len(fields)
+		collectViolations(cause, path, fields)
+		if len(fields) > nested {
+			return
+		}
+	}
+	fields[path] = fv.Reason()
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+)
+
+// violation mimics a protoc-gen-validate <Message>ValidationError.
+type violation struct {
+	field, reason string
+	cause         error
+}
+
+func (v violation) Error() string  { return v.field + ": " + v.reason }
+func (v violation) Field() string  { return v.field }
+func (v violation) Reason() string { return v.reason }
+func (v violation) Cause() error   { return v.cause }
+
+// multi mimics a protoc-gen-validate <Message>MultiError.
+type multi []error
+
+func (m multi) Error() string      { return "multiple violations" }
+func (m multi) AllErrors() []error { return m }
+
+// request's ValidateAll returns err.
+type request struct{ err error }
+
+func (r request) ValidateAll() error { return r.err }
+
+func TestFieldValidator(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantFields map[string]string
+	}{
+		{name: "valid"},
+		{
+			name:       "single field",
+			err:        violation{field: "content", reason: "value length must be at least 1 runes"},
+			wantFields: map[string]string{"content": "value length must be at least 1 runes"},
+		},
+		{
+			name: "every field with nested paths",
+			err: multi{
+				violation{field: "content", reason: "too short"},
+				violation{field: "review", reason: "embedded message failed validation", cause: multi{
+					violation{field: "score", reason: "must be in range [1, 5]"},
+				}},
+			},
+			wantFields: map[string]string{"content": "too short", "review.score": "must be in range [1, 5]"},
+		},
+		{name: "not a field violation", err: errors.New("boom"), wantFields: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := fieldValidator()(func(context.Context, any) (any, error) {
+				called = true
+				return nil, nil
+			})
+			_, err := handler(context.Background(), request{err: tt.err})
+			if tt.err == nil {
+				if err != nil || !called {
+					t.Errorf("valid request: error = %v, handler called = %v", err, called)
+				}
+				return
+			}
+			if called {
+				t.Error("handler called for an invalid request")
+			}
+			se := kerrors.FromError(err)
+			if se.Code != 400 || se.Reason != "VALIDATOR" {
+				t.Fatalf("error = %v, want a 400 VALIDATOR error", err)
+			}
+			if len(tt.wantFields) == 0 && len(se.Metadata) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(se.Metadata, tt.wantFields) {
+				t.Errorf("metadata = %v, want %v", se.Metadata, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// BuildInfo describes the running binary, injected via -ldflags at build time.
+type BuildInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// VersionHandler serves the build info as JSON.
+// It is mounted directly on the router, so it bypasses the JWT middleware.
+func VersionHandler(info BuildInfo) http.HandlerFunc {
+	body, _ := json.Marshal(info)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVersionHandler(t *testing.T) {
+	info := BuildInfo{Name: "review.service", Version: "v1.2.3", GitCommit: "abc123", BuildTime: "2026-01-02T03:04:05Z"}
+	tests := []struct {
+		name     string
+		method   string
+		wantCode int
+	}{
+		{name: "get", method: http.MethodGet, wantCode: http.StatusOK},
+		{name: "post", method: http.MethodPost, wantCode: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			VersionHandler(info)(rec, httptest.NewRequest(tt.method, "/version", nil))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+			var got BuildInfo
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if got != info {
+				t.Errorf("body = %+v, want %+v", got, info)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 
 	pb "review/api/ai/v1"
 	"review/internal/biz"
@@ -20,15 +21,55 @@ func NewAgentService(uc *biz.AgentUsecase) *AgentService {
 }
 
 // Process handles the user's natural language query.
+// The answer language comes from req.Language, then the Accept-Language header, defaulting to Chinese.
 func (s *AgentService) Process(ctx context.Context, req *pb.ProcessRequest) (*pb.ProcessResponse, error) {
-	return s.uc.Process(ctx, req.SessionId, req.Query)
+	lang, err := biz.ResponseLanguage(req.Language, acceptLanguage(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return s.uc.Process(ctx, req.SessionId, req.Query, req.Context, lang)
 }
 
 // CallTool executes a specific tool.
 func (s *AgentService) CallTool(ctx context.Context, req *pb.CallToolRequest) (*pb.CallToolResponse, error) {
-	result, err := s.uc.CallTool(ctx, req.ToolName, req.Arguments, req.OriginalQuery)
+	lang, err := biz.ResponseLanguage(req.Language, acceptLanguage(ctx))
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.uc.CallTool(ctx, req.ToolName, req.Arguments, req.OriginalQuery, lang)
 	if err != nil {
 		return nil, err
 	}
 	return &pb.CallToolResponse{Result: result}, nil
 }
+
+// GetSessionHistory returns the caller's stored messages for a session, oldest first.
+func (s *AgentService) GetSessionHistory(ctx context.Context, req *pb.GetSessionHistoryRequest) (*pb.GetSessionHistoryResponse, error) {
+	msgs, err := s.uc.GetSessionHistory(ctx, req.SessionId, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetSessionHistoryResponse{SessionId: req.SessionId, Messages: make([]*pb.SessionMessage, 0, len(msgs))}
+	for _, m := range msgs {
+		resp.Messages = append(resp.Messages, &pb.SessionMessage{Role: m.Role, Text: m.Text})
+	}
+	return resp, nil
+}
+
+// ListTools returns the tools available to the caller.
+func (s *AgentService) ListTools(ctx context.Context, req *pb.ListToolsRequest) (*pb.ListToolsResponse, error) {
+	tools := s.uc.ListTools(ctx)
+	resp := &pb.ListToolsResponse{Tools: make([]*pb.ToolInfo, 0, len(tools))}
+	for _, t := range tools {
+		params, err := json.Marshal(t.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		resp.Tools = append(resp.Tools, &pb.ToolInfo{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  string(params),
+		})
+	}
+	return resp, nil
+}
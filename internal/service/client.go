@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	"google.golang.org/grpc/peer"
+)
+
+// maxUserAgentLength 与 review_info.user_agent varchar(512) 保持一致
+const maxUserAgentLength = 512
+
+// clientInfo 从请求元数据中提取客户端IP和User-Agent, 用于滥用排查
+// 经过代理时优先取 X-Forwarded-For 的第一跳, 其次 X-Real-IP, 最后是连接的对端地址
+func clientInfo(ctx context.Context) (ip, userAgent string) {
+	tr, ok := transport.FromServerContext(ctx)
+	if !ok {
+		return "", ""
+	}
+	header := tr.RequestHeader()
+	userAgent = header.Get("User-Agent")
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	if fwd := header.Get("X-Forwarded-For"); fwd != "" {
+		ip = strings.TrimSpace(strings.Split(fwd, ",")[0])
+	} else if realIP := header.Get("X-Real-IP"); realIP != "" {
+		ip = strings.TrimSpace(realIP)
+	} else if ht, ok := tr.(khttp.Transporter); ok {
+		ip = hostOnly(ht.Request().RemoteAddr)
+	} else if p, ok := peer.FromContext(ctx); ok {
+		ip = hostOnly(p.Addr.String())
+	}
+	return ip, userAgent
+}
+
+// acceptLanguage 请求的 Accept-Language 头, 用于选择Agent回答的语言
+func acceptLanguage(ctx context.Context) string {
+	tr, ok := transport.FromServerContext(ctx)
+	if !ok {
+		return ""
+	}
+	return tr.RequestHeader().Get("Accept-Language")
+}
+
+func hostOnly(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
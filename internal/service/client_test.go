@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+// headerCarrier adapts http.Header to transport.Header.
+type headerCarrier http.Header
+
+func (h headerCarrier) Get(key string) string      { return http.Header(h).Get(key) }
+func (h headerCarrier) Set(key, value string)      { http.Header(h).Set(key, value) }
+func (h headerCarrier) Add(key, value string)      { http.Header(h).Add(key, value) }
+func (h headerCarrier) Keys() []string             { return nil }
+func (h headerCarrier) Values(key string) []string { return http.Header(h).Values(key) }
+
+// fakeTransport is a server transport carrying only request headers.
+type fakeTransport struct {
+	header headerCarrier
+}
+
+func (t *fakeTransport) Kind() transport.Kind            { return transport.KindHTTP }
+func (t *fakeTransport) Endpoint() string                { return "" }
+func (t *fakeTransport) Operation() string               { return "" }
+func (t *fakeTransport) RequestHeader() transport.Header { return t.header }
+func (t *fakeTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+
+func contextWithHeaders(kv ...string) context.Context {
+	h := headerCarrier{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		h.Set(kv[i], kv[i+1])
+	}
+	return transport.NewServerContext(context.Background(), &fakeTransport{header: h})
+}
+
+func TestClientInfo(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		wantIP        string
+		wantUserAgent string
+	}{
+		{name: "no transport", ctx: context.Background()},
+		{
+			name:          "first forwarded hop",
+			ctx:           contextWithHeaders("X-Forwarded-For", " 203.0.113.7, 10.0.0.1", "X-Real-IP", "10.0.0.2", "User-Agent", "curl/8"),
+			wantIP:        "203.0.113.7",
+			wantUserAgent: "curl/8",
+		},
+		{name: "real ip", ctx: contextWithHeaders("X-Real-IP", "198.51.100.1"), wantIP: "198.51.100.1"},
+		{
+			name:          "long user agent is truncated",
+			ctx:           contextWithHeaders("User-Agent", strings.Repeat("a", maxUserAgentLength+10)),
+			wantUserAgent: strings.Repeat("a", maxUserAgentLength),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, userAgent := clientInfo(tt.ctx)
+			if ip != tt.wantIP || userAgent != tt.wantUserAgent {
+				t.Errorf("clientInfo() = %q, %q, want %q, %q", ip, userAgent, tt.wantIP, tt.wantUserAgent)
+			}
+		})
+	}
+}
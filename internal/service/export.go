@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"review/internal/data/model"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	kratoshttp "github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// OperationExportAuditLogs 审核日志导出接口的操作名, 与生成的接口一样经过服务端中间件(JWT鉴权等)
+const OperationExportAuditLogs = "/api.review.v1.Review/ExportAuditLogs"
+
+// auditLogCSVHeader CSV导出的表头
+var auditLogCSVHeader = []string{"id", "review_id", "from_status", "to_status", "op_user", "source", "reason", "remarks", "create_at"}
+
+// ExportAuditLogs 以流的方式导出审核日志, GET /o/v1/audit-logs/export?from=&to=&format=csv|json
+// from/to 为RFC3339时间; format 默认为 csv, json 时每行一个JSON对象(NDJSON)
+// 鉴权或参数校验失败时按普通接口返回错误; 开始输出后出错只能中断响应
+// 导出受 server.http.timeout 限制, 数据量大时应缩小时间范围分多次导出
+func (s *ReviewService) ExportAuditLogs(ctx kratoshttp.Context) error {
+	kratoshttp.SetOperation(ctx, OperationExportAuditLogs)
+	q := ctx.Query()
+	from, err := parseExportTime(q.Get("from"))
+	if err != nil {
+		return err
+	}
+	to, err := parseExportTime(q.Get("to"))
+	if err != nil {
+		return err
+	}
+	format := q.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return errors.BadRequest("AUDIT_EXPORT_FORMAT_INVALID", "导出格式只能是 csv 或 json")
+	}
+
+	w := newAuditLogWriter(ctx.Response(), format)
+	h := ctx.Middleware(func(c context.Context, _ interface{}) (interface{}, error) {
+		return nil, s.uc.ExportAuditLogs(c, from, to, w.write)
+	})
+	if _, err := h(ctx, nil); err != nil {
+		if !w.started {
+			return err
+		}
+		// 已经开始输出, 无法再返回错误响应
+		return nil
+	}
+	return w.close()
+}
+
+func parseExportTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, errors.BadRequest("AUDIT_EXPORT_TIME_INVALID", "时间格式应为RFC3339，如 2024-01-02T15:04:05+08:00")
+	}
+	return t, nil
+}
+
+// auditLogWriter 逐条写出审核日志, 写出第一条时才发送响应头, 之前出错仍可返回错误响应
+type auditLogWriter struct {
+	res     http.ResponseWriter
+	format  string
+	csv     *csv.Writer
+	json    *json.Encoder
+	started bool
+	n       int
+}
+
+func newAuditLogWriter(res http.ResponseWriter, format string) *auditLogWriter {
+	return &auditLogWriter{res: res, format: format}
+}
+
+func (w *auditLogWriter) start() error {
+	w.started = true
+	filename := "audit-logs." + w.format
+	if w.format == "json" {
+		w.res.Header().Set("Content-Type", "application/x-ndjson")
+	} else {
+		w.res.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	}
+	w.res.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.res.WriteHeader(http.StatusOK)
+	if w.format == "json" {
+		w.json = json.NewEncoder(w.res)
+		return nil
+	}
+	w.csv = csv.NewWriter(w.res)
+	return w.csv.Write(auditLogCSVHeader)
+}
+
+func (w *auditLogWriter) write(l *model.ReviewAuditLog) error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	var err error
+	if w.json != nil {
+		err = w.json.Encode(map[string]interface{}{
+			"id":          l.ID,
+			"review_id":   strconv.FormatInt(l.ReviewID, 10),
+			"from_status": l.FromStatus,
+			"to_status":   l.ToStatus,
+			"op_user":     l.OpUser,
+			"source":      l.Source,
+			"reason":      l.Reason,
+			"remarks":     l.Remarks,
+			"create_at":   l.CreateAt.Format(time.RFC3339),
+		})
+	} else {
+		err = w.csv.Write([]string{
+			strconv.FormatInt(l.ID, 10),
+			strconv.FormatInt(l.ReviewID, 10),
+			strconv.Itoa(int(l.FromStatus)),
+			strconv.Itoa(int(l.ToStatus)),
+			l.OpUser,
+			l.Source,
+			l.Reason,
+			l.Remarks,
+			l.CreateAt.Format(time.RFC3339),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	// 每100条刷新一次, 让客户端尽早收到数据
+	if w.n++; w.n%100 == 0 {
+		w.flush()
+	}
+	return nil
+}
+
+func (w *auditLogWriter) flush() {
+	if w.csv != nil {
+		w.csv.Flush()
+	}
+	if f, ok := w.res.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// close 写出剩余数据; 没有任何审核日志时仍输出表头(CSV)或空内容(JSON)
+func (w *auditLogWriter) close() error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	w.flush()
+	if w.csv != nil {
+		return w.csv.Error()
+	}
+	return nil
+}
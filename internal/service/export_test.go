@@ -0,0 +1,63 @@
+package service
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"review/internal/data/model"
+)
+
+func TestAuditLogWriter(t *testing.T) {
+	at := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	logs := []*model.ReviewAuditLog{
+		{ID: 1, ReviewID: 9007199254740993, FromStatus: 10, ToStatus: 30, OpUser: "system", Source: "ai", Reason: "广告", CreateAt: at},
+		{ID: 2, ReviewID: 5, FromStatus: 30, ToStatus: 20, OpUser: "7", Source: "manual", Remarks: "申诉通过, 恢复展示", CreateAt: at},
+	}
+	tests := []struct {
+		name            string
+		format          string
+		logs            []*model.ReviewAuditLog
+		wantContentType string
+		want            string
+	}{
+		{
+			name:            "csv",
+			format:          "csv",
+			logs:            logs,
+			wantContentType: "text/csv; charset=utf-8",
+			want: "id,review_id,from_status,to_status,op_user,source,reason,remarks,create_at\n" +
+				"1,9007199254740993,10,30,system,ai,广告,,2024-05-01T08:00:00Z\n" +
+				"2,5,30,20,7,manual,,\"申诉通过, 恢复展示\",2024-05-01T08:00:00Z\n",
+		},
+		{
+			name:            "json",
+			format:          "json",
+			logs:            logs[:1],
+			wantContentType: "application/x-ndjson",
+			want: `{"create_at":"2024-05-01T08:00:00Z","from_status":10,"id":1,"op_user":"system","reason":"广告",` +
+				`"remarks":"","review_id":"9007199254740993","source":"ai","to_status":30}` + "\n",
+		},
+		{name: "empty csv still has a header", format: "csv", wantContentType: "text/csv; charset=utf-8", want: "id,review_id,from_status,to_status,op_user,source,reason,remarks,create_at\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			w := newAuditLogWriter(rec, tt.format)
+			for _, l := range tt.logs {
+				if err := w.write(l); err != nil {
+					t.Fatalf("write() error = %v", err)
+				}
+			}
+			if err := w.close(); err != nil {
+				t.Fatalf("close() error = %v", err)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if rec.Body.String() != tt.want {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
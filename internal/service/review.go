@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	pb "review/api/review/v1"
 	"review/internal/biz"
 	"review/internal/data/model"
+	"review/pkg/redact"
 	"review/pkg/snowflake"
 )
 
@@ -21,7 +23,7 @@ func NewReviewService(uc *biz.ReviewUsecase) *ReviewService {
 
 // CreateReview 创建评论
 func (s *ReviewService) CreateReview(ctx context.Context, req *pb.CreateReviewRequest) (*pb.CreateReviewReply, error) {
-	fmt.Println("[service] CreateReview, req:", req)
+	fmt.Println("[service] CreateReview, userID:", redact.ID(req.UserID), "orderID:", req.OrderID, "storeID:", req.StoreID, "content:", redact.Text(req.Content))
 	// 调用biz层
 	var anonymous int32
 	if req.Anonymous {
@@ -52,6 +54,7 @@ func (s *ReviewService) CreateReview(ctx context.Context, req *pb.CreateReviewRe
 	} else {
 		storeID = snowflake.GenID()
 	}
+	clientIP, userAgent := clientInfo(ctx)
 	review, err := s.uc.CreateReview(ctx, &model.ReviewInfo{
 		ReviewID:     reviewID,
 		UserID:       userID,
@@ -65,6 +68,8 @@ func (s *ReviewService) CreateReview(ctx context.Context, req *pb.CreateReviewRe
 		VideoInfo:    req.VideoInfo,
 		Status:       10, // Default status to "Pending"
 		Anonymous:    anonymous,
+		ClientIP:     clientIP,
+		UserAgent:    userAgent,
 	})
 	if err != nil {
 		return nil, err
@@ -87,14 +92,14 @@ func (s *ReviewService) CreateReview(ctx context.Context, req *pb.CreateReviewRe
 
 // GetReview 获取评论
 func (s *ReviewService) GetReview(ctx context.Context, req *pb.GetReviewRequest) (*pb.GetReviewReply, error) {
-	fmt.Println("[service] GetReview, req:", req)
+	fmt.Println("[service] GetReview, reviewID:", req.ReviewID)
 	// 调用biz层
 	review, err := s.uc.GetReview(ctx, req.ReviewID)
 	if err != nil {
 		return nil, err
 	}
 	// 拼装返回值
-	return &pb.GetReviewReply{ReviewInfo: &pb.ReviewInfo{
+	info := &pb.ReviewInfo{
 		ReviewID:     review.ReviewID,
 		UserID:       review.UserID,
 		OrderID:      review.OrderID,
@@ -105,12 +110,19 @@ func (s *ReviewService) GetReview(ctx context.Context, req *pb.GetReviewRequest)
 		PicInfo:      review.PicInfo,
 		VideoInfo:    review.VideoInfo,
 		Status:       review.Status,
-	}}, nil
+		Appends:      reviewAppends(review.Appends),
+	}
+	// 客户端IP和User-Agent仅对审核员/管理员可见
+	if biz.IsPrivileged(ctx) {
+		info.ClientIP = review.ClientIP
+		info.UserAgent = review.UserAgent
+	}
+	return &pb.GetReviewReply{ReviewInfo: info}, nil
 }
 
 // AuditReview 审核评论
 func (s *ReviewService) AuditReview(ctx context.Context, req *pb.AuditReviewRequest) (*pb.AuditReviewReply, error) {
-	fmt.Println("[service] AuditReview, req:", req)
+	fmt.Println("[service] AuditReview, reviewID:", req.ReviewID, "status:", req.Status)
 	// 调用biz层
 	var opRemarks string
 	if req.OpRemarks != nil {
@@ -119,7 +131,6 @@ func (s *ReviewService) AuditReview(ctx context.Context, req *pb.AuditReviewRequ
 	review, err := s.uc.AuditReview(ctx, &biz.AuditReviewParam{
 		ReviewID:  req.ReviewID,
 		Status:    req.Status,
-		OpUser:    req.OpUser,
 		OpReason:  req.OpReason,
 		OpRemarks: opRemarks,
 	})
@@ -130,9 +141,357 @@ func (s *ReviewService) AuditReview(ctx context.Context, req *pb.AuditReviewRequ
 	return &pb.AuditReviewReply{ReviewID: review.ReviewID, Status: review.Status}, nil
 }
 
+// GetModerationStats 驳回评论类别统计
+func (s *ReviewService) GetModerationStats(ctx context.Context, req *pb.GetModerationStatsRequest) (*pb.GetModerationStatsReply, error) {
+	fmt.Println("[service] GetModerationStats, storeID:", req.StoreID)
+	// 调用biz层, 时间为Unix秒, 0表示使用默认值
+	var start, end time.Time
+	if req.StartTime > 0 {
+		start = time.Unix(req.StartTime, 0)
+	}
+	if req.EndTime > 0 {
+		end = time.Unix(req.EndTime, 0)
+	}
+	stats, err := s.uc.GetModerationStats(ctx, req.StoreID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	categories := make([]*pb.CategoryCount, 0, len(stats.Categories))
+	for _, c := range stats.Categories {
+		categories = append(categories, &pb.CategoryCount{Category: c.Category, Count: c.Count})
+	}
+	return &pb.GetModerationStatsReply{Total: stats.Total, Categories: categories}, nil
+}
+
+// GetReviewerStats 审核员工作量统计
+func (s *ReviewService) GetReviewerStats(ctx context.Context, req *pb.GetReviewerStatsRequest) (*pb.GetReviewerStatsReply, error) {
+	fmt.Println("[service] GetReviewerStats, startTime:", req.StartTime, "endTime:", req.EndTime)
+	// 调用biz层, 时间为Unix秒, 0表示使用默认值
+	var start, end time.Time
+	if req.StartTime > 0 {
+		start = time.Unix(req.StartTime, 0)
+	}
+	if req.EndTime > 0 {
+		end = time.Unix(req.EndTime, 0)
+	}
+	stats, err := s.uc.GetReviewerStats(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	reviewers := make([]*pb.ReviewerStats, 0, len(stats))
+	for _, r := range stats {
+		reviewers = append(reviewers, &pb.ReviewerStats{
+			OpUser:         r.OpUser,
+			UserID:         r.UserID,
+			Username:       r.Username,
+			Approved:       r.Approved,
+			Rejected:       r.Rejected,
+			AppealsHandled: r.AppealsHandled,
+		})
+	}
+	return &pb.GetReviewerStatsReply{Reviewers: reviewers}, nil
+}
+
+// ReindexReviews 按创建时间范围在后台重建ES中的评论, 时间为Unix秒, 范围为 [created_from, created_to)
+func (s *ReviewService) ReindexReviews(ctx context.Context, req *pb.ReindexReviewsRequest) (*pb.ReindexReviewsReply, error) {
+	fmt.Println("[service] ReindexReviews, createdFrom:", req.CreatedFrom, "createdTo:", req.CreatedTo)
+	var from, to time.Time
+	if req.CreatedFrom > 0 {
+		from = time.Unix(req.CreatedFrom, 0)
+	}
+	if req.CreatedTo > 0 {
+		to = time.Unix(req.CreatedTo, 0)
+	}
+	if err := s.uc.ReindexReviews(ctx, from, to); err != nil {
+		return nil, err
+	}
+	return &pb.ReindexReviewsReply{CreatedFrom: req.CreatedFrom, CreatedTo: req.CreatedTo}, nil
+}
+
+// GetStoreRating 店铺平均评分
+func (s *ReviewService) GetStoreRating(ctx context.Context, req *pb.GetStoreRatingRequest) (*pb.GetStoreRatingReply, error) {
+	fmt.Println("[service] GetStoreRating, storeID:", req.StoreID)
+	// 调用biz层
+	rating, err := s.uc.GetStoreRating(ctx, req.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	return &pb.GetStoreRatingReply{
+		StoreID:      rating.StoreID,
+		Count:        rating.Count,
+		Score:        rating.Score,
+		ServiceScore: rating.ServiceScore,
+		ExpressScore: rating.ExpressScore,
+		ScaleMin:     rating.ScaleMin,
+		ScaleMax:     rating.ScaleMax,
+	}, nil
+}
+
+// GetTagStats 评论话题标签分布
+func (s *ReviewService) GetTagStats(ctx context.Context, req *pb.GetTagStatsRequest) (*pb.GetTagStatsReply, error) {
+	fmt.Println("[service] GetTagStats, storeID:", req.StoreID)
+	// 调用biz层
+	stats, err := s.uc.GetTagStats(ctx, req.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	tags := make([]*pb.TagCount, 0, len(stats.Tags))
+	for _, t := range stats.Tags {
+		tags = append(tags, &pb.TagCount{Tag: t.Tag, Count: t.Count})
+	}
+	return &pb.GetTagStatsReply{Total: stats.Total, Tags: tags}, nil
+}
+
+// GetModerationPolicy 当前生效的审核配置
+func (s *ReviewService) GetModerationPolicy(ctx context.Context, req *pb.GetModerationPolicyRequest) (*pb.GetModerationPolicyReply, error) {
+	fmt.Println("[service] GetModerationPolicy")
+	// 调用biz层
+	p, err := s.uc.GetModerationPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	reply := &pb.GetModerationPolicyReply{
+		Model:               p.AI.Model,
+		Categories:          p.AI.Categories,
+		ApiKeys:             int32(p.AI.APIKeys),
+		OnAiError:           p.OnAIError,
+		MediaSizeCheck:      p.MediaSizeCheck,
+		AllowRejectedAppeal: p.AllowRejectedAppeal,
+		AppealAiAssist:      p.AppealAIAssist,
+		StorePriorities:     p.StorePriorities,
+	}
+	for _, r := range p.AI.Routes {
+		reply.Routes = append(reply.Routes, &pb.ModerationRoutePolicy{Language: r.Language, Model: r.Model, Categories: r.Categories})
+	}
+	if fp := p.TrustedFastPath; fp != nil {
+		reply.TrustedFastPath = &pb.FastPathPolicy{MinApproved: fp.MinApproved, BlockedWords: fp.BlockedWords}
+	}
+	if mc := p.ModerationCache; mc != nil {
+		reply.ModerationCache = &pb.ModerationCachePolicy{Mode: mc.Mode, TtlSeconds: int64(mc.TTL.Seconds()), FuzzyDistance: int32(mc.FuzzyDistance), MaxEntries: mc.MaxEntries}
+	}
+	if lp := p.LanguagePolicy; lp != nil {
+		reply.LanguagePolicy = &pb.LanguagePolicy{Allowed: lp.Allowed, Action: lp.Action}
+	}
+	return reply, nil
+}
+
+// SuggestReviewTerms 评论搜索框的输入提示
+func (s *ReviewService) SuggestReviewTerms(ctx context.Context, req *pb.SuggestReviewTermsRequest) (*pb.SuggestReviewTermsReply, error) {
+	fmt.Println("[service] SuggestReviewTerms, storeID:", req.StoreID, "prefix:", redact.Text(req.Prefix))
+	// 调用biz层
+	suggestions, err := s.uc.SuggestReviewTerms(ctx, req.StoreID, req.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	list := make([]*pb.TermSuggestion, 0, len(suggestions))
+	for _, t := range suggestions {
+		list = append(list, &pb.TermSuggestion{Term: t.Term, Count: t.Count})
+	}
+	return &pb.SuggestReviewTermsReply{Suggestions: list}, nil
+}
+
+// GetIndexStats ES评论索引状态
+func (s *ReviewService) GetIndexStats(ctx context.Context, req *pb.GetIndexStatsRequest) (*pb.GetIndexStatsReply, error) {
+	fmt.Println("[service] GetIndexStats")
+	// 调用biz层
+	stats, err := s.uc.GetIndexStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	return &pb.GetIndexStatsReply{
+		Index:     stats.Index,
+		DocCount:  stats.DocCount,
+		SizeBytes: stats.SizeBytes,
+		Health:    stats.Health,
+		DbCount:   stats.DBCount,
+		Drift:     stats.Drift,
+	}, nil
+}
+
+// UpdateReviewScore 单独修改评论评分
+func (s *ReviewService) UpdateReviewScore(ctx context.Context, req *pb.UpdateReviewScoreRequest) (*pb.UpdateReviewScoreReply, error) {
+	fmt.Println("[service] UpdateReviewScore, reviewID:", req.ReviewID)
+	// 调用biz层
+	review, err := s.uc.UpdateReviewScore(ctx, req.ReviewID, req.Score, req.ServiceScore, req.ExpressScore)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	return &pb.UpdateReviewScoreReply{
+		ReviewID:     review.ReviewID,
+		Score:        review.Score,
+		ServiceScore: review.ServiceScore,
+		ExpressScore: review.ExpressScore,
+	}, nil
+}
+
+// PreviewModeration 提交前预审评论内容
+func (s *ReviewService) PreviewModeration(ctx context.Context, req *pb.PreviewModerationRequest) (*pb.PreviewModerationReply, error) {
+	fmt.Println("[service] PreviewModeration, content:", redact.Text(req.Content))
+	// 调用biz层
+	verdict, err := s.uc.PreviewModeration(ctx, req.Content, req.PicInfo)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	return &pb.PreviewModerationReply{
+		Approved:   verdict.Approved,
+		Category:   verdict.Category,
+		Reason:     verdict.Reason,
+		Confidence: verdict.Confidence,
+	}, nil
+}
+
+// ResubmitReview 修改被驳回的评论后重新提交审核
+func (s *ReviewService) ResubmitReview(ctx context.Context, req *pb.ResubmitReviewRequest) (*pb.ResubmitReviewReply, error) {
+	fmt.Println("[service] ResubmitReview, reviewID:", req.ReviewID, "content:", redact.Text(req.Content))
+	// 调用biz层
+	review, err := s.uc.ResubmitReview(ctx, req.ReviewID, req.Content)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	return &pb.ResubmitReviewReply{ReviewID: review.ReviewID, Status: review.Status}, nil
+}
+
+// GetReviewAuditTrail 评论的审核记录
+func (s *ReviewService) GetReviewAuditTrail(ctx context.Context, req *pb.GetReviewAuditTrailRequest) (*pb.GetReviewAuditTrailReply, error) {
+	fmt.Println("[service] GetReviewAuditTrail, reviewID:", req.ReviewID)
+	// 调用biz层
+	trail, err := s.uc.GetReviewAuditTrail(ctx, req.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	list := make([]*pb.AuditTrailEntry, 0, len(trail))
+	for _, e := range trail {
+		list = append(list, &pb.AuditTrailEntry{
+			FromStatus: e.FromStatus,
+			ToStatus:   e.ToStatus,
+			Source:     e.Source,
+			OpUser:     e.OpUser,
+			Reason:     e.Reason,
+			Category:   e.Category,
+			Remarks:    e.Remarks,
+			Confidence: e.Confidence,
+			CreateAt:   time.Time(e.CreateAt).Unix(),
+		})
+	}
+	return &pb.GetReviewAuditTrailReply{List: list}, nil
+}
+
+// GetReviewDetail 评论详情, 包含商家回复、申诉记录和审核记录
+func (s *ReviewService) GetReviewDetail(ctx context.Context, req *pb.GetReviewDetailRequest) (*pb.GetReviewDetailReply, error) {
+	fmt.Println("[service] GetReviewDetail, reviewID:", req.ReviewID)
+	// 调用biz层
+	detail, err := s.uc.GetReviewDetail(ctx, req.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值, 各部分已在biz层按读者裁剪
+	r := detail.Review
+	reply := &pb.GetReviewDetailReply{
+		Review: &pb.ReviewInfo{
+			ReviewID:     r.ReviewID,
+			UserID:       r.UserID,
+			OrderID:      r.OrderID,
+			StoreID:      r.StoreID,
+			Score:        r.Score,
+			ServiceScore: r.ServiceScore,
+			ExpressScore: r.ExpressScore,
+			Content:      r.Content,
+			PicInfo:      r.PicInfo,
+			VideoInfo:    r.VideoInfo,
+			Status:       r.Status,
+		},
+	}
+	if m := r.Moderation; m != nil {
+		reply.Review.OpReason = m.OpReason
+		reply.Review.RejectCategory = m.RejectCategory
+		reply.Review.ClientIP = m.ClientIP
+		reply.Review.UserAgent = m.UserAgent
+	}
+	if rp := detail.Reply; rp != nil {
+		reply.Reply = &pb.ReplyInfo{
+			ReplyID:   rp.ReplyID,
+			Content:   rp.Content,
+			PicInfo:   rp.PicInfo,
+			VideoInfo: rp.VideoInfo,
+			CreateAt:  time.Time(rp.CreateAt).Unix(),
+		}
+	}
+	reply.Appends = reviewAppends(detail.Appends)
+	for _, a := range detail.Appeals {
+		reply.Appeals = append(reply.Appeals, &pb.AppealInfo{
+			AppealID:  a.AppealID,
+			ReviewID:  r.ReviewID,
+			StoreID:   r.StoreID,
+			Status:    a.Status,
+			Type:      a.Type,
+			Reason:    a.Reason,
+			Content:   a.Content,
+			PicInfo:   a.PicInfo,
+			VideoInfo: a.VideoInfo,
+		})
+	}
+	for _, e := range detail.AuditTrail {
+		reply.AuditTrail = append(reply.AuditTrail, &pb.AuditTrailEntry{
+			FromStatus: e.FromStatus,
+			ToStatus:   e.ToStatus,
+			Source:     e.Source,
+			OpUser:     e.OpUser,
+			Reason:     e.Reason,
+			Category:   e.Category,
+			Remarks:    e.Remarks,
+			Confidence: e.Confidence,
+			CreateAt:   time.Time(e.CreateAt).Unix(),
+		})
+	}
+	return reply, nil
+}
+
+// DeleteMyReview 删除自己的评论
+func (s *ReviewService) DeleteMyReview(ctx context.Context, req *pb.DeleteMyReviewRequest) (*pb.DeleteMyReviewReply, error) {
+	fmt.Println("[service] DeleteMyReview, reviewID:", req.ReviewID)
+	// 调用biz层
+	if err := s.uc.DeleteMyReview(ctx, req.ReviewID); err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	return &pb.DeleteMyReviewReply{ReviewID: req.ReviewID}, nil
+}
+
+// BatchAuditReview 批量审核评论
+func (s *ReviewService) BatchAuditReview(ctx context.Context, req *pb.BatchAuditReviewRequest) (*pb.BatchAuditReviewReply, error) {
+	fmt.Println("[service] BatchAuditReview, reviewIDs:", len(req.ReviewIDs), "decision:", req.Decision)
+	// 调用biz层
+	// 操作人取自当前登录用户, 忽略 req.OpUser
+	results, err := s.uc.BatchAuditReview(ctx, req.ReviewIDs, req.Decision, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值
+	list := make([]*pb.BatchAuditResult, 0, len(results))
+	for _, r := range results {
+		item := &pb.BatchAuditResult{ReviewID: r.ReviewID, Success: r.Err == nil, Status: r.Status}
+		if r.Err != nil {
+			item.Message = r.Err.Error()
+		}
+		list = append(list, item)
+	}
+	return &pb.BatchAuditReviewReply{Results: list}, nil
+}
+
 // ReplyReview 回复评论
 func (s *ReviewService) ReplyReview(ctx context.Context, req *pb.ReplyReviewRequest) (*pb.ReplyReviewReply, error) {
-	fmt.Println("[service] ReplyReview, req:", req)
+	fmt.Println("[service] ReplyReview, reviewID:", req.ReviewID, "storeID:", req.StoreID, "content:", redact.Text(req.Content))
 	// 调用biz层
 	reply, err := s.uc.ReplyReview(ctx, &biz.ReplyReviewParam{
 		ReviewID:  req.ReviewID,
@@ -150,7 +509,7 @@ func (s *ReviewService) ReplyReview(ctx context.Context, req *pb.ReplyReviewRequ
 
 // AppealReview 申诉评论
 func (s *ReviewService) AppealReview(ctx context.Context, req *pb.AppealReviewRequest) (*pb.AppealReviewReply, error) {
-	fmt.Println("[service] AppealReview, req:", req)
+	fmt.Println("[service] AppealReview, reviewID:", req.ReviewID, "storeID:", req.StoreID, "reason:", redact.Text(req.Reason), "content:", redact.Text(req.Content))
 	// 调用biz层
 	review, err := s.uc.AppealReview(ctx, &biz.AppealReviewParam{
 		ReviewID:  req.ReviewID,
@@ -159,6 +518,7 @@ func (s *ReviewService) AppealReview(ctx context.Context, req *pb.AppealReviewRe
 		Content:   req.Content,
 		PicInfo:   req.PicInfo,
 		VideoInfo: req.VideoInfo,
+		Type:      req.AppealType,
 	})
 	if err != nil {
 		return nil, err
@@ -169,7 +529,7 @@ func (s *ReviewService) AppealReview(ctx context.Context, req *pb.AppealReviewRe
 
 // AuditAppeal 审核申诉
 func (s *ReviewService) AuditAppeal(ctx context.Context, req *pb.AuditAppealRequest) (*pb.AuditAppealReply, error) {
-	fmt.Println("[service] AuditAppeal, req:", req)
+	fmt.Println("[service] AuditAppeal, appealID:", req.AppealID, "status:", req.Status)
 	// 调用biz层
 	var opRemarks string
 	if req.OpRemarks != nil {
@@ -178,7 +538,6 @@ func (s *ReviewService) AuditAppeal(ctx context.Context, req *pb.AuditAppealRequ
 	review, err := s.uc.AuditAppeal(ctx, &biz.AuditAppealParam{
 		AppealID:  req.AppealID,
 		Status:    req.Status,
-		OpUser:    req.OpUser,
 		OpReason:  req.OpReason,
 		OpRemarks: opRemarks,
 	})
@@ -190,15 +549,83 @@ func (s *ReviewService) AuditAppeal(ctx context.Context, req *pb.AuditAppealRequ
 
 // ListReviewByStoreID 根据商家ID获取评论列表（分页）
 func (s *ReviewService) ListReviewByStoreID(ctx context.Context, req *pb.ListReviewByStoreIDRequest) (*pb.ListReviewByStoreIDReply, error) {
-	fmt.Println("[service] ListReviewByStoreID, req:", req)
+	fmt.Println("[service] ListReviewByStoreID, storeID:", req.StoreID, "page:", req.Page, "size:", req.Size)
 	// 调用biz层
-	reviews, err := s.uc.ListReviewByStoreID(ctx, req.StoreID, req.Page, req.Size)
+	reviews, err := s.uc.ListReviewByStoreID(ctx, req.StoreID, req.Page, req.Size, req.OnlyUnreplied, req.Tag)
 	if err != nil {
 		return nil, err
 	}
 	// 拼装返回值
-	list := make([]*pb.ReviewInfo, 0, len(reviews))
-	for _, review := range reviews {
+	list := make([]*pb.ReviewInfo, 0, len(reviews.List))
+	for _, review := range reviews.List {
+		list = append(list, &pb.ReviewInfo{
+			ReviewID:     review.ReviewID,
+			UserID:       review.UserID,
+			OrderID:      review.OrderID,
+			StoreID:      review.StoreID,
+			Score:        review.Score,
+			ServiceScore: review.ServiceScore,
+			ExpressScore: review.ExpressScore,
+			Content:      review.Content,
+			PicInfo:      review.PicInfo,
+			VideoInfo:    review.VideoInfo,
+			Status:       review.Status,
+			Appends:      reviewAppends(review.Appends),
+		})
+	}
+	return &pb.ListReviewByStoreIDReply{
+		List:           list,
+		Total:          reviews.Total,
+		TotalRelation:  reviews.TotalRelation,
+		Partial:        reviews.Partial,
+		UnrepliedCount: reviews.UnrepliedCount,
+		Applied:        appliedQuery(reviews.Applied),
+	}, nil
+}
+
+// ListRecentReviews 全平台最新的已通过评论（分页）, 匿名评论不返回作者
+func (s *ReviewService) ListRecentReviews(ctx context.Context, req *pb.ListRecentReviewsRequest) (*pb.ListRecentReviewsReply, error) {
+	fmt.Println("[service] ListRecentReviews, page:", req.Page, "size:", req.Size)
+	// 调用biz层
+	reviews, err := s.uc.ListRecentReviews(ctx, req.Page, req.Size)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值, 每条评论带有所属店铺的名称
+	list := make([]*pb.ReviewInfo, 0, len(reviews.List))
+	for _, review := range reviews.List {
+		list = append(list, &pb.ReviewInfo{
+			ReviewID:     review.ReviewID,
+			UserID:       review.UserID,
+			OrderID:      review.OrderID,
+			StoreID:      review.StoreID,
+			StoreName:    review.StoreName,
+			Anonymous:    review.Anonymous == 1,
+			Score:        review.Score,
+			ServiceScore: review.ServiceScore,
+			ExpressScore: review.ExpressScore,
+			Content:      review.Content,
+			PicInfo:      review.PicInfo,
+			VideoInfo:    review.VideoInfo,
+			Status:       review.Status,
+			CreateAt:     time.Time(review.CreateAt).Unix(),
+			Appends:      reviewAppends(review.Appends),
+		})
+	}
+	return &pb.ListRecentReviewsReply{List: list, Total: reviews.Total, TotalRelation: reviews.TotalRelation, Partial: reviews.Partial, Applied: appliedQuery(reviews.Applied)}, nil
+}
+
+// ListReviewsByStoreIDs 查询商家名下多个店铺的评论列表（分页）
+func (s *ReviewService) ListReviewsByStoreIDs(ctx context.Context, req *pb.ListReviewsByStoreIDsRequest) (*pb.ListReviewByUserIDReply, error) {
+	fmt.Println("[service] ListReviewsByStoreIDs, storeIDs:", req.StoreIDs, "page:", req.Page, "size:", req.Size)
+	// 调用biz层
+	reviews, err := s.uc.ListReviewsByStoreIDs(ctx, req.StoreIDs, req.Page, req.Size)
+	if err != nil {
+		return nil, err
+	}
+	// 拼装返回值, 每条评论带有所属的店铺ID
+	list := make([]*pb.ReviewInfo, 0, len(reviews.List))
+	for _, review := range reviews.List {
 		list = append(list, &pb.ReviewInfo{
 			ReviewID:     review.ReviewID,
 			UserID:       review.UserID,
@@ -211,22 +638,23 @@ func (s *ReviewService) ListReviewByStoreID(ctx context.Context, req *pb.ListRev
 			PicInfo:      review.PicInfo,
 			VideoInfo:    review.VideoInfo,
 			Status:       review.Status,
+			Appends:      reviewAppends(review.Appends),
 		})
 	}
-	return &pb.ListReviewByStoreIDReply{List: list}, nil
+	return &pb.ListReviewByUserIDReply{List: list, Total: reviews.Total, TotalRelation: reviews.TotalRelation, Partial: reviews.Partial, Applied: appliedQuery(reviews.Applied)}, nil
 }
 
 // ListReviewByUserID 根据用户ID获取评论列表（分页）
 func (s *ReviewService) ListReviewByUserID(ctx context.Context, req *pb.ListReviewByUserIDRequest) (*pb.ListReviewByUserIDReply, error) {
-	fmt.Println("[service] ListReviewByUserID, req:", req)
+	fmt.Println("[service] ListReviewByUserID, userID:", redact.ID(req.UserID), "page:", req.Page, "size:", req.Size)
 	// 调用biz层
 	reviews, err := s.uc.ListReviewByUserID(ctx, req.UserID, req.Page, req.Size)
 	if err != nil {
 		return nil, err
 	}
 	// 拼装返回值
-	list := make([]*pb.ReviewInfo, 0, len(reviews))
-	for _, review := range reviews {
+	list := make([]*pb.ReviewInfo, 0, len(reviews.List))
+	for _, review := range reviews.List {
 		list = append(list, &pb.ReviewInfo{
 			ReviewID:     review.ReviewID,
 			UserID:       review.UserID,
@@ -239,23 +667,27 @@ func (s *ReviewService) ListReviewByUserID(ctx context.Context, req *pb.ListRevi
 			PicInfo:      review.PicInfo,
 			VideoInfo:    review.VideoInfo,
 			Status:       review.Status,
+			Appends:      reviewAppends(review.Appends),
 		})
 	}
-	return &pb.ListReviewByUserIDReply{List: list}, nil
+	return &pb.ListReviewByUserIDReply{List: list, Total: reviews.Total, TotalRelation: reviews.TotalRelation, Partial: reviews.Partial, Applied: appliedQuery(reviews.Applied)}, nil
 }
 
 // ListReviewsByStatus retrieves a list of reviews by status with pagination.
 func (s *ReviewService) ListReviewsByStatus(ctx context.Context, req *pb.ListReviewsByStatusRequest) (*pb.ListReviewByUserIDReply, error) {
-	fmt.Println("[service] ListReviewsByStatus, req:", req)
+	fmt.Println("[service] ListReviewsByStatus, status:", req.Status, "appealStatus:", req.AppealStatus, "page:", req.Page, "size:", req.Size)
 	// Call the biz layer
-	reviews, err := s.uc.ListReviewsByStatus(ctx, req.Status, req.Page, req.Size)
+	reviews, err := s.uc.ListReviewsByStatus(ctx, req.Status, req.AppealStatus, req.Page, req.Size)
 	if err != nil {
 		return nil, err
 	}
 	// Assemble the response
-	list := make([]*pb.ReviewInfo, 0, len(reviews))
-	for _, review := range reviews {
-		list = append(list, &pb.ReviewInfo{
+	// Reviewers and admins auditing AI decisions see the verdict reason and category inline,
+	// taken from the indexed document; other callers get the plain review.
+	privileged := biz.IsPrivileged(ctx)
+	list := make([]*pb.ReviewInfo, 0, len(reviews.List))
+	for _, review := range reviews.List {
+		info := &pb.ReviewInfo{
 			ReviewID:     review.ReviewID,
 			UserID:       review.UserID,
 			OrderID:      review.OrderID,
@@ -267,31 +699,86 @@ func (s *ReviewService) ListReviewsByStatus(ctx context.Context, req *pb.ListRev
 			PicInfo:      review.PicInfo,
 			VideoInfo:    review.VideoInfo,
 			Status:       review.Status,
-		})
+			Appends:      reviewAppends(review.Appends),
+		}
+		if privileged && review.ReviewInfo != nil {
+			info.OpReason = review.OpReason
+			info.RejectCategory = review.RejectCategory
+		}
+		list = append(list, info)
 	}
 	// Note: We are reusing ListReviewByUserIDReply as the response message.
-	return &pb.ListReviewByUserIDReply{List: list}, nil
+	return &pb.ListReviewByUserIDReply{List: list, Total: reviews.Total, TotalRelation: reviews.TotalRelation, Partial: reviews.Partial, Applied: appliedQuery(reviews.Applied)}, nil
 }
 
 // ListAppealsByStatus retrieves a list of appeals by status with pagination.
 func (s *ReviewService) ListAppealsByStatus(ctx context.Context, req *pb.ListAppealsByStatusRequest) (*pb.ListAppealsByStatusReply, error) {
-	fmt.Println("[service] ListAppealsByStatus, req:", req)
-	appeals, err := s.uc.ListAppealsByStatus(ctx, req.Status, req.Page, req.Size)
+	fmt.Println("[service] ListAppealsByStatus, status:", req.Status, "page:", req.Page, "size:", req.Size)
+	appeals, total, err := s.uc.ListAppealsByStatus(ctx, req.Status, req.Page, req.Size)
 	if err != nil {
 		return nil, err
 	}
 	list := make([]*pb.AppealInfo, 0, len(appeals))
 	for _, a := range appeals {
-		list = append(list, &pb.AppealInfo{
+		info := &pb.AppealInfo{
 			AppealID:  a.AppealID,
 			ReviewID:  a.ReviewID,
 			StoreID:   a.StoreID,
 			Status:    a.Status,
+			Type:      a.AppealType,
 			Reason:    a.Reason,
 			Content:   a.Content,
 			PicInfo:   a.PicInfo,
 			VideoInfo: a.VideoInfo,
+		}
+		// AI建议仅供参考
+		if a.Recommendation != nil {
+			info.AiDecision = a.Recommendation.Decision
+			info.AiRationale = a.Recommendation.Rationale
+		}
+		if a.Review != nil {
+			info.Review = &pb.ReviewInfo{
+				ReviewID:     a.Review.ReviewID,
+				UserID:       a.Review.UserID,
+				OrderID:      a.Review.OrderID,
+				StoreID:      a.Review.StoreID,
+				Score:        a.Review.Score,
+				ServiceScore: a.Review.ServiceScore,
+				ExpressScore: a.Review.ExpressScore,
+				Content:      a.Review.Content,
+				PicInfo:      a.Review.PicInfo,
+				VideoInfo:    a.Review.VideoInfo,
+				Status:       a.Review.Status,
+			}
+		}
+		list = append(list, info)
+	}
+	return &pb.ListAppealsByStatusReply{List: list, Total: total}, nil
+}
+
+// appliedQuery 转换列表实际生效的查询条件
+func appliedQuery(a biz.AppliedQuery) *pb.AppliedQuery {
+	return &pb.AppliedQuery{
+		StoreID:       a.StoreID,
+		UserID:        a.UserID,
+		Status:        a.Status,
+		Tag:           a.Tag,
+		OnlyUnreplied: a.OnlyUnreplied,
+		AppealStatus:  a.AppealStatus,
+		Page:          a.Page,
+		Size:          a.Size,
+		Offset:        a.Offset,
+	}
+}
+
+// reviewAppends 按追加顺序转换追加评论
+func reviewAppends(appends []*biz.AppendView) []*pb.ReviewAppend {
+	list := make([]*pb.ReviewAppend, 0, len(appends))
+	for _, a := range appends {
+		list = append(list, &pb.ReviewAppend{
+			Content:  a.Content,
+			CreateAt: time.Time(a.CreateAt).Unix(),
 		})
 	}
-	return &pb.ListAppealsByStatusReply{List: list}, nil
+	return list
 }
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "review/api/review/v1"
+	"review/internal/biz"
+	"review/internal/conf"
+	"review/internal/data/model"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware/auth/jwt"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// fakeReviewRepo serves a fixed status listing and review; any other ReviewRepo method panics.
+type fakeReviewRepo struct {
+	biz.ReviewRepo
+	list    []*biz.MyReviewInfo
+	review  *model.ReviewInfo
+	appends []*model.ReviewAppendInfo
+}
+
+func (r *fakeReviewRepo) ListReviewsByStatus(context.Context, int32, int32, int32, int32) (*biz.ReviewList, error) {
+	return &biz.ReviewList{List: r.list, Total: int64(len(r.list))}, nil
+}
+
+func (r *fakeReviewRepo) GetReviewByReviewID(context.Context, int64) (*model.ReviewInfo, error) {
+	return r.review, nil
+}
+
+func (r *fakeReviewRepo) ListAppends(context.Context, int64) ([]*model.ReviewAppendInfo, error) {
+	return r.appends, nil
+}
+
+func TestReviewRepliesCarryAppends(t *testing.T) {
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeReviewRepo{
+		list: []*biz.MyReviewInfo{{ReviewInfo: &model.ReviewInfo{Content: "好评"}, ReviewID: 1, Status: 20, Appends: []*biz.AppendView{
+			{Content: "用了一周", CreateAt: biz.MyTime(at)},
+		}}},
+		review:  &model.ReviewInfo{ReviewID: 1, Content: "好评", Status: 20, ExtJSON: biz.WithAppendCount("", 1)},
+		appends: []*model.ReviewAppendInfo{{ReviewID: 1, Content: "用了一周", CreateAt: at}},
+	}
+	s := NewReviewService(biz.NewReviewUsecase(repo, log.DefaultLogger, &conf.Review{}))
+	ctx := jwt.NewContext(context.Background(), jwtv5.MapClaims{"user_id": float64(7), "role": "reviewer"})
+	tests := []struct {
+		name string
+		call func() (*pb.ReviewInfo, error)
+	}{
+		{name: "status list", call: func() (*pb.ReviewInfo, error) {
+			reply, err := s.ListReviewsByStatus(ctx, &pb.ListReviewsByStatusRequest{Status: 20, Page: 1, Size: 10})
+			if err != nil || len(reply.List) != 1 {
+				return nil, fmt.Errorf("list = %v, error = %v, want one review", reply, err)
+			}
+			return reply.List[0], nil
+		}},
+		{name: "get review", call: func() (*pb.ReviewInfo, error) {
+			reply, err := s.GetReview(ctx, &pb.GetReviewRequest{ReviewID: 1})
+			if err != nil {
+				return nil, err
+			}
+			return reply.ReviewInfo, nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := tt.call()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Content != "好评" || len(info.Appends) != 1 || info.Appends[0].Content != "用了一周" || info.Appends[0].CreateAt != at.Unix() {
+				t.Errorf("content, appends = %q, %+v, want the original content and its append", info.Content, info.Appends)
+			}
+		})
+	}
+}
+
+func TestListReviewsByStatusVerdict(t *testing.T) {
+	repo := &fakeReviewRepo{list: []*biz.MyReviewInfo{{ReviewInfo: &model.ReviewInfo{
+		ReviewID: 1, Status: 30, OpReason: "含联系方式", RejectCategory: "advertising",
+	}}}}
+	s := NewReviewService(biz.NewReviewUsecase(repo, log.DefaultLogger, &conf.Review{}))
+	tests := []struct {
+		role         string
+		wantReason   string
+		wantCategory string
+	}{
+		{role: "reviewer", wantReason: "含联系方式", wantCategory: "advertising"},
+		{role: "admin", wantReason: "含联系方式", wantCategory: "advertising"},
+		{role: "customer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			ctx := jwt.NewContext(context.Background(), jwtv5.MapClaims{"user_id": float64(7), "role": tt.role})
+			reply, err := s.ListReviewsByStatus(ctx, &pb.ListReviewsByStatusRequest{Status: 30, Page: 1, Size: 10})
+			if err != nil {
+				t.Fatalf("ListReviewsByStatus() error = %v", err)
+			}
+			if len(reply.List) != 1 {
+				t.Fatalf("list = %v, want one review", reply.List)
+			}
+			got := reply.List[0]
+			if got.OpReason != tt.wantReason || got.RejectCategory != tt.wantCategory {
+				t.Errorf("OpReason, RejectCategory = %q, %q, want %q, %q", got.OpReason, got.RejectCategory, tt.wantReason, tt.wantCategory)
+			}
+		})
+	}
+}
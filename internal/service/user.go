@@ -36,11 +36,18 @@ func (s *UserService) Register(ctx context.Context, req *pb.RegisterRequest) (*p
 
 // Login implements api.user.v1.UserServer.
 func (s *UserService) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginReply, error) {
-	token, err := s.uc.Login(ctx, req.Username, req.Password)
+	res, err := s.uc.Login(ctx, req.Username, req.Password)
 	if err != nil {
 		return nil, err
 	}
-	return &pb.LoginReply{Token: token, Message: "Login successful"}, nil
+	return &pb.LoginReply{
+		Token:     res.Token,
+		Message:   "Login successful",
+		UserID:    res.UserID,
+		Role:      res.Role,
+		StoreID:   res.StoreID,
+		ExpiresAt: res.ExpiresAt.Unix(),
+	}, nil
 }
 
 // GetUserInfo implements api.user.v1.UserServer.
@@ -65,7 +72,8 @@ func (s *UserService) GetUserInfo(ctx context.Context, req *pb.GetUserInfoReques
 func (s *UserService) UpdateUserInfo(ctx context.Context, req *pb.UpdateUserInfoRequest) (*pb.UpdateUserInfoReply, error) {
 	// Note: In a real-world application, you'd get the UserID from the JWT token to prevent users from updating others' info.
 	// Here we trust the request for simplicity.
-	user := &biz.User{
+	// Username and Email are optional; only the fields present in the request are updated.
+	user := &biz.UserUpdate{
 		ID:       req.UserID,
 		Username: req.Username,
 		Email:    req.Email,
@@ -107,3 +115,11 @@ func (s *UserService) GetUserList(ctx context.Context, req *pb.GetUserListReques
 
 	return &pb.GetUserListReply{Users: pbUsers, Total: total}, nil
 }
+
+// SetUserRole implements api.user.v1.UserServer.
+func (s *UserService) SetUserRole(ctx context.Context, req *pb.SetUserRoleRequest) (*pb.SetUserRoleReply, error) {
+	if err := s.uc.SetUserRole(ctx, req.UserID, req.Role); err != nil {
+		return nil, err
+	}
+	return &pb.SetUserRoleReply{Success: true, Message: "Role updated, the user must log in again"}, nil
+}
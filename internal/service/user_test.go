@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	pb "review/api/user/v1"
+	"review/internal/biz"
+	"review/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/log"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("test-secret")
+
+// fakeUserRepo issues a token with the claims of the stored user; any other UserRepo method panics.
+type fakeUserRepo struct {
+	biz.UserRepo
+	res *biz.LoginResult
+}
+
+func (r *fakeUserRepo) Login(context.Context, string, string) (*biz.LoginResult, error) {
+	claims := jwtv5.MapClaims{"user_id": r.res.UserID, "role": r.res.Role, "exp": r.res.ExpiresAt.Unix()}
+	if r.res.StoreID != 0 {
+		claims["store_id"] = r.res.StoreID
+	}
+	token, err := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, claims).SignedString(testSecret)
+	if err != nil {
+		return nil, err
+	}
+	res := *r.res
+	res.Token = token
+	return &res, nil
+}
+
+func TestLoginReplyMatchesClaims(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	tests := []struct {
+		name string
+		res  *biz.LoginResult
+	}{
+		{name: "customer", res: &biz.LoginResult{UserID: 1834567890123456789, Role: "customer", ExpiresAt: expiresAt}},
+		{name: "merchant", res: &biz.LoginResult{UserID: 1834567890123456789, Role: "merchant", StoreID: 1834567890123456790, ExpiresAt: expiresAt}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, err := biz.NewUserUsecase(&fakeUserRepo{res: tt.res}, nil, log.DefaultLogger, &conf.Auth{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			reply, err := NewUserService(uc).Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret"})
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			claims := jwtv5.MapClaims{}
+			if _, err := jwtv5.ParseWithClaims(reply.Token, claims, func(*jwtv5.Token) (interface{}, error) { return testSecret, nil },
+				jwtv5.WithJSONNumber()); err != nil {
+				t.Fatalf("reply token does not verify: %v", err)
+			}
+			claim := func(key string) int64 {
+				n, _ := claims[key].(json.Number)
+				v, _ := n.Int64()
+				return v
+			}
+			if reply.UserID != claim("user_id") {
+				t.Errorf("UserID = %d, want the user_id claim %v", reply.UserID, claims["user_id"])
+			}
+			if reply.Role != claims["role"] {
+				t.Errorf("Role = %q, want the role claim %v", reply.Role, claims["role"])
+			}
+			if reply.StoreID != claim("store_id") {
+				t.Errorf("StoreID = %d, want the store_id claim %v", reply.StoreID, claims["store_id"])
+			}
+			if reply.ExpiresAt != claim("exp") {
+				t.Errorf("ExpiresAt = %d, want the exp claim %v", reply.ExpiresAt, claims["exp"])
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+-- 为已有数据库的申诉表增加申诉类型列
+-- 此前只能申诉隐藏已通过(20)的评论, 已有申诉都是 hide 类型, 由列默认值回填
+USE reviewdb;
+
+ALTER TABLE review_appeal_info
+  ADD COLUMN `appeal_type` varchar(16) NOT NULL DEFAULT 'hide' COMMENT '申诉类型hide/reinstate' AFTER `status`;
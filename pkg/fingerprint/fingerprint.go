@@ -0,0 +1,75 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// Normalize 归一化文本, 消除常见的规避手段对指纹的影响:
+// 全角转半角、转小写, 去掉空白、标点和符号, 只保留字母和数字
+func Normalize(s string) string {
+	s = strings.ToLower(width.Fold.String(s))
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Exact 文本原文的SHA-256, 十六进制
+func Exact(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// SimHash 归一化文本的64位SimHash, 以相邻两个字符为特征
+// 只有少量字符不同的文本指纹之间的汉明距离很小, 用于识别近似重复的内容; 归一化后为空时返回0
+func SimHash(s string) uint64 {
+	runes := []rune(Normalize(s))
+	if len(runes) == 0 {
+		return 0
+	}
+	if len(runes) == 1 {
+		runes = append(runes, runes[0])
+	}
+	var weights [64]int
+	h := fnv.New64a()
+	for i := 0; i+1 < len(runes); i++ {
+		h.Reset()
+		h.Write([]byte(string(runes[i : i+2])))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+	var fp uint64
+	for bit, w := range weights {
+		if w > 0 {
+			fp |= 1 << bit
+		}
+	}
+	return fp
+}
+
+// Distance 两个SimHash指纹的汉明距离
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Bands 将指纹拆成4段16位, 汉明距离不超过3的两个指纹至少有一段完全相同, 用作近似查找的索引
+func Bands(fp uint64) [4]uint16 {
+	return [4]uint16{uint16(fp), uint16(fp >> 16), uint16(fp >> 32), uint16(fp >> 48)}
+}
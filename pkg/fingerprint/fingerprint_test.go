@@ -0,0 +1,62 @@
+package fingerprint
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: "Hello, World!", want: "helloworld"},
+		{in: "ＡＢＣ１２３", want: "abc123"},
+		{in: "加 微 信：abc-123", want: "加微信abc123"},
+		{in: "!!! ...", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimHash(t *testing.T) {
+	const base = "加微信领取优惠券，全场商品一律五折，名额有限先到先得，关注店铺还送精美礼品一份，每天前一百名下单的顾客另有惊喜红包，错过今天再等一年"
+	tests := []struct {
+		name        string
+		a, b        string
+		maxDistance int
+		minDistance int
+	}{
+		{name: "punctuation and width are ignored", a: base, b: "加微信领取优惠券!!全场商品一律五折 名额有限先到先得，关注店铺还送精美礼品一份，每天前一百名下单的顾客另有惊喜红包，错过今天再等一年。", maxDistance: 0},
+		{name: "one character changed", a: base, b: "加薇信领取优惠券，全场商品一律五折，名额有限先到先得，关注店铺还送精美礼品一份，每天前一百名下单的顾客另有惊喜红包，错过今天再等一年", maxDistance: 3},
+		{name: "unrelated text", a: base, b: "包装很结实，物流也快，下次还会再来买", maxDistance: 64, minDistance: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Distance(SimHash(tt.a), SimHash(tt.b))
+			if d > tt.maxDistance || d < tt.minDistance {
+				t.Errorf("Distance = %d, want between %d and %d", d, tt.minDistance, tt.maxDistance)
+			}
+		})
+	}
+	if fp := SimHash("!!!"); fp != 0 {
+		t.Errorf("SimHash of text without letters = %x, want 0", fp)
+	}
+}
+
+func TestBands(t *testing.T) {
+	// Flipping one bit in each of three bands leaves the fourth band intact.
+	fp := uint64(0x0123456789abcdef)
+	near := fp ^ (1 | 1<<20 | 1<<40)
+	a, b := Bands(fp), Bands(near)
+	shared := 0
+	for i := range a {
+		if a[i] == b[i] {
+			shared++
+		}
+	}
+	if shared != 1 || a[3] != b[3] {
+		t.Errorf("Bands share %d segments (%v vs %v), want only the last", shared, a, b)
+	}
+}
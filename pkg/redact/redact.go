@@ -0,0 +1,59 @@
+package redact
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// defaultMaxLength 未开启完整内容日志时, 日志中评论内容、LLM输入输出保留的最大字符数
+const defaultMaxLength = 64
+
+type options struct {
+	redact    bool
+	maxLength int
+	full      bool
+}
+
+var opts atomic.Pointer[options]
+
+func init() {
+	opts.Store(&options{maxLength: defaultMaxLength})
+}
+
+// Init 设置日志脱敏方式
+// redact 为 true 时文本内容只记录长度, 用户ID只保留后4位; 否则文本截断到 maxLength 个字符,
+// full 为 true 时记录完整文本, 仅用于排查问题; redact 优先于 full。maxLength <= 0 时使用默认值
+func Init(redact bool, maxLength int, full bool) {
+	if maxLength <= 0 {
+		maxLength = defaultMaxLength
+	}
+	opts.Store(&options{redact: redact, maxLength: maxLength, full: full})
+}
+
+// Text 返回可以写入日志的文本, 用于评论内容、查询、LLM输入输出等可能包含个人信息的内容
+func Text(s string) string {
+	o := opts.Load()
+	n := utf8.RuneCountInString(s)
+	switch {
+	case o.redact:
+		return fmt.Sprintf("[redacted len=%d]", n)
+	case o.full || n <= o.maxLength:
+		return s
+	}
+	runes := []rune(s)
+	return fmt.Sprintf("%s...(len=%d)", string(runes[:o.maxLength]), n)
+}
+
+// ID 返回可以写入日志的用户标识, 脱敏时只保留后4位
+func ID(id int64) string {
+	s := strconv.FormatInt(id, 10)
+	if !opts.Load().redact {
+		return s
+	}
+	if len(s) <= 4 {
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
@@ -0,0 +1,56 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	long := strings.Repeat("好", 10)
+	tests := []struct {
+		name      string
+		redact    bool
+		maxLength int
+		full      bool
+		in        string
+		want      string
+	}{
+		{name: "short text is kept", maxLength: 10, in: long, want: long},
+		{name: "truncated by runes", maxLength: 4, in: long, want: "好好好好...(len=10)"},
+		{name: "full content", maxLength: 4, full: true, in: long, want: long},
+		{name: "redacted", redact: true, in: long, want: "[redacted len=10]"},
+		{name: "redact wins over full", redact: true, full: true, in: "手机13800138000", want: "[redacted len=13]"},
+		{name: "default length", maxLength: 0, in: strings.Repeat("a", defaultMaxLength+1), want: strings.Repeat("a", defaultMaxLength) + "...(len=65)"},
+	}
+	t.Cleanup(func() { Init(false, 0, false) })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Init(tt.redact, tt.maxLength, tt.full)
+			if got := Text(tt.in); got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		redact bool
+		id     int64
+		want   string
+	}{
+		{name: "plain", id: 1234567, want: "1234567"},
+		{name: "redacted", redact: true, id: 1234567, want: "****4567"},
+		{name: "short id is fully masked", redact: true, id: 42, want: "****"},
+	}
+	t.Cleanup(func() { Init(false, 0, false) })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Init(tt.redact, 0, false)
+			if got := ID(tt.id); got != tt.want {
+				t.Errorf("ID(%d) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
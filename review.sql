@@ -8,6 +8,9 @@ CREATE DATABASE IF NOT EXISTS reviewdb DEFAULT CHARACTER SET utf8mb4 COLLATE utf
 USE reviewdb;
 
 -- 删除已存在的表（重新创建）
+DROP TABLE IF EXISTS review_audit_log;
+DROP TABLE IF EXISTS review_append_info;
+DROP TABLE IF EXISTS es_outbox;
 DROP TABLE IF EXISTS review_appeal_info;
 DROP TABLE IF EXISTS review_reply_info; 
 DROP TABLE IF EXISTS review_info;
@@ -19,7 +22,7 @@ CREATE TABLE IF NOT EXISTS users (
     id BIGINT UNSIGNED NOT NULL PRIMARY KEY,
     username VARCHAR(50) NOT NULL UNIQUE,
     password_hash VARCHAR(255) NOT NULL,
-    role ENUM('customer', 'merchant', 'reviewer') NOT NULL,
+    role ENUM('customer', 'merchant', 'reviewer', 'admin') NOT NULL,
     email VARCHAR(100) NOT NULL UNIQUE,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
@@ -66,6 +69,9 @@ CREATE TABLE IF NOT EXISTS review_info (
   `op_reason` varchar(512) NOT NULL DEFAULT '' COMMENT '操作原因',
   `op_remarks` varchar(512) NOT NULL DEFAULT '' COMMENT '操作备注',
   `op_user` varchar(64) NOT NULL DEFAULT '' COMMENT '操作用户',
+  `reject_category` varchar(32) NOT NULL DEFAULT '' COMMENT '驳回类别',
+  `client_ip` varchar(64) NOT NULL DEFAULT '' COMMENT '创建时的客户端IP',
+  `user_agent` varchar(512) NOT NULL DEFAULT '' COMMENT '创建时的User-Agent',
   `goods_snapshoot` varchar(2048) NOT NULL DEFAULT '' COMMENT '商品快照',
   `ext_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '扩展JSON',
   `ctrl_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '控制JSON',
@@ -73,7 +79,8 @@ CREATE TABLE IF NOT EXISTS review_info (
   KEY `idx_delete_at` (`delete_at`) COMMENT '删除时间索引',
   UNIQUE KEY `uk_review_id` (`review_id`) COMMENT '评论ID唯一索引',
   KEY `idx_order_id` (`order_id`) COMMENT '订单ID索引',
-  KEY `idx_user_id` (`user_id`) COMMENT '用户ID索引'
+  KEY `idx_user_id` (`user_id`) COMMENT '用户ID索引',
+  KEY `idx_create_at` (`create_at`, `id`) COMMENT '创建时间索引, 按创建时间分段重建索引时键集分页'
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评论信息表';
 
 
@@ -91,9 +98,74 @@ CURRENT_TIMESTAMP COMMENT '更新时间',
 `store_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '店铺id',
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='回复信息表';
 
+-- 申诉信息表，appeal_type: hide 申诉隐藏已通过的评论, reinstate 申诉恢复已驳回的评论
+-- 已有数据库执行 migrations/review_appeal_type.sql 增加 appeal_type 列
+CREATE TABLE IF NOT EXISTS review_appeal_info (
+  `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `create_by` varchar(48) NOT NULL DEFAULT '' COMMENT '创建方标识',
+  `update_by` varchar(48) NOT NULL DEFAULT '' COMMENT '更新方标识',
+  `create_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  `delete_at` timestamp NULL DEFAULT NULL COMMENT '逻辑删除标记',
+  `version` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '乐观锁标记',
+  `appeal_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '申诉id',
+  `review_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '评价id',
+  `store_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '店铺id',
+  `status` tinyint(4) NOT NULL DEFAULT '10' COMMENT '状态:10待审核;20申诉通过;30申诉驳回',
+  `appeal_type` varchar(16) NOT NULL DEFAULT 'hide' COMMENT '申诉类型hide/reinstate',
+  `reason` varchar(255) NOT NULL DEFAULT '' COMMENT '申诉原因类别',
+  `content` varchar(255) NOT NULL DEFAULT '' COMMENT '申诉内容描述',
+  `pic_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '图片信息',
+  `video_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '视频信息',
+  `op_remarks` varchar(512) NOT NULL DEFAULT '' COMMENT '运营备注',
+  `op_user` varchar(64) NOT NULL DEFAULT '' COMMENT '运营者标识',
+  `ext_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '扩展JSON',
+  `ctrl_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '控制JSON',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_appeal_id` (`appeal_id`) COMMENT '申诉ID唯一索引',
+  KEY `idx_review_id` (`review_id`) COMMENT '评论ID索引',
+  KEY `idx_status` (`status`) COMMENT '状态索引'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评价商家申诉表';
 
+-- 评论审核日志表，记录每一次评论状态流转
+CREATE TABLE IF NOT EXISTS review_audit_log (
+  `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `create_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `review_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '评论ID',
+  `from_status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '变更前状态',
+  `to_status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '变更后状态',
+  `source` varchar(32) NOT NULL DEFAULT '' COMMENT '审核来源ai/human/author/trusted-fast-path/appeal',
+  `op_user` varchar(64) NOT NULL DEFAULT '' COMMENT '操作用户',
+  `reason` varchar(512) NOT NULL DEFAULT '' COMMENT '审核原因',
+  `remarks` varchar(512) NOT NULL DEFAULT '' COMMENT '审核备注',
+  `category` varchar(32) NOT NULL DEFAULT '' COMMENT 'AI审核违规类别',
+  `confidence` decimal(4,3) NOT NULL DEFAULT '0.000' COMMENT 'AI审核置信度',
+  `language` varchar(16) NOT NULL DEFAULT '' COMMENT '评论语言',
+  `template` varchar(32) NOT NULL DEFAULT '' COMMENT 'AI审核使用的提示词',
+  PRIMARY KEY (`id`),
+  KEY `idx_review_id` (`review_id`) COMMENT '评论ID索引',
+  KEY `idx_create_at` (`create_at`) COMMENT '创建时间索引'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评论审核日志表';
 
+-- 追加评论表，同一订单的追加评论按写入顺序记录，不再拼接到评论内容中
+CREATE TABLE IF NOT EXISTS review_append_info (
+  `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `create_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `review_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '评论ID',
+  `content` varchar(512) NOT NULL DEFAULT '' COMMENT '追加内容',
+  PRIMARY KEY (`id`),
+  KEY `idx_review_id` (`review_id`) COMMENT '评论ID索引'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='追加评论表';
 
-
-
-
+-- ES同步发件箱，评论写入时在同一事务中记录，后台同步到ES成功后删除
+CREATE TABLE IF NOT EXISTS es_outbox (
+  `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `create_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `review_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '评论ID',
+  `version` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '写入后的评论版本号',
+  `attempts` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '同步失败次数',
+  `last_error` varchar(512) NOT NULL DEFAULT '' COMMENT '最近一次同步失败的原因',
+  PRIMARY KEY (`id`),
+  KEY `idx_review_id` (`review_id`) COMMENT '评论ID索引',
+  KEY `idx_create_at` (`create_at`) COMMENT '创建时间索引'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='ES同步发件箱';